@@ -16,9 +16,11 @@ import (
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 
+	"github.com/trpg-solo-engine/backend/internal/catalog"
 	"github.com/trpg-solo-engine/backend/internal/domain"
 	"github.com/trpg-solo-engine/backend/internal/handler"
 	"github.com/trpg-solo-engine/backend/internal/infrastructure/database"
+	"github.com/trpg-solo-engine/backend/internal/infrastructure/repository"
 
 	// "github.com/trpg-solo-engine/backend/internal/middleware"
 	"github.com/trpg-solo-engine/backend/internal/service"
@@ -55,15 +57,35 @@ func main() {
 	}
 	defer redisClient.Close()
 
+	// 加载游戏规则集
+	rules, err := loadRuleset()
+	if err != nil {
+		logger.Fatal("invalid game rules", zap.Error(err))
+	}
+
+	// 启动时加载并校验ARC配置，配置有误时拒绝启动
+	arcCatalog, err := catalog.LoadDir(viper.GetString("game.arc_configs_path"))
+	if err != nil {
+		logger.Fatal("invalid ARC configs", zap.Error(err))
+	}
+
+	// 角色由数据库仓储持久化，所有服务共用基于该仓储的角色服务
+	agentRepo := repository.NewAgentRepositoryWithCatalog(db, redisClient, logger, arcCatalog)
+
+	// 数据迁移：为只保存了ARC类型的旧角色补全完整ARC数据
+	migrated, err := agentRepo.MigrateLegacyARC(context.Background())
+	if err != nil {
+		logger.Fatal("failed to migrate legacy agents", zap.Error(err))
+	}
+	if migrated > 0 {
+		logger.Info("migrated legacy agents to full ARC data", zap.Int("count", migrated))
+	}
+
 	// 初始化服务
-	diceService := domain.NewDiceService()
-	agentService := service.NewAgentService()
-	gameService := service.NewGameService()
-	scenarioService := service.NewScenarioService("scenarios")
-	saveService := service.NewSaveService(gameService, agentService)
+	services := newServices(db, logger, agentRepo, rules, arcCatalog)
 
 	// 创建Gin路由
-	router := setupRouter(logger, db, redisClient, diceService, agentService, gameService, scenarioService, saveService)
+	router := setupRouter(logger, db, redisClient, arcCatalog, services)
 
 	// 创建HTTP服务器
 	port := viper.GetString("server.port")
@@ -118,6 +140,7 @@ func loadConfig() error {
 	viper.SetDefault("database.sslmode", "disable")
 	viper.SetDefault("redis.host", "localhost")
 	viper.SetDefault("redis.port", 6379)
+	viper.SetDefault("game.arc_configs_path", "configs")
 
 	// 启用环境变量支持
 	viper.AutomaticEnv()
@@ -137,7 +160,92 @@ func loadConfig() error {
 	return nil
 }
 
-func setupRouter(logger *zap.Logger, db *gorm.DB, redisClient *redis.Client, diceService domain.DiceService, agentService service.AgentService, gameService service.GameService, scenarioService service.ScenarioService, saveService service.SaveService) *gin.Engine {
+// loadRuleset 从 game.rules 读取游戏规则集，未配置的字段使用规则书默认值
+func loadRuleset() (*domain.Ruleset, error) {
+	rules := domain.DefaultRuleset()
+	if err := viper.UnmarshalKey("game.rules", rules); err != nil {
+		return nil, err
+	}
+
+	if err := rules.Validate(); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// appServices 服务器使用的全部服务
+type appServices struct {
+	dice            domain.DiceService
+	agents          service.AgentService
+	scenarios       service.ScenarioService
+	games           service.GameService
+	saves           service.SaveService
+	rollLedger      service.RollLedgerService
+	pendingRolls    service.PendingRollService
+	tripleAscension service.TripleAscensionService
+	sessionRolls    service.SessionRollService
+	overloadRelief  service.OverloadReliefService
+	agentDrafts     service.AgentDraftService
+	degradation     service.DegradationService
+	realityTriggers service.RealityTriggerService
+	behaviors       service.BehaviorService
+	ledger          service.LedgerService
+	store           service.StoreService
+	relationships   service.RelationshipService
+	bundles         service.AgentBundleService
+	deaths          service.DeathService
+	chaos           service.ChaosService
+	encounters      service.EncounterService
+	aftermath       service.AftermathService
+	abilities       service.AbilityService
+	requests        service.RequestService
+	scenes          service.SceneService
+	clues           service.ClueService
+	npcs            service.NPCService
+	ai              service.AIService
+}
+
+// newServices 初始化服务，角色读写统一经过agentRepo
+// 嘉奖花费等读-改-写操作由仓储在事务中加行锁完成
+func newServices(db *gorm.DB, logger *zap.Logger, agentRepo repository.AgentRepository, rules *domain.Ruleset, arcCatalog *catalog.Catalog) *appServices {
+	s := &appServices{}
+	s.dice = domain.NewDiceServiceWithRuleset(rules)
+	s.agents = service.NewAgentServiceWithRepo(agentRepo, arcCatalog)
+	s.scenarios = service.NewScenarioService("scenarios")
+	s.games = service.NewGameServiceWithAgents(s.scenarios, s.agents, rules)
+	s.saves = service.NewSaveService(s.games, s.agents)
+	s.rollLedger = service.NewRollLedgerServiceWithRepo(repository.NewRollRepository(db, logger))
+	s.pendingRolls = service.NewPendingRollService(s.agents, service.NewQAService(s.dice), time.Duration(viper.GetInt("game.session.pending_roll_ttl"))*time.Second)
+	s.ai = service.NewAIService()
+	s.tripleAscension = service.NewTripleAscensionService(s.agents, s.games, s.ai, rules)
+	s.sessionRolls = service.NewSessionRollService(s.rollLedger, s.tripleAscension)
+	s.overloadRelief = service.NewOverloadReliefService(s.agents, s.games, rules)
+	s.agentDrafts = service.NewAgentDraftServiceWithRepo(repository.NewDraftRepository(db, logger), s.agents, arcCatalog)
+	s.degradation = service.NewDegradationService(s.agents, arcCatalog)
+	s.realityTriggers = service.NewRealityTriggerService(s.agents, s.games, rules)
+	s.behaviors = service.NewBehaviorService(s.agents, s.games, service.NewPerformanceService())
+	s.ledger = service.NewLedgerService(s.agents)
+	s.store = service.NewStoreService(s.agents, s.games, s.scenarios, arcCatalog)
+	s.relationships = service.NewRelationshipService(s.agents, s.games)
+	bundleSigningKey := viper.GetString("auth.bundle_signing_key")
+	if bundleSigningKey == "" {
+		logger.Warn("auth.bundle_signing_key is not set, agent bundle export and import are disabled")
+	}
+	s.bundles = service.NewAgentBundleService(s.agents, []byte(bundleSigningKey))
+	s.deaths = service.NewDeathService(s.agents, s.games, rules)
+	s.chaos = service.NewChaosService()
+	s.encounters = service.NewEncounterService(s.games, s.agents, s.scenarios, s.dice, s.chaos, s.sessionRolls, rules)
+	s.aftermath = service.NewAftermathService(s.agents, s.games, s.scenarios, s.store, service.NewPerformanceService(), service.NewQAService(s.dice))
+	s.abilities = service.NewAbilityService(s.dice, service.NewQAService(s.dice), s.chaos)
+	s.requests = service.NewRequestService(s.dice, s.chaos)
+	s.scenes = service.NewSceneService(s.scenarios, s.games)
+	s.clues = service.NewClueService(s.scenarios, s.games)
+	s.npcs = service.NewNPCService(s.scenarios, s.games)
+	return s
+}
+
+func setupRouter(logger *zap.Logger, db *gorm.DB, redisClient *redis.Client, arcCatalog *catalog.Catalog, s *appServices) *gin.Engine {
 	// 设置Gin模式
 	if viper.GetString("server.mode") == "release" {
 		gin.SetMode(gin.ReleaseMode)
@@ -178,11 +286,26 @@ func setupRouter(logger *zap.Logger, db *gorm.DB, redisClient *redis.Client, dic
 	})
 
 	// 初始化处理器
-	diceHandler := handler.NewDiceHandler(diceService, agentService)
-	agentHandler := handler.NewAgentHandler(agentService)
-	sessionHandler := handler.NewSessionHandler(gameService)
-	scenarioHandler := handler.NewScenarioHandler(scenarioService)
-	saveHandler := handler.NewSaveHandler(saveService, gameService)
+	diceHandler := handler.NewDiceHandler(s.dice, s.agents, s.games, s.rollLedger, s.pendingRolls, s.tripleAscension, s.sessionRolls)
+	agentHandler := handler.NewAgentHandler(s.agents)
+	sessionHandler := handler.NewSessionHandlerWithServices(s.games, s.realityTriggers, s.store, s.npcs)
+	scenarioHandler := handler.NewScenarioHandler(s.scenarios)
+	saveHandler := handler.NewSaveHandler(s.saves, s.games)
+	overloadReliefHandler := handler.NewOverloadReliefHandler(s.overloadRelief)
+	arcHandler := handler.NewArcHandler(arcCatalog)
+	agentDraftHandler := handler.NewAgentDraftHandler(s.agentDrafts)
+	degradationHandler := handler.NewDegradationHandler(s.degradation, s.agents)
+	realityTriggerHandler := handler.NewRealityTriggerHandler(s.realityTriggers)
+	behaviorHandler := handler.NewBehaviorHandler(s.behaviors)
+	ledgerHandler := handler.NewLedgerHandler(s.ledger)
+	storeHandler := handler.NewStoreHandler(s.store)
+	relationshipHandler := handler.NewRelationshipHandler(s.relationships)
+	bundleHandler := handler.NewAgentBundleHandler(s.bundles)
+	deathHandler := handler.NewDeathHandler(s.deaths)
+	morningHandler := handler.NewMorningHandler(s.games)
+	encounterHandler := handler.NewEncounterHandler(s.encounters)
+	aftermathHandler := handler.NewAftermathHandler(s.aftermath)
+	gameplayHandler := handler.NewGameplayHandler(s.games, s.agents, s.dice, s.abilities, s.requests, s.scenes, s.clues, s.npcs, s.chaos, s.ai, s.sessionRolls)
 
 	// API文档路由
 	router.GET("/api/docs", func(c *gin.Context) {
@@ -211,6 +334,10 @@ func setupRouter(logger *zap.Logger, db *gorm.DB, redisClient *redis.Client, dic
 			dice.POST("/roll", diceHandler.RollDice)
 			dice.POST("/ability", diceHandler.RollForAbility)
 			dice.POST("/request", diceHandler.RollForRequest)
+			dice.GET("/odds", diceHandler.GetOdds)
+			dice.POST("/rolls/:token/commit", diceHandler.CommitRoll)
+			dice.POST("/rolls/:token/decline", diceHandler.DeclineRoll)
+			dice.POST("/rolls/:token/reroll", diceHandler.RerollRoll)
 		}
 
 		// 角色API
@@ -221,6 +348,46 @@ func setupRouter(logger *zap.Logger, db *gorm.DB, redisClient *redis.Client, dic
 			agents.GET("/:id", agentHandler.GetAgent)
 			agents.PUT("/:id", agentHandler.UpdateAgent)
 			agents.DELETE("/:id", agentHandler.DeleteAgent)
+			agents.GET("/:id/degradation", degradationHandler.GetDegradation)
+			agents.POST("/:id/degradation", degradationHandler.MarkDegradation)
+			agents.PUT("/:id/reality", degradationHandler.SetReality)
+			agents.GET("/:id/ledger", ledgerHandler.GetLedger)
+			agents.GET("/:id/sheet", agentHandler.GetSheet)
+			agents.GET("/:id/export", bundleHandler.Export)
+			agents.POST("/import", bundleHandler.Import)
+			agents.GET("/memorial", deathHandler.ListMemorial)
+			agents.POST("/:id/death", deathHandler.RecordDeath)
+			agents.POST("/:id/revive", deathHandler.Revive)
+			agents.POST("/:id/successor", deathHandler.CreateSuccessor)
+			agents.POST("/:id/store/purchase", storeHandler.Purchase)
+			agents.GET("/:id/inventory", storeHandler.GetInventory)
+			agents.GET("/:id/relationships", relationshipHandler.ListRelationships)
+			agents.POST("/:id/relationships", relationshipHandler.AddRelationship)
+			agents.GET("/:id/relationships/:relId", relationshipHandler.GetRelationship)
+			agents.PUT("/:id/relationships/:relId", relationshipHandler.UpdateRelationship)
+			agents.DELETE("/:id/relationships/:relId", relationshipHandler.DeleteRelationship)
+			agents.POST("/:id/relationships/:relId/notes", relationshipHandler.AddNote)
+			agents.POST("/:id/relationships/:relId/connection", relationshipHandler.ChangeConnection)
+
+			// 角色创建向导
+			agents.POST("/drafts", agentDraftHandler.CreateDraft)
+			agents.GET("/drafts/:id", agentDraftHandler.GetDraft)
+			agents.PUT("/drafts/:id/anomaly", agentDraftHandler.ChooseAnomaly)
+			agents.PUT("/drafts/:id/career", agentDraftHandler.AnswerAssessment)
+			agents.PUT("/drafts/:id/reality", agentDraftHandler.ChooseReality)
+			agents.PUT("/drafts/:id/relationships", agentDraftHandler.DistributeConnection)
+			agents.POST("/drafts/:id/finalize", agentDraftHandler.Finalize)
+		}
+
+		// ARC目录API（只读）
+		arc := api.Group("/arc")
+		{
+			arc.GET("/anomalies", arcHandler.ListAnomalies)
+			arc.GET("/anomalies/:id", arcHandler.GetAnomaly)
+			arc.GET("/realities", arcHandler.ListRealities)
+			arc.GET("/realities/:id", arcHandler.GetReality)
+			arc.GET("/careers", arcHandler.ListCareers)
+			arc.GET("/careers/:id", arcHandler.GetCareer)
 		}
 
 		// 游戏会话API
@@ -230,8 +397,40 @@ func setupRouter(logger *zap.Logger, db *gorm.DB, redisClient *redis.Client, dic
 			sessions.GET("/:id", sessionHandler.GetSession)
 			sessions.POST("/:id/actions", sessionHandler.ExecuteAction)
 			sessions.POST("/:id/phase", sessionHandler.TransitionPhase)
+			sessions.GET("/:id/morning", morningHandler.GetMorning)
+			sessions.POST("/:id/morning/goals", morningHandler.ChooseGoals)
+			sessions.POST("/:id/morning/scenes/:sceneId", morningHandler.ResolveScene)
+			sessions.POST("/:id/encounter", encounterHandler.StartEncounter)
+			sessions.GET("/:id/encounter", encounterHandler.GetEncounter)
+			sessions.POST("/:id/encounter/actions", encounterHandler.TakeAction)
+			sessions.POST("/:id/aftermath", aftermathHandler.ResolveAftermath)
+			sessions.GET("/:id/aftermath", aftermathHandler.GetDebrief)
+			sessions.POST("/:id/abilities/:abilityId/use", gameplayHandler.UseAbility)
+			sessions.POST("/:id/requests", gameplayHandler.SubmitRequest)
+			sessions.GET("/:id/scene/interactions", gameplayHandler.GetInteractions)
+			sessions.POST("/:id/scene/objects/:objectId/interact", gameplayHandler.InteractWithObject)
+			sessions.GET("/:id/clues", gameplayHandler.GetClues)
+			sessions.GET("/:id/clues/progress", gameplayHandler.GetClueProgress)
+			sessions.GET("/:id/clues/report", gameplayHandler.GetInvestigationReport)
+			sessions.GET("/:id/npcs", gameplayHandler.ListNPCs)
+			sessions.GET("/:id/npcs/:npcId", gameplayHandler.GetNPC)
+			sessions.POST("/:id/npcs/:npcId/talk", gameplayHandler.TalkToNPC)
+			sessions.GET("/:id/chaos", gameplayHandler.GetChaos)
+			sessions.GET("/:id/rolls", diceHandler.ListSessionRolls)
+			sessions.POST("/:id/overload-relief", overloadReliefHandler.ClaimRelief)
+			sessions.GET("/:id/overload-relief", overloadReliefHandler.GetRelief)
+			sessions.GET("/:id/reality-trigger", realityTriggerHandler.GetTrigger)
+			sessions.POST("/:id/reality-trigger/answer", realityTriggerHandler.Answer)
+			sessions.POST("/:id/reality-trigger/ignore", realityTriggerHandler.Ignore)
+			sessions.POST("/:id/behaviors/claim", behaviorHandler.ClaimBehavior)
+			sessions.POST("/:id/behaviors/violation", behaviorHandler.FlagViolation)
+			sessions.POST("/:id/items/use", storeHandler.UseItem)
+			sessions.POST("/:id/relationships/spend", relationshipHandler.SpendConnection)
 		}
 
+		// 机构商店API
+		api.GET("/store", storeHandler.ListItems)
+
 		// 剧本API
 		scenarios := api.Group("/scenarios")
 		{
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trpg-solo-engine/backend/internal/catalog"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+	"github.com/trpg-solo-engine/backend/internal/infrastructure/repository"
+	"go.uber.org/zap"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// testAgentModel SQLite兼容的角色表
+type testAgentModel struct {
+	ID            string `gorm:"primaryKey"`
+	Name          string
+	Pronouns      string
+	AnomalyType   string
+	RealityType   string
+	CareerType    string
+	QA            string
+	Relationships string
+	Inventory     string
+	Commendations int
+	Reprimands    int
+	Rating        string
+	Alive         bool
+	InDebt        bool
+
+	ARCVersion           int `gorm:"column:arc_version"`
+	Anomaly              string
+	Reality              string
+	Career               string
+	Assessment           string
+	PendingRealityChange bool
+	DegradationHistory   string
+	Deaths               string
+	LooseEnds            int
+
+	CreatedAt int64
+	UpdatedAt int64
+}
+
+func (testAgentModel) TableName() string {
+	return "agents"
+}
+
+// testLedgerEntryModel SQLite兼容的账本表
+type testLedgerEntryModel struct {
+	ID            string `gorm:"primaryKey"`
+	AgentID       string
+	SessionID     string
+	SceneID       string
+	Reason        string
+	Behavior      string
+	Justification string
+	FlaggedBy     string
+	Commendations int
+	Reprimands    int
+	CreatedAt     int64
+}
+
+func (testLedgerEntryModel) TableName() string {
+	return "agent_ledger_entries"
+}
+
+func TestSetupRouter_AgentsArePersisted(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&testAgentModel{}, &testLedgerEntryModel{}))
+
+	arcCatalog, err := catalog.LoadDir("../../configs")
+	require.NoError(t, err)
+
+	logger := zap.NewNop()
+	newAgentRepo := func() repository.AgentRepository {
+		return repository.NewAgentRepositoryWithCatalog(db, nil, logger, arcCatalog)
+	}
+	router := setupRouter(logger, db, nil, arcCatalog, newServices(db, logger, newAgentRepo(), domain.DefaultRuleset(), arcCatalog))
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"name":         "持久化测试",
+		"anomaly_type": domain.AnomalyWhisper,
+		"reality_type": domain.RealityCaretaker,
+		"career_type":  domain.CareerPublicRelations,
+	})
+	req, _ := http.NewRequest("POST", "/api/agents", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var response struct {
+		Data domain.Agent `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+	// 角色写入了数据库，重启后的服务能读到
+	var count int64
+	require.NoError(t, db.Model(&testAgentModel{}).Where("id = ?", response.Data.ID).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+
+	restarted := newServices(db, logger, newAgentRepo(), domain.DefaultRuleset(), arcCatalog)
+	agent, err := restarted.agents.GetAgent(response.Data.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "持久化测试", agent.Name)
+}
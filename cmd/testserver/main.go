@@ -23,6 +23,11 @@ func main() {
 	// 初始化服务（不需要数据库）
 	diceService := domain.NewDiceService()
 	agentService := service.NewAgentService()
+	gameService := service.NewGameService()
+	rollLedger := service.NewRollLedgerService()
+	pendingRolls := service.NewPendingRollService(agentService, service.NewQAService(diceService), service.DefaultPendingRollTTL)
+	tripleAscension := service.NewTripleAscensionService(agentService, gameService, service.NewAIService(), nil)
+	sessionRolls := service.NewSessionRollService(rollLedger, tripleAscension)
 
 	// 创建Gin路由
 	gin.SetMode(gin.DebugMode)
@@ -38,7 +43,7 @@ func main() {
 	})
 
 	// 初始化处理器
-	diceHandler := handler.NewDiceHandler(diceService, agentService)
+	diceHandler := handler.NewDiceHandler(diceService, agentService, gameService, rollLedger, pendingRolls, tripleAscension, sessionRolls)
 	agentHandler := handler.NewAgentHandler(agentService)
 
 	// API路由
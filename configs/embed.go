@@ -0,0 +1,8 @@
+package configs
+
+import "embed"
+
+// ARCFiles 内嵌的ARC和机构商店配置文件，作为 catalog.Default 的数据来源
+//
+//go:embed anomalies.json realities.json careers.json store.json
+var ARCFiles embed.FS
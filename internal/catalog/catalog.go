@@ -0,0 +1,315 @@
+// Package catalog 加载并校验ARC（异常体-现实-职能）配置目录
+//
+// 数据来自 configs/anomalies.json、realities.json 和 careers.json，
+// 角色服务通过目录构造带有真实能力、现实和职能资质的角色。
+// 机构商店的物品定义来自 configs/store.json。
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+
+	"github.com/trpg-solo-engine/backend/configs"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+)
+
+// 配置文件名
+const (
+	AnomaliesFile = "anomalies.json"
+	RealitiesFile = "realities.json"
+	CareersFile   = "careers.json"
+)
+
+// Anomaly 异常体定义
+type Anomaly struct {
+	ID          string                   `json:"id"`
+	Name        string                   `json:"name"`
+	Description string                   `json:"description"`
+	Focus       map[string]string        `json:"focus"`
+	Abilities   []*domain.AnomalyAbility `json:"abilities"`
+}
+
+// Reality 现实定义
+type Reality struct {
+	ID               string                 `json:"id"`
+	Name             string                 `json:"name"`
+	Description      string                 `json:"description"`
+	SpecialFeature   map[string]interface{} `json:"special_feature"`
+	Trigger          *domain.RealityTrigger `json:"trigger"`
+	OverloadRelief   *domain.OverloadRelief `json:"overload_relief"`
+	DegradationTrack DegradationTrack       `json:"degradation_track"`
+	Relationships    RelationshipRules      `json:"relationships"`
+}
+
+// DegradationTrack 退化轨道定义
+type DegradationTrack struct {
+	Name        string `json:"name"`
+	Boxes       int    `json:"boxes"`
+	Trigger     string `json:"trigger"`
+	Consequence string `json:"consequence"`
+}
+
+// RelationshipRules 人际关系规则
+type RelationshipRules struct {
+	Count                 int      `json:"count"`
+	TotalConnection       int      `json:"total_connection"`
+	SuggestedDistribution []int    `json:"suggested_distribution"`
+	Questions             []string `json:"questions"`
+}
+
+// Career 职能定义
+type Career struct {
+	ID                  string                      `json:"id"`
+	Name                string                      `json:"name"`
+	Description         string                      `json:"description"`
+	InitialQA           InitialQA                   `json:"initial_qa"`
+	PermittedBehaviors  []*domain.PermittedBehavior `json:"permitted_behaviors"`
+	PrimeDirective      *domain.PrimeDirective      `json:"prime_directive"`
+	InitialClaimable    map[string]string           `json:"initial_claimable"`
+	AssessmentQuestions []string                    `json:"assessment_questions"`
+}
+
+// InitialQA 初始资质保证分配
+type InitialQA struct {
+	Total        int            `json:"total"`
+	Distribution map[string]int `json:"distribution"`
+	Note         string         `json:"note,omitempty"`
+}
+
+// Catalog ARC配置目录
+type Catalog struct {
+	Anomalies []*Anomaly   `json:"anomalies"`
+	Realities []*Reality   `json:"realities"`
+	Careers   []*Career    `json:"careers"`
+	Store     []*StoreItem `json:"store"`
+
+	// 按ID和名称（domain中的类型常量）建立的索引
+	anomalies map[string]*Anomaly
+	realities map[string]*Reality
+	careers   map[string]*Career
+	store     map[string]*StoreItem
+}
+
+var (
+	defaultCatalog *Catalog
+	defaultOnce    sync.Once
+)
+
+// Default 返回由内嵌配置文件构建的目录
+// 内嵌文件在测试中经过校验，解析失败说明构建产物已损坏
+func Default() *Catalog {
+	defaultOnce.Do(func() {
+		catalog, err := Load(configs.ARCFiles)
+		if err != nil {
+			panic(fmt.Sprintf("内嵌ARC配置无效: %v", err))
+		}
+		defaultCatalog = catalog
+	})
+	return defaultCatalog
+}
+
+// LoadDir 从目录加载ARC配置
+func LoadDir(dir string) (*Catalog, error) {
+	return Load(os.DirFS(dir))
+}
+
+// Load 从文件系统加载并校验ARC配置
+func Load(fsys fs.FS) (*Catalog, error) {
+	catalog := &Catalog{}
+
+	var anomalies struct {
+		Anomalies []*Anomaly `json:"anomalies"`
+	}
+	if err := readJSON(fsys, AnomaliesFile, &anomalies); err != nil {
+		return nil, err
+	}
+	catalog.Anomalies = anomalies.Anomalies
+
+	var realities struct {
+		Realities []*Reality `json:"realities"`
+	}
+	if err := readJSON(fsys, RealitiesFile, &realities); err != nil {
+		return nil, err
+	}
+	catalog.Realities = realities.Realities
+
+	var careers struct {
+		Careers []*Career `json:"careers"`
+	}
+	if err := readJSON(fsys, CareersFile, &careers); err != nil {
+		return nil, err
+	}
+	catalog.Careers = careers.Careers
+
+	var store struct {
+		Items []*StoreItem `json:"items"`
+	}
+	if err := readJSON(fsys, StoreFile, &store); err != nil {
+		return nil, err
+	}
+	catalog.Store = store.Items
+
+	if err := catalog.Validate(); err != nil {
+		return nil, err
+	}
+
+	catalog.buildIndex()
+
+	return catalog, nil
+}
+
+// readJSON 读取并解析配置文件
+func readJSON(fsys fs.FS, name string, v interface{}) error {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", name, err)
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// buildIndex 建立按ID和名称的索引
+func (c *Catalog) buildIndex() {
+	c.anomalies = make(map[string]*Anomaly)
+	for _, a := range c.Anomalies {
+		c.anomalies[a.ID] = a
+		c.anomalies[a.Name] = a
+	}
+
+	c.realities = make(map[string]*Reality)
+	for _, r := range c.Realities {
+		c.realities[r.ID] = r
+		c.realities[r.Name] = r
+	}
+
+	c.careers = make(map[string]*Career)
+	for _, cr := range c.Careers {
+		c.careers[cr.ID] = cr
+		c.careers[cr.Name] = cr
+	}
+
+	c.store = make(map[string]*StoreItem)
+	for _, item := range c.Store {
+		c.store[item.ID] = item
+		c.store[item.Name] = item
+	}
+}
+
+// Anomaly 按ID或类型名称查找异常体
+func (c *Catalog) Anomaly(key string) (*Anomaly, bool) {
+	a, ok := c.anomalies[key]
+	return a, ok
+}
+
+// Reality 按ID或类型名称查找现实
+func (c *Catalog) Reality(key string) (*Reality, bool) {
+	r, ok := c.realities[key]
+	return r, ok
+}
+
+// Career 按ID或类型名称查找职能
+func (c *Catalog) Career(key string) (*Career, bool) {
+	cr, ok := c.careers[key]
+	return cr, ok
+}
+
+// NewAnomaly 构造角色使用的异常体，能力为目录数据的副本
+func (c *Catalog) NewAnomaly(anomalyType string) (*domain.Anomaly, error) {
+	entry, ok := c.Anomaly(anomalyType)
+	if !ok {
+		return nil, domain.NewGameError(domain.ErrInvalidARC, "无效的异常体类型").
+			WithDetails("type", anomalyType)
+	}
+
+	var abilities []*domain.AnomalyAbility
+	if err := clone(entry.Abilities, &abilities); err != nil {
+		return nil, err
+	}
+
+	return &domain.Anomaly{
+		Type:      entry.Name,
+		Abilities: abilities,
+	}, nil
+}
+
+// NewReality 构造角色使用的现实，退化轨道从空白开始
+func (c *Catalog) NewReality(realityType string) (*domain.Reality, error) {
+	entry, ok := c.Reality(realityType)
+	if !ok {
+		return nil, domain.NewGameError(domain.ErrInvalidARC, "无效的现实类型").
+			WithDetails("type", realityType)
+	}
+
+	reality := &domain.Reality{
+		Type: entry.Name,
+		DegradationTrack: &domain.DegradationTrack{
+			Name:   entry.DegradationTrack.Name,
+			Filled: 0,
+			Total:  entry.DegradationTrack.Boxes,
+		},
+	}
+
+	if err := clone(entry.SpecialFeature, &reality.SpecialFeature); err != nil {
+		return nil, err
+	}
+	if err := clone(entry.Trigger, &reality.Trigger); err != nil {
+		return nil, err
+	}
+	if err := clone(entry.OverloadRelief, &reality.OverloadRelief); err != nil {
+		return nil, err
+	}
+
+	return reality, nil
+}
+
+// NewCareer 构造角色使用的职能
+func (c *Catalog) NewCareer(careerType string) (*domain.Career, error) {
+	entry, ok := c.Career(careerType)
+	if !ok {
+		return nil, domain.NewGameError(domain.ErrInvalidARC, "无效的职能类型").
+			WithDetails("type", careerType)
+	}
+
+	career := &domain.Career{
+		Type: entry.Name,
+		QA:   entry.NewQA(),
+	}
+
+	if err := clone(entry.PermittedBehaviors, &career.PermittedBehaviors); err != nil {
+		return nil, err
+	}
+	if err := clone(entry.PrimeDirective, &career.PrimeDirective); err != nil {
+		return nil, err
+	}
+
+	if name := entry.InitialClaimable["name"]; name != "" {
+		career.Claimables = []string{name}
+	}
+
+	return career, nil
+}
+
+// NewQA 返回职能初始资质保证分配的副本（包含所有资质，未分配的为0）
+func (cr *Career) NewQA() map[string]int {
+	qa := make(map[string]int, len(domain.AllQualities))
+	for _, quality := range domain.AllQualities {
+		qa[quality] = cr.InitialQA.Distribution[quality]
+	}
+	return qa
+}
+
+// clone 通过JSON深拷贝目录数据，避免角色之间共享指针
+func clone(src, dst interface{}) error {
+	data, err := json.Marshal(src)
+	if err != nil {
+		return fmt.Errorf("failed to copy catalog entry: %w", err)
+	}
+	return json.Unmarshal(data, dst)
+}
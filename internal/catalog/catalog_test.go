@@ -0,0 +1,155 @@
+package catalog
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trpg-solo-engine/backend/configs"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+)
+
+func TestLoad_ConfigFiles(t *testing.T) {
+	catalog, err := LoadDir("../../configs")
+	require.NoError(t, err)
+
+	assert.Len(t, catalog.Anomalies, len(domain.AllAnomalyTypes))
+	assert.Len(t, catalog.Realities, len(domain.AllRealityTypes))
+	assert.Len(t, catalog.Careers, len(domain.AllCareerTypes))
+
+	// 按ID和名称都能查到
+	byName, ok := catalog.Anomaly(domain.AnomalyWhisper)
+	require.True(t, ok)
+	byID, ok := catalog.Anomaly("whisper")
+	require.True(t, ok)
+	assert.Same(t, byName, byID)
+
+	assert.NotNil(t, Default())
+}
+
+func TestLoad_RejectsInvalidConfig(t *testing.T) {
+	valid := func() fstest.MapFS {
+		fsys := fstest.MapFS{}
+		for _, name := range []string{AnomaliesFile, RealitiesFile, CareersFile, StoreFile} {
+			data, err := configs.ARCFiles.ReadFile(name)
+			require.NoError(t, err)
+			fsys[name] = &fstest.MapFile{Data: data}
+		}
+		return fsys
+	}
+
+	t.Run("缺少文件", func(t *testing.T) {
+		fsys := valid()
+		delete(fsys, CareersFile)
+
+		_, err := Load(fsys)
+		assert.Error(t, err)
+	})
+
+	t.Run("缺少类型定义", func(t *testing.T) {
+		fsys := valid()
+		fsys[RealitiesFile] = &fstest.MapFile{Data: []byte(`{"realities": []}`)}
+
+		_, err := Load(fsys)
+		require.Error(t, err)
+		assert.Equal(t, domain.ErrDataCorrupted, err.(*domain.GameError).Code)
+	})
+
+	t.Run("资质保证总数错误", func(t *testing.T) {
+		catalog, err := Load(valid())
+		require.NoError(t, err)
+
+		catalog.Careers[0].InitialQA.Distribution[domain.QualityFocus] += 1
+		err = catalog.Validate()
+		require.Error(t, err)
+		assert.Equal(t, domain.ErrDataCorrupted, err.(*domain.GameError).Code)
+	})
+
+	t.Run("缺少商店配置", func(t *testing.T) {
+		fsys := valid()
+		delete(fsys, StoreFile)
+
+		_, err := Load(fsys)
+		assert.Error(t, err)
+	})
+
+	t.Run("初始可申领物不在商店中", func(t *testing.T) {
+		fsys := valid()
+		fsys[StoreFile] = &fstest.MapFile{Data: []byte(`{"items": []}`)}
+
+		_, err := Load(fsys)
+		require.Error(t, err)
+		assert.Equal(t, domain.ErrDataCorrupted, err.(*domain.GameError).Code)
+	})
+
+	t.Run("物品效果无效", func(t *testing.T) {
+		catalog, err := Load(valid())
+		require.NoError(t, err)
+
+		catalog.Store[0].Effect.Amount = 0
+		err = catalog.Validate()
+		require.Error(t, err)
+		assert.Equal(t, domain.ErrDataCorrupted, err.(*domain.GameError).Code)
+
+		catalog.Store[0].Effect = &domain.ItemEffect{Type: "teleport", Amount: 1}
+		err = catalog.Validate()
+		require.Error(t, err)
+		assert.Equal(t, domain.ErrDataCorrupted, err.(*domain.GameError).Code)
+	})
+
+	t.Run("现实触发器缺少忽视效果", func(t *testing.T) {
+		catalog, err := Load(valid())
+		require.NoError(t, err)
+
+		catalog.Realities[0].Trigger.IgnoreEffect = nil
+		err = catalog.Validate()
+		require.Error(t, err)
+		assert.Equal(t, domain.ErrDataCorrupted, err.(*domain.GameError).Code)
+	})
+}
+
+func TestCatalog_NewARC(t *testing.T) {
+	catalog := Default()
+
+	t.Run("异常能力为副本", func(t *testing.T) {
+		first, err := catalog.NewAnomaly(domain.AnomalyGun)
+		require.NoError(t, err)
+		second, err := catalog.NewAnomaly(domain.AnomalyGun)
+		require.NoError(t, err)
+
+		assert.Len(t, first.Abilities, 3)
+		first.Abilities[0].Name = "改名"
+		assert.NotEqual(t, "改名", second.Abilities[0].Name)
+	})
+
+	t.Run("现实使用配置中的退化轨道和过载解除", func(t *testing.T) {
+		reality, err := catalog.NewReality("hunted")
+		require.NoError(t, err)
+
+		assert.Equal(t, domain.RealityHunted, reality.Type)
+		assert.Equal(t, 0, reality.DegradationTrack.Filled)
+		assert.Equal(t, 4, reality.DegradationTrack.Total)
+		assert.NotEmpty(t, reality.OverloadRelief.Condition)
+	})
+
+	t.Run("职能资质保证包含所有资质", func(t *testing.T) {
+		career, err := catalog.NewCareer(domain.CareerCEO)
+		require.NoError(t, err)
+
+		assert.Len(t, career.QA, len(domain.AllQualities))
+		total := 0
+		for _, points := range career.QA {
+			total += points
+		}
+		assert.Equal(t, 9, total)
+		assert.NotNil(t, career.PrimeDirective)
+		assert.Len(t, career.Claimables, 1)
+	})
+
+	t.Run("未知类型", func(t *testing.T) {
+		_, err := catalog.NewCareer("无效类型")
+		require.Error(t, err)
+		assert.Equal(t, domain.ErrInvalidARC, err.(*domain.GameError).Code)
+	})
+}
@@ -0,0 +1,142 @@
+package catalog
+
+import "github.com/trpg-solo-engine/backend/internal/domain"
+
+// AnomalyDetail 异常体浏览视图
+// 内嵌 domain.Anomaly，字段形状与角色上的异常体一致
+type AnomalyDetail struct {
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Focus       map[string]string `json:"focus"`
+	*domain.Anomaly
+}
+
+// RealityDetail 现实浏览视图
+// 内嵌 domain.Reality，另附退化轨道规则和人际关系规则
+type RealityDetail struct {
+	ID                string            `json:"id"`
+	Name              string            `json:"name"`
+	Description       string            `json:"description"`
+	DegradationRules  DegradationTrack  `json:"degradation_rules"`
+	RelationshipRules RelationshipRules `json:"relationship_rules"`
+	*domain.Reality
+}
+
+// CareerDetail 职能浏览视图
+// 内嵌 domain.Career，另附初始申领物和评估问题
+type CareerDetail struct {
+	ID                  string            `json:"id"`
+	Name                string            `json:"name"`
+	Description         string            `json:"description"`
+	InitialClaimable    map[string]string `json:"initial_claimable"`
+	AssessmentQuestions []string          `json:"assessment_questions"`
+	*domain.Career
+}
+
+// AnomalyDetails 按配置顺序返回所有异常体
+func (c *Catalog) AnomalyDetails() ([]*AnomalyDetail, error) {
+	details := make([]*AnomalyDetail, 0, len(c.Anomalies))
+	for _, a := range c.Anomalies {
+		detail, err := c.AnomalyDetail(a.ID)
+		if err != nil {
+			return nil, err
+		}
+		details = append(details, detail)
+	}
+	return details, nil
+}
+
+// AnomalyDetail 按ID或类型名称返回异常体
+func (c *Catalog) AnomalyDetail(key string) (*AnomalyDetail, error) {
+	entry, ok := c.Anomaly(key)
+	if !ok {
+		return nil, domain.NewGameError(domain.ErrNotFound, "异常体不存在").
+			WithDetails("id", key)
+	}
+
+	anomaly, err := c.NewAnomaly(entry.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AnomalyDetail{
+		ID:          entry.ID,
+		Name:        entry.Name,
+		Description: entry.Description,
+		Focus:       entry.Focus,
+		Anomaly:     anomaly,
+	}, nil
+}
+
+// RealityDetails 按配置顺序返回所有现实
+func (c *Catalog) RealityDetails() ([]*RealityDetail, error) {
+	details := make([]*RealityDetail, 0, len(c.Realities))
+	for _, r := range c.Realities {
+		detail, err := c.RealityDetail(r.ID)
+		if err != nil {
+			return nil, err
+		}
+		details = append(details, detail)
+	}
+	return details, nil
+}
+
+// RealityDetail 按ID或类型名称返回现实
+func (c *Catalog) RealityDetail(key string) (*RealityDetail, error) {
+	entry, ok := c.Reality(key)
+	if !ok {
+		return nil, domain.NewGameError(domain.ErrNotFound, "现实不存在").
+			WithDetails("id", key)
+	}
+
+	reality, err := c.NewReality(entry.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RealityDetail{
+		ID:                entry.ID,
+		Name:              entry.Name,
+		Description:       entry.Description,
+		DegradationRules:  entry.DegradationTrack,
+		RelationshipRules: entry.Relationships,
+		Reality:           reality,
+	}, nil
+}
+
+// CareerDetails 按配置顺序返回所有职能
+func (c *Catalog) CareerDetails() ([]*CareerDetail, error) {
+	details := make([]*CareerDetail, 0, len(c.Careers))
+	for _, cr := range c.Careers {
+		detail, err := c.CareerDetail(cr.ID)
+		if err != nil {
+			return nil, err
+		}
+		details = append(details, detail)
+	}
+	return details, nil
+}
+
+// CareerDetail 按ID或类型名称返回职能
+func (c *Catalog) CareerDetail(key string) (*CareerDetail, error) {
+	entry, ok := c.Career(key)
+	if !ok {
+		return nil, domain.NewGameError(domain.ErrNotFound, "职能不存在").
+			WithDetails("id", key)
+	}
+
+	career, err := c.NewCareer(entry.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CareerDetail{
+		ID:                  entry.ID,
+		Name:                entry.Name,
+		Description:         entry.Description,
+		InitialClaimable:    entry.InitialClaimable,
+		AssessmentQuestions: entry.AssessmentQuestions,
+		Career:              career,
+	}, nil
+}
@@ -0,0 +1,130 @@
+package catalog
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/trpg-solo-engine/backend/internal/domain"
+)
+
+// StoreFile 机构商店配置文件名
+const StoreFile = "store.json"
+
+// StoreItem 机构商店物品定义
+type StoreItem struct {
+	ID          string             `json:"id"`
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	Price       int                `json:"price"`             // 嘉奖
+	Careers     []string           `json:"careers,omitempty"` // 可购买的职能ID，为空表示所有职能
+	Uses        int                `json:"uses"`              // 每件物品的使用次数，0表示不限次数
+	Phases      []domain.GamePhase `json:"phases,omitempty"`  // 可以使用的阶段，为空表示任意阶段
+	Effect      *domain.ItemEffect `json:"effect"`
+}
+
+// StoreItem 按ID或名称查找商店物品
+func (c *Catalog) StoreItem(key string) (*StoreItem, bool) {
+	item, ok := c.store[key]
+	return item, ok
+}
+
+// AvailableTo 检查物品是否对该职能开放购买
+func (c *Catalog) AvailableTo(item *StoreItem, careerType string) bool {
+	if len(item.Careers) == 0 {
+		return true
+	}
+
+	career, ok := c.Career(careerType)
+	if !ok {
+		return false
+	}
+	return contains(item.Careers, career.ID)
+}
+
+// NewItem 构造放入物品栏的物品，效果为目录数据的副本
+func (c *Catalog) NewItem(key, source string) (*domain.InventoryItem, error) {
+	entry, ok := c.StoreItem(key)
+	if !ok {
+		return nil, domain.NewGameError(domain.ErrNotFound, "商店中没有该物品").
+			WithDetails("item_id", key)
+	}
+
+	item := &domain.InventoryItem{
+		ItemID:      entry.ID,
+		Name:        entry.Name,
+		Description: entry.Description,
+		Source:      source,
+		Price:       entry.Price,
+		Uses:        entry.Uses,
+		Phases:      append([]domain.GamePhase(nil), entry.Phases...),
+	}
+	if err := clone(entry.Effect, &item.Effect); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// NewInitialItem 构造职能的初始可申领物
+func (c *Catalog) NewInitialItem(careerType string) (*domain.InventoryItem, error) {
+	entry, ok := c.Career(careerType)
+	if !ok {
+		return nil, domain.NewGameError(domain.ErrInvalidARC, "无效的职能类型").
+			WithDetails("type", careerType)
+	}
+
+	return c.NewItem(entry.InitialClaimable["id"], domain.ItemSourceCareer)
+}
+
+// claimablePattern 剧本奖励的写法：名称（N次嘉奖）：描述
+var claimablePattern = regexp.MustCompile(`^(.+?)(?:[（(](\d+)次嘉奖[）)])?(?:[：:](.*))?$`)
+
+// NewRewardItem 根据剧本奖励文本构造物品
+// 商店中有同名物品时使用其效果，否则作为只有叙事效果的物品
+func (c *Catalog) NewRewardItem(claimable string) (*domain.InventoryItem, error) {
+	name, price, description := ParseClaimable(claimable)
+	if name == "" {
+		return nil, domain.NewGameError(domain.ErrInvalidInput, "奖励物品缺少名称").
+			WithDetails("claimable", claimable)
+	}
+
+	if _, ok := c.StoreItem(name); ok {
+		item, err := c.NewItem(name, domain.ItemSourceReward)
+		if err != nil {
+			return nil, err
+		}
+		if description != "" {
+			item.Description = description
+		}
+		return item, nil
+	}
+
+	return &domain.InventoryItem{
+		Name:        name,
+		Description: description,
+		Source:      domain.ItemSourceReward,
+		Price:       price,
+		Uses:        1,
+		Effect: &domain.ItemEffect{
+			Type: domain.ItemEffectNarrative,
+			Note: description,
+		},
+	}, nil
+}
+
+// ParseClaimable 解析剧本奖励文本中的名称、价值和描述
+func ParseClaimable(claimable string) (name string, price int, description string) {
+	match := claimablePattern.FindStringSubmatch(strings.TrimSpace(claimable))
+	if match == nil {
+		return "", 0, ""
+	}
+
+	name = strings.TrimSpace(match[1])
+	if match[2] != "" {
+		price, _ = strconv.Atoi(match[2])
+	}
+	description = strings.TrimSpace(match[3])
+
+	return name, price, description
+}
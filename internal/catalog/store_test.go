@@ -0,0 +1,85 @@
+package catalog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+)
+
+func TestCatalog_Store(t *testing.T) {
+	catalog := Default()
+
+	t.Run("按职能开放购买", func(t *testing.T) {
+		gun, ok := catalog.StoreItem("波纹枪")
+		require.True(t, ok)
+		assert.True(t, catalog.AvailableTo(gun, domain.CareerIntern))
+
+		kit, ok := catalog.StoreItem("pr-spin-kit")
+		require.True(t, ok)
+		assert.True(t, catalog.AvailableTo(kit, domain.CareerPublicRelations))
+		assert.True(t, catalog.AvailableTo(kit, "public-relations"))
+		assert.False(t, catalog.AvailableTo(kit, domain.CareerIntern))
+	})
+
+	t.Run("物品效果为副本", func(t *testing.T) {
+		first, err := catalog.NewItem("ripple-gun", domain.ItemSourcePurchase)
+		require.NoError(t, err)
+		second, err := catalog.NewItem("ripple-gun", domain.ItemSourcePurchase)
+		require.NoError(t, err)
+
+		assert.Equal(t, "波纹枪", first.Name)
+		assert.Equal(t, []domain.GamePhase{domain.PhaseEncounter}, first.Phases)
+		first.Effect.Amount = 99
+		assert.NotEqual(t, 99, second.Effect.Amount)
+
+		_, err = catalog.NewItem("不存在", domain.ItemSourcePurchase)
+		require.Error(t, err)
+		assert.Equal(t, domain.ErrNotFound, err.(*domain.GameError).Code)
+	})
+
+	t.Run("职能初始可申领物", func(t *testing.T) {
+		item, err := catalog.NewInitialItem(domain.CareerGravedigger)
+		require.NoError(t, err)
+		assert.Equal(t, "清理工具包", item.Name)
+		assert.Equal(t, domain.ItemSourceCareer, item.Source)
+		assert.Equal(t, domain.ItemEffectClearLooseEnds, item.Effect.Type)
+	})
+
+	t.Run("剧本奖励", func(t *testing.T) {
+		item, err := catalog.NewRewardItem("波纹枪（4次嘉奖）：剧本中的描述")
+		require.NoError(t, err)
+		assert.Equal(t, "ripple-gun", item.ItemID)
+		assert.Equal(t, domain.ItemSourceReward, item.Source)
+		assert.Equal(t, "剧本中的描述", item.Description)
+		assert.Equal(t, domain.ItemEffectReduceChaos, item.Effect.Type)
+
+		item, err = catalog.NewRewardItem("Serena的日记（2次嘉奖）：可用于抵抗心理影响")
+		require.NoError(t, err)
+		assert.Empty(t, item.ItemID)
+		assert.Equal(t, "Serena的日记", item.Name)
+		assert.Equal(t, 2, item.Price)
+		assert.Equal(t, domain.ItemEffectNarrative, item.Effect.Type)
+
+		item, err = catalog.NewRewardItem("防护服")
+		require.NoError(t, err)
+		assert.Equal(t, "hazard-suit", item.ItemID)
+	})
+}
+
+func TestParseClaimable(t *testing.T) {
+	name, price, description := ParseClaimable("同学会回执（3次嘉奖）：可以召唤一个'过去的自己'的幻影")
+	assert.Equal(t, "同学会回执", name)
+	assert.Equal(t, 3, price)
+	assert.Equal(t, "可以召唤一个'过去的自己'的幻影", description)
+
+	name, price, description = ParseClaimable("波纹枪")
+	assert.Equal(t, "波纹枪", name)
+	assert.Equal(t, 0, price)
+	assert.Empty(t, description)
+
+	name, _, description = ParseClaimable("录音带：里面是一段：奇怪的对话")
+	assert.Equal(t, "录音带", name)
+	assert.Equal(t, "里面是一段：奇怪的对话", description)
+}
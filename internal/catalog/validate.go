@@ -0,0 +1,265 @@
+package catalog
+
+import (
+	"github.com/trpg-solo-engine/backend/internal/domain"
+)
+
+// 规则书约束
+const (
+	abilitiesPerAnomaly    = 3  // 每个异常体的能力数量
+	relationshipCount      = 3  // 人际关系数量
+	relationshipConnection = 12 // 人际关系总连结点数
+	initialQATotal         = 9  // 职能初始资质保证总数
+)
+
+// allPhases 物品可以限定的游戏阶段
+var allPhases = []string{
+	string(domain.PhaseMorning),
+	string(domain.PhaseInvestigation),
+	string(domain.PhaseEncounter),
+	string(domain.PhaseAftermath),
+}
+
+// Validate 校验目录完整性
+// 每种domain类型都必须恰好出现一次，且数据满足规则书约束
+func (c *Catalog) Validate() error {
+	if err := c.validateAnomalies(); err != nil {
+		return err
+	}
+	if err := c.validateRealities(); err != nil {
+		return err
+	}
+	if err := c.validateCareers(); err != nil {
+		return err
+	}
+	return c.validateStore()
+}
+
+func (c *Catalog) validateAnomalies() error {
+	seen := make(map[string]bool)
+	for _, a := range c.Anomalies {
+		if a.ID == "" || !contains(domain.AllAnomalyTypes, a.Name) {
+			return invalid(AnomaliesFile, "未知的异常体", "name", a.Name)
+		}
+		if seen[a.Name] {
+			return invalid(AnomaliesFile, "异常体重复定义", "name", a.Name)
+		}
+		seen[a.Name] = true
+
+		if len(a.Abilities) != abilitiesPerAnomaly {
+			return invalid(AnomaliesFile, "异常体必须有3个能力", "anomaly", a.Name).
+				WithDetails("count", len(a.Abilities))
+		}
+
+		for _, ability := range a.Abilities {
+			if ability.ID == "" || ability.Name == "" {
+				return invalid(AnomaliesFile, "能力缺少ID或名称", "anomaly", a.Name)
+			}
+			if ability.AnomalyType != a.Name {
+				return invalid(AnomaliesFile, "能力所属异常体不匹配", "ability", ability.ID)
+			}
+			if ability.Roll == nil || !contains(domain.AllQualities, ability.Roll.Quality) {
+				return invalid(AnomaliesFile, "能力掷骰必须指定有效资质", "ability", ability.ID)
+			}
+		}
+	}
+
+	return missing(AnomaliesFile, domain.AllAnomalyTypes, seen)
+}
+
+func (c *Catalog) validateRealities() error {
+	seen := make(map[string]bool)
+	for _, r := range c.Realities {
+		if r.ID == "" || !contains(domain.AllRealityTypes, r.Name) {
+			return invalid(RealitiesFile, "未知的现实", "name", r.Name)
+		}
+		if seen[r.Name] {
+			return invalid(RealitiesFile, "现实重复定义", "name", r.Name)
+		}
+		seen[r.Name] = true
+
+		if r.Trigger == nil {
+			return invalid(RealitiesFile, "现实缺少触发器", "reality", r.Name)
+		}
+		if err := validateIgnoreEffect(r); err != nil {
+			return err
+		}
+		if r.OverloadRelief == nil || r.OverloadRelief.Condition == "" {
+			return invalid(RealitiesFile, "现实缺少过载解除条件", "reality", r.Name)
+		}
+		if r.DegradationTrack.Boxes < 1 {
+			return invalid(RealitiesFile, "退化轨道格数无效", "reality", r.Name).
+				WithDetails("boxes", r.DegradationTrack.Boxes)
+		}
+		if r.Relationships.Count != relationshipCount || r.Relationships.TotalConnection != relationshipConnection {
+			return invalid(RealitiesFile, "人际关系必须为3段、总计12点连结", "reality", r.Name)
+		}
+	}
+
+	return missing(RealitiesFile, domain.AllRealityTypes, seen)
+}
+
+func (c *Catalog) validateCareers() error {
+	seen := make(map[string]bool)
+	for _, cr := range c.Careers {
+		if cr.ID == "" || !contains(domain.AllCareerTypes, cr.Name) {
+			return invalid(CareersFile, "未知的职能", "name", cr.Name)
+		}
+		if seen[cr.Name] {
+			return invalid(CareersFile, "职能重复定义", "name", cr.Name)
+		}
+		seen[cr.Name] = true
+
+		total := 0
+		for quality, points := range cr.InitialQA.Distribution {
+			if !contains(domain.AllQualities, quality) || points < 0 {
+				return invalid(CareersFile, "无效的资质分配", "career", cr.Name).
+					WithDetails("quality", quality)
+			}
+			total += points
+		}
+		if total != initialQATotal || cr.InitialQA.Total != initialQATotal {
+			return invalid(CareersFile, "初始资质保证总数必须为9", "career", cr.Name).
+				WithDetails("total", total)
+		}
+
+		if cr.PrimeDirective == nil {
+			return invalid(CareersFile, "职能缺少首要指令", "career", cr.Name)
+		}
+	}
+
+	return missing(CareersFile, domain.AllCareerTypes, seen)
+}
+
+// validateStore 校验商店物品，职能的初始可申领物必须在商店中定义
+func (c *Catalog) validateStore() error {
+	ids := make(map[string]bool)
+	names := make(map[string]bool)
+	for _, item := range c.Store {
+		if item.ID == "" || item.Name == "" {
+			return invalid(StoreFile, "物品缺少ID或名称", "item", item.ID)
+		}
+		if ids[item.ID] || names[item.Name] {
+			return invalid(StoreFile, "物品重复定义", "item", item.ID)
+		}
+		ids[item.ID] = true
+		names[item.Name] = true
+
+		if item.Price < 0 || item.Uses < 0 {
+			return invalid(StoreFile, "物品价格和使用次数不能为负数", "item", item.ID)
+		}
+		for _, careerID := range item.Careers {
+			if !c.hasCareerID(careerID) {
+				return invalid(StoreFile, "物品限定了未知的职能", "item", item.ID).
+					WithDetails("career", careerID)
+			}
+		}
+		for _, phase := range item.Phases {
+			if !contains(allPhases, string(phase)) {
+				return invalid(StoreFile, "物品限定了未知的阶段", "item", item.ID).
+					WithDetails("phase", phase)
+			}
+		}
+		if err := validateItemEffect(item); err != nil {
+			return err
+		}
+	}
+
+	for _, cr := range c.Careers {
+		if id := cr.InitialClaimable["id"]; id != "" && !ids[id] {
+			return invalid(StoreFile, "职能的初始可申领物不在商店中", "career", cr.Name).
+				WithDetails("item", id)
+		}
+	}
+
+	return nil
+}
+
+// validateItemEffect 校验物品效果
+func validateItemEffect(item *StoreItem) error {
+	effect := item.Effect
+	if effect == nil || effect.Type == "" {
+		return invalid(StoreFile, "物品缺少效果", "item", item.ID)
+	}
+
+	switch effect.Type {
+	case domain.ItemEffectNarrative:
+		return nil
+	case domain.ItemEffectRestoreQA:
+		if !contains(domain.AllQualities, effect.Quality) {
+			return invalid(StoreFile, "恢复资质保证的效果必须指定有效资质", "item", item.ID)
+		}
+	case domain.ItemEffectReduceChaos, domain.ItemEffectRerollToken, domain.ItemEffectClearLooseEnds:
+	default:
+		return invalid(StoreFile, "未知的物品效果", "item", item.ID).
+			WithDetails("effect", effect.Type)
+	}
+
+	if effect.Amount < 1 {
+		return invalid(StoreFile, "物品效果数量必须为正数", "item", item.ID).
+			WithDetails("amount", effect.Amount)
+	}
+	return nil
+}
+
+// hasCareerID 检查职能ID是否存在
+func (c *Catalog) hasCareerID(id string) bool {
+	for _, cr := range c.Careers {
+		if cr.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// validateIgnoreEffect 校验忽视现实触发器的规则效果
+func validateIgnoreEffect(r *Reality) error {
+	effect := r.Trigger.IgnoreEffect
+	if effect == nil {
+		return invalid(RealitiesFile, "现实触发器缺少忽视效果", "reality", r.Name)
+	}
+	if effect.Amount < 1 {
+		return invalid(RealitiesFile, "忽视效果数量必须为正数", "reality", r.Name).
+			WithDetails("amount", effect.Amount)
+	}
+
+	switch effect.Type {
+	case domain.IgnoreEffectDegradation:
+	case domain.IgnoreEffectLoseConnection:
+		if effect.Target != domain.IgnoreTargetWeakest && effect.Target != domain.IgnoreTargetFirst {
+			return invalid(RealitiesFile, "无效的失去连结目标", "reality", r.Name).
+				WithDetails("target", effect.Target)
+		}
+	default:
+		return invalid(RealitiesFile, "无效的忽视效果类型", "reality", r.Name).
+			WithDetails("type", effect.Type)
+	}
+
+	return nil
+}
+
+// missing 检查所有domain类型都已定义
+func missing(file string, all []string, seen map[string]bool) error {
+	for _, t := range all {
+		if !seen[t] {
+			return invalid(file, "缺少类型定义", "type", t)
+		}
+	}
+	return nil
+}
+
+// invalid 创建配置校验错误
+func invalid(file, message, key string, value interface{}) *domain.GameError {
+	return domain.NewGameError(domain.ErrDataCorrupted, message).
+		WithDetails("file", file).
+		WithDetails(key, value)
+}
+
+func contains(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package domain
+
+import "time"
+
+// GoalResult 可选目标的完成情况
+type GoalResult struct {
+	GoalID      string `json:"goal_id"`
+	Description string `json:"description"`
+	Reward      int    `json:"reward"`
+	Completed   bool   `json:"completed"`
+}
+
+// DebriefReport 余波阶段的任务报告
+// 每个会话只结算一次，结算后保存在会话状态中
+type DebriefReport struct {
+	SessionID  string           `json:"session_id"`
+	AgentID    string           `json:"agent_id"`
+	ScenarioID string           `json:"scenario_id"`
+	Outcome    string           `json:"outcome"`
+	Narrative  string           `json:"narrative"` // 剧本中与结果对应的余波描述
+	Goals      []*GoalResult    `json:"goals"`     // 晨会选择的可选目标
+	Entries    []*LedgerEntry   `json:"entries"`   // 本次结算写入的账本条目
+	Claimables []*InventoryItem `json:"claimables"`
+
+	// 结算后的角色状态
+	Commendations int    `json:"commendations"`
+	Reprimands    int    `json:"reprimands"`
+	Rating        string `json:"rating"`
+	QARestored    bool   `json:"qa_restored"`
+	LooseEnds     int    `json:"loose_ends"` // 带入下次任务的散逸端
+
+	ResolvedAt time.Time `json:"resolved_at"`
+}
+
+// Clone 深拷贝任务报告
+func (r *DebriefReport) Clone() *DebriefReport {
+	copied := *r
+	copied.Goals = make([]*GoalResult, 0, len(r.Goals))
+	for _, goal := range r.Goals {
+		g := *goal
+		copied.Goals = append(copied.Goals, &g)
+	}
+	copied.Entries = make([]*LedgerEntry, 0, len(r.Entries))
+	for _, entry := range r.Entries {
+		e := *entry
+		copied.Entries = append(copied.Entries, &e)
+	}
+	copied.Claimables = make([]*InventoryItem, 0, len(r.Claimables))
+	for _, item := range r.Claimables {
+		i := *item
+		if item.Effect != nil {
+			effect := *item.Effect
+			i.Effect = &effect
+		}
+		i.Phases = append([]GamePhase(nil), item.Phases...)
+		copied.Claimables = append(copied.Claimables, &i)
+	}
+	return &copied
+}
@@ -4,21 +4,39 @@ import "time"
 
 // Agent 外勤特工
 type Agent struct {
-	ID            string          `json:"id"`
-	Name          string          `json:"name"`
-	Pronouns      string          `json:"pronouns"`
-	Anomaly       *Anomaly        `json:"anomaly"`
-	Reality       *Reality        `json:"reality"`
-	Career        *Career         `json:"career"`
-	QA            map[string]int  `json:"qa"`
-	Relationships []*Relationship `json:"relationships"`
-	Commendations int             `json:"commendations"`
-	Reprimands    int             `json:"reprimands"`
-	Rating        string          `json:"rating"`
-	Alive         bool            `json:"alive"`
-	InDebt        bool            `json:"in_debt"`
-	CreatedAt     time.Time       `json:"created_at"`
-	UpdatedAt     time.Time       `json:"updated_at"`
+	ID            string              `json:"id"`
+	Name          string              `json:"name"`
+	Pronouns      string              `json:"pronouns"`
+	Anomaly       *Anomaly            `json:"anomaly"`
+	Reality       *Reality            `json:"reality"`
+	Career        *Career             `json:"career"`
+	QA            map[string]int      `json:"qa"`
+	Relationships []*Relationship     `json:"relationships"`
+	Assessment    []*AssessmentAnswer `json:"assessment,omitempty"` // 职能评估问题的回答
+	Commendations int                 `json:"commendations"`
+	Reprimands    int                 `json:"reprimands"`
+	Rating        string              `json:"rating"`
+	Alive         bool                `json:"alive"`
+	InDebt        bool                `json:"in_debt"`
+
+	// 退化轨道填满后必须选择新现实，在此之前不能开始新任务
+	PendingRealityChange bool                `json:"pending_reality_change"`
+	DegradationHistory   []*DegradationEntry `json:"degradation_history,omitempty"`
+
+	// 嘉奖/申诫变化的绩效账本（只追加）
+	Ledger []*LedgerEntry `json:"ledger,omitempty"`
+
+	// 物品栏
+	Inventory []*InventoryItem `json:"inventory,omitempty"`
+
+	// 死亡记录，死亡后必须复活或由继任者接替
+	Deaths []*DeathRecord `json:"deaths,omitempty"`
+
+	// 上次任务留下的散逸端，下次任务开始时进入混沌池
+	LooseEnds int `json:"loose_ends"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 // Anomaly 异常体
@@ -94,12 +112,32 @@ type Reality struct {
 
 // RealityTrigger 现实触发器
 type RealityTrigger struct {
-	Name        string `json:"name"`
-	Cost        int    `json:"cost"`        // 混沌消耗
-	Effect      string `json:"effect"`      // 触发效果
-	Consequence string `json:"consequence"` // 忽视后果
+	Name         string               `json:"name"`
+	Cost         int                  `json:"cost"`                    // 混沌消耗
+	Effect       string               `json:"effect"`                  // 触发效果
+	Consequence  string               `json:"consequence"`             // 忽视后果
+	IgnoreEffect *TriggerIgnoreEffect `json:"ignore_effect,omitempty"` // 忽视后自动应用的规则效果
 }
 
+// TriggerIgnoreEffect 忽视现实触发器的规则效果
+type TriggerIgnoreEffect struct {
+	Type   string `json:"type"`             // 见 IgnoreEffect* 常量
+	Target string `json:"target,omitempty"` // 失去连结的人际关系（见 IgnoreTarget* 常量）
+	Amount int    `json:"amount"`
+}
+
+// 忽视现实触发器的效果类型
+const (
+	IgnoreEffectLoseConnection = "lose_connection" // 人际关系失去连结
+	IgnoreEffectDegradation    = "degradation"     // 标记退化格
+)
+
+// 失去连结的人际关系
+const (
+	IgnoreTargetWeakest = "weakest" // 连结最低的人际关系
+	IgnoreTargetFirst   = "first"   // 第一段人际关系（现实的第一个人际关系问题）
+)
+
 // OverloadRelief 过载解除
 type OverloadRelief struct {
 	Name      string `json:"name"`
@@ -137,6 +175,9 @@ type Relationship struct {
 	Connection  int      `json:"connection"`
 	PlayedBy    string   `json:"played_by"`
 	Notes       []string `json:"notes"`
+
+	// 连结变化记录（只追加）
+	History []*ConnectionChange `json:"history,omitempty"`
 }
 
 // DegradationTrack 退化轨道
@@ -301,14 +342,18 @@ func (a *Agent) RestoreQA() {
 	}
 }
 
-// AddCommendations 添加嘉奖
+// AddCommendations 添加嘉奖，记为未注明原因的账本条目
 func (a *Agent) AddCommendations(amount int) {
-	a.Commendations += amount
+	if amount != 0 {
+		a.RecordLedger(&LedgerEntry{Reason: LedgerReasonAdjustment, Commendations: amount})
+	}
 }
 
-// AddReprimands 添加申诫并更新评级
+// AddReprimands 添加申诫并更新评级，记为未注明原因的账本条目
 func (a *Agent) AddReprimands(amount int) {
-	a.Reprimands += amount
+	if amount != 0 {
+		a.RecordLedger(&LedgerEntry{Reason: LedgerReasonAdjustment, Reprimands: amount})
+	}
 	a.Rating = GetRating(a.Reprimands)
 }
 
@@ -345,8 +390,18 @@ func (a *Agent) TotalConnection() int {
 	return total
 }
 
-// ValidateARC 验证ARC组合的有效性
+// ValidateARC 验证ARC组合的有效性（创建角色时）
 func (a *Agent) ValidateARC() error {
+	return a.validate(true)
+}
+
+// ValidateInPlay 验证游戏过程中的角色
+// 连结会随现实触发器等事件增减，人际关系也可以增删，不再要求3段、总数为12
+func (a *Agent) ValidateInPlay() error {
+	return a.validate(false)
+}
+
+func (a *Agent) validate(checkConnection bool) error {
 	// 验证异常体类型
 	validAnomaly := false
 	for _, t := range AllAnomalyTypes {
@@ -392,17 +447,27 @@ func (a *Agent) ValidateARC() error {
 			WithDetails("count", len(a.Anomaly.Abilities))
 	}
 
-	// 验证人际关系数量
-	if len(a.Relationships) != 3 {
-		return NewGameError(ErrInvalidARC, "人际关系必须为3段").
-			WithDetails("count", len(a.Relationships))
+	// 人际关系数量和总连结点数只在创建时检查，游戏中可以增减
+	if checkConnection {
+		if len(a.Relationships) != 3 {
+			return NewGameError(ErrInvalidARC, "人际关系必须为3段").
+				WithDetails("count", len(a.Relationships))
+		}
+
+		totalConnection := a.TotalConnection()
+		if totalConnection != 12 {
+			return NewGameError(ErrInvalidARC, "总连结点数必须为12").
+				WithDetails("total", totalConnection)
+		}
 	}
 
-	// 验证总连结点数
-	totalConnection := a.TotalConnection()
-	if totalConnection != 12 {
-		return NewGameError(ErrInvalidARC, "总连结点数必须为12").
-			WithDetails("total", totalConnection)
+	// 单段人际关系的连结始终在0到9之间
+	for _, rel := range a.Relationships {
+		if rel.Connection < 0 || rel.Connection > MaxConnection {
+			return NewGameError(ErrInvalidARC, "连结必须在0到9之间").
+				WithDetails("relationship_id", rel.ID).
+				WithDetails("connection", rel.Connection)
+		}
 	}
 
 	// 验证总QA点数
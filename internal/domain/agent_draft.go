@@ -0,0 +1,117 @@
+package domain
+
+import "time"
+
+// DraftStep 角色创建向导步骤
+type DraftStep string
+
+const (
+	DraftStepAnomaly       DraftStep = "anomaly"       // 选择异常体
+	DraftStepCareer        DraftStep = "career"        // 选择职能并回答评估问题
+	DraftStepReality       DraftStep = "reality"       // 选择现实
+	DraftStepRelationships DraftStep = "relationships" // 分配人际关系连结
+	DraftStepFinalize      DraftStep = "finalize"      // 确认创建
+	DraftStepCompleted     DraftStep = "completed"     // 已生成角色
+)
+
+// DraftSteps 向导步骤顺序
+var DraftSteps = []DraftStep{
+	DraftStepAnomaly,
+	DraftStepCareer,
+	DraftStepReality,
+	DraftStepRelationships,
+	DraftStepFinalize,
+}
+
+// AgentDraft 角色草稿
+// Step 为下一个待完成的步骤，已完成的步骤可以重新提交
+type AgentDraft struct {
+	ID            string               `json:"id"`
+	Name          string               `json:"name"`
+	Pronouns      string               `json:"pronouns"`
+	Step          DraftStep            `json:"step"`
+	AnomalyType   string               `json:"anomaly_type,omitempty"`
+	CareerType    string               `json:"career_type,omitempty"`
+	Assessment    []*AssessmentAnswer  `json:"assessment,omitempty"`
+	RealityType   string               `json:"reality_type,omitempty"`
+	Relationships []*DraftRelationship `json:"relationships,omitempty"`
+	AgentID       string               `json:"agent_id,omitempty"` // 完成后生成的角色ID
+	CreatedAt     time.Time            `json:"created_at"`
+	UpdatedAt     time.Time            `json:"updated_at"`
+}
+
+// AssessmentAnswer 职能评估问题的回答
+type AssessmentAnswer struct {
+	Question string `json:"question"`
+	Answer   string `json:"answer"`
+}
+
+// DraftRelationship 按现实的人际关系问题填写的关系
+type DraftRelationship struct {
+	Question    string `json:"question"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Connection  int    `json:"connection"`
+}
+
+// StepIndex 返回步骤在向导中的位置，未知步骤返回-1
+func StepIndex(step DraftStep) int {
+	if step == DraftStepCompleted {
+		return len(DraftSteps)
+	}
+	for i, s := range DraftSteps {
+		if s == step {
+			return i
+		}
+	}
+	return -1
+}
+
+// CheckStep 检查草稿当前是否可以提交指定步骤
+// 已完成的草稿不可再修改；不能跳过尚未到达的步骤
+func (d *AgentDraft) CheckStep(step DraftStep) error {
+	if d.Step == DraftStepCompleted {
+		return NewGameError(ErrInvalidState, "草稿已完成，不能再修改").
+			WithDetails("draft_id", d.ID).
+			WithDetails("agent_id", d.AgentID)
+	}
+
+	if StepIndex(step) > StepIndex(d.Step) {
+		return NewGameError(ErrInvalidState, "请先完成前面的步骤").
+			WithDetails("step", step).
+			WithDetails("current_step", d.Step)
+	}
+
+	return nil
+}
+
+// Advance 完成指定步骤，推进到下一步（重新提交已完成的步骤不会回退进度）
+func (d *AgentDraft) Advance(step DraftStep) {
+	next := StepIndex(step) + 1
+	if next > StepIndex(d.Step) && next < len(DraftSteps) {
+		d.Step = DraftSteps[next]
+	}
+	d.UpdatedAt = time.Now()
+}
+
+// Rewind 将进度退回到指定步骤（用于前置选择变化导致后续数据失效）
+func (d *AgentDraft) Rewind(step DraftStep) {
+	if StepIndex(step) < StepIndex(d.Step) {
+		d.Step = step
+	}
+	d.UpdatedAt = time.Now()
+}
+
+// AgentRelationships 将草稿关系转换为角色人际关系，问题记录在备注中
+func (d *AgentDraft) AgentRelationships() []*Relationship {
+	relationships := make([]*Relationship, 0, len(d.Relationships))
+	for _, rel := range d.Relationships {
+		relationships = append(relationships, &Relationship{
+			Name:        rel.Name,
+			Description: rel.Description,
+			Connection:  rel.Connection,
+			Notes:       []string{rel.Question},
+		})
+	}
+	return relationships
+}
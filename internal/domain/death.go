@@ -0,0 +1,81 @@
+package domain
+
+import "time"
+
+// DeathRecord 角色的一次死亡记录
+type DeathRecord struct {
+	Cause       string     `json:"cause"`
+	SessionID   string     `json:"session_id,omitempty"` // 死亡发生的会话
+	LooseEnds   int        `json:"loose_ends"`           // 死亡留下的散逸端
+	DiedAt      time.Time  `json:"died_at"`
+	RevivedAt   *time.Time `json:"revived_at,omitempty"`
+	RevivalCost int        `json:"revival_cost,omitempty"` // 复活花费的嘉奖
+	SuccessorID string     `json:"successor_id,omitempty"` // 接替该角色的继任者
+}
+
+// LastDeath 返回最近一次死亡记录，从未死亡时返回nil
+func (a *Agent) LastDeath() *DeathRecord {
+	if len(a.Deaths) == 0 {
+		return nil
+	}
+	return a.Deaths[len(a.Deaths)-1]
+}
+
+// Die 记录角色死亡
+func (a *Agent) Die(cause, sessionID string, looseEnds int) (*DeathRecord, error) {
+	if !a.Alive {
+		return nil, NewGameError(ErrInvalidState, "角色已经死亡").
+			WithDetails("agent_id", a.ID)
+	}
+	if looseEnds < 0 {
+		return nil, NewGameError(ErrInvalidInput, "散逸端不能为负数").
+			WithDetails("loose_ends", looseEnds)
+	}
+
+	record := &DeathRecord{
+		Cause:     cause,
+		SessionID: sessionID,
+		LooseEnds: looseEnds,
+		DiedAt:    time.Now(),
+	}
+	a.Deaths = append(a.Deaths, record)
+	a.Alive = false
+
+	return record, nil
+}
+
+// Revive 花费嘉奖复活死亡的角色，已有继任者的角色不能复活
+func (a *Agent) Revive(cost int) (*LedgerEntry, error) {
+	if a.Alive {
+		return nil, NewGameError(ErrInvalidState, "角色没有死亡").
+			WithDetails("agent_id", a.ID)
+	}
+
+	death := a.LastDeath()
+	if death != nil && death.SuccessorID != "" {
+		return nil, NewGameError(ErrInvalidState, "角色已有继任者，不能复活").
+			WithDetails("successor_id", death.SuccessorID)
+	}
+
+	if a.Commendations < cost {
+		return nil, NewGameError(ErrInsufficientCommendations, "嘉奖不足，无法复活").
+			WithDetails("commendations", a.Commendations).
+			WithDetails("cost", cost)
+	}
+
+	entry := &LedgerEntry{
+		Reason:        LedgerReasonDeath,
+		Justification: "死亡后由机构复活",
+		Commendations: -cost,
+	}
+	if death != nil {
+		entry.SessionID = death.SessionID
+		now := time.Now()
+		death.RevivedAt = &now
+		death.RevivalCost = cost
+	}
+	a.RecordLedger(entry)
+	a.Alive = true
+
+	return entry, nil
+}
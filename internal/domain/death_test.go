@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newDeathTestAgent() *Agent {
+	return &Agent{
+		ID:            "agent-1",
+		Alive:         true,
+		Commendations: 7,
+		Reality:       &Reality{Type: RealityCaretaker},
+	}
+}
+
+func TestAgent_Die(t *testing.T) {
+	agent := newDeathTestAgent()
+
+	record, err := agent.Die("被异常体吞噬", "session-1", 2)
+	require.NoError(t, err)
+	assert.False(t, agent.Alive)
+	assert.Equal(t, "被异常体吞噬", record.Cause)
+	assert.Equal(t, "session-1", record.SessionID)
+	assert.Equal(t, 2, record.LooseEnds)
+	assert.Same(t, record, agent.LastDeath())
+
+	// 死亡的角色不能开始任务
+	err = agent.CheckCanStartSession()
+	require.Error(t, err)
+	assert.Equal(t, ErrInvalidState, err.(*GameError).Code)
+
+	// 不能重复死亡
+	_, err = agent.Die("再次死亡", "", 0)
+	require.Error(t, err)
+	assert.Equal(t, ErrInvalidState, err.(*GameError).Code)
+}
+
+func TestAgent_Revive(t *testing.T) {
+	t.Run("花费嘉奖复活并记入账本", func(t *testing.T) {
+		agent := newDeathTestAgent()
+		_, err := agent.Die("坠楼", "session-1", 0)
+		require.NoError(t, err)
+
+		entry, err := agent.Revive(5)
+		require.NoError(t, err)
+		assert.True(t, agent.Alive)
+		assert.Equal(t, 2, agent.Commendations)
+		assert.Equal(t, LedgerReasonDeath, entry.Reason)
+		assert.Equal(t, "session-1", entry.SessionID)
+		assert.Equal(t, -5, entry.Commendations)
+		require.NotNil(t, agent.LastDeath().RevivedAt)
+		assert.Equal(t, 5, agent.LastDeath().RevivalCost)
+		assert.NoError(t, agent.CheckCanStartSession())
+	})
+
+	t.Run("嘉奖不足时拒绝", func(t *testing.T) {
+		agent := newDeathTestAgent()
+		_, err := agent.Die("坠楼", "", 0)
+		require.NoError(t, err)
+
+		_, err = agent.Revive(10)
+		require.Error(t, err)
+		assert.Equal(t, ErrInsufficientCommendations, err.(*GameError).Code)
+		assert.False(t, agent.Alive)
+		assert.Empty(t, agent.Ledger)
+	})
+
+	t.Run("活着的角色和已有继任者的角色不能复活", func(t *testing.T) {
+		agent := newDeathTestAgent()
+		_, err := agent.Revive(0)
+		require.Error(t, err)
+		assert.Equal(t, ErrInvalidState, err.(*GameError).Code)
+
+		_, err = agent.Die("坠楼", "", 0)
+		require.NoError(t, err)
+		agent.LastDeath().SuccessorID = "agent-2"
+
+		_, err = agent.Revive(0)
+		require.Error(t, err)
+		assert.Equal(t, ErrInvalidState, err.(*GameError).Code)
+	})
+}
@@ -0,0 +1,150 @@
+package domain
+
+import "time"
+
+// DegradationEntryKind 退化历史条目类型
+type DegradationEntryKind string
+
+const (
+	DegradationMarked        DegradationEntryKind = "marked"         // 标记退化格
+	DegradationTrackFilled   DegradationEntryKind = "track_filled"   // 退化轨道填满
+	DegradationRealityChange DegradationEntryKind = "reality_change" // 选择了新现实
+)
+
+// DegradationEntry 退化历史条目（只追加）
+type DegradationEntry struct {
+	Kind        DegradationEntryKind `json:"kind"`
+	RealityType string               `json:"reality_type"`
+	Reason      string               `json:"reason,omitempty"`
+	SessionID   string               `json:"session_id,omitempty"`
+	Boxes       int                  `json:"boxes,omitempty"`
+	Filled      int                  `json:"filled"`
+	Total       int                  `json:"total"`
+	NewReality  string               `json:"new_reality,omitempty"`
+	CreatedAt   time.Time            `json:"created_at"`
+}
+
+// IsFull 检查退化轨道是否已填满
+func (t *DegradationTrack) IsFull() bool {
+	return t.Total > 0 && t.Filled >= t.Total
+}
+
+// MarkDegradation 标记退化格并记录原因
+// 轨道填满后角色进入必须选择新现实的状态，在此之前不能再标记
+func (a *Agent) MarkDegradation(boxes int, reason, sessionID string) (*DegradationEntry, error) {
+	if reason == "" {
+		return nil, NewGameError(ErrInvalidInput, "标记退化必须说明原因")
+	}
+	if boxes < 1 {
+		return nil, NewGameError(ErrInvalidInput, "标记的退化格数必须为正数").
+			WithDetails("boxes", boxes)
+	}
+	if a.Reality == nil || a.Reality.DegradationTrack == nil {
+		return nil, NewGameError(ErrInvalidState, "角色没有退化轨道")
+	}
+	if a.PendingRealityChange {
+		return nil, NewGameError(ErrInvalidState, "退化轨道已满，必须先选择新现实").
+			WithDetails("reality_type", a.Reality.Type)
+	}
+
+	track := a.Reality.DegradationTrack
+	track.Filled += boxes
+	if track.Filled > track.Total {
+		track.Filled = track.Total
+	}
+
+	now := time.Now()
+	entry := &DegradationEntry{
+		Kind:        DegradationMarked,
+		RealityType: a.Reality.Type,
+		Reason:      reason,
+		SessionID:   sessionID,
+		Boxes:       boxes,
+		Filled:      track.Filled,
+		Total:       track.Total,
+		CreatedAt:   now,
+	}
+	a.DegradationHistory = append(a.DegradationHistory, entry)
+
+	if track.IsFull() {
+		a.PendingRealityChange = true
+		a.DegradationHistory = append(a.DegradationHistory, &DegradationEntry{
+			Kind:        DegradationTrackFilled,
+			RealityType: a.Reality.Type,
+			SessionID:   sessionID,
+			Filled:      track.Filled,
+			Total:       track.Total,
+			CreatedAt:   now,
+		})
+	}
+
+	return entry, nil
+}
+
+// ChangeReality 更换现实，新现实的退化轨道从空白开始
+// 只有退化轨道填满（或尚未开始任务）时才能更换，且必须选择与当前不同的现实，完成后解除待选状态
+func (a *Agent) ChangeReality(reality *Reality) error {
+	previous := ""
+	if a.Reality != nil {
+		previous = a.Reality.Type
+	}
+
+	if reality.Type == previous {
+		if a.PendingRealityChange {
+			return NewGameError(ErrInvalidInput, "退化轨道已满，必须选择新的现实").
+				WithDetails("reality_type", previous)
+		}
+		return NewGameError(ErrInvalidInput, "角色已经是该现实").
+			WithDetails("reality_type", previous)
+	}
+
+	if previous != "" && !a.PendingRealityChange && a.hasFieldRecord() {
+		return NewGameError(ErrInvalidState, "只有退化轨道填满后才能更换现实").
+			WithDetails("reality_type", previous)
+	}
+
+	if previous != "" && previous != reality.Type {
+		entry := &DegradationEntry{
+			Kind:        DegradationRealityChange,
+			RealityType: previous,
+			NewReality:  reality.Type,
+			CreatedAt:   time.Now(),
+		}
+		if a.Reality.DegradationTrack != nil {
+			entry.Filled = a.Reality.DegradationTrack.Filled
+			entry.Total = a.Reality.DegradationTrack.Total
+		}
+		a.DegradationHistory = append(a.DegradationHistory, entry)
+	}
+
+	a.Reality = reality
+	a.PendingRealityChange = false
+	return nil
+}
+
+// hasFieldRecord 角色是否已经开始过任务：有退化或死亡记录，或者账本关联过会话
+func (a *Agent) hasFieldRecord() bool {
+	if len(a.DegradationHistory) > 0 || len(a.Deaths) > 0 {
+		return true
+	}
+	for _, entry := range a.Ledger {
+		if entry.SessionID != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckCanStartSession 检查角色是否可以开始新的任务
+func (a *Agent) CheckCanStartSession() error {
+	if !a.Alive {
+		return NewGameError(ErrInvalidState, "角色已死亡，必须先复活才能开始任务").
+			WithDetails("agent_id", a.ID)
+	}
+	if a.PendingRealityChange {
+		return NewGameError(ErrInvalidState, "退化轨道已满，必须先选择新现实才能开始任务").
+			WithDetails("agent_id", a.ID).
+			WithDetails("reality_type", a.Reality.Type)
+	}
+	return nil
+}
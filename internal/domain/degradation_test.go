@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newDegradationTestAgent() *Agent {
+	return &Agent{
+		ID:    "agent-1",
+		Alive: true,
+		Reality: &Reality{
+			Type:             RealityCaretaker,
+			DegradationTrack: &DegradationTrack{Name: "独立", Filled: 0, Total: 4},
+		},
+	}
+}
+
+func TestAgent_MarkDegradation(t *testing.T) {
+	agent := newDegradationTestAgent()
+
+	_, err := agent.MarkDegradation(1, "", "")
+	require.Error(t, err)
+	assert.Equal(t, ErrInvalidInput, err.(*GameError).Code)
+
+	entry, err := agent.MarkDegradation(3, "让孩子自己去上学", "session-1")
+	require.NoError(t, err)
+	assert.Equal(t, DegradationMarked, entry.Kind)
+	assert.Equal(t, 3, entry.Filled)
+	assert.False(t, agent.PendingRealityChange)
+	assert.NoError(t, agent.CheckCanStartSession())
+
+	// 超出格数时停在满格
+	entry, err = agent.MarkDegradation(2, "把孩子交给邻居照看", "session-1")
+	require.NoError(t, err)
+	assert.Equal(t, 4, entry.Filled)
+	assert.True(t, agent.PendingRealityChange)
+	require.Len(t, agent.DegradationHistory, 3)
+	assert.Equal(t, DegradationTrackFilled, agent.DegradationHistory[2].Kind)
+
+	err = agent.CheckCanStartSession()
+	require.Error(t, err)
+	assert.Equal(t, ErrInvalidState, err.(*GameError).Code)
+
+	_, err = agent.MarkDegradation(1, "再次失职", "")
+	require.Error(t, err)
+	assert.Equal(t, ErrInvalidState, err.(*GameError).Code)
+}
+
+func TestAgent_ChangeReality(t *testing.T) {
+	agent := newDegradationTestAgent()
+	_, err := agent.MarkDegradation(4, "受照料者独立了", "")
+	require.NoError(t, err)
+
+	// 必须选择不同的现实
+	err = agent.ChangeReality(&Reality{Type: RealityCaretaker, DegradationTrack: &DegradationTrack{Total: 4}})
+	require.Error(t, err)
+	assert.True(t, agent.PendingRealityChange)
+
+	err = agent.ChangeReality(&Reality{Type: RealityStar, DegradationTrack: &DegradationTrack{Name: "过气", Total: 4}})
+	require.NoError(t, err)
+	assert.False(t, agent.PendingRealityChange)
+	assert.Equal(t, 0, agent.Reality.DegradationTrack.Filled)
+	assert.NoError(t, agent.CheckCanStartSession())
+
+	last := agent.DegradationHistory[len(agent.DegradationHistory)-1]
+	assert.Equal(t, DegradationRealityChange, last.Kind)
+	assert.Equal(t, RealityCaretaker, last.RealityType)
+	assert.Equal(t, RealityStar, last.NewReality)
+	assert.Equal(t, 4, last.Filled)
+}
+
+func TestAgent_ChangeReality_RequiresFullTrack(t *testing.T) {
+	agent := newDegradationTestAgent()
+
+	// 尚未开始任务时可以更换现实
+	require.NoError(t, agent.ChangeReality(&Reality{Type: RealityStar, DegradationTrack: &DegradationTrack{Total: 4}}))
+	assert.Equal(t, RealityStar, agent.Reality.Type)
+
+	_, err := agent.MarkDegradation(2, "错过了首映礼", "session-1")
+	require.NoError(t, err)
+
+	// 选择相同的现实不会重置退化轨道
+	err = agent.ChangeReality(&Reality{Type: RealityStar, DegradationTrack: &DegradationTrack{Total: 4}})
+	require.Error(t, err)
+	assert.Equal(t, ErrInvalidInput, err.(*GameError).Code)
+	assert.Equal(t, 2, agent.Reality.DegradationTrack.Filled)
+
+	// 轨道未满时不能更换现实
+	err = agent.ChangeReality(&Reality{Type: RealityCaretaker, DegradationTrack: &DegradationTrack{Total: 4}})
+	require.Error(t, err)
+	assert.Equal(t, ErrInvalidState, err.(*GameError).Code)
+	assert.Equal(t, RealityStar, agent.Reality.Type)
+	assert.Equal(t, 2, agent.Reality.DegradationTrack.Filled)
+}
@@ -1,22 +1,21 @@
 package domain
 
 import (
+	"math"
 	"math/rand"
-	"time"
 )
 
-func init() {
-	rand.Seed(time.Now().UnixNano())
-}
-
 // RollResult 掷骰结果
 type RollResult struct {
+	RawDice   []int `json:"raw_dice"`   // 原始骰子（调整前）
 	Dice      []int `json:"dice"`       // 骰子结果
-	Threes    int   `json:"threes"`     // "3"的数量
+	Threes    int   `json:"threes"`     // "3"（成功值）的数量
 	Success   bool  `json:"success"`    // 是否成功
 	Chaos     int   `json:"chaos"`      // 产生的混沌
 	Overload  int   `json:"overload"`   // 过载点数
 	TripleAsc bool  `json:"triple_asc"` // 三重升华
+
+	OverloadRelieved int `json:"overload_relieved,omitempty"` // 被过载解除抵消的过载点数
 }
 
 // DiceService 骰子服务接口
@@ -27,34 +26,151 @@ type DiceService interface {
 	ApplyQA(roll *RollResult, quality string, amount int) *RollResult
 	ApplyOverload(roll *RollResult, amount int) *RollResult
 	CheckTripleAscension(roll *RollResult) bool
+
+	// ForSession 返回从会话随机流中取数、使用会话规则集的骰子服务（session为nil时返回自身）
+	ForSession(session *GameSession) DiceService
+
+	// 规则集
+	Ruleset() *Ruleset
+	WithRuleset(rules *Ruleset) DiceService
+}
+
+// RandomSource 骰子随机数来源
+type RandomSource interface {
+	Intn(n int) int
+}
+
+// globalSource 进程级随机源（不可复现）
+type globalSource struct{}
+
+func (globalSource) Intn(n int) int {
+	return rand.Intn(n)
+}
+
+// DiceStream 可复现的骰子随机流
+// 第N次取数只由种子和位置N决定，因此可以从任意位置重放
+type DiceStream struct {
+	Seed     int64
+	Position int64
+}
+
+// NewDiceStream 创建骰子随机流
+func NewDiceStream(seed, position int64) *DiceStream {
+	return &DiceStream{Seed: seed, Position: position}
+}
+
+// Intn 返回[0,n)内的随机数并推进流位置
+func (s *DiceStream) Intn(n int) int {
+	if n <= 0 {
+		panic("invalid argument to Intn")
+	}
+
+	// 拒绝采样，避免取模偏差
+	bound := uint64(n)
+	limit := math.MaxUint64 - math.MaxUint64%bound
+	for {
+		v := splitmix64(uint64(s.Seed) + uint64(s.Position+1)*0x9e3779b97f4a7c15)
+		s.Position++
+		if v < limit {
+			return int(v % bound)
+		}
+	}
+}
+
+// splitmix64 64位混合函数
+func splitmix64(x uint64) uint64 {
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
+
+// sessionSource 绑定到游戏会话的随机源，每次取数都会推进会话的骰子游标
+type sessionSource struct {
+	session *GameSession
+}
+
+func (s *sessionSource) Intn(n int) int {
+	stream := NewDiceStream(s.session.DiceSeed, s.session.DiceCursor)
+	v := stream.Intn(n)
+	s.session.DiceCursor = stream.Position
+	return v
+}
+
+// NewDiceSeed 生成新的骰子种子
+func NewDiceSeed() int64 {
+	return rand.Int63()
 }
 
 // diceService 骰子服务实现
-type diceService struct{}
+type diceService struct {
+	rng     RandomSource
+	rules   *Ruleset
+	session *GameSession // 绑定的会话，用于判定过载解除
+}
 
-// NewDiceService 创建骰子服务
+// NewDiceService 创建使用默认规则集的骰子服务
 func NewDiceService() DiceService {
-	return &diceService{}
+	return &diceService{rng: globalSource{}, rules: DefaultRuleset()}
+}
+
+// NewDiceServiceWithRuleset 创建使用指定规则集的骰子服务
+func NewDiceServiceWithRuleset(rules *Ruleset) DiceService {
+	if rules == nil {
+		rules = DefaultRuleset()
+	}
+	return &diceService{rng: globalSource{}, rules: rules}
+}
+
+// NewSeededDiceService 创建使用固定种子的骰子服务（结果可复现）
+func NewSeededDiceService(seed int64) DiceService {
+	return &diceService{rng: NewDiceStream(seed, 0), rules: DefaultRuleset()}
+}
+
+// ForSession 返回绑定到会话随机流的骰子服务
+// 会话带有规则集时（来自剧本覆盖）优先使用会话规则集
+func (s *diceService) ForSession(session *GameSession) DiceService {
+	if session == nil {
+		return s
+	}
+
+	rules := s.rules
+	if session.Rules != nil {
+		rules = session.Rules
+	}
+	return &diceService{rng: &sessionSource{session: session}, rules: rules, session: session}
+}
+
+// Ruleset 返回当前规则集
+func (s *diceService) Ruleset() *Ruleset {
+	return s.rules
+}
+
+// WithRuleset 返回使用指定规则集、共享随机源的骰子服务
+func (s *diceService) WithRuleset(rules *Ruleset) DiceService {
+	if rules == nil {
+		return s
+	}
+	return &diceService{rng: s.rng, rules: rules, session: s.session}
 }
 
-// Roll 基础掷骰（6d4）
+// Roll 基础掷骰（默认6d4）
 func (s *diceService) Roll(count int) *RollResult {
 	if count <= 0 {
-		count = 6
+		count = s.rules.DiceCount
 	}
 
 	dice := make([]int, count)
 	threes := 0
 
 	for i := 0; i < count; i++ {
-		dice[i] = rand.Intn(4) + 1 // 1-4
-		if dice[i] == 3 {
+		dice[i] = s.rng.Intn(s.rules.DiceSides) + 1 // 1-面数
+		if s.rules.IsSuccess(dice[i]) {
 			threes++
 		}
 	}
 
 	// 检查三重升华（调整前恰好3个"3"）
-	tripleAsc := (threes == 3)
+	tripleAsc := s.rules.IsTripleAscension(threes)
 
 	// 判定成功/失败
 	success := threes > 0
@@ -71,6 +187,7 @@ func (s *diceService) Roll(count int) *RollResult {
 	}
 
 	return &RollResult{
+		RawDice:   append([]int(nil), dice...),
 		Dice:      dice,
 		Threes:    threes,
 		Success:   success,
@@ -83,7 +200,7 @@ func (s *diceService) Roll(count int) *RollResult {
 // RollForAbility 为异常能力掷骰
 func (s *diceService) RollForAbility(agent *Agent, ability *AnomalyAbility) *RollResult {
 	if ability.Roll == nil {
-		return s.Roll(6)
+		return s.Roll(s.rules.DiceCount)
 	}
 
 	roll := s.Roll(ability.Roll.DiceCount)
@@ -91,7 +208,7 @@ func (s *diceService) RollForAbility(agent *Agent, ability *AnomalyAbility) *Rol
 	// 检查是否需要应用过载
 	quality := ability.Roll.Quality
 	if agent.QA[quality] == 0 {
-		roll = s.ApplyOverload(roll, 1)
+		roll = s.overload(agent, roll)
 	}
 
 	return roll
@@ -99,23 +216,34 @@ func (s *diceService) RollForAbility(agent *Agent, ability *AnomalyAbility) *Rol
 
 // RollForQuality 为特定资质掷骰
 func (s *diceService) RollForQuality(agent *Agent, quality string) *RollResult {
-	roll := s.Roll(6)
+	roll := s.Roll(s.rules.DiceCount)
 
 	// 检查是否需要应用过载
 	if agent.QA[quality] == 0 {
-		roll = s.ApplyOverload(roll, 1)
+		roll = s.overload(agent, roll)
 	}
 
 	return roll
 }
 
+// overload 应用1点过载，会话中有生效的过载解除时跳过
+// 跳过时骰子保持过载前的结果，并记录被抵消的过载点数
+func (s *diceService) overload(agent *Agent, roll *RollResult) *RollResult {
+	if s.session != nil && s.session.ConsumeOverloadRelief(agent) {
+		roll.OverloadRelieved++
+		return roll
+	}
+
+	return s.ApplyOverload(roll, 1)
+}
+
 // ApplyQA 应用资质保证调整
 func (s *diceService) ApplyQA(roll *RollResult, quality string, amount int) *RollResult {
 	// QA可以将任意骰子调整为"3"或从"3"调整为其他数字
 	// 这里简化实现：假设总是将骰子调整为"3"
 	for i := 0; i < amount && i < len(roll.Dice); i++ {
-		if roll.Dice[i] != 3 {
-			roll.Dice[i] = 3
+		if !s.rules.IsSuccess(roll.Dice[i]) {
+			roll.Dice[i] = s.rules.SuccessValue
 			roll.Threes++
 		}
 	}
@@ -139,8 +267,8 @@ func (s *diceService) ApplyOverload(roll *RollResult, amount int) *RollResult {
 	for i := 0; i < amount && roll.Threes > 0; i++ {
 		// 找到第一个"3"并改为其他数字
 		for j := 0; j < len(roll.Dice); j++ {
-			if roll.Dice[j] == 3 {
-				roll.Dice[j] = 1 // 改为1
+			if s.rules.IsSuccess(roll.Dice[j]) {
+				roll.Dice[j] = s.rules.FailValue()
 				roll.Threes--
 				roll.Chaos++
 				break
@@ -154,12 +282,15 @@ func (s *diceService) ApplyOverload(roll *RollResult, amount int) *RollResult {
 	return roll
 }
 
-// CheckTripleAscension 检查三重升华
+// CheckTripleAscension 检查三重升华（按规则集判定调整前的原始骰子）
 func (s *diceService) CheckTripleAscension(roll *RollResult) bool {
-	return roll.TripleAsc
+	if len(roll.RawDice) == 0 {
+		return roll.TripleAsc
+	}
+	return s.rules.IsTripleAscension(s.rules.CountSuccesses(roll.RawDice))
 }
 
-// CountThrees 统计"3"的数量
+// CountThrees 统计"3"的数量（默认规则集）
 func CountThrees(dice []int) int {
 	count := 0
 	for _, d := range dice {
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/gen"
 	"github.com/leanovate/gopter/prop"
 )
 
@@ -93,3 +94,108 @@ func TestProperty_TripleAscensionZeroChaos(t *testing.T) {
 
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
 }
+
+// Feature: trpg-solo-engine, Property 2a: 种子掷骰可复现
+// 相同种子和位置的骰子流必须产生完全相同的掷骰序列
+func TestProperty_SeededRollReplay(t *testing.T) {
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("相同种子产生相同掷骰", prop.ForAll(
+		func(seed int64, count int) bool {
+			first := NewSeededDiceService(seed)
+			second := NewSeededDiceService(seed)
+
+			for i := 0; i < 10; i++ {
+				a := first.Roll(count)
+				b := second.Roll(count)
+				if len(a.Dice) != len(b.Dice) {
+					return false
+				}
+				for j := range a.Dice {
+					if a.Dice[j] != b.Dice[j] || a.Dice[j] < 1 || a.Dice[j] > 4 {
+						return false
+					}
+				}
+			}
+			return true
+		},
+		gen.Int64(),
+		gen.IntRange(1, 12),
+	))
+
+	properties.Property("会话游标可从任意位置继续重放", prop.ForAll(
+		func(seed int64, skip int) bool {
+			session := &GameSession{DiceSeed: seed}
+			dice := NewDiceService().ForSession(session)
+			for i := 0; i < skip; i++ {
+				dice.Roll(6)
+			}
+
+			resumed := &GameSession{DiceSeed: seed, DiceCursor: session.DiceCursor}
+			expected := dice.Roll(6)
+			actual := NewDiceService().ForSession(resumed).Roll(6)
+
+			for i := range expected.Dice {
+				if expected.Dice[i] != actual.Dice[i] {
+					return false
+				}
+			}
+			return session.DiceCursor == resumed.DiceCursor
+		},
+		gen.Int64(),
+		gen.IntRange(0, 20),
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+// Feature: trpg-solo-engine, Property 2b: 自定义规则集掷骰一致性
+// 骰子范围、成功判定和三重升华都应由规则集决定
+func TestProperty_RulesetRollConsistency(t *testing.T) {
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("自定义规则集掷骰一致性", prop.ForAll(
+		func(seed int64, count, sides, successOffset int) bool {
+			rules := &Ruleset{DiceRules: DiceRules{
+				DiceCount:            count,
+				DiceSides:            sides,
+				SuccessValue:         successOffset%sides + 1,
+				TripleAscensionCount: 3,
+			}}
+			dice := NewSeededDiceService(seed).WithRuleset(rules)
+			roll := dice.Roll(0)
+
+			if len(roll.Dice) != count {
+				return false
+			}
+			for _, d := range roll.Dice {
+				if !rules.IsValidDieValue(d) {
+					return false
+				}
+			}
+
+			successes := rules.CountSuccesses(roll.Dice)
+			if roll.Threes != successes || roll.Success != (successes > 0) {
+				return false
+			}
+			if roll.TripleAsc != (successes == 3) || dice.CheckTripleAscension(roll) != roll.TripleAsc {
+				return false
+			}
+
+			// 过载只会把成功骰改为非成功值
+			overloaded := dice.ApplyOverload(roll, 1)
+			for _, d := range overloaded.Dice {
+				if !rules.IsValidDieValue(d) {
+					return false
+				}
+			}
+			return rules.CountSuccesses(overloaded.Dice) == overloaded.Threes
+		},
+		gen.Int64(),
+		gen.IntRange(1, 10),
+		gen.IntRange(2, 12),
+		gen.IntRange(0, 11),
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
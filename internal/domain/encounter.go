@@ -0,0 +1,84 @@
+package domain
+
+import "time"
+
+// EncounterChoice 遭遇阶段中可以采取的行动
+type EncounterChoice struct {
+	ID        string `json:"id"` // 阶段ID加序号，如 initial-contact-1
+	PhaseID   string `json:"phase_id"`
+	Action    string `json:"action"`
+	Quality   string `json:"quality"`             // 判定使用的资质
+	Outcome   string `json:"outcome,omitempty"`   // 最终阶段判定成功时的任务结果
+	Reprimand bool   `json:"reprimand,omitempty"` // 采取该行动会受到申诫
+}
+
+// EncounterTurn 一个玩家回合，以及回合结束后异常体的行动
+type EncounterTurn struct {
+	Turn           int          `json:"turn"`
+	PhaseID        string       `json:"phase_id"`
+	ChoiceID       string       `json:"choice_id"`
+	Action         string       `json:"action"`
+	Quality        string       `json:"quality"`
+	Roll           *RollResult  `json:"roll"`
+	Success        bool         `json:"success"`
+	ChaosGenerated int          `json:"chaos_generated"`
+	AnomalyEffect  *ChaosEffect `json:"anomaly_effect,omitempty"` // 异常体在玩家回合之间使用的混沌效应
+	Outcome        string       `json:"outcome,omitempty"`        // 本回合结束遭遇时的任务结果
+	CreatedAt      time.Time    `json:"created_at"`
+}
+
+// EncounterState 遭遇进度
+// 玩家在当前阶段判定成功后进入下一阶段，最终阶段判定成功时以所选行动的结果结束遭遇
+type EncounterState struct {
+	EncounterID string           `json:"encounter_id"`
+	PhaseIndex  int              `json:"phase_index"` // 当前阶段在剧本遭遇中的下标
+	Turns       []*EncounterTurn `json:"turns"`
+	Outcome     string           `json:"outcome,omitempty"`
+	StartedAt   time.Time        `json:"started_at"`
+	EndedAt     *time.Time       `json:"ended_at,omitempty"`
+}
+
+// Ended 遭遇是否已经结束
+func (e *EncounterState) Ended() bool {
+	return e.Outcome != ""
+}
+
+// CurrentPhase 返回当前阶段，遭遇结束或阶段越界时返回nil
+func (e *EncounterState) CurrentPhase(encounter *Encounter) *Phase {
+	if e.Ended() || encounter == nil || e.PhaseIndex >= len(encounter.Phases) {
+		return nil
+	}
+	return encounter.Phases[e.PhaseIndex]
+}
+
+// End 以指定结果结束遭遇
+func (e *EncounterState) End(outcome string) {
+	now := time.Now()
+	e.Outcome = outcome
+	e.EndedAt = &now
+}
+
+// Clone 深拷贝遭遇进度
+func (e *EncounterState) Clone() *EncounterState {
+	copied := *e
+	copied.Turns = make([]*EncounterTurn, 0, len(e.Turns))
+	for _, turn := range e.Turns {
+		t := *turn
+		if turn.Roll != nil {
+			roll := *turn.Roll
+			roll.RawDice = append([]int(nil), turn.Roll.RawDice...)
+			roll.Dice = append([]int(nil), turn.Roll.Dice...)
+			t.Roll = &roll
+		}
+		if turn.AnomalyEffect != nil {
+			effect := *turn.AnomalyEffect
+			t.AnomalyEffect = &effect
+		}
+		copied.Turns = append(copied.Turns, &t)
+	}
+	if e.EndedAt != nil {
+		endedAt := *e.EndedAt
+		copied.EndedAt = &endedAt
+	}
+	return &copied
+}
@@ -12,8 +12,10 @@ const (
 	ErrInvalidAction ErrorCode = "INVALID_ACTION"
 
 	// 资源错误
-	ErrInsufficientQA    ErrorCode = "INSUFFICIENT_QA"
-	ErrInsufficientChaos ErrorCode = "INSUFFICIENT_CHAOS"
+	ErrInsufficientQA            ErrorCode = "INSUFFICIENT_QA"
+	ErrInsufficientChaos         ErrorCode = "INSUFFICIENT_CHAOS"
+	ErrInsufficientCommendations ErrorCode = "INSUFFICIENT_COMMENDATIONS"
+	ErrInsufficientConnection    ErrorCode = "INSUFFICIENT_CONNECTION"
 
 	// 状态错误
 	ErrInvalidPhase ErrorCode = "INVALID_PHASE"
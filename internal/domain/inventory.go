@@ -0,0 +1,93 @@
+package domain
+
+import "time"
+
+// 物品效果类型，会话中使用物品时由商店服务的效果钩子处理
+const (
+	ItemEffectReduceChaos    = "reduce_chaos"     // 从混沌池移除混沌
+	ItemEffectRestoreQA      = "restore_qa"       // 恢复指定资质的资质保证
+	ItemEffectRerollToken    = "reroll_token"     // 获得免费重掷令牌
+	ItemEffectClearLooseEnds = "clear_loose_ends" // 消除散逸端
+	ItemEffectNarrative      = "narrative"        // 仅有叙事效果，由主持人裁定
+)
+
+// 物品来源
+const (
+	ItemSourcePurchase = "purchase" // 在机构商店购买
+	ItemSourceCareer   = "career"   // 职能初始可申领物
+	ItemSourceReward   = "reward"   // 任务奖励
+)
+
+// ItemEffect 物品效果
+type ItemEffect struct {
+	Type    string `json:"type"`
+	Amount  int    `json:"amount,omitempty"`
+	Quality string `json:"quality,omitempty"` // restore_qa 恢复的资质
+	Note    string `json:"note,omitempty"`    // 效果的叙事描述
+}
+
+// InventoryItem 角色持有的物品
+type InventoryItem struct {
+	ID          string      `json:"id"`      // 物品实例ID
+	ItemID      string      `json:"item_id"` // 商店目录中的物品ID，目录外的奖励物品为空
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Source      string      `json:"source"`
+	Price       int         `json:"price"` // 购买价格或奖励标注的价值（嘉奖）
+	Uses        int         `json:"uses"`  // 剩余使用次数，0表示不限次数
+	Effect      *ItemEffect `json:"effect,omitempty"`
+	Phases      []GamePhase `json:"phases,omitempty"`     // 可以使用的阶段，为空表示任意阶段
+	SessionID   string      `json:"session_id,omitempty"` // 奖励物品来自的会话
+	AcquiredAt  time.Time   `json:"acquired_at"`
+}
+
+// UsableIn 检查物品能否在指定阶段使用
+func (i *InventoryItem) UsableIn(phase GamePhase) bool {
+	if len(i.Phases) == 0 {
+		return true
+	}
+	for _, p := range i.Phases {
+		if p == phase {
+			return true
+		}
+	}
+	return false
+}
+
+// AddItem 将物品放入角色的物品栏
+func (a *Agent) AddItem(item *InventoryItem) {
+	if item.AcquiredAt.IsZero() {
+		item.AcquiredAt = time.Now()
+	}
+	a.Inventory = append(a.Inventory, item)
+}
+
+// FindItem 按实例ID查找物品栏中的物品
+func (a *Agent) FindItem(id string) *InventoryItem {
+	for _, item := range a.Inventory {
+		if item.ID == id {
+			return item
+		}
+	}
+	return nil
+}
+
+// ConsumeItem 消耗一次物品，次数用尽时从物品栏移除
+func (a *Agent) ConsumeItem(id string) error {
+	for i, item := range a.Inventory {
+		if item.ID != id {
+			continue
+		}
+		if item.Uses == 0 {
+			return nil
+		}
+		item.Uses--
+		if item.Uses == 0 {
+			a.Inventory = append(a.Inventory[:i], a.Inventory[i+1:]...)
+		}
+		return nil
+	}
+
+	return NewGameError(ErrNotFound, "物品不在物品栏中").
+		WithDetails("item_id", id)
+}
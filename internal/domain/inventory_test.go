@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInventoryItem_UsableIn(t *testing.T) {
+	anyPhase := &InventoryItem{}
+	assert.True(t, anyPhase.UsableIn(PhaseMorning))
+	assert.True(t, anyPhase.UsableIn(PhaseEncounter))
+
+	encounterOnly := &InventoryItem{Phases: []GamePhase{PhaseEncounter}}
+	assert.True(t, encounterOnly.UsableIn(PhaseEncounter))
+	assert.False(t, encounterOnly.UsableIn(PhaseInvestigation))
+}
+
+func TestAgent_ConsumeItem(t *testing.T) {
+	agent := &Agent{ID: "agent-1"}
+	agent.AddItem(&InventoryItem{ID: "gun", Name: "波纹枪", Uses: 2})
+	agent.AddItem(&InventoryItem{ID: "handbook", Name: "实习生手册"})
+
+	require.NotNil(t, agent.FindItem("gun"))
+	assert.False(t, agent.FindItem("gun").AcquiredAt.IsZero())
+
+	// 次数用尽后移除
+	require.NoError(t, agent.ConsumeItem("gun"))
+	assert.Equal(t, 1, agent.FindItem("gun").Uses)
+	require.NoError(t, agent.ConsumeItem("gun"))
+	assert.Nil(t, agent.FindItem("gun"))
+	assert.Len(t, agent.Inventory, 1)
+
+	// 不限次数的物品不会被移除
+	require.NoError(t, agent.ConsumeItem("handbook"))
+	assert.NotNil(t, agent.FindItem("handbook"))
+
+	err := agent.ConsumeItem("gun")
+	require.Error(t, err)
+	assert.Equal(t, ErrNotFound, err.(*GameError).Code)
+}
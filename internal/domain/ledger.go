@@ -0,0 +1,149 @@
+package domain
+
+import "time"
+
+// LedgerReason 嘉奖/申诫变化的原因代码
+type LedgerReason string
+
+const (
+	LedgerReasonPermittedBehavior  LedgerReason = "permitted_behavior"  // 申报许可行为，获得嘉奖
+	LedgerReasonDirectiveViolation LedgerReason = "directive_violation" // 违反首要指令，受到申诫
+	LedgerReasonCaptureBonus       LedgerReason = "capture_bonus"       // 捕获异常体
+	LedgerReasonEscapePenalty      LedgerReason = "escape_penalty"      // 异常体逃脱
+	LedgerReasonTripleAscension    LedgerReason = "triple_ascension"    // 三重升华奖励
+	LedgerReasonOffDutyAbility     LedgerReason = "off_duty_ability"    // 工作时间外使用异常能力
+	LedgerReasonDeath              LedgerReason = "death"               // 死亡后复活的代价
+	LedgerReasonStorePurchase      LedgerReason = "store_purchase"      // 在机构商店购买物品
+	LedgerReasonInheritance        LedgerReason = "inheritance"         // 继任者继承前任的嘉奖
+	LedgerReasonEncounterAction    LedgerReason = "encounter_action"    // 遭遇中采取了会受到申诫的行动
+	LedgerReasonMissionReward      LedgerReason = "mission_reward"      // 剧本的任务奖励
+	LedgerReasonOptionalGoal       LedgerReason = "optional_goal"       // 完成可选目标
+	LedgerReasonAdjustment         LedgerReason = "adjustment"          // 手动调整或未注明原因
+)
+
+// IsValidLedgerReason 检查原因代码是否有效
+func IsValidLedgerReason(reason LedgerReason) bool {
+	switch reason {
+	case LedgerReasonPermittedBehavior, LedgerReasonDirectiveViolation,
+		LedgerReasonCaptureBonus, LedgerReasonEscapePenalty,
+		LedgerReasonTripleAscension, LedgerReasonOffDutyAbility,
+		LedgerReasonDeath, LedgerReasonStorePurchase, LedgerReasonInheritance,
+		LedgerReasonEncounterAction, LedgerReasonMissionReward, LedgerReasonOptionalGoal,
+		LedgerReasonAdjustment:
+		return true
+	default:
+		return false
+	}
+}
+
+// 违规标记来源
+const (
+	FlaggedByGM = "gm" // 人类主持人
+	FlaggedByAI = "ai" // AI主持人
+)
+
+// LedgerEntry 角色绩效账本条目（只追加）
+// Commendations和Reprimands是本次变化量，嘉奖可以为负（花费或扣除）
+type LedgerEntry struct {
+	ID            string       `json:"id"`
+	AgentID       string       `json:"agent_id"`
+	SessionID     string       `json:"session_id,omitempty"`
+	SceneID       string       `json:"scene_id,omitempty"`
+	Reason        LedgerReason `json:"reason"`
+	Behavior      string       `json:"behavior,omitempty"` // 许可行为、首要指令或物品的描述
+	Justification string       `json:"justification"`
+	FlaggedBy     string       `json:"flagged_by,omitempty"`
+	Commendations int          `json:"commendations"`
+	Reprimands    int          `json:"reprimands"`
+	CreatedAt     time.Time    `json:"created_at"`
+}
+
+// LedgerFilter 绩效账本查询条件
+type LedgerFilter struct {
+	Reason    LedgerReason `json:"reason,omitempty"`
+	SessionID string       `json:"session_id,omitempty"`
+}
+
+// Matches 检查账本条目是否满足筛选条件
+func (f *LedgerFilter) Matches(entry *LedgerEntry) bool {
+	if f == nil {
+		return true
+	}
+
+	if f.Reason != "" && entry.Reason != f.Reason {
+		return false
+	}
+	if f.SessionID != "" && entry.SessionID != f.SessionID {
+		return false
+	}
+
+	return true
+}
+
+// LedgerReconciliation 角色计数与账本合计的核对结果
+type LedgerReconciliation struct {
+	Commendations       int  `json:"commendations"`        // 角色当前嘉奖
+	Reprimands          int  `json:"reprimands"`           // 角色当前申诫
+	LedgerCommendations int  `json:"ledger_commendations"` // 账本嘉奖合计
+	LedgerReprimands    int  `json:"ledger_reprimands"`    // 账本申诫合计
+	Balanced            bool `json:"balanced"`
+}
+
+// RecordLedger 记录一次嘉奖/申诫变化并同步更新计数和评级
+func (a *Agent) RecordLedger(entry *LedgerEntry) *LedgerEntry {
+	entry.AgentID = a.ID
+	if entry.Reason == "" {
+		entry.Reason = LedgerReasonAdjustment
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+
+	a.Commendations += entry.Commendations
+	if entry.Reprimands != 0 {
+		a.Reprimands += entry.Reprimands
+		a.Rating = GetRating(a.Reprimands)
+	}
+
+	a.Ledger = append(a.Ledger, entry)
+	return entry
+}
+
+// ReconcileLedger 核对嘉奖/申诫计数与账本合计是否一致
+func (a *Agent) ReconcileLedger() *LedgerReconciliation {
+	result := &LedgerReconciliation{
+		Commendations: a.Commendations,
+		Reprimands:    a.Reprimands,
+	}
+	for _, entry := range a.Ledger {
+		result.LedgerCommendations += entry.Commendations
+		result.LedgerReprimands += entry.Reprimands
+	}
+	result.Balanced = result.Commendations == result.LedgerCommendations &&
+		result.Reprimands == result.LedgerReprimands
+
+	return result
+}
+
+// FindPermittedBehavior 按行为描述查找职能的许可行为
+func (c *Career) FindPermittedBehavior(action string) *PermittedBehavior {
+	for _, behavior := range c.PermittedBehaviors {
+		if behavior.Action == action {
+			return behavior
+		}
+	}
+	return nil
+}
+
+// HasClaimedBehavior 检查角色是否已在该会话场景中申报过同一许可行为
+func (a *Agent) HasClaimedBehavior(sessionID, sceneID, action string) bool {
+	for _, entry := range a.Ledger {
+		if entry.Reason == LedgerReasonPermittedBehavior &&
+			entry.SessionID == sessionID &&
+			entry.SceneID == sceneID &&
+			entry.Behavior == action {
+			return true
+		}
+	}
+	return false
+}
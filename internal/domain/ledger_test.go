@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAgent_RecordLedger(t *testing.T) {
+	agent := &Agent{ID: "agent-1", Rating: GetRating(0)}
+
+	entry := agent.RecordLedger(&LedgerEntry{
+		SessionID:     "session-1",
+		Reason:        LedgerReasonCaptureBonus,
+		Justification: "捕获了异常体",
+		Commendations: 3,
+	})
+	assert.Equal(t, "agent-1", entry.AgentID)
+	assert.False(t, entry.CreatedAt.IsZero())
+	assert.Equal(t, 3, agent.Commendations)
+
+	agent.RecordLedger(&LedgerEntry{Reason: LedgerReasonEscapePenalty, Reprimands: 5})
+	assert.Equal(t, 5, agent.Reprimands)
+	assert.Equal(t, GetRating(5), agent.Rating)
+
+	// 未注明原因的修改也会留下记录
+	agent.AddCommendations(2)
+	agent.AddReprimands(0)
+	require.Len(t, agent.Ledger, 3)
+	assert.Equal(t, LedgerReasonAdjustment, agent.Ledger[2].Reason)
+
+	reconciliation := agent.ReconcileLedger()
+	assert.True(t, reconciliation.Balanced)
+	assert.Equal(t, 5, reconciliation.LedgerCommendations)
+	assert.Equal(t, 5, reconciliation.LedgerReprimands)
+
+	// 绕过账本直接修改计数会被核对发现
+	agent.Commendations = 10
+	reconciliation = agent.ReconcileLedger()
+	assert.False(t, reconciliation.Balanced)
+	assert.Equal(t, 10, reconciliation.Commendations)
+}
+
+func TestLedgerFilter_Matches(t *testing.T) {
+	entry := &LedgerEntry{Reason: LedgerReasonDeath, SessionID: "session-1"}
+
+	var filter *LedgerFilter
+	assert.True(t, filter.Matches(entry))
+	assert.True(t, (&LedgerFilter{Reason: LedgerReasonDeath}).Matches(entry))
+	assert.False(t, (&LedgerFilter{Reason: LedgerReasonCaptureBonus}).Matches(entry))
+	assert.True(t, (&LedgerFilter{SessionID: "session-1"}).Matches(entry))
+	assert.False(t, (&LedgerFilter{Reason: LedgerReasonDeath, SessionID: "session-2"}).Matches(entry))
+
+	assert.True(t, IsValidLedgerReason(LedgerReasonOffDutyAbility))
+	assert.False(t, IsValidLedgerReason("bribe"))
+}
@@ -0,0 +1,51 @@
+package domain
+
+import "time"
+
+// 晨会场景类型
+const (
+	MorningSceneCasual        = "casual"        // 日常
+	MorningSceneRelationship  = "relationship"  // 与人际关系共度的场景，可以获得或花费连结
+	MorningSceneForeshadowing = "foreshadowing" // 伏笔
+	MorningSceneWarning       = "warning"       // 警示
+)
+
+// MorningSceneRecord 已完成的晨会场景
+type MorningSceneRecord struct {
+	SceneID        string    `json:"scene_id"`
+	Type           string    `json:"type"`
+	RelationshipID string    `json:"relationship_id,omitempty"` // 人际关系场景中互动的人际关系
+	Delta          int       `json:"delta,omitempty"`           // 连结的实际变化
+	Note           string    `json:"note,omitempty"`
+	CompletedAt    time.Time `json:"completed_at"`
+}
+
+// MorningSceneDone 检查晨会场景是否已完成
+func (s *GameState) MorningSceneDone(sceneID string) bool {
+	for _, record := range s.MorningScenes {
+		if record.SceneID == sceneID {
+			return true
+		}
+	}
+	return false
+}
+
+// NextMorningScene 返回剧本中第一个未完成的晨会场景，全部完成时返回nil
+func (s *GameState) NextMorningScene(scenes []*MorningScene) *MorningScene {
+	for _, scene := range scenes {
+		if !s.MorningSceneDone(scene.ID) {
+			return scene
+		}
+	}
+	return nil
+}
+
+// GoalChosen 检查可选目标是否已被选择
+func (s *GameState) GoalChosen(goalID string) bool {
+	for _, id := range s.ChosenGoals {
+		if id == goalID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,144 @@
+package domain
+
+import "math/big"
+
+// MaxOddsDiceCount 概率计算允许的最大骰子数量
+const MaxOddsDiceCount = 30
+
+// OddsQuery 掷骰概率查询条件
+type OddsQuery struct {
+	DiceCount       int  `json:"dice_count"`       // 骰子数量
+	QAAvailable     int  `json:"qa_available"`     // 相关资质的可用QA
+	Overload        int  `json:"overload"`         // 过载点数
+	TripleAscension bool `json:"triple_ascension"` // 是否计算三重升华
+}
+
+// RollOdds 掷骰概率
+// 规则与 diceService.Roll / ApplyOverload 以及 qaService.AdjustDiceWithQA 一致：
+// 三重升华按调整前的原始骰子判定，过载在QA调整之前生效
+type RollOdds struct {
+	OddsQuery
+
+	// 不花费QA
+	Success       float64   `json:"success"`        // 成功率
+	Threes        []float64 `json:"threes"`         // 第k项为恰好k个"3"的概率（已计入过载）
+	ExpectedChaos float64   `json:"expected_chaos"` // 期望混沌
+
+	// 失败时花费1点QA确保成功（最少花费策略）
+	SuccessWithQA       float64 `json:"success_with_qa"`
+	ExpectedChaosWithQA float64 `json:"expected_chaos_with_qa"`
+	ExpectedQASpent     float64 `json:"expected_qa_spent"`
+
+	// 花光全部可用QA把非"3"骰子调整为"3"后，第k项为恰好k个"3"的概率
+	ThreesWithAllQA []float64 `json:"threes_with_all_qa"`
+
+	TripleAscensionChance float64 `json:"triple_ascension_chance"` // 三重升华概率
+}
+
+// Validate 验证概率查询条件
+func (q *OddsQuery) Validate() error {
+	if q.DiceCount < 1 || q.DiceCount > MaxOddsDiceCount {
+		return NewGameError(ErrInvalidInput, "骰子数量超出范围").
+			WithDetails("dice_count", q.DiceCount).
+			WithDetails("max", MaxOddsDiceCount)
+	}
+
+	if q.QAAvailable < 0 {
+		return NewGameError(ErrInvalidInput, "可用QA不能为负数").
+			WithDetails("qa_available", q.QAAvailable)
+	}
+
+	if q.Overload < 0 {
+		return NewGameError(ErrInvalidInput, "过载点数不能为负数").
+			WithDetails("overload", q.Overload)
+	}
+
+	return nil
+}
+
+// CalculateOdds 按规则集精确计算掷骰概率
+// 骰子独立同分布，因此按"3"的数量聚合后与穷举全部 面数^N 种结果等价
+func CalculateOdds(rules *Ruleset, query *OddsQuery) (*RollOdds, error) {
+	if rules == nil {
+		rules = DefaultRuleset()
+	}
+
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	n := query.DiceCount
+	odds := &RollOdds{
+		OddsQuery:       *query,
+		Threes:          make([]float64, n+1),
+		ThreesWithAllQA: make([]float64, n+1),
+	}
+
+	for k, p := range successDistribution(n, rules.DiceSides) {
+		tripleAsc := query.TripleAscension && rules.IsTripleAscension(k)
+
+		// 掷骰：失败时每颗非成功骰子产生1点混沌，三重升华时不产生混沌
+		chaos := 0
+		if k == 0 {
+			chaos = n
+		}
+		if tripleAsc {
+			chaos = 0
+		}
+
+		// 过载：每点过载移除一个"3"并产生1点混沌
+		removed := query.Overload
+		if removed > k {
+			removed = k
+		}
+		threes := k - removed
+		chaos += removed
+
+		odds.Threes[threes] += p
+		if tripleAsc {
+			odds.TripleAscensionChance += p
+		}
+
+		if threes > 0 {
+			odds.Success += p
+			odds.SuccessWithQA += p
+			odds.ExpectedChaos += p * float64(chaos)
+			odds.ExpectedChaosWithQA += p * float64(chaos)
+		} else {
+			odds.ExpectedChaos += p * float64(chaos)
+			if query.QAAvailable > 0 {
+				// 调整一颗骰子为"3"后重新结算，成功时混沌为0
+				odds.SuccessWithQA += p
+				odds.ExpectedQASpent += p
+			} else {
+				odds.ExpectedChaosWithQA += p * float64(chaos)
+			}
+		}
+
+		adjusted := threes + query.QAAvailable
+		if adjusted > n {
+			adjusted = n
+		}
+		odds.ThreesWithAllQA[adjusted] += p
+	}
+
+	return odds, nil
+}
+
+// successDistribution 返回n颗骰子中恰好k颗为成功值的精确概率
+// P(k) = C(n,k) * (面数-1)^(n-k) / 面数^n，用整数精确计算后再转换为浮点数
+func successDistribution(n, sides int) []float64 {
+	total := new(big.Int).Exp(big.NewInt(int64(sides)), big.NewInt(int64(n)), nil)
+	others := big.NewInt(int64(sides - 1))
+
+	dist := make([]float64, n+1)
+	for k := 0; k <= n; k++ {
+		count := new(big.Int).Binomial(int64(n), int64(k))
+		count.Mul(count, new(big.Int).Exp(others, big.NewInt(int64(n-k)), nil))
+
+		p, _ := new(big.Rat).SetFrac(count, total).Float64()
+		dist[k] = p
+	}
+
+	return dist
+}
@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptedSource 按预设顺序返回骰子值的随机源
+type scriptedSource struct {
+	values []int
+	pos    int
+}
+
+func (s *scriptedSource) Intn(n int) int {
+	v := s.values[s.pos]
+	s.pos++
+	return v
+}
+
+// TestCalculateOdds_MatchesEnumeration 概率结果应与穷举骰子服务的全部结果一致
+func TestCalculateOdds_MatchesEnumeration(t *testing.T) {
+	rulesets := []*Ruleset{
+		DefaultRuleset(),
+		{DiceRules: DiceRules{DiceCount: 4, DiceSides: 6, SuccessValue: 6, TripleAscensionCount: 2}},
+	}
+
+	for _, rules := range rulesets {
+		for n := 1; n <= 5; n++ {
+			for overload := 0; overload <= 2; overload++ {
+				query := &OddsQuery{DiceCount: n, Overload: overload, TripleAscension: true}
+				odds, err := CalculateOdds(rules, query)
+				require.NoError(t, err)
+
+				total := int(math.Pow(float64(rules.DiceSides), float64(n)))
+				success, triple, chaos := 0, 0, 0
+				threes := make([]int, n+1)
+
+				for outcome := 0; outcome < total; outcome++ {
+					values := make([]int, n)
+					for i, rest := 0, outcome; i < n; i++ {
+						values[i] = rest % rules.DiceSides
+						rest /= rules.DiceSides
+					}
+
+					svc := &diceService{rng: &scriptedSource{values: values}, rules: rules}
+					roll := svc.Roll(n)
+					if overload > 0 {
+						roll = svc.ApplyOverload(roll, overload)
+					}
+
+					threes[roll.Threes]++
+					chaos += roll.Chaos
+					if roll.Success {
+						success++
+					}
+					if svc.CheckTripleAscension(roll) {
+						triple++
+					}
+				}
+
+				assert.InDelta(t, float64(success)/float64(total), odds.Success, 1e-12)
+				assert.InDelta(t, float64(triple)/float64(total), odds.TripleAscensionChance, 1e-12)
+				assert.InDelta(t, float64(chaos)/float64(total), odds.ExpectedChaos, 1e-12)
+				for k := range threes {
+					assert.InDelta(t, float64(threes[k])/float64(total), odds.Threes[k], 1e-12)
+				}
+			}
+		}
+	}
+}
+
+func TestCalculateOdds_WithQA(t *testing.T) {
+	odds, err := CalculateOdds(DefaultRuleset(), &OddsQuery{DiceCount: 6, QAAvailable: 2})
+	require.NoError(t, err)
+
+	// 失败概率为 (3/4)^6
+	fail := math.Pow(0.75, 6)
+	assert.InDelta(t, 1-fail, odds.Success, 1e-12)
+	assert.InDelta(t, 1.0, odds.SuccessWithQA, 1e-12)
+	assert.InDelta(t, fail, odds.ExpectedQASpent, 1e-12)
+	assert.InDelta(t, 6*fail, odds.ExpectedChaos, 1e-12)
+	assert.InDelta(t, 0.0, odds.ExpectedChaosWithQA, 1e-12)
+
+	// 花光2点QA后至少有2个"3"
+	assert.Zero(t, odds.ThreesWithAllQA[0])
+	assert.Zero(t, odds.ThreesWithAllQA[1])
+	sum := 0.0
+	for _, p := range odds.ThreesWithAllQA {
+		sum += p
+	}
+	assert.InDelta(t, 1.0, sum, 1e-12)
+
+	// 未要求时不计算三重升华
+	assert.Zero(t, odds.TripleAscensionChance)
+}
+
+func TestCalculateOdds_InvalidQuery(t *testing.T) {
+	invalid := []*OddsQuery{
+		{DiceCount: 0},
+		{DiceCount: MaxOddsDiceCount + 1},
+		{DiceCount: 6, QAAvailable: -1},
+		{DiceCount: 6, Overload: -1},
+	}
+
+	for _, query := range invalid {
+		_, err := CalculateOdds(DefaultRuleset(), query)
+		require.Error(t, err)
+		assert.Equal(t, ErrInvalidInput, err.(*GameError).Code)
+	}
+}
@@ -0,0 +1,100 @@
+package domain
+
+import "time"
+
+// RealityTriggerCause 现实触发器的触发原因
+type RealityTriggerCause string
+
+const (
+	TriggerCausePhase  RealityTriggerCause = "phase"  // 阶段转换
+	TriggerCauseAction RealityTriggerCause = "action" // 累计行动次数
+	TriggerCauseChaos  RealityTriggerCause = "chaos"  // 混沌激增
+)
+
+// RealityTriggerStatus 现实触发器状态
+type RealityTriggerStatus string
+
+const (
+	TriggerStatusPending  RealityTriggerStatus = "pending"  // 等待玩家回应
+	TriggerStatusAnswered RealityTriggerStatus = "answered" // 玩家回应了个人生活
+	TriggerStatusIgnored  RealityTriggerStatus = "ignored"  // 玩家忽视，已应用后果
+)
+
+// RealityTriggerEvent 会话中触发的现实触发器
+type RealityTriggerEvent struct {
+	ID          string               `json:"id"`
+	AgentID     string               `json:"agent_id"`
+	RealityType string               `json:"reality_type"`
+	Name        string               `json:"name"`
+	Cost        int                  `json:"cost"` // 触发时从混沌池扣除的混沌
+	Effect      string               `json:"effect"`
+	Consequence string               `json:"consequence"`
+	Cause       RealityTriggerCause  `json:"cause"`
+	Phase       GamePhase            `json:"phase"`
+	SceneID     string               `json:"scene_id,omitempty"`
+	Status      RealityTriggerStatus `json:"status"`
+	Response    string               `json:"response,omitempty"` // 玩家的回应
+	Applied     *AppliedConsequence  `json:"applied,omitempty"`  // 忽视后应用的后果
+	RaisedAt    time.Time            `json:"raised_at"`
+	ResolvedAt  *time.Time           `json:"resolved_at,omitempty"`
+}
+
+// AppliedConsequence 忽视现实触发器后实际应用的后果
+type AppliedConsequence struct {
+	Type             string `json:"type"`
+	RelationshipID   string `json:"relationship_id,omitempty"`
+	RelationshipName string `json:"relationship_name,omitempty"`
+	Connection       int    `json:"connection,omitempty"` // 人际关系剩余连结
+	Boxes            int    `json:"boxes,omitempty"`      // 标记的退化格数
+	Note             string `json:"note,omitempty"`
+}
+
+// PendingRealityTrigger 返回等待回应的现实触发器，没有时返回nil
+func (s *GameState) PendingRealityTrigger() *RealityTriggerEvent {
+	for i := len(s.RealityTriggers) - 1; i >= 0; i-- {
+		if s.RealityTriggers[i].Status == TriggerStatusPending {
+			return s.RealityTriggers[i]
+		}
+	}
+	return nil
+}
+
+// CheckRealityTrigger 根据规则集判断本次事件是否应触发现实触发器
+// 行动事件会累计行动次数；混沌池减少时同步降低基准，只有增长才算激增
+func (s *GameState) CheckRealityTrigger(rules *Ruleset, cause RealityTriggerCause, phase GamePhase) (RealityTriggerCause, bool) {
+	if s.ChaosBaseline > s.ChaosPool {
+		s.ChaosBaseline = s.ChaosPool
+	}
+	if cause == TriggerCauseAction {
+		s.ActionsSinceTrigger++
+	}
+
+	if s.PendingRealityTrigger() != nil {
+		return "", false
+	}
+
+	if rules.RealityTriggerChaos > 0 && s.ChaosPool-s.ChaosBaseline >= rules.RealityTriggerChaos {
+		return TriggerCauseChaos, true
+	}
+
+	switch cause {
+	case TriggerCausePhase:
+		if rules.TriggersOnPhase(phase) {
+			return TriggerCausePhase, true
+		}
+	case TriggerCauseAction:
+		if rules.RealityTriggerActions > 0 && s.ActionsSinceTrigger >= rules.RealityTriggerActions {
+			return TriggerCauseAction, true
+		}
+	}
+
+	return "", false
+}
+
+// RaiseRealityTrigger 记录新触发的现实触发器，并重置行动计数和混沌基准
+func (s *GameState) RaiseRealityTrigger(event *RealityTriggerEvent) {
+	s.ChaosPool -= event.Cost
+	s.ActionsSinceTrigger = 0
+	s.ChaosBaseline = s.ChaosPool
+	s.RealityTriggers = append(s.RealityTriggers, event)
+}
@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGameState_CheckRealityTrigger(t *testing.T) {
+	rules := DefaultRuleset()
+	rules.RealityTriggerActions = 3
+	rules.RealityTriggerChaos = 2
+
+	t.Run("进入配置的阶段时触发", func(t *testing.T) {
+		state := &GameState{}
+		_, ok := state.CheckRealityTrigger(rules, TriggerCausePhase, PhaseMorning)
+		assert.False(t, ok)
+
+		cause, ok := state.CheckRealityTrigger(rules, TriggerCausePhase, PhaseInvestigation)
+		assert.True(t, ok)
+		assert.Equal(t, TriggerCausePhase, cause)
+	})
+
+	t.Run("累计行动次数后触发", func(t *testing.T) {
+		state := &GameState{}
+		for i := 0; i < 2; i++ {
+			_, ok := state.CheckRealityTrigger(rules, TriggerCauseAction, PhaseInvestigation)
+			assert.False(t, ok)
+		}
+		cause, ok := state.CheckRealityTrigger(rules, TriggerCauseAction, PhaseInvestigation)
+		assert.True(t, ok)
+		assert.Equal(t, TriggerCauseAction, cause)
+
+		state.RaiseRealityTrigger(&RealityTriggerEvent{Status: TriggerStatusPending})
+		assert.Equal(t, 0, state.ActionsSinceTrigger)
+	})
+
+	t.Run("混沌激增时触发，混沌减少时降低基准", func(t *testing.T) {
+		state := &GameState{ChaosPool: 5, ChaosBaseline: 5}
+
+		state.ChaosPool = 1
+		_, ok := state.CheckRealityTrigger(rules, TriggerCauseAction, PhaseEncounter)
+		assert.False(t, ok)
+		assert.Equal(t, 1, state.ChaosBaseline)
+
+		state.ChaosPool = 3
+		cause, ok := state.CheckRealityTrigger(rules, TriggerCauseAction, PhaseEncounter)
+		assert.True(t, ok)
+		assert.Equal(t, TriggerCauseChaos, cause)
+
+		// 触发时扣除混沌消耗并以扣除后的混沌池为新基准
+		state.RaiseRealityTrigger(&RealityTriggerEvent{Cost: 1, Status: TriggerStatusPending})
+		assert.Equal(t, 2, state.ChaosPool)
+		assert.Equal(t, 2, state.ChaosBaseline)
+	})
+
+	t.Run("有待回应的触发器时不再触发", func(t *testing.T) {
+		state := &GameState{}
+		state.RaiseRealityTrigger(&RealityTriggerEvent{Status: TriggerStatusPending})
+
+		_, ok := state.CheckRealityTrigger(rules, TriggerCausePhase, PhaseEncounter)
+		assert.False(t, ok)
+		assert.NotNil(t, state.PendingRealityTrigger())
+
+		state.PendingRealityTrigger().Status = TriggerStatusAnswered
+		assert.Nil(t, state.PendingRealityTrigger())
+		_, ok = state.CheckRealityTrigger(rules, TriggerCausePhase, PhaseEncounter)
+		assert.True(t, ok)
+	})
+}
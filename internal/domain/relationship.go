@@ -0,0 +1,71 @@
+package domain
+
+import "time"
+
+// MaxConnection 单段人际关系的连结上限
+const MaxConnection = 9
+
+// ConnectionChange 人际关系连结的一次变化
+type ConnectionChange struct {
+	Delta      int       `json:"delta"`      // 实际变化量（已按0到9截断）
+	Connection int       `json:"connection"` // 变化后的连结
+	Reason     string    `json:"reason"`
+	SessionID  string    `json:"session_id,omitempty"` // 在会话中花费或失去连结时记录
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// IsLost 连结降至0时永久失去这段人际关系
+func (r *Relationship) IsLost() bool {
+	return r.Connection <= 0
+}
+
+// ChangeConnection 按原因增减连结并记录变化，结果截断在0到9之间
+func (r *Relationship) ChangeConnection(delta int, reason, sessionID string) *ConnectionChange {
+	connection := r.Connection + delta
+	if connection < 0 {
+		connection = 0
+	}
+	if connection > MaxConnection {
+		connection = MaxConnection
+	}
+
+	change := &ConnectionChange{
+		Delta:      connection - r.Connection,
+		Connection: connection,
+		Reason:     reason,
+		SessionID:  sessionID,
+		CreatedAt:  time.Now(),
+	}
+	r.Connection = connection
+	r.History = append(r.History, change)
+
+	return change
+}
+
+// AddNote 追加一条人际关系笔记
+func (r *Relationship) AddNote(note string) {
+	r.Notes = append(r.Notes, note)
+}
+
+// FindRelationship 按ID查找人际关系
+func (a *Agent) FindRelationship(id string) *Relationship {
+	for _, rel := range a.Relationships {
+		if rel.ID == id {
+			return rel
+		}
+	}
+	return nil
+}
+
+// RemoveRelationship 删除人际关系
+func (a *Agent) RemoveRelationship(id string) error {
+	for i, rel := range a.Relationships {
+		if rel.ID == id {
+			a.Relationships = append(a.Relationships[:i], a.Relationships[i+1:]...)
+			return nil
+		}
+	}
+
+	return NewGameError(ErrNotFound, "人际关系不存在").
+		WithDetails("relationship_id", id)
+}
@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelationship_ChangeConnection(t *testing.T) {
+	rel := &Relationship{ID: "1", Name: "李娜", Connection: 8}
+
+	change := rel.ChangeConnection(3, "一起过了生日", "")
+	assert.Equal(t, MaxConnection, rel.Connection)
+	assert.Equal(t, 1, change.Delta, "超过上限的部分不计入")
+	assert.False(t, change.CreatedAt.IsZero())
+
+	change = rel.ChangeConnection(-12, "忽视现实触发器", "session-1")
+	assert.Equal(t, 0, rel.Connection)
+	assert.Equal(t, -9, change.Delta)
+	assert.Equal(t, "session-1", change.SessionID)
+	assert.True(t, rel.IsLost())
+
+	require.Len(t, rel.History, 2)
+	assert.Equal(t, "一起过了生日", rel.History[0].Reason)
+}
+
+func TestAgent_RemoveRelationship(t *testing.T) {
+	agent := &Agent{
+		Relationships: []*Relationship{
+			{ID: "1", Name: "李娜", Connection: 6},
+			{ID: "2", Name: "王强", Connection: 3},
+		},
+	}
+
+	require.NotNil(t, agent.FindRelationship("2"))
+	require.NoError(t, agent.RemoveRelationship("2"))
+	assert.Nil(t, agent.FindRelationship("2"))
+	assert.Len(t, agent.Relationships, 1)
+
+	err := agent.RemoveRelationship("2")
+	require.Error(t, err)
+	assert.Equal(t, ErrNotFound, err.(*GameError).Code)
+}
+
+func TestAgent_ValidateInPlayRelationships(t *testing.T) {
+	agent := &Agent{
+		Anomaly: &Anomaly{Type: AnomalyWhisper, Abilities: []*AnomalyAbility{{}, {}, {}}},
+		Reality: &Reality{Type: RealityCaretaker},
+		Career:  &Career{Type: CareerPublicRelations},
+		Relationships: []*Relationship{
+			{ID: "1", Connection: 6},
+			{ID: "2", Connection: 3},
+			{ID: "3", Connection: 3},
+			{ID: "4", Connection: 1},
+		},
+	}
+
+	// 创建时必须是3段，游戏中可以增减
+	assert.Error(t, agent.ValidateARC())
+	assert.NoError(t, agent.ValidateInPlay())
+
+	agent.Relationships[0].Connection = 10
+	assert.Error(t, agent.ValidateInPlay(), "单段连结不能超过9")
+}
@@ -0,0 +1,100 @@
+package domain
+
+import "time"
+
+// RollKind 掷骰类型
+type RollKind string
+
+const (
+	RollKindBasic     RollKind = "basic"     // 基础掷骰
+	RollKindAbility   RollKind = "ability"   // 异常能力掷骰
+	RollKindRequest   RollKind = "request"   // 请求机构掷骰
+	RollKindEncounter RollKind = "encounter" // 遭遇行动掷骰
+)
+
+// RollOutcome 掷骰结果筛选条件
+const (
+	RollOutcomeSuccess   = "success"    // 成功
+	RollOutcomeFailure   = "failure"    // 失败
+	RollOutcomeTripleAsc = "triple_asc" // 三重升华
+)
+
+// RollRecord 掷骰账本条目（只追加，不修改）
+type RollRecord struct {
+	ID        string    `json:"id"`
+	SessionID string    `json:"session_id"`
+	AgentID   string    `json:"agent_id"`
+	Kind      RollKind  `json:"kind"`
+	Quality   string    `json:"quality"`
+	AbilityID string    `json:"ability_id,omitempty"`
+	Request   string    `json:"request,omitempty"` // 能力名称或请求效果文本
+	RawDice   []int     `json:"raw_dice"`          // 原始骰子
+	Dice      []int     `json:"dice"`              // 调整后的骰子
+	Threes    int       `json:"threes"`
+	Success   bool      `json:"success"`
+	QASpent   int       `json:"qa_spent"`
+	Overload  int       `json:"overload"`
+	Chaos     int       `json:"chaos"`
+	TripleAsc bool      `json:"triple_asc"`
+	Effect    string    `json:"effect,omitempty"` // 三重升华获得的效果
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RollFilter 掷骰账本查询条件
+type RollFilter struct {
+	Quality string `json:"quality,omitempty"`
+	Outcome string `json:"outcome,omitempty"`
+}
+
+// IsValidRollOutcome 检查结果筛选值是否有效
+func IsValidRollOutcome(outcome string) bool {
+	switch outcome {
+	case "", RollOutcomeSuccess, RollOutcomeFailure, RollOutcomeTripleAsc:
+		return true
+	default:
+		return false
+	}
+}
+
+// Matches 检查账本条目是否满足筛选条件
+func (f *RollFilter) Matches(record *RollRecord) bool {
+	if f == nil {
+		return true
+	}
+
+	if f.Quality != "" && record.Quality != f.Quality {
+		return false
+	}
+
+	switch f.Outcome {
+	case RollOutcomeSuccess:
+		return record.Success
+	case RollOutcomeFailure:
+		return !record.Success
+	case RollOutcomeTripleAsc:
+		return record.TripleAsc
+	}
+
+	return true
+}
+
+// NewRollRecord 根据掷骰结果创建账本条目
+func NewRollRecord(sessionID, agentID string, kind RollKind, roll *RollResult) *RollRecord {
+	record := &RollRecord{
+		SessionID: sessionID,
+		AgentID:   agentID,
+		Kind:      kind,
+		RawDice:   append([]int(nil), roll.RawDice...),
+		Dice:      append([]int(nil), roll.Dice...),
+		Threes:    roll.Threes,
+		Success:   roll.Success,
+		Overload:  roll.Overload,
+		Chaos:     roll.Chaos,
+		TripleAsc: roll.TripleAsc,
+		CreatedAt: time.Now(),
+	}
+	if record.RawDice == nil {
+		record.RawDice = append([]int(nil), roll.Dice...)
+	}
+	return record
+}
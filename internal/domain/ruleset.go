@@ -0,0 +1,283 @@
+package domain
+
+import "slices"
+
+// DiceRules 骰子规则
+// 默认值与《三角机构》规则书一致（6d4，"3"为成功，恰好3个"3"为三重升华）
+type DiceRules struct {
+	DiceCount            int `json:"dice_count" mapstructure:"dice_count"`                         // 默认骰子数量
+	DiceSides            int `json:"dice_sides" mapstructure:"dice_sides"`                         // 骰子面数
+	SuccessValue         int `json:"success_value" mapstructure:"success_value"`                   // 成功值
+	TripleAscensionCount int `json:"triple_ascension_count" mapstructure:"triple_ascension_count"` // 三重升华所需的成功骰数量
+}
+
+// Ruleset 游戏规则集
+// 由骰子规则和三重升华效果、过载解除、现实触发器、死亡与继任、遭遇、晨会的玩法规则组成；
+// 默认值与规则书一致，可通过配置 game.rules 修改，并由剧本的 RulesetOverride 覆盖
+type Ruleset struct {
+	DiceRules `mapstructure:",squash"`
+
+	// 三重升华与过载
+	TripleAscensionEffect string `json:"triple_ascension_effect" mapstructure:"triple_ascension_effect"` // 三重升华效果
+	TripleAscensionReward int    `json:"triple_ascension_reward" mapstructure:"triple_ascension_reward"` // 三重升华效果数量
+	OverloadReliefScope   string `json:"overload_relief_scope" mapstructure:"overload_relief_scope"`     // 过载解除的持续范围
+
+	// 现实触发器节奏（次数为0时关闭对应的触发方式）
+	RealityTriggerPhases  []string `json:"reality_trigger_phases" mapstructure:"reality_trigger_phases"`   // 进入这些阶段时触发
+	RealityTriggerActions int      `json:"reality_trigger_actions" mapstructure:"reality_trigger_actions"` // 上次触发后每N次行动触发
+	RealityTriggerChaos   int      `json:"reality_trigger_chaos" mapstructure:"reality_trigger_chaos"`     // 混沌池较上次触发增长N点时触发
+
+	// 死亡与继任（房规）
+	DeathCommendationCost      int `json:"death_commendation_cost" mapstructure:"death_commendation_cost"`           // 复活死亡角色花费的嘉奖
+	SuccessorCommendationShare int `json:"successor_commendation_share" mapstructure:"successor_commendation_share"` // 继任者继承前任嘉奖的百分比
+	SuccessorRelationships     int `json:"successor_relationships" mapstructure:"successor_relationships"`           // 继任者最多继承的人际关系数量
+
+	// 遭遇
+	EncounterTurnLimit int `json:"encounter_turn_limit" mapstructure:"encounter_turn_limit"` // 玩家回合数达到上限仍未解决时异常体逃脱（0为不限制）
+
+	// 晨会
+	MorningSceneConnectionGain int `json:"morning_scene_connection_gain" mapstructure:"morning_scene_connection_gain"` // 一次人际关系场景最多获得的连结
+}
+
+// 三重升华效果
+const (
+	TripleAscEffectCommendation = "commendation" // 额外嘉奖
+	TripleAscEffectReroll       = "reroll"       // 免费重掷令牌
+)
+
+// 过载解除持续范围
+const (
+	OverloadReliefScopeRoll    = "roll"    // 仅抵消下一次过载
+	OverloadReliefScopeScene   = "scene"   // 持续到离开当前场景
+	OverloadReliefScopeSession = "session" // 持续到会话结束
+)
+
+// DefaultRuleset 返回规则书默认规则集
+func DefaultRuleset() *Ruleset {
+	return &Ruleset{
+		DiceRules: DiceRules{
+			DiceCount:            6,
+			DiceSides:            4,
+			SuccessValue:         3,
+			TripleAscensionCount: 3,
+		},
+		TripleAscensionEffect: TripleAscEffectCommendation,
+		TripleAscensionReward: 1,
+		OverloadReliefScope:   OverloadReliefScopeScene,
+		RealityTriggerPhases:  []string{string(PhaseInvestigation), string(PhaseEncounter)},
+		RealityTriggerActions: 5,
+		RealityTriggerChaos:   3,
+
+		DeathCommendationCost:      5,
+		SuccessorCommendationShare: 50,
+		SuccessorRelationships:     1,
+
+		EncounterTurnLimit: 10,
+
+		MorningSceneConnectionGain: 1,
+	}
+}
+
+// Validate 验证骰子规则
+func (r *DiceRules) Validate() error {
+	if r.DiceCount < 1 {
+		return NewGameError(ErrInvalidInput, "骰子数量必须大于0").
+			WithDetails("dice_count", r.DiceCount)
+	}
+
+	if r.DiceSides < 2 {
+		return NewGameError(ErrInvalidInput, "骰子面数至少为2").
+			WithDetails("dice_sides", r.DiceSides)
+	}
+
+	if !r.IsValidDieValue(r.SuccessValue) {
+		return NewGameError(ErrInvalidInput, "成功值必须在骰子面数范围内").
+			WithDetails("success_value", r.SuccessValue).
+			WithDetails("dice_sides", r.DiceSides)
+	}
+
+	if r.TripleAscensionCount < 1 {
+		return NewGameError(ErrInvalidInput, "三重升华所需数量必须大于0").
+			WithDetails("triple_ascension_count", r.TripleAscensionCount)
+	}
+
+	return nil
+}
+
+// Validate 验证规则集
+func (r *Ruleset) Validate() error {
+	if err := r.DiceRules.Validate(); err != nil {
+		return err
+	}
+
+	switch r.TripleAscensionEffect {
+	case TripleAscEffectCommendation, TripleAscEffectReroll:
+	default:
+		return NewGameError(ErrInvalidInput, "无效的三重升华效果").
+			WithDetails("triple_ascension_effect", r.TripleAscensionEffect)
+	}
+
+	if r.TripleAscensionReward < 0 {
+		return NewGameError(ErrInvalidInput, "三重升华效果数量不能为负数").
+			WithDetails("triple_ascension_reward", r.TripleAscensionReward)
+	}
+
+	switch r.OverloadReliefScope {
+	case OverloadReliefScopeRoll, OverloadReliefScopeScene, OverloadReliefScopeSession:
+	default:
+		return NewGameError(ErrInvalidInput, "无效的过载解除范围").
+			WithDetails("overload_relief_scope", r.OverloadReliefScope)
+	}
+
+	for _, phase := range r.RealityTriggerPhases {
+		switch GamePhase(phase) {
+		case PhaseMorning, PhaseInvestigation, PhaseEncounter, PhaseAftermath:
+		default:
+			return NewGameError(ErrInvalidInput, "无效的现实触发阶段").
+				WithDetails("reality_trigger_phases", phase)
+		}
+	}
+
+	if r.RealityTriggerActions < 0 {
+		return NewGameError(ErrInvalidInput, "现实触发行动次数不能为负数").
+			WithDetails("reality_trigger_actions", r.RealityTriggerActions)
+	}
+
+	if r.RealityTriggerChaos < 0 {
+		return NewGameError(ErrInvalidInput, "现实触发混沌增量不能为负数").
+			WithDetails("reality_trigger_chaos", r.RealityTriggerChaos)
+	}
+
+	if r.DeathCommendationCost < 0 {
+		return NewGameError(ErrInvalidInput, "复活花费的嘉奖不能为负数").
+			WithDetails("death_commendation_cost", r.DeathCommendationCost)
+	}
+
+	if r.SuccessorCommendationShare < 0 || r.SuccessorCommendationShare > 100 {
+		return NewGameError(ErrInvalidInput, "继任者继承嘉奖的百分比必须在0到100之间").
+			WithDetails("successor_commendation_share", r.SuccessorCommendationShare)
+	}
+
+	if r.SuccessorRelationships < 0 {
+		return NewGameError(ErrInvalidInput, "继任者继承的人际关系数量不能为负数").
+			WithDetails("successor_relationships", r.SuccessorRelationships)
+	}
+
+	if r.EncounterTurnLimit < 0 {
+		return NewGameError(ErrInvalidInput, "遭遇回合上限不能为负数").
+			WithDetails("encounter_turn_limit", r.EncounterTurnLimit)
+	}
+
+	if r.MorningSceneConnectionGain < 0 || r.MorningSceneConnectionGain > MaxConnection {
+		return NewGameError(ErrInvalidInput, "晨会场景获得的连结必须在0到连结上限之间").
+			WithDetails("morning_scene_connection_gain", r.MorningSceneConnectionGain).
+			WithDetails("max_connection", MaxConnection)
+	}
+
+	return nil
+}
+
+// RulesetOverride 剧本对规则集的覆盖
+// 未给出的字段沿用基础规则集；显式给出的零值同样生效，例如关闭现实触发器或取消遭遇回合上限
+type RulesetOverride struct {
+	DiceCount                  *int     `json:"dice_count,omitempty"`
+	DiceSides                  *int     `json:"dice_sides,omitempty"`
+	SuccessValue               *int     `json:"success_value,omitempty"`
+	TripleAscensionCount       *int     `json:"triple_ascension_count,omitempty"`
+	TripleAscensionEffect      *string  `json:"triple_ascension_effect,omitempty"`
+	TripleAscensionReward      *int     `json:"triple_ascension_reward,omitempty"`
+	OverloadReliefScope        *string  `json:"overload_relief_scope,omitempty"`
+	RealityTriggerPhases       []string `json:"reality_trigger_phases"` // 为nil时沿用，空数组表示任何阶段都不触发
+	RealityTriggerActions      *int     `json:"reality_trigger_actions,omitempty"`
+	RealityTriggerChaos        *int     `json:"reality_trigger_chaos,omitempty"`
+	DeathCommendationCost      *int     `json:"death_commendation_cost,omitempty"`
+	SuccessorCommendationShare *int     `json:"successor_commendation_share,omitempty"`
+	SuccessorRelationships     *int     `json:"successor_relationships,omitempty"`
+	EncounterTurnLimit         *int     `json:"encounter_turn_limit,omitempty"`
+	MorningSceneConnectionGain *int     `json:"morning_scene_connection_gain,omitempty"`
+}
+
+// Override 返回用override中给出的字段覆盖后的新规则集
+// override为nil时返回副本
+func (r *Ruleset) Override(override *RulesetOverride) *Ruleset {
+	merged := *r
+	merged.RealityTriggerPhases = slices.Clone(r.RealityTriggerPhases)
+	if override == nil {
+		return &merged
+	}
+
+	overrideInt(&merged.DiceCount, override.DiceCount)
+	overrideInt(&merged.DiceSides, override.DiceSides)
+	overrideInt(&merged.SuccessValue, override.SuccessValue)
+	overrideInt(&merged.TripleAscensionCount, override.TripleAscensionCount)
+	if override.TripleAscensionEffect != nil {
+		merged.TripleAscensionEffect = *override.TripleAscensionEffect
+	}
+	overrideInt(&merged.TripleAscensionReward, override.TripleAscensionReward)
+	if override.OverloadReliefScope != nil {
+		merged.OverloadReliefScope = *override.OverloadReliefScope
+	}
+	if override.RealityTriggerPhases != nil {
+		merged.RealityTriggerPhases = slices.Clone(override.RealityTriggerPhases)
+	}
+	overrideInt(&merged.RealityTriggerActions, override.RealityTriggerActions)
+	overrideInt(&merged.RealityTriggerChaos, override.RealityTriggerChaos)
+	overrideInt(&merged.DeathCommendationCost, override.DeathCommendationCost)
+	overrideInt(&merged.SuccessorCommendationShare, override.SuccessorCommendationShare)
+	overrideInt(&merged.SuccessorRelationships, override.SuccessorRelationships)
+	overrideInt(&merged.EncounterTurnLimit, override.EncounterTurnLimit)
+	overrideInt(&merged.MorningSceneConnectionGain, override.MorningSceneConnectionGain)
+
+	return &merged
+}
+
+// overrideInt 覆盖值不为nil时写入目标字段
+func overrideInt(target *int, value *int) {
+	if value != nil {
+		*target = *value
+	}
+}
+
+// TriggersOnPhase 检查进入指定阶段时是否触发现实触发器
+func (r *Ruleset) TriggersOnPhase(phase GamePhase) bool {
+	for _, p := range r.RealityTriggerPhases {
+		if GamePhase(p) == phase {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidDieValue 检查骰子数值是否在1到面数之间
+func (r *DiceRules) IsValidDieValue(value int) bool {
+	return value >= 1 && value <= r.DiceSides
+}
+
+// IsSuccess 检查单颗骰子是否为成功值
+func (r *DiceRules) IsSuccess(value int) bool {
+	return value == r.SuccessValue
+}
+
+// CountSuccesses 统计成功骰数量
+func (r *DiceRules) CountSuccesses(dice []int) int {
+	count := 0
+	for _, d := range dice {
+		if r.IsSuccess(d) {
+			count++
+		}
+	}
+	return count
+}
+
+// IsTripleAscension 检查成功骰数量是否恰好触发三重升华
+func (r *DiceRules) IsTripleAscension(successes int) bool {
+	return successes == r.TripleAscensionCount
+}
+
+// FailValue 返回过载时替换成功骰使用的非成功值
+func (r *DiceRules) FailValue() int {
+	if r.SuccessValue == 1 {
+		return 2
+	}
+	return 1
+}
@@ -0,0 +1,119 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRuleset_Validate(t *testing.T) {
+	assert.NoError(t, DefaultRuleset().Validate())
+
+	invalid := []*Ruleset{
+		{DiceRules: DiceRules{DiceCount: 0, DiceSides: 4, SuccessValue: 3, TripleAscensionCount: 3}},
+		{DiceRules: DiceRules{DiceCount: 6, DiceSides: 1, SuccessValue: 1, TripleAscensionCount: 3}},
+		{DiceRules: DiceRules{DiceCount: 6, DiceSides: 4, SuccessValue: 5, TripleAscensionCount: 3}},
+		{DiceRules: DiceRules{DiceCount: 6, DiceSides: 4, SuccessValue: 3, TripleAscensionCount: 0}},
+	}
+	badScope := DefaultRuleset()
+	badScope.OverloadReliefScope = "forever"
+	invalid = append(invalid, badScope)
+	badPhase := DefaultRuleset()
+	badPhase.RealityTriggerPhases = []string{"lunch"}
+	invalid = append(invalid, badPhase)
+	negativeActions := DefaultRuleset()
+	negativeActions.RealityTriggerActions = -1
+	invalid = append(invalid, negativeActions)
+	badShare := DefaultRuleset()
+	badShare.SuccessorCommendationShare = 120
+	invalid = append(invalid, badShare)
+	negativeTurns := DefaultRuleset()
+	negativeTurns.EncounterTurnLimit = -1
+	invalid = append(invalid, negativeTurns)
+	negativeGain := DefaultRuleset()
+	negativeGain.MorningSceneConnectionGain = -1
+	invalid = append(invalid, negativeGain)
+	excessiveGain := DefaultRuleset()
+	excessiveGain.MorningSceneConnectionGain = MaxConnection + 1
+	invalid = append(invalid, excessiveGain)
+
+	for _, rules := range invalid {
+		err := rules.Validate()
+		assert.Error(t, err)
+		assert.Equal(t, ErrInvalidInput, err.(*GameError).Code)
+	}
+}
+
+func TestRuleset_Override(t *testing.T) {
+	base := DefaultRuleset()
+
+	// 剧本只覆盖给出的字段
+	merged := base.Override(&RulesetOverride{DiceSides: intPtr(6), SuccessValue: intPtr(6)})
+	assert.Equal(t, 6, merged.DiceCount)
+	assert.Equal(t, 6, merged.DiceSides)
+	assert.Equal(t, 6, merged.SuccessValue)
+	assert.Equal(t, 3, merged.TripleAscensionCount)
+
+	// 剧本可以改变现实触发器节奏
+	paced := base.Override(&RulesetOverride{RealityTriggerPhases: []string{string(PhaseEncounter)}, RealityTriggerActions: intPtr(2)})
+	assert.True(t, paced.TriggersOnPhase(PhaseEncounter))
+	assert.False(t, paced.TriggersOnPhase(PhaseInvestigation))
+	assert.Equal(t, 2, paced.RealityTriggerActions)
+	assert.Equal(t, base.RealityTriggerChaos, paced.RealityTriggerChaos)
+
+	// 剧本可以放宽晨会场景获得的连结
+	generous := base.Override(&RulesetOverride{MorningSceneConnectionGain: intPtr(2)})
+	assert.Equal(t, 2, generous.MorningSceneConnectionGain)
+	assert.Equal(t, 1, base.Override(nil).MorningSceneConnectionGain)
+
+	// 显式给出的零值同样覆盖，剧本可以关闭现实触发器和遭遇回合上限
+	disabled := base.Override(&RulesetOverride{
+		RealityTriggerPhases:  []string{},
+		RealityTriggerActions: intPtr(0),
+		RealityTriggerChaos:   intPtr(0),
+		EncounterTurnLimit:    intPtr(0),
+		TripleAscensionReward: intPtr(0),
+	})
+	assert.False(t, disabled.TriggersOnPhase(PhaseInvestigation))
+	assert.Equal(t, 0, disabled.RealityTriggerActions)
+	assert.Equal(t, 0, disabled.RealityTriggerChaos)
+	assert.Equal(t, 0, disabled.EncounterTurnLimit)
+	assert.Equal(t, 0, disabled.TripleAscensionReward)
+	assert.NoError(t, disabled.Validate())
+
+	// 原规则集不受影响
+	assert.Equal(t, 4, base.DiceSides)
+
+	// 无覆盖时返回副本
+	copied := base.Override(nil)
+	assert.Equal(t, base, copied)
+	assert.NotSame(t, base, copied)
+}
+
+func TestRulesetOverride_JSON(t *testing.T) {
+	var override RulesetOverride
+	assert.NoError(t, json.Unmarshal([]byte(`{"encounter_turn_limit": 0, "dice_sides": 6}`), &override))
+
+	merged := DefaultRuleset().Override(&override)
+	assert.Equal(t, 0, merged.EncounterTurnLimit)
+	assert.Equal(t, 6, merged.DiceSides)
+	assert.Equal(t, DefaultRuleset().RealityTriggerPhases, merged.RealityTriggerPhases)
+	assert.Equal(t, DefaultRuleset().RealityTriggerActions, merged.RealityTriggerActions)
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestDiceService_ForSessionUsesSessionRules(t *testing.T) {
+	rules := &Ruleset{DiceRules: DiceRules{DiceCount: 4, DiceSides: 6, SuccessValue: 6, TripleAscensionCount: 2}}
+	session := &GameSession{DiceSeed: 1, Rules: rules}
+
+	dice := NewDiceService().ForSession(session)
+	assert.Same(t, rules, dice.Ruleset())
+
+	roll := dice.Roll(0)
+	assert.Len(t, roll.Dice, 4)
+	assert.Equal(t, rules.CountSuccesses(roll.Dice), roll.Threes)
+}
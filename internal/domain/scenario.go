@@ -2,18 +2,19 @@ package domain
 
 // Scenario 剧本
 type Scenario struct {
-	ID               string            `json:"id"`
-	Name             string            `json:"name"`
-	Description      string            `json:"description"`
-	Anomaly          *AnomalyProfile   `json:"anomaly"`
-	MorningScenes    []*MorningScene   `json:"morning_scenes"`
-	Briefing         *Briefing         `json:"briefing"`
-	OptionalGoals    []*OptionalGoal   `json:"optional_goals"`
-	Scenes           map[string]*Scene `json:"scenes"`
-	StartingSceneID  string            `json:"starting_scene_id"`
-	Encounter        *Encounter        `json:"encounter"`
-	Aftermath        *Aftermath        `json:"aftermath"`
-	Rewards          *Rewards          `json:"rewards"`
+	ID              string            `json:"id"`
+	Name            string            `json:"name"`
+	Description     string            `json:"description"`
+	Anomaly         *AnomalyProfile   `json:"anomaly"`
+	MorningScenes   []*MorningScene   `json:"morning_scenes"`
+	Briefing        *Briefing         `json:"briefing"`
+	OptionalGoals   []*OptionalGoal   `json:"optional_goals"`
+	Scenes          map[string]*Scene `json:"scenes"`
+	StartingSceneID string            `json:"starting_scene_id"`
+	Encounter       *Encounter        `json:"encounter"`
+	Aftermath       *Aftermath        `json:"aftermath"`
+	Rewards         *Rewards          `json:"rewards"`
+	Rules           *RulesetOverride  `json:"rules,omitempty"` // 剧本规则覆盖（可选，仅覆盖给出的字段）
 }
 
 // AnomalyProfile 异常体档案
@@ -59,9 +60,9 @@ type MorningScene struct {
 
 // Briefing 任务简报
 type Briefing struct {
-	Summary     string   `json:"summary"`
-	Objectives  []string `json:"objectives"`
-	Warnings    []string `json:"warnings"`
+	Summary    string   `json:"summary"`
+	Objectives []string `json:"objectives"`
+	Warnings   []string `json:"warnings"`
 }
 
 // OptionalGoal 可选目标
@@ -73,13 +74,13 @@ type OptionalGoal struct {
 
 // Scene 场景
 type Scene struct {
-	ID          string            `json:"id"`
-	Name        string            `json:"name"`
-	Description string            `json:"description"`
-	NPCs        []*NPC            `json:"npcs"`
-	Clues       []*Clue           `json:"clues"`
-	Events      []*Event          `json:"events"`
-	Connections []string          `json:"connections"`
+	ID          string                 `json:"id"`
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	NPCs        []*NPC                 `json:"npcs"`
+	Clues       []*Clue                `json:"clues"`
+	Events      []*Event               `json:"events"`
+	Connections []string               `json:"connections"`
 	State       map[string]interface{} `json:"state"`
 }
 
@@ -104,11 +105,11 @@ type Clue struct {
 
 // Event 事件
 type Event struct {
-	ID          string   `json:"id"`
-	Name        string   `json:"name"`
-	Description string   `json:"description"`
-	Trigger     string   `json:"trigger"`
-	Effect      string   `json:"effect"`
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Trigger     string `json:"trigger"`
+	Effect      string `json:"effect"`
 }
 
 // Encounter 遭遇
@@ -120,9 +121,17 @@ type Encounter struct {
 
 // Phase 遭遇阶段
 type Phase struct {
-	ID          string `json:"id"`
+	ID          string         `json:"id"`
+	Description string         `json:"description"`
+	Actions     []*PhaseAction `json:"actions"`
+}
+
+// PhaseAction 遭遇阶段中可以采取的行动
+type PhaseAction struct {
 	Description string `json:"description"`
-	Actions     []string `json:"actions"`
+	Quality     string `json:"quality,omitempty"`   // 判定使用的资质，为空时使用气场
+	Outcome     string `json:"outcome,omitempty"`   // 最终阶段判定成功时的任务结果，最终阶段必填
+	Reprimand   bool   `json:"reprimand,omitempty"` // 采取该行动会受到申诫
 }
 
 // Aftermath 余波
@@ -130,6 +139,7 @@ type Aftermath struct {
 	Captured    string `json:"captured"`
 	Neutralized string `json:"neutralized"`
 	Escaped     string `json:"escaped"`
+	Deal        string `json:"deal,omitempty"` // 与异常体达成协议（可选）
 }
 
 // Rewards 奖励
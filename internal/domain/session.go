@@ -1,6 +1,9 @@
 package domain
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // GameSession 游戏会话
 type GameSession struct {
@@ -9,10 +12,47 @@ type GameSession struct {
 	ScenarioID string     `json:"scenario_id"`
 	Phase      GamePhase  `json:"phase"`
 	State      *GameState `json:"state"`
+	DiceSeed   int64      `json:"-"`               // 会话骰子种子，不在API响应中返回，防止客户端预测掷骰
+	DiceCursor int64      `json:"dice_cursor"`     // 骰子随机流位置
+	Rules      *Ruleset   `json:"rules,omitempty"` // 会话规则集（全局规则经剧本覆盖后的结果）
 	CreatedAt  time.Time  `json:"created_at"`
 	UpdatedAt  time.Time  `json:"updated_at"`
 }
 
+// StoredSession 持久化用的会话JSON，附带API响应中隐藏的骰子种子
+type StoredSession struct {
+	*GameSession
+}
+
+type storedSessionJSON struct {
+	*GameSession
+	DiceSeed int64 `json:"dice_seed"`
+}
+
+// MarshalJSON 序列化会话及其骰子种子
+func (s StoredSession) MarshalJSON() ([]byte, error) {
+	if s.GameSession == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(storedSessionJSON{GameSession: s.GameSession, DiceSeed: s.DiceSeed})
+}
+
+// UnmarshalJSON 反序列化会话并恢复骰子种子
+func (s *StoredSession) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		s.GameSession = nil
+		return nil
+	}
+
+	stored := storedSessionJSON{GameSession: &GameSession{}}
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return err
+	}
+	stored.GameSession.DiceSeed = stored.DiceSeed
+	s.GameSession = stored.GameSession
+	return nil
+}
+
 // GamePhase 游戏阶段
 type GamePhase string
 
@@ -36,6 +76,126 @@ type GameState struct {
 	LocationOverloads map[string]int       `json:"location_overloads"` // 地点过载追踪
 	AnomalyStatus     string               `json:"anomaly_status"`
 	MissionOutcome    string               `json:"mission_outcome"`
+	TripleAscensions  []*TripleAscension   `json:"triple_ascensions,omitempty"` // 三重升华记录
+	RerollTokens      int                  `json:"reroll_tokens"`               // 可用的免费重掷令牌
+	OverloadRelief    *OverloadReliefClaim `json:"overload_relief,omitempty"`   // 当前生效的过载解除
+	ReliefScenes      []string             `json:"relief_scenes,omitempty"`     // 申请过过载解除的场景，用于限制申请次数
+	RewardsGranted    bool                 `json:"rewards_granted"`             // 剧本奖励物品是否已发放
+
+	// 晨会
+	ChosenGoals   []string              `json:"chosen_goals,omitempty"`   // 选择的可选目标ID，在余波阶段计分
+	MorningScenes []*MorningSceneRecord `json:"morning_scenes,omitempty"` // 已完成的晨会场景
+
+	// 遭遇
+	Encounter *EncounterState `json:"encounter,omitempty"` // 遭遇进度，开始遭遇前为空
+
+	// 余波
+	Debrief          *DebriefReport `json:"debrief,omitempty"` // 任务报告，余波结算前为空
+	DebriefResolving bool           `json:"-"`                 // 余波正在结算，防止重复请求重复发放奖励
+
+	// 现实触发器
+	RealityTriggers     []*RealityTriggerEvent `json:"reality_triggers,omitempty"` // 本次任务触发过的现实触发器
+	ActionsSinceTrigger int                    `json:"actions_since_trigger"`      // 上次触发后的行动次数
+	ChaosBaseline       int                    `json:"chaos_baseline"`             // 上次触发后的混沌池基准
+}
+
+// TripleAscension 三重升华事件
+type TripleAscension struct {
+	RollID    string    `json:"roll_id"`
+	AgentID   string    `json:"agent_id"`
+	Quality   string    `json:"quality,omitempty"`
+	Effect    string    `json:"effect"` // 获得的效果（见 TripleAscEffect* 常量）
+	Amount    int       `json:"amount"`
+	Narration string    `json:"narration"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// OverloadReliefClaim 会话中已申请的过载解除
+type OverloadReliefClaim struct {
+	AgentID       string    `json:"agent_id"`
+	Name          string    `json:"name"`
+	Condition     string    `json:"condition"`     // 现实的过载解除条件
+	Justification string    `json:"justification"` // 玩家满足条件的说明
+	Scope         string    `json:"scope"`         // 持续范围（见 OverloadReliefScope* 常量）
+	SceneID       string    `json:"scene_id"`      // 申请时所在场景
+	Used          int       `json:"used"`          // 已抵消的过载次数
+	ClaimedAt     time.Time `json:"claimed_at"`
+}
+
+// ActiveOverloadRelief 返回角色当前生效的过载解除，没有时返回nil
+// 角色的现实必须有过载解除，申请和掷骰都以此为准
+func (s *GameSession) ActiveOverloadRelief(agent *Agent) *OverloadReliefClaim {
+	if s.State == nil || s.State.OverloadRelief == nil {
+		return nil
+	}
+
+	if agent == nil || agent.Reality == nil || agent.Reality.OverloadRelief == nil {
+		return nil
+	}
+
+	relief := s.State.OverloadRelief
+	if relief.AgentID != agent.ID {
+		return nil
+	}
+
+	switch relief.Scope {
+	case OverloadReliefScopeRoll:
+		if relief.Used > 0 {
+			return nil
+		}
+	case OverloadReliefScopeScene:
+		if relief.SceneID != s.State.CurrentSceneID {
+			return nil
+		}
+	case OverloadReliefScopeSession:
+	default:
+		return nil
+	}
+
+	return relief
+}
+
+// ConsumeOverloadRelief 用生效的过载解除抵消一次过载
+// 返回false表示没有生效的过载解除，调用方应正常应用过载
+func (s *GameSession) ConsumeOverloadRelief(agent *Agent) bool {
+	relief := s.ActiveOverloadRelief(agent)
+	if relief == nil {
+		return false
+	}
+
+	relief.Used++
+	return true
+}
+
+// OverloadReliefClaimed 检查在指定范围内是否已经申请过过载解除
+// session范围每个会话只能申请一次，roll和scene范围每个场景只能申请一次
+func (s *GameState) OverloadReliefClaimed(scope string) bool {
+	if scope == OverloadReliefScopeSession {
+		return len(s.ReliefScenes) > 0
+	}
+
+	for _, sceneID := range s.ReliefScenes {
+		if sceneID == s.CurrentSceneID {
+			return true
+		}
+	}
+	return false
+}
+
+// ClaimOverloadRelief 记录新申请的过载解除
+func (s *GameState) ClaimOverloadRelief(claim *OverloadReliefClaim) {
+	s.OverloadRelief = claim
+	s.ReliefScenes = append(s.ReliefScenes, claim.SceneID)
+}
+
+// EnterScene 切换当前场景
+// 范围为场景的过载解除随之失效，回到原场景也不会恢复
+func (s *GameState) EnterScene(sceneID string) {
+	if relief := s.OverloadRelief; relief != nil && relief.Scope == OverloadReliefScopeScene && relief.SceneID != sceneID {
+		s.OverloadRelief = nil
+	}
+
+	s.CurrentSceneID = sceneID
 }
 
 // NPCState NPC状态
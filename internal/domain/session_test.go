@@ -0,0 +1,160 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newReliefSession(scope string) *GameSession {
+	return &GameSession{
+		ID:       "session-1",
+		AgentID:  "agent-1",
+		DiceSeed: 1,
+		State: &GameState{
+			CurrentSceneID: "scene-1",
+			OverloadRelief: &OverloadReliefClaim{
+				AgentID: "agent-1",
+				Scope:   scope,
+				SceneID: "scene-1",
+			},
+		},
+	}
+}
+
+// newReliefAgent 创建现实带有过载解除的角色
+func newReliefAgent(id string) *Agent {
+	return &Agent{
+		ID: id,
+		QA: map[string]int{QualityFocus: 0},
+		Reality: &Reality{
+			OverloadRelief: &OverloadRelief{Name: "过载解除", Condition: "条件"},
+		},
+	}
+}
+
+func TestGameSession_OverloadReliefScope(t *testing.T) {
+	agent := newReliefAgent("agent-1")
+
+	t.Run("roll范围只抵消一次过载", func(t *testing.T) {
+		session := newReliefSession(OverloadReliefScopeRoll)
+		assert.True(t, session.ConsumeOverloadRelief(agent))
+		assert.False(t, session.ConsumeOverloadRelief(agent))
+	})
+
+	t.Run("scene范围在离开场景后失效", func(t *testing.T) {
+		session := newReliefSession(OverloadReliefScopeScene)
+		assert.True(t, session.ConsumeOverloadRelief(agent))
+		assert.True(t, session.ConsumeOverloadRelief(agent))
+
+		session.State.CurrentSceneID = "scene-2"
+		assert.Nil(t, session.ActiveOverloadRelief(agent))
+		assert.False(t, session.ConsumeOverloadRelief(agent))
+	})
+
+	t.Run("session范围持续整个会话", func(t *testing.T) {
+		session := newReliefSession(OverloadReliefScopeSession)
+		session.State.CurrentSceneID = "scene-2"
+		assert.True(t, session.ConsumeOverloadRelief(agent))
+		assert.Equal(t, 1, session.State.OverloadRelief.Used)
+	})
+
+	t.Run("只对申请的角色生效", func(t *testing.T) {
+		session := newReliefSession(OverloadReliefScopeSession)
+		assert.Nil(t, session.ActiveOverloadRelief(newReliefAgent("agent-2")))
+	})
+
+	t.Run("现实没有过载解除时不生效", func(t *testing.T) {
+		session := newReliefSession(OverloadReliefScopeSession)
+		assert.Nil(t, session.ActiveOverloadRelief(&Agent{ID: "agent-1"}))
+		assert.False(t, session.ConsumeOverloadRelief(&Agent{ID: "agent-1"}))
+	})
+
+	t.Run("scene范围切换场景后失效，回到原场景也不恢复", func(t *testing.T) {
+		session := newReliefSession(OverloadReliefScopeScene)
+		session.State.EnterScene("scene-1")
+		assert.NotNil(t, session.ActiveOverloadRelief(agent))
+
+		session.State.EnterScene("scene-2")
+		session.State.EnterScene("scene-1")
+		assert.Nil(t, session.ActiveOverloadRelief(agent))
+	})
+}
+
+func TestGameState_OverloadReliefClaimed(t *testing.T) {
+	t.Run("scene范围每个场景只能申请一次", func(t *testing.T) {
+		state := &GameState{CurrentSceneID: "scene-1"}
+		assert.False(t, state.OverloadReliefClaimed(OverloadReliefScopeScene))
+
+		state.ClaimOverloadRelief(&OverloadReliefClaim{AgentID: "agent-1", Scope: OverloadReliefScopeScene, SceneID: "scene-1"})
+		assert.True(t, state.OverloadReliefClaimed(OverloadReliefScopeScene))
+
+		state.EnterScene("scene-2")
+		assert.False(t, state.OverloadReliefClaimed(OverloadReliefScopeScene))
+
+		state.EnterScene("scene-1")
+		assert.True(t, state.OverloadReliefClaimed(OverloadReliefScopeScene))
+	})
+
+	t.Run("session范围每个会话只能申请一次", func(t *testing.T) {
+		state := &GameState{CurrentSceneID: "scene-1"}
+		state.ClaimOverloadRelief(&OverloadReliefClaim{AgentID: "agent-1", Scope: OverloadReliefScopeSession, SceneID: "scene-1"})
+
+		state.EnterScene("scene-2")
+		assert.True(t, state.OverloadReliefClaimed(OverloadReliefScopeSession))
+		assert.NotNil(t, state.OverloadRelief, "session范围不随场景失效")
+	})
+}
+
+func TestDiceService_OverloadRelief(t *testing.T) {
+	agent := newReliefAgent("agent-1")
+
+	// 相同种子和位置得到相同骰子，便于比较有无过载解除的结果
+	relieved := newReliefSession(OverloadReliefScopeRoll)
+	plain := newReliefSession(OverloadReliefScopeRoll)
+	plain.State.OverloadRelief = nil
+
+	for i := 0; i < 20; i++ {
+		relieved.State.OverloadRelief.Used = 0
+
+		withRelief := NewDiceService().ForSession(relieved).RollForQuality(agent, QualityFocus)
+		without := NewDiceService().ForSession(plain).RollForQuality(agent, QualityFocus)
+		require.Equal(t, without.RawDice, withRelief.RawDice)
+
+		// 过载解除时骰子保持过载前的结果
+		assert.Equal(t, withRelief.RawDice, withRelief.Dice)
+		assert.Equal(t, 0, withRelief.Overload)
+		assert.Equal(t, 1, withRelief.OverloadRelieved)
+		assert.Equal(t, 1, without.Overload)
+		assert.Equal(t, 0, without.OverloadRelieved)
+		assert.GreaterOrEqual(t, withRelief.Threes, without.Threes)
+	}
+}
+
+func TestStoredSession_DiceSeed(t *testing.T) {
+	session := &GameSession{ID: "session-1", DiceSeed: 42, DiceCursor: 7, State: &GameState{ChaosPool: 2}}
+
+	// API响应不包含种子
+	data, err := json.Marshal(session)
+	require.NoError(t, err)
+	var public map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &public))
+	assert.NotContains(t, public, "dice_seed")
+	assert.Equal(t, float64(7), public["dice_cursor"])
+
+	// 持久化的会话保留种子
+	data, err = json.Marshal(StoredSession{GameSession: session})
+	require.NoError(t, err)
+	var restored StoredSession
+	require.NoError(t, json.Unmarshal(data, &restored))
+	require.NotNil(t, restored.GameSession)
+	assert.Equal(t, int64(42), restored.DiceSeed)
+	assert.Equal(t, int64(7), restored.DiceCursor)
+	assert.Equal(t, 2, restored.State.ChaosPool)
+
+	data, err = json.Marshal(StoredSession{})
+	require.NoError(t, err)
+	assert.Equal(t, "null", string(data))
+}
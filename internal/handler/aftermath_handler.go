@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/trpg-solo-engine/backend/internal/service"
+)
+
+type AftermathHandler struct {
+	aftermathService service.AftermathService
+}
+
+func NewAftermathHandler(aftermathService service.AftermathService) *AftermathHandler {
+	return &AftermathHandler{
+		aftermathService: aftermathService,
+	}
+}
+
+// ResolveAftermath 结算余波 POST /api/sessions/:id/aftermath
+func (h *AftermathHandler) ResolveAftermath(c *gin.Context) {
+	var req service.ResolveAftermathRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	report, err := h.aftermathService.Resolve(c.Param("id"), &req)
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    report,
+	})
+}
+
+// GetDebrief 查询任务报告 GET /api/sessions/:id/aftermath
+func (h *AftermathHandler) GetDebrief(c *gin.Context) {
+	report, err := h.aftermathService.GetDebrief(c.Param("id"))
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    report,
+	})
+}
+
+// bind 解析请求体，失败时返回400
+func (h *AftermathHandler) bind(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "请求参数无效: " + err.Error(),
+		})
+		return false
+	}
+	return true
+}
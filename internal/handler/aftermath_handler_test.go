@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+	"github.com/trpg-solo-engine/backend/internal/service"
+)
+
+func TestAftermathHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	agentService := service.NewAgentService()
+	scenarioService := service.NewScenarioService("../../scenarios")
+	gameService := service.NewGameServiceWithAgents(scenarioService, agentService, nil)
+	store := service.NewStoreService(agentService, gameService, scenarioService, nil)
+	aftermathService := service.NewAftermathService(agentService, gameService, scenarioService, store, service.NewPerformanceService(), service.NewQAService(domain.NewDiceService()))
+	aftermathHandler := NewAftermathHandler(aftermathService)
+
+	router := gin.New()
+	sessions := router.Group("/api/sessions")
+	{
+		sessions.POST("/:id/aftermath", aftermathHandler.ResolveAftermath)
+		sessions.GET("/:id/aftermath", aftermathHandler.GetDebrief)
+	}
+
+	agent, err := agentService.CreateAgent(&service.CreateAgentRequest{
+		Name:        "余波测试",
+		AnomalyType: domain.AnomalyWhisper,
+		RealityType: domain.RealityCaretaker,
+		CareerType:  domain.CareerPublicRelations,
+	})
+	require.NoError(t, err)
+	session, err := gameService.CreateSession(agent.ID, "eternal-spring")
+	require.NoError(t, err)
+	_, err = gameService.ChooseGoals(session.ID, []string{"minimal-exposure"})
+	require.NoError(t, err)
+	base := "/api/sessions/" + session.ID + "/aftermath"
+
+	do := func(method, path string, body interface{}) (*httptest.ResponseRecorder, map[string]interface{}) {
+		data, _ := json.Marshal(body)
+		req, _ := http.NewRequest(method, path, bytes.NewReader(data))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return w, response
+	}
+
+	// 不在余波阶段
+	w, _ := do("POST", base, map[string]interface{}{})
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	require.NoError(t, gameService.TransitionPhase(session.ID, domain.PhaseInvestigation))
+	require.NoError(t, gameService.TransitionPhase(session.ID, domain.PhaseEncounter))
+	require.NoError(t, gameService.UpdateState(session.ID, func(state *domain.GameState) error {
+		state.MissionOutcome = service.OutcomeCaptured
+		state.LooseEnds = 2
+		return nil
+	}))
+	require.NoError(t, gameService.TransitionPhase(session.ID, domain.PhaseAftermath))
+
+	w, _ = do("GET", base, nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w, _ = do("POST", base, map[string]interface{}{"completed_goals": []string{"save-serena"}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w, response := do("POST", base, map[string]interface{}{"completed_goals": []string{"minimal-exposure"}})
+	require.Equal(t, http.StatusOK, w.Code)
+	data := response["data"].(map[string]interface{})
+	assert.Equal(t, service.OutcomeCaptured, data["outcome"])
+	assert.Len(t, data["entries"], 2)
+	assert.Equal(t, float64(2), data["loose_ends"])
+
+	w, _ = do("POST", base, map[string]interface{}{})
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w, response = do("GET", base, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, service.OutcomeCaptured, response["data"].(map[string]interface{})["outcome"])
+
+	w, _ = do("GET", "/api/sessions/non-existent/aftermath", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/trpg-solo-engine/backend/internal/service"
+)
+
+type AgentBundleHandler struct {
+	bundleService service.AgentBundleService
+}
+
+func NewAgentBundleHandler(bundleService service.AgentBundleService) *AgentBundleHandler {
+	return &AgentBundleHandler{
+		bundleService: bundleService,
+	}
+}
+
+// Export 导出角色包 GET /api/agents/:id/export
+// 响应体就是角色包本身，可以直接保存为文件再导入
+func (h *AgentBundleHandler) Export(c *gin.Context) {
+	bundle, err := h.bundleService.Export(c.Param("id"))
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="agent-`+bundle.Agent.ID+`.json"`)
+	c.JSON(http.StatusOK, bundle)
+}
+
+// Import 导入角色包 POST /api/agents/import?on_conflict=rename|reject
+// 请求体为导出的角色包
+func (h *AgentBundleHandler) Import(c *gin.Context) {
+	var bundle service.AgentBundle
+	if err := c.ShouldBindJSON(&bundle); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "请求参数无效: " + err.Error(),
+		})
+		return
+	}
+
+	result, err := h.bundleService.Import(&bundle, &service.ImportOptions{
+		OnConflict: c.Query("on_conflict"),
+	})
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
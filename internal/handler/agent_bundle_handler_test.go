@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+	"github.com/trpg-solo-engine/backend/internal/service"
+)
+
+func TestAgentBundleHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	agentService := service.NewAgentService()
+	bundleHandler := NewAgentBundleHandler(service.NewAgentBundleService(agentService, []byte("test-signing-key")))
+
+	router := gin.New()
+	router.GET("/api/agents/:id/export", bundleHandler.Export)
+	router.POST("/api/agents/import", bundleHandler.Import)
+
+	agent, err := agentService.CreateAgent(&service.CreateAgentRequest{
+		Name:        "迁移测试",
+		AnomalyType: domain.AnomalyWhisper,
+		RealityType: domain.RealityCaretaker,
+		CareerType:  domain.CareerPublicRelations,
+	})
+	require.NoError(t, err)
+
+	req, _ := http.NewRequest("GET", "/api/agents/"+agent.ID+"/export", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Disposition"), agent.ID)
+	exported := w.Body.Bytes()
+
+	post := func(path string, body []byte) (*httptest.ResponseRecorder, map[string]interface{}) {
+		req, _ := http.NewRequest("POST", path, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return w, response
+	}
+
+	w, response := post("/api/agents/import", exported)
+	require.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, true, response["data"].(map[string]interface{})["renamed"])
+
+	w, _ = post("/api/agents/import?on_conflict=reject", exported)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	// 篡改后的角色包
+	var bundle map[string]interface{}
+	require.NoError(t, json.Unmarshal(exported, &bundle))
+	bundle["agent"].(map[string]interface{})["commendations"] = 99
+	tampered, _ := json.Marshal(bundle)
+	w, response = post("/api/agents/import", tampered)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, response["error"], "校验和")
+
+	req, _ = http.NewRequest("GET", "/api/agents/missing/export", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+	"github.com/trpg-solo-engine/backend/internal/service"
+)
+
+type AgentDraftHandler struct {
+	draftService service.AgentDraftService
+}
+
+func NewAgentDraftHandler(draftService service.AgentDraftService) *AgentDraftHandler {
+	return &AgentDraftHandler{
+		draftService: draftService,
+	}
+}
+
+// CreateDraft 开始角色创建向导 POST /api/agents/drafts
+func (h *AgentDraftHandler) CreateDraft(c *gin.Context) {
+	var req service.CreateDraftRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	draft, err := h.draftService.CreateDraft(&req)
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    draft,
+	})
+}
+
+// GetDraft 获取草稿 GET /api/agents/drafts/:id
+func (h *AgentDraftHandler) GetDraft(c *gin.Context) {
+	draft, err := h.draftService.GetDraft(c.Param("id"))
+	h.respondDraft(c, draft, err)
+}
+
+// ChooseAnomaly 选择异常体 PUT /api/agents/drafts/:id/anomaly
+func (h *AgentDraftHandler) ChooseAnomaly(c *gin.Context) {
+	var req service.ChooseAnomalyRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	draft, err := h.draftService.ChooseAnomaly(c.Param("id"), &req)
+	h.respondDraft(c, draft, err)
+}
+
+// AnswerAssessment 选择职能并回答评估问题 PUT /api/agents/drafts/:id/career
+func (h *AgentDraftHandler) AnswerAssessment(c *gin.Context) {
+	var req service.AnswerAssessmentRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	draft, err := h.draftService.AnswerAssessment(c.Param("id"), &req)
+	h.respondDraft(c, draft, err)
+}
+
+// ChooseReality 选择现实 PUT /api/agents/drafts/:id/reality
+func (h *AgentDraftHandler) ChooseReality(c *gin.Context) {
+	var req service.ChooseRealityRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	draft, err := h.draftService.ChooseReality(c.Param("id"), &req)
+	h.respondDraft(c, draft, err)
+}
+
+// DistributeConnection 分配人际关系连结 PUT /api/agents/drafts/:id/relationships
+func (h *AgentDraftHandler) DistributeConnection(c *gin.Context) {
+	var req service.DistributeConnectionRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	draft, err := h.draftService.DistributeConnection(c.Param("id"), &req)
+	h.respondDraft(c, draft, err)
+}
+
+// Finalize 确认草稿并生成角色 POST /api/agents/drafts/:id/finalize
+func (h *AgentDraftHandler) Finalize(c *gin.Context) {
+	agent, err := h.draftService.Finalize(c.Param("id"))
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    agent,
+	})
+}
+
+// bind 解析请求体
+func (h *AgentDraftHandler) bind(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "请求参数无效: " + err.Error(),
+		})
+		return false
+	}
+	return true
+}
+
+// respondDraft 返回草稿
+func (h *AgentDraftHandler) respondDraft(c *gin.Context, draft *domain.AgentDraft, err error) {
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    draft,
+	})
+}
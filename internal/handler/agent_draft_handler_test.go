@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trpg-solo-engine/backend/internal/service"
+)
+
+func setupAgentDraftTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	agentService := service.NewAgentService()
+	agentHandler := NewAgentHandler(agentService)
+	draftHandler := NewAgentDraftHandler(service.NewAgentDraftService(agentService, nil))
+
+	agents := router.Group("/api/agents")
+	{
+		agents.GET("/:id", agentHandler.GetAgent)
+		agents.POST("/drafts", draftHandler.CreateDraft)
+		agents.GET("/drafts/:id", draftHandler.GetDraft)
+		agents.PUT("/drafts/:id/anomaly", draftHandler.ChooseAnomaly)
+		agents.PUT("/drafts/:id/career", draftHandler.AnswerAssessment)
+		agents.PUT("/drafts/:id/reality", draftHandler.ChooseReality)
+		agents.PUT("/drafts/:id/relationships", draftHandler.DistributeConnection)
+		agents.POST("/drafts/:id/finalize", draftHandler.Finalize)
+	}
+
+	return router
+}
+
+func doDraftRequest(router *gin.Engine, method, path string, body interface{}) (*httptest.ResponseRecorder, map[string]interface{}) {
+	var reader *bytes.Reader
+	if body != nil {
+		data, _ := json.Marshal(body)
+		reader = bytes.NewReader(data)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req, _ := http.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	return w, response
+}
+
+func TestAgentDraftHandler_Wizard(t *testing.T) {
+	router := setupAgentDraftTestRouter()
+
+	w, response := doDraftRequest(router, "POST", "/api/agents/drafts", gin.H{"name": "向导特工"})
+	require.Equal(t, http.StatusCreated, w.Code)
+	draftID := response["data"].(map[string]interface{})["id"].(string)
+	base := "/api/agents/drafts/" + draftID
+
+	// 跳过步骤返回409
+	w, _ = doDraftRequest(router, "POST", base+"/finalize", nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w, _ = doDraftRequest(router, "PUT", base+"/anomaly", gin.H{"anomaly_type": "catalog"})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	// 步骤校验错误返回400并说明所属步骤
+	w, response = doDraftRequest(router, "PUT", base+"/career", gin.H{"career_type": "barista", "answers": []string{"只有一个"}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "career", response["details"].(map[string]interface{})["step"])
+
+	w, _ = doDraftRequest(router, "PUT", base+"/career", gin.H{"career_type": "barista", "answers": []string{"一", "二", "三"}})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w, _ = doDraftRequest(router, "PUT", base+"/reality", gin.H{"reality_type": "pillar"})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w, response = doDraftRequest(router, "PUT", base+"/relationships", gin.H{"relationships": []gin.H{
+		{"name": "副手", "connection": 5},
+		{"name": "对手", "connection": 4},
+		{"name": "导师", "connection": 3},
+	}})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "finalize", response["data"].(map[string]interface{})["step"])
+
+	w, response = doDraftRequest(router, "POST", base+"/finalize", nil)
+	require.Equal(t, http.StatusCreated, w.Code)
+	agentID := response["data"].(map[string]interface{})["id"].(string)
+
+	w, _ = doDraftRequest(router, "GET", "/api/agents/"+agentID, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w, response = doDraftRequest(router, "GET", base, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "completed", response["data"].(map[string]interface{})["step"])
+
+	w, _ = doDraftRequest(router, "GET", "/api/agents/drafts/non-existent", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -1,11 +1,14 @@
 package handler
 
 import (
+	"bytes"
+	"encoding/json"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/trpg-solo-engine/backend/internal/domain"
 	"github.com/trpg-solo-engine/backend/internal/service"
+	"github.com/trpg-solo-engine/backend/internal/sheet"
 )
 
 type AgentHandler struct {
@@ -31,21 +34,7 @@ func (h *AgentHandler) CreateAgent(c *gin.Context) {
 
 	agent, err := h.agentService.CreateAgent(&req)
 	if err != nil {
-		// 根据错误类型返回不同的状态码
-		if gameErr, ok := err.(*domain.GameError); ok {
-			switch gameErr.Code {
-			case domain.ErrInvalidARC, domain.ErrInvalidInput:
-				c.JSON(http.StatusBadRequest, gin.H{
-					"success": false,
-					"error":   err.Error(),
-				})
-				return
-			}
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+		respondGameError(c, err)
 		return
 	}
 
@@ -61,19 +50,7 @@ func (h *AgentHandler) GetAgent(c *gin.Context) {
 
 	agent, err := h.agentService.GetAgent(agentID)
 	if err != nil {
-		if gameErr, ok := err.(*domain.GameError); ok {
-			if gameErr.Code == domain.ErrNotFound {
-				c.JSON(http.StatusNotFound, gin.H{
-					"success": false,
-					"error":   err.Error(),
-				})
-				return
-			}
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+		respondGameError(c, err)
 		return
 	}
 
@@ -83,6 +60,49 @@ func (h *AgentHandler) GetAgent(c *gin.Context) {
 	})
 }
 
+// GetSheet 导出可打印的角色卡 GET /api/agents/:id/sheet?format=md|html|json
+// 默认导出Markdown
+func (h *AgentHandler) GetSheet(c *gin.Context) {
+	format := c.DefaultQuery("format", sheet.FormatMarkdown)
+	if !sheet.IsValidFormat(format) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "不支持的角色卡格式: " + format,
+		})
+		return
+	}
+
+	agent, err := h.agentService.GetAgent(c.Param("id"))
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	if format == sheet.FormatJSON {
+		c.JSON(http.StatusOK, gin.H{
+			"success": true,
+			"data":    sheet.Build(agent),
+		})
+		return
+	}
+
+	// 先渲染到缓冲区，模板出错时还能返回JSON错误
+	var buf bytes.Buffer
+	if err := sheet.Render(&buf, agent, format); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	contentType := "text/markdown; charset=utf-8"
+	if format == sheet.FormatHTML {
+		contentType = "text/html; charset=utf-8"
+	}
+	c.Data(http.StatusOK, contentType, buf.Bytes())
+}
+
 // UpdateAgent 更新角色 PUT /api/agents/:id
 func (h *AgentHandler) UpdateAgent(c *gin.Context) {
 	agentID := c.Param("id")
@@ -90,30 +110,20 @@ func (h *AgentHandler) UpdateAgent(c *gin.Context) {
 	// 先获取现有角色
 	agent, err := h.agentService.GetAgent(agentID)
 	if err != nil {
-		if gameErr, ok := err.(*domain.GameError); ok {
-			if gameErr.Code == domain.ErrNotFound {
-				c.JSON(http.StatusNotFound, gin.H{
-					"success": false,
-					"error":   err.Error(),
-				})
-				return
-			}
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+		respondGameError(c, err)
 		return
 	}
 
 	// 绑定更新请求
+	// 人际关系和QA只作检查：连结变化必须经过人际关系接口记录理由和历史，QA只能由掷骰花费和任务结算恢复
 	var updateReq struct {
-		Name          *string                `json:"name"`
-		Pronouns      *string                `json:"pronouns"`
-		Relationships []*domain.Relationship `json:"relationships"`
-		QA            map[string]int         `json:"qa"`
-		Commendations *int                   `json:"commendations"`
-		Reprimands    *int                   `json:"reprimands"`
+		Name          *string         `json:"name"`
+		Pronouns      *string         `json:"pronouns"`
+		Relationships json.RawMessage `json:"relationships"`
+		QA            json.RawMessage `json:"qa"`
+		Commendations *int            `json:"commendations"`
+		Reprimands    *int            `json:"reprimands"`
+		Justification string          `json:"justification"` // 修改嘉奖/申诫的理由
 	}
 
 	if err := c.ShouldBindJSON(&updateReq); err != nil {
@@ -124,6 +134,18 @@ func (h *AgentHandler) UpdateAgent(c *gin.Context) {
 		return
 	}
 
+	if updateReq.Relationships != nil {
+		respondGameError(c, domain.NewGameError(domain.ErrInvalidInput, "不能直接替换人际关系，请使用人际关系接口").
+			WithDetails("field", "relationships").
+			WithDetails("endpoint", "/api/agents/"+agentID+"/relationships"))
+		return
+	}
+	if updateReq.QA != nil {
+		respondGameError(c, domain.NewGameError(domain.ErrInvalidInput, "不能直接修改资质保证").
+			WithDetails("field", "qa"))
+		return
+	}
+
 	// 更新字段
 	if updateReq.Name != nil {
 		agent.Name = *updateReq.Name
@@ -131,41 +153,28 @@ func (h *AgentHandler) UpdateAgent(c *gin.Context) {
 	if updateReq.Pronouns != nil {
 		agent.Pronouns = *updateReq.Pronouns
 	}
-	if updateReq.Relationships != nil {
-		agent.Relationships = updateReq.Relationships
-	}
-	if updateReq.QA != nil {
-		agent.QA = updateReq.QA
+
+	// 嘉奖/申诫的修改记为账本调整条目
+	adjustment := &domain.LedgerEntry{
+		Reason:        domain.LedgerReasonAdjustment,
+		Justification: updateReq.Justification,
 	}
 	if updateReq.Commendations != nil {
-		agent.Commendations = *updateReq.Commendations
+		adjustment.Commendations = *updateReq.Commendations - agent.Commendations
 	}
 	if updateReq.Reprimands != nil {
-		agent.Reprimands = *updateReq.Reprimands
+		adjustment.Reprimands = *updateReq.Reprimands - agent.Reprimands
+	}
+	if adjustment.Commendations != 0 || adjustment.Reprimands != 0 {
+		if adjustment.Justification == "" {
+			adjustment.Justification = "手动修改"
+		}
+		agent.RecordLedger(adjustment)
 	}
 
 	// 保存更新
 	if err := h.agentService.UpdateAgent(agent); err != nil {
-		if gameErr, ok := err.(*domain.GameError); ok {
-			switch gameErr.Code {
-			case domain.ErrInvalidARC, domain.ErrInvalidInput:
-				c.JSON(http.StatusBadRequest, gin.H{
-					"success": false,
-					"error":   err.Error(),
-				})
-				return
-			case domain.ErrNotFound:
-				c.JSON(http.StatusNotFound, gin.H{
-					"success": false,
-					"error":   err.Error(),
-				})
-				return
-			}
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+		respondGameError(c, err)
 		return
 	}
 
@@ -180,19 +189,7 @@ func (h *AgentHandler) DeleteAgent(c *gin.Context) {
 	agentID := c.Param("id")
 
 	if err := h.agentService.DeleteAgent(agentID); err != nil {
-		if gameErr, ok := err.(*domain.GameError); ok {
-			if gameErr.Code == domain.ErrNotFound {
-				c.JSON(http.StatusNotFound, gin.H{
-					"success": false,
-					"error":   err.Error(),
-				})
-				return
-			}
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+		respondGameError(c, err)
 		return
 	}
 
@@ -34,6 +34,7 @@ func setupTestRouter() (*gin.Engine, *AgentHandler) {
 			agents.GET("/:id", handler.GetAgent)
 			agents.PUT("/:id", handler.UpdateAgent)
 			agents.DELETE("/:id", handler.DeleteAgent)
+			agents.GET("/:id/sheet", handler.GetSheet)
 		}
 	}
 
@@ -236,6 +237,24 @@ func TestAgentHandler_UpdateAgent(t *testing.T) {
 			expectedStatus: http.StatusOK,
 			expectSuccess:  true,
 		},
+		{
+			name:    "不能直接替换人际关系",
+			agentID: agentID,
+			updateData: map[string]interface{}{
+				"relationships": []interface{}{},
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectSuccess:  false,
+		},
+		{
+			name:    "不能直接修改资质保证",
+			agentID: agentID,
+			updateData: map[string]interface{}{
+				"qa": map[string]int{"focus": 3},
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectSuccess:  false,
+		},
 		{
 			name:    "更新不存在的角色",
 			agentID: "non-existent-id",
@@ -522,3 +541,67 @@ func TestAgentHandler_ErrorResponses(t *testing.T) {
 		})
 	}
 }
+
+// TestAgentHandler_GetSheet 测试导出角色卡
+func TestAgentHandler_GetSheet(t *testing.T) {
+	router, _ := setupTestRouter()
+
+	createReq := service.CreateAgentRequest{
+		Name:        "<b>测试特工</b>",
+		AnomalyType: domain.AnomalyWhisper,
+		RealityType: domain.RealityCaretaker,
+		CareerType:  domain.CareerPublicRelations,
+	}
+	body, _ := json.Marshal(createReq)
+	req, _ := http.NewRequest("POST", "/api/agents", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var createResponse map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &createResponse)
+	agentID := createResponse["data"].(map[string]interface{})["id"].(string)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("默认导出Markdown", func(t *testing.T) {
+		w := get("/api/agents/" + agentID + "/sheet")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "text/markdown")
+		assert.Contains(t, w.Body.String(), "# <b>测试特工</b>")
+		assert.Contains(t, w.Body.String(), "| 共情 |")
+	})
+
+	t.Run("HTML转义角色文本", func(t *testing.T) {
+		w := get("/api/agents/" + agentID + "/sheet?format=html")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "text/html")
+		assert.Contains(t, w.Body.String(), "&lt;b&gt;测试特工&lt;/b&gt;")
+		assert.Contains(t, w.Body.String(), "公关应急包")
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		w := get("/api/agents/" + agentID + "/sheet?format=json")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		data := response["data"].(map[string]interface{})
+		assert.Len(t, data["qa"], len(domain.AllQualities))
+	})
+
+	t.Run("不支持的格式", func(t *testing.T) {
+		w := get("/api/agents/" + agentID + "/sheet?format=pdf")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("角色不存在", func(t *testing.T) {
+		w := get("/api/agents/non-existent-id/sheet")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/trpg-solo-engine/backend/internal/catalog"
+)
+
+// ArcHandler ARC目录只读浏览
+type ArcHandler struct {
+	catalog *catalog.Catalog
+}
+
+func NewArcHandler(arc *catalog.Catalog) *ArcHandler {
+	return &ArcHandler{
+		catalog: arc,
+	}
+}
+
+// ListAnomalies 列出所有异常体 GET /api/arc/anomalies
+func (h *ArcHandler) ListAnomalies(c *gin.Context) {
+	details, err := h.catalog.AnomalyDetails()
+	h.respond(c, details, err)
+}
+
+// GetAnomaly 获取异常体详情 GET /api/arc/anomalies/:id
+func (h *ArcHandler) GetAnomaly(c *gin.Context) {
+	detail, err := h.catalog.AnomalyDetail(c.Param("id"))
+	h.respond(c, detail, err)
+}
+
+// ListRealities 列出所有现实 GET /api/arc/realities
+func (h *ArcHandler) ListRealities(c *gin.Context) {
+	details, err := h.catalog.RealityDetails()
+	h.respond(c, details, err)
+}
+
+// GetReality 获取现实详情 GET /api/arc/realities/:id
+func (h *ArcHandler) GetReality(c *gin.Context) {
+	detail, err := h.catalog.RealityDetail(c.Param("id"))
+	h.respond(c, detail, err)
+}
+
+// ListCareers 列出所有职能 GET /api/arc/careers
+func (h *ArcHandler) ListCareers(c *gin.Context) {
+	details, err := h.catalog.CareerDetails()
+	h.respond(c, details, err)
+}
+
+// GetCareer 获取职能详情 GET /api/arc/careers/:id
+func (h *ArcHandler) GetCareer(c *gin.Context) {
+	detail, err := h.catalog.CareerDetail(c.Param("id"))
+	h.respond(c, detail, err)
+}
+
+// respond 返回目录数据并附带ETag
+// 目录在启动后不再变化，响应体的哈希即可作为强ETag；客户端携带匹配的If-None-Match时返回304
+func (h *ArcHandler) respond(c *gin.Context, data interface{}, err error) {
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	body, err := json.Marshal(gin.H{
+		"success": true,
+		"data":    data,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	etag := fmt.Sprintf(`"%x"`, sha256.Sum256(body))
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "no-cache")
+
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+}
+
+// etagMatches 检查If-None-Match是否包含给定ETag（按弱比较，支持列表和"*"）
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
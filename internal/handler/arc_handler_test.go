@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trpg-solo-engine/backend/internal/catalog"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+)
+
+func setupArcTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	arcHandler := NewArcHandler(catalog.Default())
+	arc := router.Group("/api/arc")
+	{
+		arc.GET("/anomalies", arcHandler.ListAnomalies)
+		arc.GET("/anomalies/:id", arcHandler.GetAnomaly)
+		arc.GET("/realities", arcHandler.ListRealities)
+		arc.GET("/realities/:id", arcHandler.GetReality)
+		arc.GET("/careers", arcHandler.ListCareers)
+		arc.GET("/careers/:id", arcHandler.GetCareer)
+	}
+
+	return router
+}
+
+// TestArcHandler_List 测试目录列表
+func TestArcHandler_List(t *testing.T) {
+	router := setupArcTestRouter()
+
+	for _, path := range []string{"/api/arc/anomalies", "/api/arc/realities", "/api/arc/careers"} {
+		t.Run(path, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", path, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.NotEmpty(t, w.Header().Get("ETag"))
+
+			var response struct {
+				Success bool              `json:"success"`
+				Data    []json.RawMessage `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.True(t, response.Success)
+			assert.Len(t, response.Data, 9)
+		})
+	}
+}
+
+// TestArcHandler_Detail 测试按ID获取详情，形状与domain类型一致
+func TestArcHandler_Detail(t *testing.T) {
+	router := setupArcTestRouter()
+
+	req, _ := http.NewRequest("GET", "/api/arc/anomalies/whisper", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var anomaly struct {
+		Data struct {
+			ID          string `json:"id"`
+			Description string `json:"description"`
+			domain.Anomaly
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &anomaly))
+	assert.Equal(t, "whisper", anomaly.Data.ID)
+	assert.Equal(t, domain.AnomalyWhisper, anomaly.Data.Type)
+	assert.Len(t, anomaly.Data.Abilities, 3)
+
+	req, _ = http.NewRequest("GET", "/api/arc/realities/caretaker", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var reality struct {
+		Data struct {
+			domain.Reality
+			RelationshipRules catalog.RelationshipRules `json:"relationship_rules"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &reality))
+	assert.Equal(t, domain.RealityCaretaker, reality.Data.Type)
+	assert.Equal(t, "独立", reality.Data.DegradationTrack.Name)
+	assert.Len(t, reality.Data.RelationshipRules.Questions, 3)
+
+	// 也可以用类型名称查询
+	req, _ = http.NewRequest("GET", "/api/arc/careers/"+domain.CareerCEO, nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	req, _ = http.NewRequest("GET", "/api/arc/careers/non-existent", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+// TestArcHandler_ETag 测试条件请求
+func TestArcHandler_ETag(t *testing.T) {
+	router := setupArcTestRouter()
+
+	req, _ := http.NewRequest("GET", "/api/arc/careers", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+
+	req, _ = http.NewRequest("GET", "/api/arc/careers", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.Bytes())
+
+	// 不同资源的ETag不同
+	req, _ = http.NewRequest("GET", "/api/arc/realities", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEqual(t, etag, w.Header().Get("ETag"))
+}
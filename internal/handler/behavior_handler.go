@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/trpg-solo-engine/backend/internal/service"
+)
+
+type BehaviorHandler struct {
+	behaviorService service.BehaviorService
+}
+
+func NewBehaviorHandler(behaviorService service.BehaviorService) *BehaviorHandler {
+	return &BehaviorHandler{
+		behaviorService: behaviorService,
+	}
+}
+
+// ClaimBehavior 申报许可行为 POST /api/sessions/:id/behaviors/claim
+func (h *BehaviorHandler) ClaimBehavior(c *gin.Context) {
+	var req service.ClaimBehaviorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "请求参数无效: " + err.Error(),
+		})
+		return
+	}
+
+	entry, err := h.behaviorService.ClaimBehavior(c.Param("id"), &req)
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    entry,
+	})
+}
+
+// FlagViolation 标记违反首要指令 POST /api/sessions/:id/behaviors/violation
+func (h *BehaviorHandler) FlagViolation(c *gin.Context) {
+	var req service.FlagViolationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "请求参数无效: " + err.Error(),
+		})
+		return
+	}
+
+	entry, err := h.behaviorService.FlagViolation(c.Param("id"), &req)
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    entry,
+	})
+}
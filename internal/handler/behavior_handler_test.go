@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+	"github.com/trpg-solo-engine/backend/internal/service"
+)
+
+func TestBehaviorHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	agentService := service.NewAgentService()
+	gameService := service.NewGameServiceWithAgents(nil, agentService, nil)
+	behaviors := service.NewBehaviorService(agentService, gameService, service.NewPerformanceService())
+	behaviorHandler := NewBehaviorHandler(behaviors)
+
+	router := gin.New()
+	sessions := router.Group("/api/sessions")
+	{
+		sessions.POST("/:id/behaviors/claim", behaviorHandler.ClaimBehavior)
+		sessions.POST("/:id/behaviors/violation", behaviorHandler.FlagViolation)
+	}
+
+	agent, err := agentService.CreateAgent(&service.CreateAgentRequest{
+		Name:        "行为测试",
+		AnomalyType: domain.AnomalyWhisper,
+		RealityType: domain.RealityCaretaker,
+		CareerType:  domain.CareerPublicRelations,
+	})
+	require.NoError(t, err)
+	session, err := gameService.CreateSession(agent.ID, "eternal-spring")
+	require.NoError(t, err)
+	base := "/api/sessions/" + session.ID
+
+	do := func(path string, body interface{}) (*httptest.ResponseRecorder, map[string]interface{}) {
+		data, _ := json.Marshal(body)
+		req, _ := http.NewRequest("POST", path, bytes.NewReader(data))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return w, response
+	}
+
+	w, _ := do(base+"/behaviors/claim", map[string]string{"behavior": "说服他人相信一切正常"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w, response := do(base+"/behaviors/claim", map[string]string{
+		"behavior":      "飞上天",
+		"justification": "想飞",
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.NotNil(t, response["details"])
+
+	w, response = do(base+"/behaviors/claim", map[string]string{
+		"behavior":      "说服他人相信一切正常",
+		"justification": "安抚了惊慌的店主",
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+	data := response["data"].(map[string]interface{})
+	assert.Equal(t, "permitted_behavior", data["reason"])
+	assert.Equal(t, float64(1), data["commendations"])
+
+	w, _ = do(base+"/behaviors/claim", map[string]string{
+		"behavior":      "说服他人相信一切正常",
+		"justification": "又安抚了一次",
+	})
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w, response = do(base+"/behaviors/violation", map[string]string{
+		"justification": "对记者承认了机构的存在",
+		"flagged_by":    "ai",
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+	data = response["data"].(map[string]interface{})
+	assert.Equal(t, "directive_violation", data["reason"])
+	assert.Equal(t, float64(3), data["reprimands"])
+
+	w, _ = do("/api/sessions/missing/behaviors/violation", map[string]string{"justification": "无"})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	stored, err := agentService.GetAgent(agent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stored.Commendations)
+	assert.Equal(t, 3, stored.Reprimands)
+	assert.Len(t, stored.Ledger, 2)
+}
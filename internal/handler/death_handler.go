@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/trpg-solo-engine/backend/internal/service"
+)
+
+type DeathHandler struct {
+	deathService service.DeathService
+}
+
+func NewDeathHandler(deathService service.DeathService) *DeathHandler {
+	return &DeathHandler{
+		deathService: deathService,
+	}
+}
+
+// RecordDeath 记录死亡 POST /api/agents/:id/death
+func (h *DeathHandler) RecordDeath(c *gin.Context) {
+	var req service.RecordDeathRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	record, err := h.deathService.RecordDeath(c.Param("id"), &req)
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    record,
+	})
+}
+
+// Revive 花费嘉奖复活 POST /api/agents/:id/revive
+func (h *DeathHandler) Revive(c *gin.Context) {
+	result, err := h.deathService.Revive(c.Param("id"))
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
+
+// CreateSuccessor 创建继任者 POST /api/agents/:id/successor
+func (h *DeathHandler) CreateSuccessor(c *gin.Context) {
+	var req service.CreateSuccessorRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	result, err := h.deathService.CreateSuccessor(c.Param("id"), &req)
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
+
+// ListMemorial 纪念名单 GET /api/agents/memorial
+func (h *DeathHandler) ListMemorial(c *gin.Context) {
+	entries, err := h.deathService.ListMemorial()
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    entries,
+	})
+}
+
+// bind 解析请求体，失败时返回400
+func (h *DeathHandler) bind(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "请求参数无效: " + err.Error(),
+		})
+		return false
+	}
+	return true
+}
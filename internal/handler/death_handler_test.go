@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+	"github.com/trpg-solo-engine/backend/internal/service"
+)
+
+func TestDeathHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	agentService := service.NewAgentService()
+	gameService := service.NewGameServiceWithAgents(nil, agentService, nil)
+	deathHandler := NewDeathHandler(service.NewDeathService(agentService, gameService, nil))
+
+	router := gin.New()
+	agents := router.Group("/api/agents")
+	{
+		agents.GET("/memorial", deathHandler.ListMemorial)
+		agents.POST("/:id/death", deathHandler.RecordDeath)
+		agents.POST("/:id/revive", deathHandler.Revive)
+		agents.POST("/:id/successor", deathHandler.CreateSuccessor)
+	}
+
+	agent, err := agentService.CreateAgent(&service.CreateAgentRequest{
+		Name:        "殉职测试",
+		AnomalyType: domain.AnomalyWhisper,
+		RealityType: domain.RealityCaretaker,
+		CareerType:  domain.CareerPublicRelations,
+	})
+	require.NoError(t, err)
+	base := "/api/agents/" + agent.ID
+
+	do := func(method, path string, body interface{}) (*httptest.ResponseRecorder, map[string]interface{}) {
+		data, _ := json.Marshal(body)
+		req, _ := http.NewRequest(method, path, bytes.NewReader(data))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return w, response
+	}
+
+	// 缺少死亡原因
+	w, _ := do("POST", base+"/death", map[string]interface{}{})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w, response := do("POST", base+"/death", map[string]interface{}{"cause": "被异常体吞噬", "loose_ends": 1})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "被异常体吞噬", response["data"].(map[string]interface{})["cause"])
+
+	// 重复记录死亡
+	w, _ = do("POST", base+"/death", map[string]interface{}{"cause": "再次死亡"})
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w, response = do("GET", "/api/agents/memorial", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, response["data"], 1)
+
+	// 没有嘉奖时无法复活
+	w, _ = do("POST", base+"/revive", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w, response = do("POST", base+"/successor", map[string]interface{}{
+		"name":                  "继任者",
+		"anomaly_type":          domain.AnomalyWhisper,
+		"reality_type":          domain.RealityCaretaker,
+		"career_type":           domain.CareerPublicRelations,
+		"inherit_relationships": []string{agent.Relationships[0].ID},
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+	data := response["data"].(map[string]interface{})
+	assert.Equal(t, agent.ID, data["predecessor_id"])
+	assert.Len(t, data["inherited_relationships"], 1)
+
+	// 已有继任者
+	w, _ = do("POST", base+"/successor", map[string]interface{}{
+		"name":         "第二位继任者",
+		"anomaly_type": domain.AnomalyWhisper,
+		"reality_type": domain.RealityCaretaker,
+		"career_type":  domain.CareerPublicRelations,
+	})
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w, _ = do("POST", "/api/agents/non-existent/revive", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/trpg-solo-engine/backend/internal/service"
+)
+
+type DegradationHandler struct {
+	degradationService service.DegradationService
+	agentService       service.AgentService
+}
+
+func NewDegradationHandler(degradationService service.DegradationService, agentService service.AgentService) *DegradationHandler {
+	return &DegradationHandler{
+		degradationService: degradationService,
+		agentService:       agentService,
+	}
+}
+
+// MarkDegradation 标记退化格 POST /api/agents/:id/degradation
+func (h *DegradationHandler) MarkDegradation(c *gin.Context) {
+	var req service.MarkDegradationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "请求参数无效: " + err.Error(),
+		})
+		return
+	}
+
+	status, err := h.degradationService.MarkDegradation(c.Param("id"), &req)
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    status,
+	})
+}
+
+// GetDegradation 查询退化轨道和历史 GET /api/agents/:id/degradation
+func (h *DegradationHandler) GetDegradation(c *gin.Context) {
+	status, err := h.degradationService.GetDegradation(c.Param("id"))
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    status,
+	})
+}
+
+// SetReality 选择新现实 PUT /api/agents/:id/reality
+// 退化轨道填满后通过此接口解除待选状态
+func (h *DegradationHandler) SetReality(c *gin.Context) {
+	var req struct {
+		RealityType string `json:"reality_type" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "请求参数无效: " + err.Error(),
+		})
+		return
+	}
+
+	agentID := c.Param("id")
+	if err := h.agentService.SetReality(agentID, req.RealityType); err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	status, err := h.degradationService.GetDegradation(agentID)
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    status,
+	})
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+	"github.com/trpg-solo-engine/backend/internal/service"
+)
+
+func TestDegradationHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	agentService := service.NewAgentService()
+	gameService := service.NewGameServiceWithAgents(nil, agentService, nil)
+	degradationHandler := NewDegradationHandler(service.NewDegradationService(agentService, nil), agentService)
+	sessionHandler := NewSessionHandler(gameService)
+
+	router.GET("/api/agents/:id/degradation", degradationHandler.GetDegradation)
+	router.POST("/api/agents/:id/degradation", degradationHandler.MarkDegradation)
+	router.PUT("/api/agents/:id/reality", degradationHandler.SetReality)
+	router.POST("/api/sessions", sessionHandler.CreateSession)
+
+	agent, err := agentService.CreateAgent(&service.CreateAgentRequest{
+		Name:        "退化测试",
+		AnomalyType: domain.AnomalyGun,
+		RealityType: domain.RealityHunted,
+		CareerType:  domain.CareerIntern,
+	})
+	require.NoError(t, err)
+
+	do := func(method, path string, body interface{}) *httptest.ResponseRecorder {
+		data, _ := json.Marshal(body)
+		req, _ := http.NewRequest(method, path, bytes.NewReader(data))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	base := "/api/agents/" + agent.ID
+
+	w := do("POST", base+"/degradation", gin.H{"boxes": 1})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = do("POST", base+"/degradation", gin.H{"boxes": 4, "reason": "被老同学认了出来"})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Data service.DegradationStatus `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.True(t, response.Data.PendingRealityChange)
+
+	w = do("POST", "/api/sessions", gin.H{"agent_id": agent.ID, "scenario_id": "eternal-spring"})
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = do("POST", base+"/degradation", gin.H{"reason": "又一次暴露"})
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = do("PUT", base+"/reality", gin.H{"reality_type": domain.RealityOutsider})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = do("GET", base+"/degradation", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.False(t, response.Data.PendingRealityChange)
+	assert.Equal(t, domain.RealityOutsider, response.Data.RealityType)
+
+	w = do("POST", "/api/sessions", gin.H{"agent_id": agent.ID, "scenario_id": "eternal-spring"})
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	w = do("GET", "/api/agents/non-existent/degradation", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -1,7 +1,10 @@
 package handler
 
 import (
+	"errors"
+	"io"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/trpg-solo-engine/backend/internal/domain"
@@ -11,34 +14,133 @@ import (
 type DiceHandler struct {
 	diceService  domain.DiceService
 	agentService service.AgentService
+	gameService  service.GameService
+	rollLedger   service.RollLedgerService
+	pendingRolls service.PendingRollService
+
+	tripleAscension service.TripleAscensionService
+	sessionRolls    service.SessionRollService
 }
 
-func NewDiceHandler(diceService domain.DiceService, agentService service.AgentService) *DiceHandler {
+func NewDiceHandler(diceService domain.DiceService, agentService service.AgentService, gameService service.GameService, rollLedger service.RollLedgerService, pendingRolls service.PendingRollService, tripleAscension service.TripleAscensionService, sessionRolls service.SessionRollService) *DiceHandler {
 	return &DiceHandler{
-		diceService:  diceService,
-		agentService: agentService,
+		diceService:     diceService,
+		agentService:    agentService,
+		gameService:     gameService,
+		rollLedger:      rollLedger,
+		pendingRolls:    pendingRolls,
+		tripleAscension: tripleAscension,
+		sessionRolls:    sessionRolls,
+	}
+}
+
+// sessionDice 获取会话绑定的骰子服务
+// sessionID为空时返回全局骰子服务和nil会话
+func (h *DiceHandler) sessionDice(c *gin.Context, sessionID string) (domain.DiceService, *domain.GameSession, bool) {
+	if sessionID == "" {
+		return h.diceService, nil, true
+	}
+
+	session, err := h.gameService.GetSession(sessionID)
+	if err != nil {
+		respondGameError(c, err)
+		return nil, nil, false
+	}
+
+	return h.diceService.ForSession(session), session, true
+}
+
+// agentSessionDice 获取会话绑定的骰子服务，并确认掷骰的角色是会话的角色
+// 账本按请求中的角色记录掷骰，不属于该会话的角色不能使用会话骰子
+func (h *DiceHandler) agentSessionDice(c *gin.Context, sessionID, agentID string) (domain.DiceService, *domain.GameSession, bool) {
+	dice, session, ok := h.sessionDice(c, sessionID)
+	if !ok {
+		return nil, nil, false
+	}
+
+	if session != nil && session.AgentID != agentID {
+		respondGameError(c, domain.NewGameError(domain.ErrInvalidInput, "角色不属于该会话").
+			WithDetails("agent_id", agentID).
+			WithDetails("session_id", session.ID))
+		return nil, nil, false
+	}
+
+	return dice, session, true
+}
+
+// saveSessionCursor 保存掷骰后推进的会话骰子游标
+func (h *DiceHandler) saveSessionCursor(c *gin.Context, session *domain.GameSession) bool {
+	if session == nil {
+		return true
+	}
+
+	if err := h.gameService.SaveSession(session); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "保存会话骰子游标失败: " + err.Error(),
+		})
+		return false
+	}
+
+	return true
+}
+
+// settleRoll 结算会话内的掷骰：先处理三重升华效果，再写入账本
+// 未绑定会话的掷骰不结算
+func (h *DiceHandler) settleRoll(c *gin.Context, session *domain.GameSession, record *domain.RollRecord) (*domain.TripleAscension, bool) {
+	event, err := h.sessionRolls.Settle(session, record)
+	if err != nil {
+		respondGameError(c, err)
+		return nil, false
 	}
+
+	return event, true
 }
 
 // RollDice 基础掷骰 POST /api/dice/roll
 func (h *DiceHandler) RollDice(c *gin.Context) {
 	var req struct {
-		Count int `json:"count"`
+		Count     int    `json:"count"`
+		SessionID string `json:"session_id"`
 	}
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		req.Count = 6 // 默认6颗骰子
+	// 请求体可以为空，但不能是无效的JSON
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "请求参数无效: " + err.Error(),
+		})
+		return
 	}
 
+	dice, session, ok := h.sessionDice(c, req.SessionID)
+	if !ok {
+		return
+	}
+
+	// 默认使用当前规则集（含剧本覆盖）的骰子数量
 	if req.Count <= 0 {
-		req.Count = 6
+		req.Count = dice.Ruleset().DiceCount
 	}
 
-	result := h.diceService.Roll(req.Count)
+	result := dice.Roll(req.Count)
+
+	if !h.saveSessionCursor(c, session) {
+		return
+	}
+
+	var tripleAscension *domain.TripleAscension
+	if session != nil {
+		record := domain.NewRollRecord(session.ID, session.AgentID, domain.RollKindBasic, result)
+		if tripleAscension, ok = h.settleRoll(c, session, record); !ok {
+			return
+		}
+	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    result,
+		"success":          true,
+		"data":             result,
+		"triple_ascension": tripleAscension,
 	})
 }
 
@@ -47,7 +149,9 @@ func (h *DiceHandler) RollForAbility(c *gin.Context) {
 	var req struct {
 		AgentID   string `json:"agent_id" binding:"required"`
 		AbilityID string `json:"ability_id" binding:"required"`
+		SessionID string `json:"session_id"`
 		QASpend   int    `json:"qa_spend"`
+		Pending   bool   `json:"pending"` // 先查看骰子，稍后再提交QA调整
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -58,22 +162,18 @@ func (h *DiceHandler) RollForAbility(c *gin.Context) {
 		return
 	}
 
+	if req.Pending && req.QASpend > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "待确认掷骰不能预先指定qa_spend，请在提交时给出骰子调整",
+		})
+		return
+	}
+
 	// 获取角色
 	agent, err := h.agentService.GetAgent(req.AgentID)
 	if err != nil {
-		if gameErr, ok := err.(*domain.GameError); ok {
-			if gameErr.Code == domain.ErrNotFound {
-				c.JSON(http.StatusNotFound, gin.H{
-					"success": false,
-					"error":   err.Error(),
-				})
-				return
-			}
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+		respondGameError(c, err)
 		return
 	}
 
@@ -94,28 +194,55 @@ func (h *DiceHandler) RollForAbility(c *gin.Context) {
 		return
 	}
 
+	// 先检查QA，QA不足的请求不消耗会话骰子
+	if ability.Roll != nil && !h.checkQA(c, agent, ability.Roll.Quality, req.QASpend) {
+		return
+	}
+
+	// 已有未结算的待确认掷骰时不再掷骰
+	if req.Pending {
+		if err := h.pendingRolls.CheckNoOpenRoll(agent.ID, req.SessionID); err != nil {
+			respondGameError(c, err)
+			return
+		}
+	}
+
+	dice, session, ok := h.agentSessionDice(c, req.SessionID, agent.ID)
+	if !ok {
+		return
+	}
+
 	// 执行掷骰
-	result := h.diceService.RollForAbility(agent, ability)
+	result := dice.RollForAbility(agent, ability)
 
-	// 应用QA调整
+	if !h.saveSessionCursor(c, session) {
+		return
+	}
+
+	if req.Pending {
+		pending := &service.PendingRoll{
+			AgentID:   agent.ID,
+			SessionID: req.SessionID,
+			Kind:      domain.RollKindAbility,
+			AbilityID: ability.ID,
+			Request:   ability.Name,
+			Roll:      result,
+			Rules:     dice.Ruleset(),
+		}
+		if ability.Roll != nil {
+			pending.Quality = ability.Roll.Quality
+		}
+		h.respondPending(c, pending)
+		return
+	}
+
+	// 应用QA调整，能力没有掷骰资质时不花费QA
+	qaSpent := 0
 	if req.QASpend > 0 && ability.Roll != nil {
 		quality := ability.Roll.Quality
-		// 检查QA是否足够
-		if agent.QA[quality] < req.QASpend {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"success": false,
-				"error":   "资质保证不足",
-				"details": gin.H{
-					"quality":   quality,
-					"available": agent.QA[quality],
-					"required":  req.QASpend,
-				},
-			})
-			return
-		}
 
 		// 应用QA
-		result = h.diceService.ApplyQA(result, quality, req.QASpend)
+		result = dice.ApplyQA(result, quality, req.QASpend)
 
 		// 扣除QA
 		if err := h.agentService.SpendQA(req.AgentID, quality, req.QASpend); err != nil {
@@ -125,15 +252,36 @@ func (h *DiceHandler) RollForAbility(c *gin.Context) {
 			})
 			return
 		}
+		// 持久化的角色服务返回副本，重新读取以返回扣除后的剩余QA
+		if agent, err = h.agentService.GetAgent(req.AgentID); err != nil {
+			respondGameError(c, err)
+			return
+		}
+		qaSpent = req.QASpend
+	}
+
+	var tripleAscension *domain.TripleAscension
+	if session != nil {
+		record := domain.NewRollRecord(session.ID, agent.ID, domain.RollKindAbility, result)
+		record.AbilityID = ability.ID
+		record.Request = ability.Name
+		record.QASpent = qaSpent
+		if ability.Roll != nil {
+			record.Quality = ability.Roll.Quality
+		}
+		if tripleAscension, ok = h.settleRoll(c, session, record); !ok {
+			return
+		}
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"data": gin.H{
-			"roll":         result,
-			"ability":      ability,
-			"qa_spent":     req.QASpend,
-			"qa_remaining": agent.QA,
+			"roll":             result,
+			"ability":          ability,
+			"qa_spent":         qaSpent,
+			"qa_remaining":     agent.QA,
+			"triple_ascension": tripleAscension,
 		},
 	})
 }
@@ -145,7 +293,9 @@ func (h *DiceHandler) RollForRequest(c *gin.Context) {
 		Quality     string `json:"quality" binding:"required"`
 		Effect      string `json:"effect" binding:"required"`
 		CausalChain string `json:"causal_chain" binding:"required"`
+		SessionID   string `json:"session_id"`
 		QASpend     int    `json:"qa_spend"`
+		Pending     bool   `json:"pending"` // 先查看骰子，稍后再提交QA调整
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -156,6 +306,14 @@ func (h *DiceHandler) RollForRequest(c *gin.Context) {
 		return
 	}
 
+	if req.Pending && req.QASpend > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "待确认掷骰不能预先指定qa_spend，请在提交时给出骰子调整",
+		})
+		return
+	}
+
 	// 验证资质类型
 	validQualities := []string{
 		domain.QualityFocus,
@@ -188,43 +346,52 @@ func (h *DiceHandler) RollForRequest(c *gin.Context) {
 	// 获取角色
 	agent, err := h.agentService.GetAgent(req.AgentID)
 	if err != nil {
-		if gameErr, ok := err.(*domain.GameError); ok {
-			if gameErr.Code == domain.ErrNotFound {
-				c.JSON(http.StatusNotFound, gin.H{
-					"success": false,
-					"error":   err.Error(),
-				})
-				return
-			}
+		respondGameError(c, err)
+		return
+	}
+
+	// 先检查QA，QA不足的请求不消耗会话骰子
+	if !h.checkQA(c, agent, req.Quality, req.QASpend) {
+		return
+	}
+
+	// 已有未结算的待确认掷骰时不再掷骰
+	if req.Pending {
+		if err := h.pendingRolls.CheckNoOpenRoll(agent.ID, req.SessionID); err != nil {
+			respondGameError(c, err)
+			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+	}
+
+	dice, session, ok := h.agentSessionDice(c, req.SessionID, agent.ID)
+	if !ok {
 		return
 	}
 
 	// 执行掷骰
-	result := h.diceService.RollForQuality(agent, req.Quality)
+	result := dice.RollForQuality(agent, req.Quality)
+
+	if !h.saveSessionCursor(c, session) {
+		return
+	}
+
+	if req.Pending {
+		h.respondPending(c, &service.PendingRoll{
+			AgentID:   agent.ID,
+			SessionID: req.SessionID,
+			Kind:      domain.RollKindRequest,
+			Quality:   req.Quality,
+			Request:   req.Effect,
+			Roll:      result,
+			Rules:     dice.Ruleset(),
+		})
+		return
+	}
 
 	// 应用QA调整
 	if req.QASpend > 0 {
-		// 检查QA是否足够
-		if agent.QA[req.Quality] < req.QASpend {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"success": false,
-				"error":   "资质保证不足",
-				"details": gin.H{
-					"quality":   req.Quality,
-					"available": agent.QA[req.Quality],
-					"required":  req.QASpend,
-				},
-			})
-			return
-		}
-
 		// 应用QA
-		result = h.diceService.ApplyQA(result, req.Quality, req.QASpend)
+		result = dice.ApplyQA(result, req.Quality, req.QASpend)
 
 		// 扣除QA
 		if err := h.agentService.SpendQA(req.AgentID, req.Quality, req.QASpend); err != nil {
@@ -234,17 +401,323 @@ func (h *DiceHandler) RollForRequest(c *gin.Context) {
 			})
 			return
 		}
+		// 持久化的角色服务返回副本，重新读取以返回扣除后的剩余QA
+		if agent, err = h.agentService.GetAgent(req.AgentID); err != nil {
+			respondGameError(c, err)
+			return
+		}
+	}
+
+	var tripleAscension *domain.TripleAscension
+	if session != nil {
+		record := domain.NewRollRecord(session.ID, agent.ID, domain.RollKindRequest, result)
+		record.Quality = req.Quality
+		record.Request = req.Effect
+		record.QASpent = req.QASpend
+		if tripleAscension, ok = h.settleRoll(c, session, record); !ok {
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"roll":             result,
+			"quality":          req.Quality,
+			"effect":           req.Effect,
+			"causal_chain":     req.CausalChain,
+			"qa_spent":         req.QASpend,
+			"qa_remaining":     agent.QA,
+			"triple_ascension": tripleAscension,
+		},
+	})
+}
+
+// checkQA 检查角色是否有足够的资质保证，不足时返回400
+func (h *DiceHandler) checkQA(c *gin.Context, agent *domain.Agent, quality string, spend int) bool {
+	if spend <= 0 || agent.QA[quality] >= spend {
+		return true
+	}
+
+	c.JSON(http.StatusBadRequest, gin.H{
+		"success": false,
+		"error":   "资质保证不足",
+		"details": gin.H{
+			"quality":   quality,
+			"available": agent.QA[quality],
+			"required":  spend,
+		},
+	})
+	return false
+}
+
+// GetOdds 计算掷骰概率 GET /api/dice/odds
+func (h *DiceHandler) GetOdds(c *gin.Context) {
+	// 提供session_id时使用该会话的规则集
+	dice, _, ok := h.sessionDice(c, c.Query("session_id"))
+	if !ok {
+		return
+	}
+	rules := dice.Ruleset()
+
+	query := &domain.OddsQuery{DiceCount: rules.DiceCount}
+	params := []struct {
+		name  string
+		value *int
+	}{
+		{"dice_count", &query.DiceCount},
+		{"qa_available", &query.QAAvailable},
+		{"overload", &query.Overload},
+	}
+	for _, param := range params {
+		raw := c.Query(param.name)
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.Atoi(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "请求参数无效: " + param.name,
+			})
+			return
+		}
+		*param.value = value
+	}
+
+	if raw := c.Query("triple_ascension"); raw != "" {
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "请求参数无效: triple_ascension",
+			})
+			return
+		}
+		query.TripleAscension = value
+	}
+
+	odds, err := domain.CalculateOdds(rules, query)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"odds":  odds,
+			"rules": rules,
+		},
+	})
+}
+
+// respondPending 登记待确认掷骰并返回令牌
+// 令牌过期时按放弃调整写入账本，与显式放弃相同
+func (h *DiceHandler) respondPending(c *gin.Context, pending *service.PendingRoll) {
+	pending.Expire = h.settlePending(pending.SessionID)
+
+	pending, err := h.pendingRolls.CreatePendingRoll(pending)
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    pending,
+	})
+}
+
+// CommitRoll 提交待确认掷骰的骰子调整 POST /api/dice/rolls/:token/commit
+func (h *DiceHandler) CommitRoll(c *gin.Context) {
+	var req struct {
+		Adjustments []service.DiceAdjustment `json:"adjustments"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "请求参数无效: " + err.Error(),
+		})
+		return
+	}
+
+	settle, ok := h.pendingSettlement(c, c.Param("token"))
+	if !ok {
+		return
+	}
+
+	pending, err := h.pendingRolls.CommitRoll(c.Param("token"), req.Adjustments, settle)
+	h.respondSettled(c, pending, err)
+}
+
+// DeclineRoll 放弃调整并按原始结果结算 POST /api/dice/rolls/:token/decline
+func (h *DiceHandler) DeclineRoll(c *gin.Context) {
+	settle, ok := h.pendingSettlement(c, c.Param("token"))
+	if !ok {
+		return
+	}
+
+	pending, err := h.pendingRolls.DeclineRoll(c.Param("token"), settle)
+	h.respondSettled(c, pending, err)
+}
+
+// RerollRoll 消耗免费重掷令牌重掷待确认掷骰 POST /api/dice/rolls/:token/reroll
+// 重掷后掷骰令牌仍然有效，玩家可以继续提交或放弃调整
+func (h *DiceHandler) RerollRoll(c *gin.Context) {
+	pending, err := h.pendingRolls.GetPendingRoll(c.Param("token"))
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	if pending.SessionID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "只有会话内的掷骰可以使用免费重掷",
+		})
+		return
+	}
+
+	dice, session, ok := h.sessionDice(c, pending.SessionID)
+	if !ok {
+		return
+	}
+
+	// 先扣除令牌再重掷，令牌不足的并发请求不会推进骰子
+	if err := h.tripleAscension.SpendRerollToken(session); err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	pending, err = h.pendingRolls.RerollRoll(c.Param("token"), dice.WithRuleset(pending.Rules))
+	if err != nil {
+		// 重掷没有发生，退还令牌
+		_ = h.gameService.UpdateState(session.ID, func(state *domain.GameState) error {
+			state.RerollTokens++
+			return nil
+		})
+		respondGameError(c, err)
+		return
+	}
+
+	if !h.saveSessionCursor(c, session) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"pending":       pending,
+			"reroll_tokens": session.State.RerollTokens,
+		},
+	})
+}
+
+// pendingSettlement 在令牌失效前查找掷骰所属的会话，并返回结算回调
+// 会话不存在时直接返回错误，令牌和QA都不受影响；未绑定会话的掷骰不结算
+func (h *DiceHandler) pendingSettlement(c *gin.Context, token string) (service.SettleFunc, bool) {
+	pending, err := h.pendingRolls.GetPendingRoll(token)
+	if err != nil {
+		respondGameError(c, err)
+		return nil, false
+	}
+
+	if _, _, ok := h.sessionDice(c, pending.SessionID); !ok {
+		return nil, false
+	}
+
+	return h.settlePending(pending.SessionID), true
+}
+
+// settlePending 返回把待确认掷骰写入会话账本的结算回调
+// 提交、放弃和过期都经过这里，结算时重新查找会话；未绑定会话的掷骰不结算
+func (h *DiceHandler) settlePending(sessionID string) service.SettleFunc {
+	if sessionID == "" {
+		return nil
+	}
+
+	return func(settled *service.PendingRoll) error {
+		session, err := h.gameService.GetSession(sessionID)
+		if err != nil {
+			return err
+		}
+
+		// 以令牌作为账本ID，结算失败重试时三重升华不会重复生效
+		record := domain.NewRollRecord(settled.SessionID, settled.AgentID, settled.Kind, settled.Roll)
+		record.ID = settled.Token
+		record.Quality = settled.Quality
+		record.AbilityID = settled.AbilityID
+		record.Request = settled.Request
+		record.QASpent = settled.QASpent
+
+		event, err := h.sessionRolls.Settle(session, record)
+		if err != nil {
+			return err
+		}
+
+		settled.TripleAscension = event
+		return nil
+	}
+}
+
+// respondSettled 返回已结算的待确认掷骰
+func (h *DiceHandler) respondSettled(c *gin.Context, pending *service.PendingRoll, err error) {
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    pending,
+	})
+}
+
+// ListSessionRolls 查询会话掷骰账本 GET /api/sessions/:id/rolls
+func (h *DiceHandler) ListSessionRolls(c *gin.Context) {
+	sessionID := c.Param("id")
+
+	if _, err := h.gameService.GetSession(sessionID); err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	// 过期的待确认掷骰先按放弃调整入账
+	h.pendingRolls.SettleExpired()
+
+	filter := &domain.RollFilter{
+		Quality: c.Query("quality"),
+		Outcome: c.Query("outcome"),
+	}
+
+	if !domain.IsValidRollOutcome(filter.Outcome) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "无效的结果筛选条件: " + filter.Outcome,
+		})
+		return
+	}
+
+	records, err := h.rollLedger.ListRolls(sessionID, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"data": gin.H{
-			"roll":         result,
-			"quality":      req.Quality,
-			"effect":       req.Effect,
-			"causal_chain": req.CausalChain,
-			"qa_spent":     req.QASpend,
-			"qa_remaining": agent.QA,
+			"session_id": sessionID,
+			"rolls":      records,
+			"count":      len(records),
 		},
 	})
 }
@@ -9,26 +9,42 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/trpg-solo-engine/backend/internal/domain"
 	"github.com/trpg-solo-engine/backend/internal/service"
 )
 
 func setupDiceTestRouter() (*gin.Engine, *DiceHandler, service.AgentService) {
+	router, diceHandler, agentService, _ := setupDiceTestRouterWithSessions()
+	return router, diceHandler, agentService
+}
+
+func setupDiceTestRouterWithSessions() (*gin.Engine, *DiceHandler, service.AgentService, service.GameService) {
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
 
 	diceService := domain.NewDiceService()
 	agentService := service.NewAgentService()
-	diceHandler := NewDiceHandler(diceService, agentService)
+	gameService := service.NewGameService()
+	rollLedger := service.NewRollLedgerService()
+	pendingRolls := service.NewPendingRollService(agentService, service.NewQAService(diceService), service.DefaultPendingRollTTL)
+	tripleAscension := service.NewTripleAscensionService(agentService, gameService, service.NewAIService(), nil)
+	sessionRolls := service.NewSessionRollService(rollLedger, tripleAscension)
+	diceHandler := NewDiceHandler(diceService, agentService, gameService, rollLedger, pendingRolls, tripleAscension, sessionRolls)
 
 	api := router.Group("/api/dice")
 	{
 		api.POST("/roll", diceHandler.RollDice)
 		api.POST("/ability", diceHandler.RollForAbility)
 		api.POST("/request", diceHandler.RollForRequest)
+		api.GET("/odds", diceHandler.GetOdds)
+		api.POST("/rolls/:token/commit", diceHandler.CommitRoll)
+		api.POST("/rolls/:token/decline", diceHandler.DeclineRoll)
+		api.POST("/rolls/:token/reroll", diceHandler.RerollRoll)
 	}
+	router.GET("/api/sessions/:id/rolls", diceHandler.ListSessionRolls)
 
-	return router, diceHandler, agentService
+	return router, diceHandler, agentService, gameService
 }
 
 // TestRollDice_BasicRoll 测试基础掷骰
@@ -461,3 +477,612 @@ func TestRollForRequest_InsufficientQA(t *testing.T) {
 	assert.False(t, response["success"].(bool))
 	assert.Contains(t, response["error"], "资质保证不足")
 }
+
+// TestRollForRequest_InsufficientQAKeepsStream 测试QA不足的会话掷骰不消耗骰子随机流
+func TestRollForRequest_InsufficientQAKeepsStream(t *testing.T) {
+	router, _, agentService, gameService := setupDiceTestRouterWithSessions()
+
+	agent, err := agentService.CreateAgent(&service.CreateAgentRequest{
+		Name:        "测试角色",
+		AnomalyType: domain.AnomalyWhisper,
+		RealityType: domain.RealityCaretaker,
+		CareerType:  domain.CareerPublicRelations,
+	})
+	require.NoError(t, err)
+	session, err := gameService.CreateSession(agent.ID, "test-scenario")
+	require.NoError(t, err)
+
+	requests := map[string]map[string]interface{}{
+		"/api/dice/request": {
+			"agent_id":     agent.ID,
+			"session_id":   session.ID,
+			"quality":      domain.QualityFocus,
+			"effect":       "某个效果",
+			"causal_chain": "某个因果链",
+			"qa_spend":     100,
+		},
+		"/api/dice/ability": {
+			"agent_id":   agent.ID,
+			"session_id": session.ID,
+			"ability_id": agent.Anomaly.Abilities[0].ID,
+			"qa_spend":   100,
+		},
+	}
+	for path, request := range requests {
+		body, _ := json.Marshal(request)
+		req, _ := http.NewRequest("POST", path, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, path)
+	}
+
+	stored, err := gameService.GetSession(session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), stored.DiceCursor)
+}
+
+// TestRollDice_SessionStream 测试会话绑定的掷骰可按种子重放
+func TestRollDice_SessionStream(t *testing.T) {
+	router, _, agentService, gameService := setupDiceTestRouterWithSessions()
+
+	// 三重升华会向会话角色授予嘉奖，因此需要真实角色
+	agent, err := agentService.CreateAgent(&service.CreateAgentRequest{
+		Name:        "测试角色",
+		AnomalyType: domain.AnomalyWhisper,
+		RealityType: domain.RealityCaretaker,
+		CareerType:  domain.CareerPublicRelations,
+	})
+	assert.NoError(t, err)
+
+	session, err := gameService.CreateSession(agent.ID, "test-scenario")
+	assert.NoError(t, err)
+	seed := session.DiceSeed
+
+	rolls := make([][]int, 0, 3)
+	for i := 0; i < 3; i++ {
+		body, _ := json.Marshal(map[string]interface{}{"session_id": session.ID})
+		req, _ := http.NewRequest("POST", "/api/dice/roll", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Data domain.RollResult `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		rolls = append(rolls, response.Data.Dice)
+	}
+
+	// 会话游标应随掷骰推进
+	stored, err := gameService.GetSession(session.ID)
+	assert.NoError(t, err)
+	assert.Greater(t, stored.DiceCursor, int64(0))
+
+	// 从种子重放应得到相同结果
+	replay := domain.NewDiceService().ForSession(&domain.GameSession{DiceSeed: seed})
+	for _, dice := range rolls {
+		assert.Equal(t, dice, replay.Roll(6).Dice)
+	}
+}
+
+// TestRollDice_DefaultCount 测试默认骰子数量取自规则集，无效请求体返回400
+func TestRollDice_DefaultCount(t *testing.T) {
+	router, _, agentService, gameService := setupDiceTestRouterWithSessions()
+
+	roll := func(body string) (int, []interface{}) {
+		req, _ := http.NewRequest("POST", "/api/dice/roll", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		if data, ok := response["data"].(map[string]interface{}); ok {
+			return w.Code, data["dice"].([]interface{})
+		}
+		return w.Code, nil
+	}
+
+	code, dice := roll("")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Len(t, dice, domain.DefaultRuleset().DiceCount)
+
+	code, _ = roll(`{"count": "six"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, _ = roll(`{"count": 4`)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	// 会话规则集覆盖骰子数量
+	agent, err := agentService.CreateAgent(&service.CreateAgentRequest{
+		Name:        "测试角色",
+		AnomalyType: domain.AnomalyWhisper,
+		RealityType: domain.RealityCaretaker,
+		CareerType:  domain.CareerPublicRelations,
+	})
+	require.NoError(t, err)
+	session, err := gameService.CreateSession(agent.ID, "test-scenario")
+	require.NoError(t, err)
+
+	rules := domain.DefaultRuleset()
+	rules.DiceCount = 4
+	session.Rules = rules
+	require.NoError(t, gameService.SaveSession(session))
+
+	code, dice = roll(`{"session_id": "` + session.ID + `"}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Len(t, dice, 4)
+}
+
+// TestRollDice_SessionNotFound 测试会话不存在
+func TestRollDice_SessionNotFound(t *testing.T) {
+	router, _, _ := setupDiceTestRouter()
+
+	body, _ := json.Marshal(map[string]interface{}{"session_id": "non-existent"})
+	req, _ := http.NewRequest("POST", "/api/dice/roll", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+// TestListSessionRolls 测试会话掷骰账本查询
+func TestListSessionRolls(t *testing.T) {
+	router, _, agentService, gameService := setupDiceTestRouterWithSessions()
+
+	agent, err := agentService.CreateAgent(&service.CreateAgentRequest{
+		Name:        "测试角色",
+		AnomalyType: domain.AnomalyWhisper,
+		RealityType: domain.RealityCaretaker,
+		CareerType:  domain.CareerPublicRelations,
+	})
+	assert.NoError(t, err)
+
+	session, err := gameService.CreateSession(agent.ID, "test-scenario")
+	assert.NoError(t, err)
+
+	requests := []struct {
+		path string
+		body map[string]interface{}
+	}{
+		{"/api/dice/roll", map[string]interface{}{"session_id": session.ID}},
+		{"/api/dice/request", map[string]interface{}{
+			"session_id":   session.ID,
+			"agent_id":     agent.ID,
+			"quality":      domain.QualityFocus,
+			"effect":       "让门锁失灵",
+			"causal_chain": "门锁年久失修",
+		}},
+		{"/api/dice/ability", map[string]interface{}{
+			"session_id": session.ID,
+			"agent_id":   agent.ID,
+			"ability_id": agent.Anomaly.Abilities[0].ID,
+		}},
+		// 未绑定会话的掷骰不进入账本
+		{"/api/dice/roll", map[string]interface{}{}},
+	}
+
+	for _, r := range requests {
+		body, _ := json.Marshal(r.body)
+		req, _ := http.NewRequest("POST", r.path, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+
+	listRolls := func(query string) (int, []domain.RollRecord) {
+		req, _ := http.NewRequest("GET", "/api/sessions/"+session.ID+"/rolls"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var response struct {
+			Data struct {
+				Rolls []domain.RollRecord `json:"rolls"`
+			} `json:"data"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &response)
+		return w.Code, response.Data.Rolls
+	}
+
+	t.Run("按时间顺序返回全部记录", func(t *testing.T) {
+		code, rolls := listRolls("")
+		assert.Equal(t, http.StatusOK, code)
+		assert.Len(t, rolls, 3)
+		assert.Equal(t, domain.RollKindBasic, rolls[0].Kind)
+		assert.Equal(t, domain.RollKindRequest, rolls[1].Kind)
+		assert.Equal(t, "让门锁失灵", rolls[1].Request)
+		assert.Equal(t, domain.RollKindAbility, rolls[2].Kind)
+		assert.Equal(t, agent.Anomaly.Abilities[0].ID, rolls[2].AbilityID)
+		for _, roll := range rolls {
+			assert.Equal(t, agent.ID, roll.AgentID)
+			assert.Len(t, roll.RawDice, len(roll.Dice))
+		}
+	})
+
+	t.Run("按品质筛选", func(t *testing.T) {
+		code, rolls := listRolls("?quality=" + domain.QualityFocus)
+		assert.Equal(t, http.StatusOK, code)
+		for _, roll := range rolls {
+			assert.Equal(t, domain.QualityFocus, roll.Quality)
+		}
+	})
+
+	t.Run("按结果筛选", func(t *testing.T) {
+		_, all := listRolls("")
+		_, succeeded := listRolls("?outcome=success")
+		_, failed := listRolls("?outcome=failure")
+		assert.Equal(t, len(all), len(succeeded)+len(failed))
+	})
+
+	t.Run("无效结果筛选", func(t *testing.T) {
+		code, _ := listRolls("?outcome=maybe")
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+
+	t.Run("会话不存在", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/api/sessions/missing/rolls", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("其他角色不能使用会话骰子", func(t *testing.T) {
+		other, err := agentService.CreateAgent(&service.CreateAgentRequest{
+			Name:        "其他角色",
+			AnomalyType: domain.AnomalyWhisper,
+			RealityType: domain.RealityCaretaker,
+			CareerType:  domain.CareerPublicRelations,
+		})
+		require.NoError(t, err)
+
+		body, _ := json.Marshal(map[string]interface{}{
+			"session_id":   session.ID,
+			"agent_id":     other.ID,
+			"quality":      domain.QualityFocus,
+			"effect":       "让门锁失灵",
+			"causal_chain": "门锁年久失修",
+		})
+		req, _ := http.NewRequest("POST", "/api/dice/request", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		_, rolls := listRolls("")
+		assert.Len(t, rolls, 3)
+	})
+}
+
+// TestPendingRoll_CommitAdjustments 测试先掷骰后提交QA调整
+func TestPendingRoll_CommitAdjustments(t *testing.T) {
+	router, _, agentService, gameService := setupDiceTestRouterWithSessions()
+
+	agent, err := agentService.CreateAgent(&service.CreateAgentRequest{
+		Name:        "测试角色",
+		AnomalyType: domain.AnomalyWhisper,
+		RealityType: domain.RealityCaretaker,
+		CareerType:  domain.CareerPublicRelations,
+	})
+	assert.NoError(t, err)
+	initialQA := agent.QA[domain.QualityFocus]
+
+	session, err := gameService.CreateSession(agent.ID, "test-scenario")
+	assert.NoError(t, err)
+
+	post := func(path string, payload map[string]interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(payload)
+		req, _ := http.NewRequest("POST", path, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := post("/api/dice/request", map[string]interface{}{
+		"session_id":   session.ID,
+		"agent_id":     agent.ID,
+		"quality":      domain.QualityFocus,
+		"effect":       "让门锁失灵",
+		"causal_chain": "门锁年久失修",
+		"pending":      true,
+	})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var pending struct {
+		Data service.PendingRoll `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &pending))
+	assert.NotEmpty(t, pending.Data.Token)
+	original := append([]int(nil), pending.Data.Roll.Dice...)
+
+	// 掷骰后不扣除QA，也不写入账本
+	stored, _ := agentService.GetAgent(agent.ID)
+	assert.Equal(t, initialQA, stored.QA[domain.QualityFocus])
+
+	// 把第一颗非3的骰子调整为3
+	index := 0
+	for i, d := range original {
+		if d != 3 {
+			index = i
+			break
+		}
+	}
+
+	w = post("/api/dice/rolls/"+pending.Data.Token+"/commit", map[string]interface{}{
+		"adjustments": []map[string]int{{"dice_index": index, "new_value": 3}},
+	})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var committed struct {
+		Data service.PendingRoll `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &committed))
+	assert.Equal(t, 3, committed.Data.Roll.Dice[index])
+	assert.Equal(t, original, committed.Data.Roll.RawDice)
+	assert.True(t, committed.Data.Roll.Success)
+	assert.Equal(t, 1, committed.Data.QASpent)
+
+	stored, _ = agentService.GetAgent(agent.ID)
+	assert.Equal(t, initialQA-1, stored.QA[domain.QualityFocus])
+
+	// 令牌只能使用一次
+	w = post("/api/dice/rolls/"+pending.Data.Token+"/commit", map[string]interface{}{})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// 账本只记录结算后的结果
+	req, _ := http.NewRequest("GET", "/api/sessions/"+session.ID+"/rolls", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var ledger struct {
+		Data struct {
+			Rolls []domain.RollRecord `json:"rolls"`
+		} `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &ledger))
+	assert.Len(t, ledger.Data.Rolls, 1)
+	assert.Equal(t, 1, ledger.Data.Rolls[0].QASpent)
+}
+
+// TestDiceHandler_RollForAbility_NoRollQuality 测试能力没有掷骰资质时不记录QA花费
+func TestDiceHandler_RollForAbility_NoRollQuality(t *testing.T) {
+	router, _, agentService, gameService := setupDiceTestRouterWithSessions()
+
+	agent, err := agentService.CreateAgent(&service.CreateAgentRequest{
+		Name:        "测试角色",
+		AnomalyType: domain.AnomalyWhisper,
+		RealityType: domain.RealityCaretaker,
+		CareerType:  domain.CareerPublicRelations,
+	})
+	require.NoError(t, err)
+	agent.Anomaly.Abilities[0].Roll = nil
+	initialQA := agent.TotalQA()
+
+	session, err := gameService.CreateSession(agent.ID, "test-scenario")
+	require.NoError(t, err)
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"session_id": session.ID,
+		"agent_id":   agent.ID,
+		"ability_id": agent.Anomaly.Abilities[0].ID,
+		"qa_spend":   2,
+	})
+	req, _ := http.NewRequest("POST", "/api/dice/ability", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Data struct {
+			QASpent int `json:"qa_spent"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 0, response.Data.QASpent)
+
+	stored, _ := agentService.GetAgent(agent.ID)
+	assert.Equal(t, initialQA, stored.TotalQA())
+
+	req, _ = http.NewRequest("GET", "/api/sessions/"+session.ID+"/rolls", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var ledger struct {
+		Data struct {
+			Rolls []domain.RollRecord `json:"rolls"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &ledger))
+	require.Len(t, ledger.Data.Rolls, 1)
+	assert.Equal(t, 0, ledger.Data.Rolls[0].QASpent)
+}
+
+// TestPendingRoll_Decline 测试放弃调整
+func TestPendingRoll_Decline(t *testing.T) {
+	router, _, agentService := setupDiceTestRouter()
+
+	agent, err := agentService.CreateAgent(&service.CreateAgentRequest{
+		Name:        "测试角色",
+		AnomalyType: domain.AnomalyWhisper,
+		RealityType: domain.RealityCaretaker,
+		CareerType:  domain.CareerPublicRelations,
+	})
+	assert.NoError(t, err)
+	initialQA := agent.TotalQA()
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"agent_id":   agent.ID,
+		"ability_id": agent.Anomaly.Abilities[0].ID,
+		"pending":    true,
+	})
+	req, _ := http.NewRequest("POST", "/api/dice/ability", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var pending struct {
+		Data service.PendingRoll `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &pending))
+
+	req, _ = http.NewRequest("POST", "/api/dice/rolls/"+pending.Data.Token+"/decline", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var declined struct {
+		Data service.PendingRoll `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &declined))
+	assert.Equal(t, pending.Data.Roll.Dice, declined.Data.Roll.Dice)
+	assert.Equal(t, 0, declined.Data.QASpent)
+
+	stored, _ := agentService.GetAgent(agent.ID)
+	assert.Equal(t, initialQA, stored.TotalQA())
+}
+
+// TestPendingRoll_Reroll 测试消耗免费重掷令牌重掷
+func TestPendingRoll_Reroll(t *testing.T) {
+	router, _, agentService, gameService := setupDiceTestRouterWithSessions()
+
+	agent, err := agentService.CreateAgent(&service.CreateAgentRequest{
+		Name:        "测试角色",
+		AnomalyType: domain.AnomalyWhisper,
+		RealityType: domain.RealityCaretaker,
+		CareerType:  domain.CareerPublicRelations,
+	})
+	assert.NoError(t, err)
+
+	session, err := gameService.CreateSession(agent.ID, "test-scenario")
+	assert.NoError(t, err)
+	session.State.RerollTokens = 1
+	assert.NoError(t, gameService.SaveSession(session))
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"agent_id":     agent.ID,
+		"quality":      domain.QualityFocus,
+		"effect":       "效果",
+		"causal_chain": "因果链",
+		"session_id":   session.ID,
+		"pending":      true,
+	})
+	req, _ := http.NewRequest("POST", "/api/dice/request", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var pending struct {
+		Data service.PendingRoll `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &pending))
+
+	req, _ = http.NewRequest("POST", "/api/dice/rolls/"+pending.Data.Token+"/reroll", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	stored, _ := gameService.GetSession(session.ID)
+	assert.Equal(t, 0, stored.State.RerollTokens)
+	cursor := stored.DiceCursor
+
+	// 令牌用尽后不能再重掷，也不推进会话骰子
+	req, _ = http.NewRequest("POST", "/api/dice/rolls/"+pending.Data.Token+"/reroll", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, cursor, stored.DiceCursor)
+
+	// 未结算前不能再掷一次待确认掷骰
+	req, _ = http.NewRequest("POST", "/api/dice/request", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, cursor, stored.DiceCursor)
+
+	// 掷骰令牌在重掷后仍可提交
+	req, _ = http.NewRequest("POST", "/api/dice/rolls/"+pending.Data.Token+"/decline", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+// TestPendingRoll_RejectsUpfrontQA 测试待确认掷骰不能预先指定QA
+func TestPendingRoll_RejectsUpfrontQA(t *testing.T) {
+	router, _, agentService := setupDiceTestRouter()
+
+	agent, err := agentService.CreateAgent(&service.CreateAgentRequest{
+		Name:        "测试角色",
+		AnomalyType: domain.AnomalyWhisper,
+		RealityType: domain.RealityCaretaker,
+		CareerType:  domain.CareerPublicRelations,
+	})
+	assert.NoError(t, err)
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"agent_id":     agent.ID,
+		"quality":      domain.QualityFocus,
+		"effect":       "效果",
+		"causal_chain": "因果链",
+		"qa_spend":     1,
+		"pending":      true,
+	})
+	req, _ := http.NewRequest("POST", "/api/dice/request", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+// TestGetOdds 测试掷骰概率计算
+func TestGetOdds(t *testing.T) {
+	router, _, _ := setupDiceTestRouter()
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+	}{
+		{"默认参数", "", http.StatusOK},
+		{"指定全部参数", "?dice_count=4&qa_available=1&overload=1&triple_ascension=true", http.StatusOK},
+		{"骰子数量无效", "?dice_count=abc", http.StatusBadRequest},
+		{"骰子数量超出范围", "?dice_count=0", http.StatusBadRequest},
+		{"三重升华参数无效", "?triple_ascension=maybe", http.StatusBadRequest},
+		{"会话不存在", "?session_id=non-existent", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/api/dice/odds"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+
+	req, _ := http.NewRequest("GET", "/api/dice/odds?dice_count=6&qa_available=1&triple_ascension=true", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var response struct {
+		Data struct {
+			Odds domain.RollOdds `json:"odds"`
+		} `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Data.Odds.Threes, 7)
+	assert.InDelta(t, 1.0, response.Data.Odds.SuccessWithQA, 1e-9)
+	assert.Greater(t, response.Data.Odds.TripleAscensionChance, 0.0)
+}
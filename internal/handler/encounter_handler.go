@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/trpg-solo-engine/backend/internal/service"
+)
+
+type EncounterHandler struct {
+	encounterService service.EncounterService
+}
+
+func NewEncounterHandler(encounterService service.EncounterService) *EncounterHandler {
+	return &EncounterHandler{
+		encounterService: encounterService,
+	}
+}
+
+// StartEncounter 开始遭遇 POST /api/sessions/:id/encounter
+func (h *EncounterHandler) StartEncounter(c *gin.Context) {
+	view, err := h.encounterService.Start(c.Param("id"))
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    view,
+	})
+}
+
+// GetEncounter 查询遭遇进度 GET /api/sessions/:id/encounter
+func (h *EncounterHandler) GetEncounter(c *gin.Context) {
+	view, err := h.encounterService.GetEncounter(c.Param("id"))
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    view,
+	})
+}
+
+// TakeAction 采取当前阶段的行动 POST /api/sessions/:id/encounter/actions
+func (h *EncounterHandler) TakeAction(c *gin.Context) {
+	var req service.EncounterActionRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	result, err := h.encounterService.TakeAction(c.Param("id"), &req)
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
+
+// bind 解析请求体，失败时返回400
+func (h *EncounterHandler) bind(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "请求参数无效: " + err.Error(),
+		})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+	"github.com/trpg-solo-engine/backend/internal/service"
+)
+
+func TestEncounterHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	agentService := service.NewAgentService()
+	scenarioService := service.NewScenarioService("../../scenarios")
+	gameService := service.NewGameServiceWithAgents(scenarioService, agentService, nil)
+	sessionRolls := service.NewSessionRollService(service.NewRollLedgerService(), service.NewTripleAscensionService(agentService, gameService, service.NewAIService(), nil))
+	encounterService := service.NewEncounterService(gameService, agentService, scenarioService, domain.NewDiceService(), service.NewChaosService(), sessionRolls, nil)
+	encounterHandler := NewEncounterHandler(encounterService)
+
+	router := gin.New()
+	sessions := router.Group("/api/sessions")
+	{
+		sessions.POST("/:id/encounter", encounterHandler.StartEncounter)
+		sessions.GET("/:id/encounter", encounterHandler.GetEncounter)
+		sessions.POST("/:id/encounter/actions", encounterHandler.TakeAction)
+	}
+
+	agent, err := agentService.CreateAgent(&service.CreateAgentRequest{
+		Name:        "遭遇测试",
+		AnomalyType: domain.AnomalyWhisper,
+		RealityType: domain.RealityCaretaker,
+		CareerType:  domain.CareerPublicRelations,
+	})
+	require.NoError(t, err)
+	session, err := gameService.CreateSession(agent.ID, "eternal-spring")
+	require.NoError(t, err)
+	base := "/api/sessions/" + session.ID + "/encounter"
+
+	do := func(method, path string, body interface{}) (*httptest.ResponseRecorder, map[string]interface{}) {
+		data, _ := json.Marshal(body)
+		req, _ := http.NewRequest(method, path, bytes.NewReader(data))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return w, response
+	}
+
+	// 晨会阶段不能开始遭遇
+	w, _ := do("POST", base, nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	require.NoError(t, gameService.TransitionPhase(session.ID, domain.PhaseInvestigation))
+	require.NoError(t, gameService.TransitionPhase(session.ID, domain.PhaseEncounter))
+
+	w, _ = do("GET", base, nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w, response := do("POST", base, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	data := response["data"].(map[string]interface{})
+	assert.Equal(t, "永恒之泉", data["anomaly_name"])
+	assert.Len(t, data["choices"], 3)
+
+	w, _ = do("POST", base+"/actions", map[string]interface{}{})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w, _ = do("POST", base+"/actions", map[string]interface{}{"choice_id": "final-resolution-1"})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w, response = do("POST", base+"/actions", map[string]interface{}{"choice_id": "initial-contact-1"})
+	require.Equal(t, http.StatusOK, w.Code)
+	turn := response["data"].(map[string]interface{})["turn"].(map[string]interface{})
+	assert.Equal(t, float64(1), turn["turn"])
+	assert.Equal(t, "initial-contact", turn["phase_id"])
+
+	w, response = do("GET", base, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	state := response["data"].(map[string]interface{})["state"].(map[string]interface{})
+	assert.Len(t, state["turns"], 1)
+
+	w, _ = do("GET", "/api/sessions/non-existent/encounter", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+)
+
+// gameErrorStatus 游戏错误代码对应的HTTP状态码，未列出的代码返回500
+var gameErrorStatus = map[domain.ErrorCode]int{
+	domain.ErrInvalidInput:              http.StatusBadRequest,
+	domain.ErrInvalidARC:                http.StatusBadRequest,
+	domain.ErrInvalidAction:             http.StatusBadRequest,
+	domain.ErrInsufficientQA:            http.StatusBadRequest,
+	domain.ErrInsufficientChaos:         http.StatusBadRequest,
+	domain.ErrInsufficientCommendations: http.StatusBadRequest,
+	domain.ErrInsufficientConnection:    http.StatusBadRequest,
+	domain.ErrNotFound:                  http.StatusNotFound,
+	domain.ErrInvalidPhase:              http.StatusConflict,
+	domain.ErrInvalidState:              http.StatusConflict,
+	domain.ErrAlreadyExists:             http.StatusConflict,
+	domain.ErrDataCorrupted:             http.StatusUnprocessableEntity,
+}
+
+// respondGameError 根据错误链中游戏错误的代码返回对应状态码的错误响应，错误详情不为空时一并返回
+func respondGameError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	response := gin.H{
+		"success": false,
+		"error":   err.Error(),
+	}
+
+	// 结算回调等路径会用%w包装游戏错误，按错误链查找
+	var gameErr *domain.GameError
+	if errors.As(err, &gameErr) {
+		if code, ok := gameErrorStatus[gameErr.Code]; ok {
+			status = code
+		}
+		if len(gameErr.Details) > 0 {
+			response["details"] = gameErr.Details
+		}
+	}
+
+	c.JSON(status, response)
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+)
+
+func TestRespondGameError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name        string
+		err         error
+		status      int
+		withDetails bool
+	}{
+		{"无效输入", domain.NewGameError(domain.ErrInvalidInput, "参数无效").WithDetails("field", "name"), http.StatusBadRequest, true},
+		{"无效行动", domain.NewGameError(domain.ErrInvalidAction, "不能这样做"), http.StatusBadRequest, false},
+		{"资质保证不足", domain.NewGameError(domain.ErrInsufficientQA, "QA不足"), http.StatusBadRequest, false},
+		{"不存在", domain.NewGameError(domain.ErrNotFound, "会话不存在"), http.StatusNotFound, false},
+		{"阶段错误", domain.NewGameError(domain.ErrInvalidPhase, "阶段错误").WithDetails("current_phase", "morning"), http.StatusConflict, true},
+		{"状态错误", domain.NewGameError(domain.ErrInvalidState, "状态错误"), http.StatusConflict, false},
+		{"已存在", domain.NewGameError(domain.ErrAlreadyExists, "已存在"), http.StatusConflict, false},
+		{"数据损坏", domain.NewGameError(domain.ErrDataCorrupted, "数据损坏"), http.StatusUnprocessableEntity, false},
+		{"内部错误", domain.NewGameError(domain.ErrInternal, "内部错误"), http.StatusInternalServerError, false},
+		{"包装的游戏错误", fmt.Errorf("结算掷骰失败: %w", domain.NewGameError(domain.ErrNotFound, "会话不存在")), http.StatusNotFound, false},
+		{"普通错误", errors.New("连接断开"), http.StatusInternalServerError, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			respondGameError(c, tt.err)
+
+			assert.Equal(t, tt.status, w.Code)
+			var response map[string]interface{}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, false, response["success"])
+			assert.Equal(t, tt.err.Error(), response["error"])
+			_, hasDetails := response["details"]
+			assert.Equal(t, tt.withDetails, hasDetails)
+		})
+	}
+}
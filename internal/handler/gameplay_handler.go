@@ -0,0 +1,435 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+	"github.com/trpg-solo-engine/backend/internal/service"
+)
+
+// 会话内玩法接口允许的阶段
+// 查询类接口不限制阶段；异常能力在任何阶段都能使用，晨会和余波中使用由能力服务记为工作外使用
+var (
+	missionPhases       = []domain.GamePhase{domain.PhaseInvestigation, domain.PhaseEncounter}
+	investigationPhases = []domain.GamePhase{domain.PhaseInvestigation}
+)
+
+// GameplayHandler 会话内的玩法接口：异常能力、现实变更请求、场景交互、线索、NPC对话和混沌池
+type GameplayHandler struct {
+	gameService    service.GameService
+	agentService   service.AgentService
+	diceService    domain.DiceService
+	abilityService service.AbilityService
+	requestService service.RequestService
+	sceneService   service.SceneService
+	clueService    service.ClueService
+	npcService     service.NPCService
+	chaosService   service.ChaosService
+	aiService      service.AIService
+	sessionRolls   service.SessionRollService
+}
+
+func NewGameplayHandler(gameService service.GameService, agentService service.AgentService, diceService domain.DiceService, abilityService service.AbilityService, requestService service.RequestService, sceneService service.SceneService, clueService service.ClueService, npcService service.NPCService, chaosService service.ChaosService, aiService service.AIService, sessionRolls service.SessionRollService) *GameplayHandler {
+	return &GameplayHandler{
+		gameService:    gameService,
+		agentService:   agentService,
+		diceService:    diceService,
+		abilityService: abilityService,
+		requestService: requestService,
+		sceneService:   sceneService,
+		clueService:    clueService,
+		npcService:     npcService,
+		chaosService:   chaosService,
+		aiService:      aiService,
+		sessionRolls:   sessionRolls,
+	}
+}
+
+// UseAbility 使用异常能力 POST /api/sessions/:id/abilities/:abilityId/use
+func (h *GameplayHandler) UseAbility(c *gin.Context) {
+	var req struct {
+		TargetID    string                 `json:"target_id"`
+		Description string                 `json:"description"`
+		CustomData  map[string]interface{} `json:"custom_data"`
+	}
+	if !h.bind(c, &req) {
+		return
+	}
+
+	session, err := h.gameService.GetSession(c.Param("id"))
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	agent, err := h.agentService.GetAgent(session.AgentID)
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	// 掷骰只读角色，不在角色锁内进行
+	result, err := h.abilityService.RollAbility(agent, session, c.Param("abilityId"), &service.AbilityContext{
+		TargetID:    req.TargetID,
+		LocationID:  session.State.CurrentSceneID,
+		OnDuty:      !h.abilityService.CheckOffDutyUsage(agent, session),
+		CustomData:  req.CustomData,
+		Description: req.Description,
+	})
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	// 先结算并保存会话，再修改角色：骰子已经从会话随机流取出，后续失败也不能重掷
+	record := domain.NewRollRecord(session.ID, agent.ID, domain.RollKindAbility, result.Roll)
+	record.AbilityID = result.Ability.ID
+	record.Request = result.Ability.Name
+	if result.Ability.Roll != nil {
+		record.Quality = result.Ability.Roll.Quality
+	}
+	tripleAscension, err := h.sessionRolls.Settle(session, record)
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	// 掷骰推进了会话骰子游标，失败时还向混沌池添加了混沌
+	if err := h.gameService.SaveSession(session); err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	agent, err = h.agentService.ModifyAgent(session.AgentID, func(agent *domain.Agent) error {
+		h.abilityService.RecordOffDutyUsage(agent, session, result)
+		return nil
+	})
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"result":           result,
+			"reprimands":       agent.Reprimands,
+			"chaos_pool":       h.chaosService.GetChaosPool(session),
+			"triple_ascension": tripleAscension,
+		},
+	})
+}
+
+// SubmitRequest 提交现实变更请求 POST /api/sessions/:id/requests
+// 未指定地点时以当前场景为失败时添加过载的地点
+func (h *GameplayHandler) SubmitRequest(c *gin.Context) {
+	var req service.RealityChangeRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	session, err := h.missionSession(c.Param("id"), missionPhases)
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+	if req.LocationID == "" {
+		req.LocationID = session.State.CurrentSceneID
+	}
+
+	// 先校验请求，无效的请求不消耗会话骰子
+	if err := h.requestService.ValidateRequest(&req); err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	agent, err := h.agentService.GetAgent(session.AgentID)
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	roll := h.diceService.ForSession(session).RollForQuality(agent, req.Quality)
+	result, err := h.requestService.ProcessRequest(agent, session, &req, roll)
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	record := domain.NewRollRecord(session.ID, agent.ID, domain.RollKindRequest, roll)
+	record.Quality = req.Quality
+	record.Request = req.Effect
+	tripleAscension, err := h.sessionRolls.Settle(session, record)
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	if err := h.gameService.SaveSession(session); err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"result":            result,
+			"roll":              roll,
+			"chaos_pool":        h.chaosService.GetChaosPool(session),
+			"established_facts": h.requestService.GetEstablishedFacts(session),
+			"triple_ascension":  tripleAscension,
+		},
+	})
+}
+
+// GetInteractions 查询当前场景可用的交互 GET /api/sessions/:id/scene/interactions
+func (h *GameplayHandler) GetInteractions(c *gin.Context) {
+	interactions, err := h.sceneService.GetAvailableInteractions(c.Param("id"))
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    interactions,
+	})
+}
+
+// InteractWithObject 与当前场景的对象交互 POST /api/sessions/:id/scene/objects/:objectId/interact
+func (h *GameplayHandler) InteractWithObject(c *gin.Context) {
+	var req struct {
+		Action string `json:"action"`
+	}
+	if !h.bind(c, &req) {
+		return
+	}
+	if req.Action == "" {
+		req.Action = "调查"
+	}
+
+	session, err := h.missionSession(c.Param("id"), investigationPhases)
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	result, err := h.sceneService.InteractWithObject(session.ID, c.Param("objectId"), req.Action)
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
+
+// GetClues 查询已收集和当前可收集的线索 GET /api/sessions/:id/clues
+func (h *GameplayHandler) GetClues(c *gin.Context) {
+	sessionID := c.Param("id")
+
+	collected, err := h.clueService.GetCollectedClues(sessionID)
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+	available, err := h.clueService.GetAvailableClues(sessionID)
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"collected": collected,
+			"available": available,
+		},
+	})
+}
+
+// GetClueProgress 查询线索进度 GET /api/sessions/:id/clues/progress
+func (h *GameplayHandler) GetClueProgress(c *gin.Context) {
+	progress, err := h.clueService.GetClueProgress(c.Param("id"))
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    progress,
+	})
+}
+
+// GetInvestigationReport 生成调查报告 GET /api/sessions/:id/clues/report
+func (h *GameplayHandler) GetInvestigationReport(c *gin.Context) {
+	report, err := h.clueService.GenerateInvestigationReport(c.Param("id"))
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    report,
+	})
+}
+
+// ListNPCs 查询当前场景中的NPC GET /api/sessions/:id/npcs
+func (h *GameplayHandler) ListNPCs(c *gin.Context) {
+	session, err := h.gameService.GetSession(c.Param("id"))
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	npcs, err := h.npcService.GetNPCsInScene(session.ID, session.State.CurrentSceneID)
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    npcs,
+	})
+}
+
+// GetNPC 查询NPC GET /api/sessions/:id/npcs/:npcId
+func (h *GameplayHandler) GetNPC(c *gin.Context) {
+	npc, err := h.npcService.LoadNPC(c.Param("id"), c.Param("npcId"))
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    npc,
+	})
+}
+
+// TalkToNPC 与当前场景中的NPC对话 POST /api/sessions/:id/npcs/:npcId/talk
+func (h *GameplayHandler) TalkToNPC(c *gin.Context) {
+	var req struct {
+		PlayerAction string `json:"player_action"`
+	}
+	if !h.bind(c, &req) {
+		return
+	}
+
+	session, err := h.missionSession(c.Param("id"), missionPhases)
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	scene, err := h.sceneService.GetCurrentScene(session.ID)
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	npcID := c.Param("npcId")
+	var npc *domain.NPC
+	for _, n := range scene.NPCs {
+		if n.ID == npcID {
+			npc = n
+			break
+		}
+	}
+	if npc == nil {
+		respondGameError(c, domain.NewGameError(domain.ErrNotFound, "当前场景中没有该NPC").
+			WithDetails("npc_id", npcID).
+			WithDetails("scene_id", scene.ID))
+		return
+	}
+
+	// 首次对话时初始化NPC状态
+	if _, err := h.npcService.LoadNPC(session.ID, npcID); err != nil {
+		respondGameError(c, err)
+		return
+	}
+	state, err := h.npcService.GetNPCState(session.ID, npcID)
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	dialogue, err := h.aiService.GenerateNPCDialogue(npc, &service.DialogueContext{
+		PlayerAction: req.PlayerAction,
+		SceneID:      scene.ID,
+		GamePhase:    session.Phase,
+		NPCState:     state,
+	})
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"npc_id":    npc.ID,
+			"name":      npc.Name,
+			"dialogue":  dialogue,
+			"npc_state": state,
+		},
+	})
+}
+
+// GetChaos 查询混沌池和地点过载 GET /api/sessions/:id/chaos
+func (h *GameplayHandler) GetChaos(c *gin.Context) {
+	session, err := h.gameService.GetSession(c.Param("id"))
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	overloads := session.State.LocationOverloads
+	if overloads == nil {
+		overloads = map[string]int{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"chaos_pool":         h.chaosService.GetChaosPool(session),
+			"loose_ends":         session.State.LooseEnds,
+			"location_overloads": overloads,
+		},
+	})
+}
+
+// missionSession 获取会话并检查当前阶段是否允许该操作
+func (h *GameplayHandler) missionSession(sessionID string, allowed []domain.GamePhase) (*domain.GameSession, error) {
+	session, err := h.gameService.GetSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, phase := range allowed {
+		if session.Phase == phase {
+			return session, nil
+		}
+	}
+	return nil, domain.NewGameError(domain.ErrInvalidPhase, "当前阶段不能进行该操作").
+		WithDetails("current_phase", session.Phase).
+		WithDetails("allowed_phases", allowed)
+}
+
+// bind 解析请求体，失败时返回400
+func (h *GameplayHandler) bind(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "请求参数无效: " + err.Error(),
+		})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,214 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+	"github.com/trpg-solo-engine/backend/internal/service"
+)
+
+func TestGameplayHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	agentService := service.NewAgentService()
+	scenarioService := service.NewScenarioService("../../scenarios")
+	gameService := service.NewGameServiceWithAgents(scenarioService, agentService, nil)
+	dice := domain.NewDiceService()
+	chaos := service.NewChaosService()
+	ledger := service.NewRollLedgerService()
+	sessionRolls := service.NewSessionRollService(ledger, service.NewTripleAscensionService(agentService, gameService, service.NewAIService(), nil))
+	gameplayHandler := NewGameplayHandler(gameService, agentService, dice,
+		service.NewAbilityService(dice, service.NewQAService(dice), chaos),
+		service.NewRequestService(dice, chaos),
+		service.NewSceneService(scenarioService, gameService),
+		service.NewClueService(scenarioService, gameService),
+		service.NewNPCService(scenarioService, gameService),
+		chaos,
+		service.NewAIService(),
+		sessionRolls,
+	)
+
+	router := gin.New()
+	sessions := router.Group("/api/sessions")
+	{
+		sessions.POST("/:id/abilities/:abilityId/use", gameplayHandler.UseAbility)
+		sessions.POST("/:id/requests", gameplayHandler.SubmitRequest)
+		sessions.GET("/:id/scene/interactions", gameplayHandler.GetInteractions)
+		sessions.POST("/:id/scene/objects/:objectId/interact", gameplayHandler.InteractWithObject)
+		sessions.GET("/:id/clues", gameplayHandler.GetClues)
+		sessions.GET("/:id/clues/progress", gameplayHandler.GetClueProgress)
+		sessions.GET("/:id/clues/report", gameplayHandler.GetInvestigationReport)
+		sessions.GET("/:id/npcs", gameplayHandler.ListNPCs)
+		sessions.GET("/:id/npcs/:npcId", gameplayHandler.GetNPC)
+		sessions.POST("/:id/npcs/:npcId/talk", gameplayHandler.TalkToNPC)
+		sessions.GET("/:id/chaos", gameplayHandler.GetChaos)
+	}
+
+	agent, err := agentService.CreateAgent(&service.CreateAgentRequest{
+		Name:        "玩法测试",
+		AnomalyType: domain.AnomalyWhisper,
+		RealityType: domain.RealityCaretaker,
+		CareerType:  domain.CareerPublicRelations,
+	})
+	require.NoError(t, err)
+	session, err := gameService.CreateSession(agent.ID, "eternal-spring")
+	require.NoError(t, err)
+	base := "/api/sessions/" + session.ID
+
+	do := func(method, path string, body interface{}) (*httptest.ResponseRecorder, map[string]interface{}) {
+		data, _ := json.Marshal(body)
+		req, _ := http.NewRequest(method, path, bytes.NewReader(data))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return w, response
+	}
+
+	request := map[string]interface{}{
+		"effect":       "商业街的监控录像恰好拍到了泉水的来源",
+		"causal_chain": "商场保安昨晚检查过摄像头，录像保存在服务器中",
+		"quality":      domain.QualityProfession,
+	}
+
+	t.Run("晨会阶段不能进行任务操作", func(t *testing.T) {
+		w, response := do("POST", base+"/requests", request)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		details := response["details"].(map[string]interface{})
+		assert.Equal(t, string(domain.PhaseMorning), details["current_phase"])
+
+		w, _ = do("POST", base+"/scene/objects/similar-appearances/interact", map[string]interface{}{})
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		w, _ = do("POST", base+"/npcs/maya-ng/talk", map[string]interface{}{})
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("工作外使用异常能力记录申诫", func(t *testing.T) {
+		w, response := do("POST", base+"/abilities/whisper-tip-tongue/use", map[string]interface{}{"target_id": "maya-ng"})
+		require.Equal(t, http.StatusOK, w.Code)
+		data := response["data"].(map[string]interface{})
+		result := data["result"].(map[string]interface{})
+		assert.Equal(t, true, result["reprimand_added"])
+
+		stored, err := agentService.GetAgent(agent.ID)
+		require.NoError(t, err)
+		assert.Equal(t, float64(stored.Reprimands), data["reprimands"])
+
+		// 会话掷骰写入账本
+		rolls, err := ledger.ListRolls(session.ID, nil)
+		require.NoError(t, err)
+		require.Len(t, rolls, 1)
+		assert.Equal(t, domain.RollKindAbility, rolls[0].Kind)
+		assert.Equal(t, "whisper-tip-tongue", rolls[0].AbilityID)
+
+		w, _ = do("POST", base+"/abilities/unknown/use", map[string]interface{}{})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	require.NoError(t, gameService.TransitionPhase(session.ID, domain.PhaseInvestigation))
+
+	t.Run("现实变更请求", func(t *testing.T) {
+		w, _ := do("POST", base+"/requests", map[string]interface{}{"effect": "缺少因果链"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		invalid := map[string]interface{}{
+			"effect":       request["effect"],
+			"causal_chain": request["causal_chain"],
+			"quality":      "魅力",
+		}
+		w, _ = do("POST", base+"/requests", invalid)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w, response := do("POST", base+"/requests", request)
+		require.Equal(t, http.StatusOK, w.Code)
+		data := response["data"].(map[string]interface{})
+		result := data["result"].(map[string]interface{})
+		require.NotNil(t, data["roll"])
+
+		rolls, err := ledger.ListRolls(session.ID, &domain.RollFilter{Quality: domain.QualityProfession})
+		require.NoError(t, err)
+		require.Len(t, rolls, 1)
+		assert.Equal(t, domain.RollKindRequest, rolls[0].Kind)
+		assert.Equal(t, request["effect"], rolls[0].Request)
+
+		w, chaos := do("GET", base+"/chaos", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		pool := chaos["data"].(map[string]interface{})
+		assert.Equal(t, data["chaos_pool"], pool["chaos_pool"])
+
+		if result["success"] == true {
+			assert.Contains(t, data["established_facts"], request["effect"])
+
+			// 已确立的事实不能再改变
+			w, _ = do("POST", base+"/requests", request)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		} else {
+			overloads := pool["location_overloads"].(map[string]interface{})
+			assert.Equal(t, float64(1), overloads["commercial-avenue"], "失败时为当前场景添加过载")
+		}
+	})
+
+	t.Run("场景交互和线索", func(t *testing.T) {
+		w, response := do("GET", base+"/scene/interactions", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.NotEmpty(t, response["data"])
+
+		w, response = do("POST", base+"/scene/objects/similar-appearances/interact", map[string]interface{}{})
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, response["data"].(map[string]interface{})["clues_gained"], "similar-appearances")
+
+		w, _ = do("POST", base+"/scene/objects/similar-appearances/interact", map[string]interface{}{})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		// 需要先与Maya交谈
+		w, _ = do("POST", base+"/scene/objects/okafi-products/interact", map[string]interface{}{})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w, _ = do("POST", base+"/scene/objects/unknown/interact", map[string]interface{}{})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		w, response = do("GET", base+"/clues", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Len(t, response["data"].(map[string]interface{})["collected"], 1)
+
+		// 请求成功时确立的既定事实不计入线索进度
+		w, response = do("GET", base+"/clues/progress", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, float64(1), response["data"].(map[string]interface{})["collected_count"])
+
+		w, response = do("GET", base+"/clues/report", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, response["data"].(map[string]interface{})["unlocked_scenes"], "the-source")
+	})
+
+	t.Run("与NPC对话", func(t *testing.T) {
+		w, response := do("GET", base+"/npcs", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Len(t, response["data"], 1)
+
+		w, response = do("POST", base+"/npcs/maya-ng/talk", map[string]interface{}{"player_action": "询问泉水的事"})
+		require.Equal(t, http.StatusOK, w.Code)
+		data := response["data"].(map[string]interface{})
+		assert.Contains(t, data["dialogue"], data["name"])
+
+		w, _ = do("GET", base+"/npcs/maya-ng", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		// 不在当前场景的NPC
+		w, _ = do("POST", base+"/npcs/serena-evermore/talk", map[string]interface{}{})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	w, _ := do("GET", "/api/sessions/non-existent/chaos", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
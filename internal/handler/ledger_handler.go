@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+	"github.com/trpg-solo-engine/backend/internal/service"
+)
+
+type LedgerHandler struct {
+	ledgerService service.LedgerService
+}
+
+func NewLedgerHandler(ledgerService service.LedgerService) *LedgerHandler {
+	return &LedgerHandler{
+		ledgerService: ledgerService,
+	}
+}
+
+// GetLedger 查询角色的嘉奖/申诫账本 GET /api/agents/:id/ledger
+// 支持按 reason 和 session_id 筛选
+func (h *LedgerHandler) GetLedger(c *gin.Context) {
+	filter := &domain.LedgerFilter{
+		Reason:    domain.LedgerReason(c.Query("reason")),
+		SessionID: c.Query("session_id"),
+	}
+
+	ledger, err := h.ledgerService.GetLedger(c.Param("id"), filter)
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    ledger,
+	})
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+	"github.com/trpg-solo-engine/backend/internal/service"
+)
+
+func TestLedgerHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	agentService := service.NewAgentService()
+	agentHandler := NewAgentHandler(agentService)
+	ledgerHandler := NewLedgerHandler(service.NewLedgerService(agentService))
+
+	router := gin.New()
+	agents := router.Group("/api/agents")
+	{
+		agents.PUT("/:id", agentHandler.UpdateAgent)
+		agents.GET("/:id/ledger", ledgerHandler.GetLedger)
+	}
+
+	agent, err := agentService.CreateAgent(&service.CreateAgentRequest{
+		Name:        "账本测试",
+		AnomalyType: domain.AnomalyWhisper,
+		RealityType: domain.RealityCaretaker,
+		CareerType:  domain.CareerPublicRelations,
+	})
+	require.NoError(t, err)
+	base := "/api/agents/" + agent.ID
+
+	do := func(method, path string, body interface{}) (*httptest.ResponseRecorder, map[string]interface{}) {
+		data, _ := json.Marshal(body)
+		req, _ := http.NewRequest(method, path, bytes.NewReader(data))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return w, response
+	}
+
+	// 手动修改计数记为调整条目
+	w, _ := do("PUT", base, map[string]interface{}{
+		"commendations": 4,
+		"reprimands":    2,
+		"justification": "补录上次任务的奖惩",
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w, response := do("GET", base+"/ledger", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	data := response["data"].(map[string]interface{})
+	assert.Equal(t, float64(1), data["count"])
+	entry := data["entries"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "adjustment", entry["reason"])
+	assert.Equal(t, "补录上次任务的奖惩", entry["justification"])
+	assert.Equal(t, float64(4), entry["commendations"])
+	assert.Equal(t, float64(2), entry["reprimands"])
+	reconciliation := data["reconciliation"].(map[string]interface{})
+	assert.Equal(t, true, reconciliation["balanced"])
+
+	w, response = do("GET", base+"/ledger?reason=capture_bonus", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, float64(0), response["data"].(map[string]interface{})["count"])
+
+	w, _ = do("GET", base+"/ledger?reason=bribe", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w, _ = do("GET", "/api/agents/missing/ledger", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/trpg-solo-engine/backend/internal/service"
+)
+
+type MorningHandler struct {
+	gameService service.GameService
+}
+
+func NewMorningHandler(gameService service.GameService) *MorningHandler {
+	return &MorningHandler{
+		gameService: gameService,
+	}
+}
+
+// GetMorning 晨会简报、可选目标和晨会场景 GET /api/sessions/:id/morning
+func (h *MorningHandler) GetMorning(c *gin.Context) {
+	result, err := h.gameService.StartMorningPhase(c.Param("id"))
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
+
+// ChooseGoals 选择可选目标 POST /api/sessions/:id/morning/goals
+func (h *MorningHandler) ChooseGoals(c *gin.Context) {
+	var req struct {
+		GoalIDs []string `json:"goal_ids"`
+	}
+	if !h.bind(c, &req) {
+		return
+	}
+
+	goals, err := h.gameService.ChooseGoals(c.Param("id"), req.GoalIDs)
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    goals,
+	})
+}
+
+// ResolveScene 完成晨会场景 POST /api/sessions/:id/morning/scenes/:sceneId
+func (h *MorningHandler) ResolveScene(c *gin.Context) {
+	var req service.MorningSceneRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	result, err := h.gameService.ResolveMorningScene(c.Param("id"), c.Param("sceneId"), &req)
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
+
+// bind 解析请求体，失败时返回400
+func (h *MorningHandler) bind(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "请求参数无效: " + err.Error(),
+		})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+	"github.com/trpg-solo-engine/backend/internal/service"
+)
+
+func TestMorningHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	agentService := service.NewAgentService()
+	gameService := service.NewGameServiceWithAgents(service.NewScenarioService("../../scenarios"), agentService, nil)
+	morningHandler := NewMorningHandler(gameService)
+
+	router := gin.New()
+	sessions := router.Group("/api/sessions")
+	{
+		sessions.GET("/:id/morning", morningHandler.GetMorning)
+		sessions.POST("/:id/morning/goals", morningHandler.ChooseGoals)
+		sessions.POST("/:id/morning/scenes/:sceneId", morningHandler.ResolveScene)
+	}
+
+	agent, err := agentService.CreateAgent(&service.CreateAgentRequest{
+		Name:        "晨会测试",
+		AnomalyType: domain.AnomalyWhisper,
+		RealityType: domain.RealityCaretaker,
+		CareerType:  domain.CareerPublicRelations,
+	})
+	require.NoError(t, err)
+	session, err := gameService.CreateSession(agent.ID, "eternal-spring")
+	require.NoError(t, err)
+	base := "/api/sessions/" + session.ID + "/morning"
+
+	do := func(method, path string, body interface{}) (*httptest.ResponseRecorder, map[string]interface{}) {
+		data, _ := json.Marshal(body)
+		req, _ := http.NewRequest(method, path, bytes.NewReader(data))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return w, response
+	}
+
+	w, response := do("GET", base, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	data := response["data"].(map[string]interface{})
+	assert.Len(t, data["goals"], 3)
+	assert.Len(t, data["scenes"], 4)
+
+	w, response = do("POST", base+"/goals", map[string]interface{}{"goal_ids": []string{"minimal-exposure"}})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Len(t, response["data"], 1)
+
+	w, _ = do("POST", base+"/goals", map[string]interface{}{"goal_ids": []string{"unknown"}})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// 跳过场景
+	w, _ = do("POST", base+"/scenes/news-report", map[string]interface{}{})
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w, response = do("POST", base+"/scenes/morning-routine", map[string]interface{}{})
+	require.Equal(t, http.StatusOK, w.Code)
+	next := response["data"].(map[string]interface{})["next_scene"].(map[string]interface{})
+	assert.Equal(t, "relationship-moment", next["id"])
+
+	w, _ = do("POST", base+"/scenes/relationship-moment", map[string]interface{}{})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w, response = do("POST", base+"/scenes/relationship-moment", map[string]interface{}{
+		"relationship_id": agent.Relationships[0].ID,
+		"delta":           -2,
+		"note":            "请对方帮忙打听消息",
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+	change := response["data"].(map[string]interface{})["change"].(map[string]interface{})
+	assert.Equal(t, float64(-2), change["delta"])
+
+	w, _ = do("GET", "/api/sessions/non-existent/morning", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
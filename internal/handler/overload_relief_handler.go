@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/trpg-solo-engine/backend/internal/service"
+)
+
+type OverloadReliefHandler struct {
+	reliefService service.OverloadReliefService
+}
+
+func NewOverloadReliefHandler(reliefService service.OverloadReliefService) *OverloadReliefHandler {
+	return &OverloadReliefHandler{
+		reliefService: reliefService,
+	}
+}
+
+// ClaimRelief 援引现实条件申请过载解除 POST /api/sessions/:id/overload-relief
+func (h *OverloadReliefHandler) ClaimRelief(c *gin.Context) {
+	var req service.ClaimReliefRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "请求参数无效: " + err.Error(),
+		})
+		return
+	}
+
+	relief, err := h.reliefService.ClaimRelief(c.Param("id"), &req)
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    relief,
+	})
+}
+
+// GetRelief 查询当前生效的过载解除 GET /api/sessions/:id/overload-relief
+func (h *OverloadReliefHandler) GetRelief(c *gin.Context) {
+	relief, err := h.reliefService.GetRelief(c.Param("id"))
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"active": relief != nil,
+			"relief": relief,
+		},
+	})
+}
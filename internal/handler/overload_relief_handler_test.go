@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+	"github.com/trpg-solo-engine/backend/internal/service"
+)
+
+func setupOverloadReliefTestRouter(t *testing.T) (*gin.Engine, *domain.Agent, *domain.GameSession) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	agentService := service.NewAgentService()
+	gameService := service.NewGameService()
+	reliefHandler := NewOverloadReliefHandler(service.NewOverloadReliefService(agentService, gameService, nil))
+
+	router.POST("/api/sessions/:id/overload-relief", reliefHandler.ClaimRelief)
+	router.GET("/api/sessions/:id/overload-relief", reliefHandler.GetRelief)
+
+	agent, err := agentService.CreateAgent(&service.CreateAgentRequest{
+		Name:        "测试角色",
+		AnomalyType: domain.AnomalyWhisper,
+		RealityType: domain.RealityCaretaker,
+		CareerType:  domain.CareerPublicRelations,
+	})
+	assert.NoError(t, err)
+
+	session, err := gameService.CreateSession(agent.ID, "test-scenario")
+	assert.NoError(t, err)
+
+	return router, agent, session
+}
+
+// TestClaimOverloadRelief 测试申请过载解除
+func TestClaimOverloadRelief(t *testing.T) {
+	router, agent, session := setupOverloadReliefTestRouter(t)
+
+	tests := []struct {
+		name       string
+		request    map[string]interface{}
+		wantStatus int
+	}{
+		{
+			name:       "缺少说明",
+			request:    map[string]interface{}{"condition": agent.Reality.OverloadRelief.Condition},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "条件不符",
+			request:    map[string]interface{}{"condition": "其他条件", "justification": "说明"},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "申请成功",
+			request:    map[string]interface{}{"condition": agent.Reality.OverloadRelief.Condition, "justification": "说明"},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "重复申请",
+			request:    map[string]interface{}{"condition": agent.Reality.OverloadRelief.Condition, "justification": "说明"},
+			wantStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(tt.request)
+			req, _ := http.NewRequest("POST", "/api/sessions/"+session.ID+"/overload-relief", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+
+	req, _ := http.NewRequest("GET", "/api/sessions/"+session.ID+"/overload-relief", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Data struct {
+			Active bool                        `json:"active"`
+			Relief *domain.OverloadReliefClaim `json:"relief"`
+		} `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.True(t, response.Data.Active)
+	assert.Equal(t, domain.OverloadReliefScopeScene, response.Data.Relief.Scope)
+}
+
+// TestClaimOverloadRelief_SessionNotFound 测试会话不存在
+func TestClaimOverloadRelief_SessionNotFound(t *testing.T) {
+	router, _, _ := setupOverloadReliefTestRouter(t)
+
+	body, _ := json.Marshal(map[string]interface{}{"condition": "条件", "justification": "说明"})
+	req, _ := http.NewRequest("POST", "/api/sessions/non-existent/overload-relief", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/trpg-solo-engine/backend/internal/service"
+)
+
+type RealityTriggerHandler struct {
+	triggerService service.RealityTriggerService
+}
+
+func NewRealityTriggerHandler(triggerService service.RealityTriggerService) *RealityTriggerHandler {
+	return &RealityTriggerHandler{
+		triggerService: triggerService,
+	}
+}
+
+// GetTrigger 查询等待回应的现实触发器 GET /api/sessions/:id/reality-trigger
+func (h *RealityTriggerHandler) GetTrigger(c *gin.Context) {
+	event, err := h.triggerService.GetTrigger(c.Param("id"))
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"pending": event != nil,
+			"trigger": event,
+		},
+	})
+}
+
+// Answer 回应现实触发器 POST /api/sessions/:id/reality-trigger/answer
+func (h *RealityTriggerHandler) Answer(c *gin.Context) {
+	var req service.AnswerTriggerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "请求参数无效: " + err.Error(),
+		})
+		return
+	}
+
+	event, err := h.triggerService.Answer(c.Param("id"), &req)
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    event,
+	})
+}
+
+// Ignore 忽视现实触发器并应用后果 POST /api/sessions/:id/reality-trigger/ignore
+func (h *RealityTriggerHandler) Ignore(c *gin.Context) {
+	event, err := h.triggerService.Ignore(c.Param("id"))
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    event,
+	})
+}
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+	"github.com/trpg-solo-engine/backend/internal/service"
+)
+
+func TestRealityTriggerHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	agentService := service.NewAgentService()
+	gameService := service.NewGameServiceWithAgents(service.NewScenarioService("../../scenarios"), agentService, nil)
+	triggers := service.NewRealityTriggerService(agentService, gameService, nil)
+	sessionHandler := NewSessionHandlerWithTriggers(gameService, triggers)
+	triggerHandler := NewRealityTriggerHandler(triggers)
+
+	router := gin.New()
+	sessions := router.Group("/api/sessions")
+	{
+		sessions.POST("/:id/actions", sessionHandler.ExecuteAction)
+		sessions.POST("/:id/phase", sessionHandler.TransitionPhase)
+		sessions.GET("/:id/reality-trigger", triggerHandler.GetTrigger)
+		sessions.POST("/:id/reality-trigger/answer", triggerHandler.Answer)
+		sessions.POST("/:id/reality-trigger/ignore", triggerHandler.Ignore)
+	}
+
+	agent, err := agentService.CreateAgent(&service.CreateAgentRequest{
+		Name:        "现实测试",
+		AnomalyType: domain.AnomalyWhisper,
+		RealityType: domain.RealityHunted,
+		CareerType:  domain.CareerHotline,
+	})
+	require.NoError(t, err)
+	session, err := gameService.CreateSession(agent.ID, "eternal-spring")
+	require.NoError(t, err)
+	base := "/api/sessions/" + session.ID
+
+	do := func(method, path string, body interface{}) (*httptest.ResponseRecorder, map[string]interface{}) {
+		data, _ := json.Marshal(body)
+		req, _ := http.NewRequest(method, path, bytes.NewReader(data))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return w, response
+	}
+
+	w, _ := do("POST", base+"/reality-trigger/ignore", nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	// 进入调查阶段时触发
+	w, response := do("POST", base+"/phase", gin.H{"phase": "investigation"})
+	require.Equal(t, http.StatusOK, w.Code)
+	trigger, ok := response["reality_trigger"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, "踪迹暴露", trigger["name"])
+
+	w, response = do("GET", base+"/reality-trigger", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, true, response["data"].(map[string]interface{})["pending"])
+
+	// 待回应期间的行动不会再次触发
+	w, response = do("POST", base+"/actions", gin.H{"action_type": "move_to_scene", "target": "the-source"})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Nil(t, response["reality_trigger"])
+
+	w, response = do("POST", base+"/reality-trigger/ignore", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	applied := response["data"].(map[string]interface{})["applied"].(map[string]interface{})
+	assert.Equal(t, domain.IgnoreEffectLoseConnection, applied["type"])
+
+	stored, err := agentService.GetAgent(agent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 11, stored.TotalConnection())
+
+	w, _ = do("POST", base+"/reality-trigger/answer", gin.H{"response": "已处理"})
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w, _ = do("GET", "/api/sessions/non-existent/reality-trigger", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+// failingTriggers 检查现实触发器时总是失败
+type failingTriggers struct {
+	service.RealityTriggerService
+}
+
+func (failingTriggers) Evaluate(sessionID string, cause domain.RealityTriggerCause) (*domain.RealityTriggerEvent, error) {
+	return nil, domain.NewGameError(domain.ErrInternal, "触发器存储不可用")
+}
+
+func TestSessionHandler_TriggerErrorKeepsActionResult(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	gameService := service.NewGameService()
+	sessionHandler := NewSessionHandlerWithTriggers(gameService, failingTriggers{})
+
+	router := gin.New()
+	router.POST("/api/sessions/:id/actions", sessionHandler.ExecuteAction)
+
+	session, err := gameService.CreateSession("agent-1", "scenario-1")
+	require.NoError(t, err)
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"action_type": "update_npc_state",
+		"target":      "npc-1",
+		"parameters":  map[string]interface{}{"status": "警惕"},
+	})
+	req, _ := http.NewRequest("POST", "/api/sessions/"+session.ID+"/actions", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// 行动已经生效，触发器错误单独报告
+	require.Equal(t, http.StatusOK, w.Code)
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, true, response["success"])
+	assert.NotNil(t, response["data"])
+	assert.Contains(t, response["reality_trigger_error"], "触发器存储不可用")
+
+	state, err := gameService.GetState(session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "警惕", state.NPCStates["npc-1"].CurrentState)
+}
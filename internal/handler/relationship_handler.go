@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/trpg-solo-engine/backend/internal/service"
+)
+
+type RelationshipHandler struct {
+	relationshipService service.RelationshipService
+}
+
+func NewRelationshipHandler(relationshipService service.RelationshipService) *RelationshipHandler {
+	return &RelationshipHandler{
+		relationshipService: relationshipService,
+	}
+}
+
+// ListRelationships 列出人际关系 GET /api/agents/:id/relationships
+func (h *RelationshipHandler) ListRelationships(c *gin.Context) {
+	relationships, err := h.relationshipService.ListRelationships(c.Param("id"))
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    relationships,
+	})
+}
+
+// GetRelationship 获取人际关系 GET /api/agents/:id/relationships/:relId
+func (h *RelationshipHandler) GetRelationship(c *gin.Context) {
+	rel, err := h.relationshipService.GetRelationship(c.Param("id"), c.Param("relId"))
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    rel,
+	})
+}
+
+// AddRelationship 添加人际关系 POST /api/agents/:id/relationships
+func (h *RelationshipHandler) AddRelationship(c *gin.Context) {
+	var req service.AddRelationshipRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	rel, err := h.relationshipService.AddRelationship(c.Param("id"), &req)
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    rel,
+	})
+}
+
+// UpdateRelationship 编辑人际关系 PUT /api/agents/:id/relationships/:relId
+func (h *RelationshipHandler) UpdateRelationship(c *gin.Context) {
+	var req service.UpdateRelationshipRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	rel, err := h.relationshipService.UpdateRelationship(c.Param("id"), c.Param("relId"), &req)
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    rel,
+	})
+}
+
+// DeleteRelationship 删除人际关系 DELETE /api/agents/:id/relationships/:relId
+func (h *RelationshipHandler) DeleteRelationship(c *gin.Context) {
+	if err := h.relationshipService.DeleteRelationship(c.Param("id"), c.Param("relId")); err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "人际关系已删除",
+	})
+}
+
+// AddNote 追加笔记 POST /api/agents/:id/relationships/:relId/notes
+func (h *RelationshipHandler) AddNote(c *gin.Context) {
+	var req service.RelationshipNoteRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	rel, err := h.relationshipService.AddNote(c.Param("id"), c.Param("relId"), &req)
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    rel,
+	})
+}
+
+// ChangeConnection 调整连结 POST /api/agents/:id/relationships/:relId/connection
+func (h *RelationshipHandler) ChangeConnection(c *gin.Context) {
+	var req service.ChangeConnectionRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	result, err := h.relationshipService.ChangeConnection(c.Param("id"), c.Param("relId"), &req)
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
+
+// SpendConnection 在会话中花费连结 POST /api/sessions/:id/relationships/spend
+func (h *RelationshipHandler) SpendConnection(c *gin.Context) {
+	var req service.SpendConnectionRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	result, err := h.relationshipService.SpendConnection(c.Param("id"), &req)
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
+
+// bind 解析请求体，失败时返回400
+func (h *RelationshipHandler) bind(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "请求参数无效: " + err.Error(),
+		})
+		return false
+	}
+	return true
+}
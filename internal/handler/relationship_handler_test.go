@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+	"github.com/trpg-solo-engine/backend/internal/service"
+)
+
+func TestRelationshipHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	agentService := service.NewAgentService()
+	gameService := service.NewGameServiceWithAgents(nil, agentService, nil)
+	relationshipHandler := NewRelationshipHandler(service.NewRelationshipService(agentService, gameService))
+
+	router := gin.New()
+	agents := router.Group("/api/agents")
+	{
+		agents.GET("/:id/relationships", relationshipHandler.ListRelationships)
+		agents.POST("/:id/relationships", relationshipHandler.AddRelationship)
+		agents.GET("/:id/relationships/:relId", relationshipHandler.GetRelationship)
+		agents.PUT("/:id/relationships/:relId", relationshipHandler.UpdateRelationship)
+		agents.DELETE("/:id/relationships/:relId", relationshipHandler.DeleteRelationship)
+		agents.POST("/:id/relationships/:relId/notes", relationshipHandler.AddNote)
+		agents.POST("/:id/relationships/:relId/connection", relationshipHandler.ChangeConnection)
+	}
+	router.POST("/api/sessions/:id/relationships/spend", relationshipHandler.SpendConnection)
+
+	agent, err := agentService.CreateAgent(&service.CreateAgentRequest{
+		Name:        "关系测试",
+		AnomalyType: domain.AnomalyWhisper,
+		RealityType: domain.RealityCaretaker,
+		CareerType:  domain.CareerPublicRelations,
+	})
+	require.NoError(t, err)
+	session, err := gameService.CreateSession(agent.ID, "test-scenario")
+	require.NoError(t, err)
+	base := "/api/agents/" + agent.ID + "/relationships"
+
+	do := func(method, path string, body interface{}) (*httptest.ResponseRecorder, map[string]interface{}) {
+		data, _ := json.Marshal(body)
+		req, _ := http.NewRequest(method, path, bytes.NewReader(data))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return w, response
+	}
+
+	w, response := do("GET", base, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Len(t, response["data"], 3)
+
+	w, _ = do("POST", base, map[string]string{})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w, response = do("POST", base, map[string]interface{}{"name": "新邻居", "played_by": "玩家2"})
+	require.Equal(t, http.StatusCreated, w.Code)
+	relID := response["data"].(map[string]interface{})["id"].(string)
+
+	w, response = do("PUT", base+"/"+relID, map[string]string{"description": "住在楼下"})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "住在楼下", response["data"].(map[string]interface{})["description"])
+
+	w, response = do("POST", base+"/"+relID+"/notes", map[string]string{"note": "养了一只猫"})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Len(t, response["data"].(map[string]interface{})["notes"], 1)
+
+	w, response = do("POST", base+"/"+relID+"/connection", map[string]interface{}{"delta": 2, "reason": "帮忙搬家"})
+	require.Equal(t, http.StatusOK, w.Code)
+	rel := response["data"].(map[string]interface{})["relationship"].(map[string]interface{})
+	assert.Equal(t, float64(3), rel["connection"])
+
+	spend := "/api/sessions/" + session.ID + "/relationships/spend"
+	w, response = do("POST", spend, map[string]interface{}{"relationship_id": relID, "amount": 5, "reason": "求助"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.NotNil(t, response["details"])
+
+	w, _ = do("POST", spend, map[string]interface{}{"relationship_id": relID, "reason": "求助"})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w, _ = do("DELETE", base+"/"+relID, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w, _ = do("GET", base+"/"+relID, nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
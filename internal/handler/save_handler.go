@@ -4,7 +4,6 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
-	"github.com/trpg-solo-engine/backend/internal/domain"
 	"github.com/trpg-solo-engine/backend/internal/service"
 )
 
@@ -38,27 +37,7 @@ func (h *SaveHandler) CreateSave(c *gin.Context) {
 	// 创建存档
 	snapshot, err := h.saveService.CreateSave(req.SessionID, req.Name)
 	if err != nil {
-		// 根据错误类型返回不同的状态码
-		if gameErr, ok := err.(*domain.GameError); ok {
-			switch gameErr.Code {
-			case domain.ErrNotFound:
-				c.JSON(http.StatusNotFound, gin.H{
-					"success": false,
-					"error":   err.Error(),
-				})
-				return
-			case domain.ErrInvalidInput:
-				c.JSON(http.StatusBadRequest, gin.H{
-					"success": false,
-					"error":   err.Error(),
-				})
-				return
-			}
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+		respondGameError(c, err)
 		return
 	}
 
@@ -94,19 +73,7 @@ func (h *SaveHandler) GetSave(c *gin.Context) {
 
 	snapshot, err := h.saveService.GetSave(saveID)
 	if err != nil {
-		if gameErr, ok := err.(*domain.GameError); ok {
-			if gameErr.Code == domain.ErrNotFound {
-				c.JSON(http.StatusNotFound, gin.H{
-					"success": false,
-					"error":   err.Error(),
-				})
-				return
-			}
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+		respondGameError(c, err)
 		return
 	}
 
@@ -123,35 +90,13 @@ func (h *SaveHandler) LoadSave(c *gin.Context) {
 	// 加载存档（这会创建一个新的会话ID）
 	session, err := h.saveService.LoadSave(saveID)
 	if err != nil {
-		if gameErr, ok := err.(*domain.GameError); ok {
-			switch gameErr.Code {
-			case domain.ErrNotFound:
-				c.JSON(http.StatusNotFound, gin.H{
-					"success": false,
-					"error":   err.Error(),
-				})
-				return
-			case domain.ErrDataCorrupted:
-				c.JSON(http.StatusUnprocessableEntity, gin.H{
-					"success": false,
-					"error":   err.Error(),
-				})
-				return
-			}
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+		respondGameError(c, err)
 		return
 	}
 
-	// 将加载的会话注册到游戏服务
+	// 将加载的会话注册到游戏服务，角色不能开始任务时拒绝加载
 	if err := h.gameService.RegisterSession(session); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "注册会话失败: " + err.Error(),
-		})
+		respondGameError(c, err)
 		return
 	}
 
@@ -170,19 +115,7 @@ func (h *SaveHandler) DeleteSave(c *gin.Context) {
 	saveID := c.Param("id")
 
 	if err := h.saveService.DeleteSave(saveID); err != nil {
-		if gameErr, ok := err.(*domain.GameError); ok {
-			if gameErr.Code == domain.ErrNotFound {
-				c.JSON(http.StatusNotFound, gin.H{
-					"success": false,
-					"error":   err.Error(),
-				})
-				return
-			}
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+		respondGameError(c, err)
 		return
 	}
 
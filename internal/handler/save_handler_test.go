@@ -19,7 +19,7 @@ func setupSaveTestRouter() (*gin.Engine, *SaveHandler, service.GameService, serv
 
 	// 创建服务
 	agentService := service.NewAgentService()
-	gameService := service.NewGameService()
+	gameService := service.NewGameServiceWithAgents(nil, agentService, nil)
 	saveService := service.NewSaveService(gameService, agentService)
 
 	// 创建处理器
@@ -398,6 +398,19 @@ func TestSaveHandler_LoadSave(t *testing.T) {
 			}
 		})
 	}
+
+	t.Run("角色死亡时不能加载存档", func(t *testing.T) {
+		_, err := agentService.ModifyAgent(agent.ID, func(agent *domain.Agent) error {
+			agent.Alive = false
+			return nil
+		})
+		require.NoError(t, err)
+
+		req, _ := http.NewRequest("POST", "/api/saves/"+saveID+"/load", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
 }
 
 // TestSaveHandler_DeleteSave 测试存档删除
@@ -4,7 +4,6 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
-	"github.com/trpg-solo-engine/backend/internal/domain"
 	"github.com/trpg-solo-engine/backend/internal/service"
 )
 
@@ -49,26 +48,7 @@ func (h *ScenarioHandler) GetScenario(c *gin.Context) {
 
 	scenario, err := h.scenarioService.LoadScenario(scenarioID)
 	if err != nil {
-		if gameErr, ok := err.(*domain.GameError); ok {
-			if gameErr.Code == domain.ErrNotFound {
-				c.JSON(http.StatusNotFound, gin.H{
-					"success": false,
-					"error":   err.Error(),
-				})
-				return
-			}
-			if gameErr.Code == domain.ErrDataCorrupted {
-				c.JSON(http.StatusUnprocessableEntity, gin.H{
-					"success": false,
-					"error":   err.Error(),
-				})
-				return
-			}
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+		respondGameError(c, err)
 		return
 	}
 
@@ -101,19 +81,7 @@ func (h *ScenarioHandler) GetScene(c *gin.Context) {
 
 	scene, err := h.scenarioService.GetScene(scenarioID, sceneID)
 	if err != nil {
-		if gameErr, ok := err.(*domain.GameError); ok {
-			if gameErr.Code == domain.ErrNotFound {
-				c.JSON(http.StatusNotFound, gin.H{
-					"success": false,
-					"error":   err.Error(),
-				})
-				return
-			}
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+		respondGameError(c, err)
 		return
 	}
 
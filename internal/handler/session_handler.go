@@ -9,12 +9,33 @@ import (
 )
 
 type SessionHandler struct {
-	gameService service.GameService
+	gameService     service.GameService
+	npcs            service.NPCService            // 更新NPC状态
+	realityTriggers service.RealityTriggerService // 可选，行动和阶段转换后检查现实触发器
+	store           service.StoreService          // 可选，进入余波阶段时发放剧本奖励物品
 }
 
 func NewSessionHandler(gameService service.GameService) *SessionHandler {
+	return NewSessionHandlerWithStore(gameService, nil, nil)
+}
+
+// NewSessionHandlerWithTriggers 创建在行动和阶段转换后检查现实触发器的会话处理器
+func NewSessionHandlerWithTriggers(gameService service.GameService, realityTriggers service.RealityTriggerService) *SessionHandler {
+	return NewSessionHandlerWithStore(gameService, realityTriggers, nil)
+}
+
+// NewSessionHandlerWithStore 创建检查现实触发器并在余波阶段发放剧本奖励物品的会话处理器
+func NewSessionHandlerWithStore(gameService service.GameService, realityTriggers service.RealityTriggerService, store service.StoreService) *SessionHandler {
+	return NewSessionHandlerWithServices(gameService, realityTriggers, store, service.NewNPCService(nil, gameService))
+}
+
+// NewSessionHandlerWithServices 创建通过指定的NPC服务更新NPC状态的会话处理器
+func NewSessionHandlerWithServices(gameService service.GameService, realityTriggers service.RealityTriggerService, store service.StoreService, npcs service.NPCService) *SessionHandler {
 	return &SessionHandler{
-		gameService: gameService,
+		gameService:     gameService,
+		npcs:            npcs,
+		realityTriggers: realityTriggers,
+		store:           store,
 	}
 }
 
@@ -35,27 +56,7 @@ func (h *SessionHandler) CreateSession(c *gin.Context) {
 
 	session, err := h.gameService.CreateSession(req.AgentID, req.ScenarioID)
 	if err != nil {
-		// 根据错误类型返回不同的状态码
-		if gameErr, ok := err.(*domain.GameError); ok {
-			switch gameErr.Code {
-			case domain.ErrInvalidInput, domain.ErrInvalidARC:
-				c.JSON(http.StatusBadRequest, gin.H{
-					"success": false,
-					"error":   err.Error(),
-				})
-				return
-			case domain.ErrNotFound:
-				c.JSON(http.StatusNotFound, gin.H{
-					"success": false,
-					"error":   err.Error(),
-				})
-				return
-			}
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+		respondGameError(c, err)
 		return
 	}
 
@@ -71,19 +72,7 @@ func (h *SessionHandler) GetSession(c *gin.Context) {
 
 	session, err := h.gameService.GetSession(sessionID)
 	if err != nil {
-		if gameErr, ok := err.(*domain.GameError); ok {
-			if gameErr.Code == domain.ErrNotFound {
-				c.JSON(http.StatusNotFound, gin.H{
-					"success": false,
-					"error":   err.Error(),
-				})
-				return
-			}
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+		respondGameError(c, err)
 		return
 	}
 
@@ -111,30 +100,18 @@ func (h *SessionHandler) ExecuteAction(c *gin.Context) {
 		return
 	}
 
-	// 获取会话
-	session, err := h.gameService.GetSession(sessionID)
-	if err != nil {
-		if gameErr, ok := err.(*domain.GameError); ok {
-			if gameErr.Code == domain.ErrNotFound {
-				c.JSON(http.StatusNotFound, gin.H{
-					"success": false,
-					"error":   err.Error(),
-				})
-				return
-			}
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+	// 确认会话存在
+	if _, err := h.gameService.GetSession(sessionID); err != nil {
+		respondGameError(c, err)
 		return
 	}
 
 	// 根据行动类型执行不同的逻辑
 	var result interface{}
+	var err error
 	switch req.ActionType {
 	case "move_to_scene":
-		// 移动到场景
+		// 移动到与当前场景相连或已解锁的场景
 		if req.Target == "" {
 			c.JSON(http.StatusBadRequest, gin.H{
 				"success": false,
@@ -142,19 +119,21 @@ func (h *SessionHandler) ExecuteAction(c *gin.Context) {
 			})
 			return
 		}
-		err = h.gameService.UpdateState(sessionID, func(state *domain.GameState) error {
-			state.CurrentSceneID = req.Target
-			state.VisitedScenes[req.Target] = true
-			return nil
-		})
-		result = gin.H{
-			"action":   "move_to_scene",
-			"scene_id": req.Target,
-			"message":  "已移动到场景: " + req.Target,
+		var moved *service.SceneMoveResult
+		moved, err = h.gameService.MoveToScene(sessionID, req.Target)
+		if err == nil {
+			result = gin.H{
+				"action":           "move_to_scene",
+				"scene_id":         req.Target,
+				"from_scene_id":    moved.FromSceneID,
+				"first_visit":      moved.FirstVisit,
+				"reachable_scenes": moved.ReachableScenes,
+				"message":          "已移动到场景: " + moved.Scene.Name,
+			}
 		}
 
 	case "collect_clue":
-		// 收集线索
+		// 收集当前场景中的线索
 		if req.Target == "" {
 			c.JSON(http.StatusBadRequest, gin.H{
 				"success": false,
@@ -162,66 +141,15 @@ func (h *SessionHandler) ExecuteAction(c *gin.Context) {
 			})
 			return
 		}
-		err = h.gameService.UpdateState(sessionID, func(state *domain.GameState) error {
-			// 检查是否已收集
-			for _, clue := range state.CollectedClues {
-				if clue == req.Target {
-					return domain.NewGameError(domain.ErrInvalidAction, "线索已收集")
-				}
-			}
-			state.CollectedClues = append(state.CollectedClues, req.Target)
-			return nil
-		})
-		result = gin.H{
-			"action":  "collect_clue",
-			"clue_id": req.Target,
-			"message": "已收集线索: " + req.Target,
-		}
-
-	case "unlock_location":
-		// 解锁地点
-		if req.Target == "" {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"success": false,
-				"error":   "地点ID不能为空",
-			})
-			return
-		}
-		err = h.gameService.UpdateState(sessionID, func(state *domain.GameState) error {
-			// 检查是否已解锁
-			for _, loc := range state.UnlockedLocations {
-				if loc == req.Target {
-					return domain.NewGameError(domain.ErrInvalidAction, "地点已解锁")
-				}
+		var collected *service.ClueCollectResult
+		collected, err = h.gameService.CollectClue(sessionID, req.Target)
+		if err == nil {
+			result = gin.H{
+				"action":   "collect_clue",
+				"clue_id":  req.Target,
+				"unlocked": collected.Unlocked,
+				"message":  "已收集线索: " + collected.Clue.Name,
 			}
-			state.UnlockedLocations = append(state.UnlockedLocations, req.Target)
-			return nil
-		})
-		result = gin.H{
-			"action":      "unlock_location",
-			"location_id": req.Target,
-			"message":     "已解锁地点: " + req.Target,
-		}
-
-	case "add_chaos":
-		// 添加混沌
-		amount, ok := req.Parameters["amount"].(float64)
-		if !ok {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"success": false,
-				"error":   "混沌数量参数无效",
-			})
-			return
-		}
-		err = h.gameService.UpdateState(sessionID, func(state *domain.GameState) error {
-			state.ChaosPool += int(amount)
-			return nil
-		})
-		result = gin.H{
-			"action":     "add_chaos",
-			"amount":     int(amount),
-			"chaos_pool": session.State.ChaosPool + int(amount),
-			"message":    "已添加混沌",
 		}
 
 	case "update_npc_state":
@@ -233,30 +161,7 @@ func (h *SessionHandler) ExecuteAction(c *gin.Context) {
 			})
 			return
 		}
-		err = h.gameService.UpdateState(sessionID, func(state *domain.GameState) error {
-			if state.NPCStates == nil {
-				state.NPCStates = make(map[string]*domain.NPCState)
-			}
-			// 创建或更新NPC状态
-			npcState := &domain.NPCState{
-				ID:              req.Target,
-				CurrentState:    "",
-				AnomalyAffected: false,
-				Relationship:    0,
-				CustomData:      make(map[string]interface{}),
-			}
-			if status, ok := req.Parameters["status"].(string); ok {
-				npcState.CurrentState = status
-			}
-			if influenced, ok := req.Parameters["anomaly_affected"].(bool); ok {
-				npcState.AnomalyAffected = influenced
-			}
-			if relationship, ok := req.Parameters["relationship"].(float64); ok {
-				npcState.Relationship = int(relationship)
-			}
-			state.NPCStates[req.Target] = npcState
-			return nil
-		})
+		err = h.updateNPCState(sessionID, req.Target, req.Parameters)
 		result = gin.H{
 			"action":  "update_npc_state",
 			"npc_id":  req.Target,
@@ -272,33 +177,17 @@ func (h *SessionHandler) ExecuteAction(c *gin.Context) {
 	}
 
 	if err != nil {
-		if gameErr, ok := err.(*domain.GameError); ok {
-			switch gameErr.Code {
-			case domain.ErrInvalidAction, domain.ErrInvalidInput:
-				c.JSON(http.StatusBadRequest, gin.H{
-					"success": false,
-					"error":   err.Error(),
-				})
-				return
-			case domain.ErrInvalidPhase:
-				c.JSON(http.StatusConflict, gin.H{
-					"success": false,
-					"error":   err.Error(),
-				})
-				return
-			}
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+		respondGameError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	response := gin.H{
 		"success": true,
 		"data":    result,
-	})
+	}
+	h.evaluateTrigger(sessionID, domain.TriggerCauseAction, response)
+
+	c.JSON(http.StatusOK, response)
 }
 
 // TransitionPhase 转换阶段 POST /api/sessions/:id/phase
@@ -330,26 +219,7 @@ func (h *SessionHandler) TransitionPhase(c *gin.Context) {
 	// 执行阶段转换
 	err := h.gameService.TransitionPhase(sessionID, phase)
 	if err != nil {
-		if gameErr, ok := err.(*domain.GameError); ok {
-			switch gameErr.Code {
-			case domain.ErrNotFound:
-				c.JSON(http.StatusNotFound, gin.H{
-					"success": false,
-					"error":   err.Error(),
-				})
-				return
-			case domain.ErrInvalidPhase:
-				c.JSON(http.StatusConflict, gin.H{
-					"success": false,
-					"error":   err.Error(),
-				})
-				return
-			}
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+		respondGameError(c, err)
 		return
 	}
 
@@ -363,14 +233,76 @@ func (h *SessionHandler) TransitionPhase(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	response := gin.H{
 		"success": true,
 		"data": gin.H{
 			"session_id": sessionID,
 			"phase":      session.Phase,
 			"message":    "阶段已转换为: " + string(phase),
 		},
-	})
+	}
+	h.evaluateTrigger(sessionID, domain.TriggerCausePhase, response)
+
+	// 进入余波阶段时发放剧本奖励物品
+	if phase == domain.PhaseAftermath && h.store != nil {
+		rewards, err := h.store.GrantScenarioRewards(sessionID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+		response["rewards"] = rewards
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// updateNPCState 通过NPC服务更新参数中提供的字段，未提供的字段保持不变
+func (h *SessionHandler) updateNPCState(sessionID, npcID string, params map[string]interface{}) error {
+	status, hasStatus := params["status"].(string)
+	affected, hasAffected := params["anomaly_affected"].(bool)
+	relationship, hasRelationship := params["relationship"].(float64)
+	if !hasStatus && !hasAffected && !hasRelationship {
+		return domain.NewGameError(domain.ErrInvalidInput, "没有要更新的NPC字段").
+			WithDetails("allowed_parameters", []string{"status", "anomaly_affected", "relationship"})
+	}
+
+	if hasStatus {
+		if err := h.npcs.UpdateNPCState(sessionID, npcID, status); err != nil {
+			return err
+		}
+	}
+	if hasAffected {
+		if err := h.npcs.SetAnomalyAffected(sessionID, npcID, affected); err != nil {
+			return err
+		}
+	}
+	if hasRelationship {
+		if err := h.npcs.SetRelationship(sessionID, npcID, int(relationship)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// evaluateTrigger 检查现实触发器，触发时在响应中附带 reality_trigger
+// 行动或阶段转换已经生效，检查失败时不改变响应状态，在 reality_trigger_error 中报告
+func (h *SessionHandler) evaluateTrigger(sessionID string, cause domain.RealityTriggerCause, response gin.H) {
+	if h.realityTriggers == nil {
+		return
+	}
+
+	event, err := h.realityTriggers.Evaluate(sessionID, cause)
+	if err != nil {
+		response["reality_trigger_error"] = err.Error()
+		return
+	}
+
+	if event != nil {
+		response["reality_trigger"] = event
+	}
 }
 
 // isValidPhase 验证阶段是否有效
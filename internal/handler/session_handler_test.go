@@ -18,7 +18,7 @@ func setupSessionTestRouter() (*gin.Engine, *SessionHandler, service.GameService
 	gin.SetMode(gin.TestMode)
 
 	// 创建服务
-	gameService := service.NewGameService()
+	gameService := service.NewGameServiceWithScenarios(service.NewScenarioService("../../scenarios"), nil)
 
 	// 创建处理器
 	handler := NewSessionHandler(gameService)
@@ -138,6 +138,7 @@ func TestSessionHandler_GetSession(t *testing.T) {
 	json.Unmarshal(w.Body.Bytes(), &createResponse)
 	sessionData := createResponse["data"].(map[string]interface{})
 	sessionID := sessionData["id"].(string)
+	assert.NotContains(t, sessionData, "dice_seed", "骰子种子不应返回给客户端")
 
 	tests := []struct {
 		name           string
@@ -186,7 +187,7 @@ func TestSessionHandler_GetSession(t *testing.T) {
 
 // TestSessionHandler_ExecuteAction 测试执行行动
 func TestSessionHandler_ExecuteAction(t *testing.T) {
-	router, _, _ := setupSessionTestRouter()
+	router, _, gameService := setupSessionTestRouter()
 
 	// 先创建一个会话
 	createReq := map[string]string{
@@ -204,6 +205,9 @@ func TestSessionHandler_ExecuteAction(t *testing.T) {
 	sessionData := createResponse["data"].(map[string]interface{})
 	sessionID := sessionData["id"].(string)
 
+	// 场景移动和线索收集只能在调查阶段进行，调查从剧本的起始场景开始
+	require.NoError(t, gameService.TransitionPhase(sessionID, domain.PhaseInvestigation))
+
 	tests := []struct {
 		name           string
 		sessionID      string
@@ -212,46 +216,96 @@ func TestSessionHandler_ExecuteAction(t *testing.T) {
 		expectSuccess  bool
 	}{
 		{
-			name:      "移动到场景",
+			name:      "收集线索",
 			sessionID: sessionID,
 			action: map[string]interface{}{
-				"action_type": "move_to_scene",
-				"target":      "scene-001",
+				"action_type": "collect_clue",
+				"target":      "similar-appearances",
 			},
 			expectedStatus: http.StatusOK,
 			expectSuccess:  true,
 		},
 		{
-			name:      "收集线索",
+			name:      "收集需求未满足的线索",
+			sessionID: sessionID,
+			action: map[string]interface{}{
+				"action_type": "collect_clue",
+				"target":      "okafi-products",
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectSuccess:  false,
+		},
+		{
+			name:      "收集不在当前场景的线索",
+			sessionID: sessionID,
+			action: map[string]interface{}{
+				"action_type": "collect_clue",
+				"target":      "plant-growth",
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectSuccess:  false,
+		},
+		{
+			name:      "收集不存在的线索",
 			sessionID: sessionID,
 			action: map[string]interface{}{
 				"action_type": "collect_clue",
 				"target":      "clue-001",
 			},
+			expectedStatus: http.StatusNotFound,
+			expectSuccess:  false,
+		},
+		{
+			name:      "移动到场景",
+			sessionID: sessionID,
+			action: map[string]interface{}{
+				"action_type": "move_to_scene",
+				"target":      "the-source",
+			},
 			expectedStatus: http.StatusOK,
 			expectSuccess:  true,
 		},
 		{
-			name:      "解锁地点",
+			name:      "移动到不相连且未解锁的场景",
+			sessionID: sessionID,
+			action: map[string]interface{}{
+				"action_type": "move_to_scene",
+				"target":      "domain-entrance",
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectSuccess:  false,
+		},
+		{
+			name:      "移动到不存在的场景",
+			sessionID: sessionID,
+			action: map[string]interface{}{
+				"action_type": "move_to_scene",
+				"target":      "scene-001",
+			},
+			expectedStatus: http.StatusNotFound,
+			expectSuccess:  false,
+		},
+		{
+			name:      "不能直接解锁地点",
 			sessionID: sessionID,
 			action: map[string]interface{}{
 				"action_type": "unlock_location",
 				"target":      "location-001",
 			},
-			expectedStatus: http.StatusOK,
-			expectSuccess:  true,
+			expectedStatus: http.StatusBadRequest,
+			expectSuccess:  false,
 		},
 		{
-			name:      "添加混沌",
+			name:      "不能直接添加混沌",
 			sessionID: sessionID,
 			action: map[string]interface{}{
 				"action_type": "add_chaos",
 				"parameters": map[string]interface{}{
-					"amount": 3.0,
+					"amount": -3.0,
 				},
 			},
-			expectedStatus: http.StatusOK,
-			expectSuccess:  true,
+			expectedStatus: http.StatusBadRequest,
+			expectSuccess:  false,
 		},
 		{
 			name:      "更新NPC状态",
@@ -267,6 +321,16 @@ func TestSessionHandler_ExecuteAction(t *testing.T) {
 			expectedStatus: http.StatusOK,
 			expectSuccess:  true,
 		},
+		{
+			name:      "更新NPC状态时没有字段",
+			sessionID: sessionID,
+			action: map[string]interface{}{
+				"action_type": "update_npc_state",
+				"target":      "npc-001",
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectSuccess:  false,
+		},
 		{
 			name:      "缺少action_type",
 			sessionID: sessionID,
@@ -494,7 +558,7 @@ func TestSessionHandler_PhaseTransitionFlow(t *testing.T) {
 
 // TestSessionHandler_ActionSequence 测试行动序列
 func TestSessionHandler_ActionSequence(t *testing.T) {
-	router, _, _ := setupSessionTestRouter()
+	router, _, gameService := setupSessionTestRouter()
 
 	// 创建会话
 	createReq := map[string]string{
@@ -511,30 +575,25 @@ func TestSessionHandler_ActionSequence(t *testing.T) {
 	json.Unmarshal(w.Body.Bytes(), &createResponse)
 	sessionData := createResponse["data"].(map[string]interface{})
 	sessionID := sessionData["id"].(string)
+	require.NoError(t, gameService.TransitionPhase(sessionID, domain.PhaseInvestigation))
 
 	// 执行一系列行动
 	actions := []map[string]interface{}{
-		{
-			"action_type": "move_to_scene",
-			"target":      "scene-001",
-		},
 		{
 			"action_type": "collect_clue",
-			"target":      "clue-001",
+			"target":      "similar-appearances",
 		},
 		{
-			"action_type": "collect_clue",
-			"target":      "clue-002",
+			"action_type": "move_to_scene",
+			"target":      "the-source",
 		},
 		{
-			"action_type": "unlock_location",
-			"target":      "location-001",
+			"action_type": "move_to_scene",
+			"target":      "underground-access",
 		},
 		{
-			"action_type": "add_chaos",
-			"parameters": map[string]interface{}{
-				"amount": 2.0,
-			},
+			"action_type": "collect_clue",
+			"target":      "plant-growth",
 		},
 	}
 
@@ -563,21 +622,19 @@ func TestSessionHandler_ActionSequence(t *testing.T) {
 	state := sessionData["state"].(map[string]interface{})
 
 	// 验证状态更新
-	assert.Equal(t, "scene-001", state["current_scene_id"])
+	assert.Equal(t, "underground-access", state["current_scene_id"])
 
 	collectedClues := state["collected_clues"].([]interface{})
 	assert.Equal(t, 2, len(collectedClues), "应该收集了2个线索")
 
+	// 地点只能由线索解锁：the-source和domain-entrance
 	unlockedLocations := state["unlocked_locations"].([]interface{})
-	assert.Equal(t, 1, len(unlockedLocations), "应该解锁了1个地点")
-
-	chaosPool := int(state["chaos_pool"].(float64))
-	assert.Equal(t, 2, chaosPool, "混沌池应该有2点混沌")
+	assert.ElementsMatch(t, []interface{}{"the-source", "domain-entrance"}, unlockedLocations)
 }
 
 // TestSessionHandler_DuplicateClueCollection 测试重复收集线索
 func TestSessionHandler_DuplicateClueCollection(t *testing.T) {
-	router, _, _ := setupSessionTestRouter()
+	router, _, gameService := setupSessionTestRouter()
 
 	// 创建会话
 	createReq := map[string]string{
@@ -594,11 +651,12 @@ func TestSessionHandler_DuplicateClueCollection(t *testing.T) {
 	json.Unmarshal(w.Body.Bytes(), &createResponse)
 	sessionData := createResponse["data"].(map[string]interface{})
 	sessionID := sessionData["id"].(string)
+	require.NoError(t, gameService.TransitionPhase(sessionID, domain.PhaseInvestigation))
 
 	// 第一次收集线索
 	action := map[string]interface{}{
 		"action_type": "collect_clue",
-		"target":      "clue-001",
+		"target":      "similar-appearances",
 	}
 	body, _ = json.Marshal(action)
 	req, _ = http.NewRequest("POST", "/api/sessions/"+sessionID+"/actions", bytes.NewBuffer(body))
@@ -698,3 +756,47 @@ func TestSessionHandler_ErrorResponses(t *testing.T) {
 		})
 	}
 }
+
+// TestSessionHandler_ActionValidation 测试行动违反规则时返回结构化错误
+func TestSessionHandler_ActionValidation(t *testing.T) {
+	router, _, gameService := setupSessionTestRouter()
+
+	session, err := gameService.CreateSession("test-agent-id", "eternal-spring")
+	require.NoError(t, err)
+
+	do := func(action map[string]interface{}) (*httptest.ResponseRecorder, map[string]interface{}) {
+		body, _ := json.Marshal(action)
+		req, _ := http.NewRequest("POST", "/api/sessions/"+session.ID+"/actions", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return w, response
+	}
+
+	// 晨会阶段不能移动
+	w, response := do(map[string]interface{}{"action_type": "move_to_scene", "target": "the-source"})
+	assert.Equal(t, http.StatusConflict, w.Code)
+	details := response["details"].(map[string]interface{})
+	assert.Equal(t, string(domain.PhaseInvestigation), details["expected_phase"])
+
+	require.NoError(t, gameService.TransitionPhase(session.ID, domain.PhaseInvestigation))
+
+	w, response = do(map[string]interface{}{"action_type": "move_to_scene", "target": "underground-access"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	details = response["details"].(map[string]interface{})
+	assert.Equal(t, "commercial-avenue", details["current_scene_id"])
+	assert.NotEmpty(t, details["missing_requirements"])
+	assert.ElementsMatch(t, []interface{}{"the-source", "okafi-warehouse"}, details["reachable_scenes"])
+
+	w, response = do(map[string]interface{}{"action_type": "collect_clue", "target": "water-dreams"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	details = response["details"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"talk-to-maya"}, details["missing_requirements"])
+
+	w, response = do(map[string]interface{}{"action_type": "collect_clue", "target": "similar-appearances"})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []interface{}{"the-source"}, response["data"].(map[string]interface{})["unlocked"])
+}
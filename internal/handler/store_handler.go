@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/trpg-solo-engine/backend/internal/service"
+)
+
+type StoreHandler struct {
+	storeService service.StoreService
+}
+
+func NewStoreHandler(storeService service.StoreService) *StoreHandler {
+	return &StoreHandler{
+		storeService: storeService,
+	}
+}
+
+// ListItems 查看机构商店目录 GET /api/store
+// 指定 agent_id 时附带该角色能否购买的标记
+func (h *StoreHandler) ListItems(c *gin.Context) {
+	listing, err := h.storeService.ListItems(c.Query("agent_id"))
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    listing,
+	})
+}
+
+// Purchase 购买物品 POST /api/agents/:id/store/purchase
+func (h *StoreHandler) Purchase(c *gin.Context) {
+	var req service.PurchaseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "请求参数无效: " + err.Error(),
+		})
+		return
+	}
+
+	result, err := h.storeService.Purchase(c.Param("id"), &req)
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
+
+// GetInventory 查看角色物品栏 GET /api/agents/:id/inventory
+func (h *StoreHandler) GetInventory(c *gin.Context) {
+	items, err := h.storeService.GetInventory(c.Param("id"))
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"items": items,
+			"count": len(items),
+		},
+	})
+}
+
+// UseItem 在会话中使用物品 POST /api/sessions/:id/items/use
+func (h *StoreHandler) UseItem(c *gin.Context) {
+	var req service.UseItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "请求参数无效: " + err.Error(),
+		})
+		return
+	}
+
+	result, err := h.storeService.UseItem(c.Param("id"), &req)
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
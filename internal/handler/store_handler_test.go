@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+	"github.com/trpg-solo-engine/backend/internal/service"
+)
+
+func TestStoreHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	agentService := service.NewAgentService()
+	scenarioService := service.NewScenarioService("../../scenarios")
+	gameService := service.NewGameServiceWithAgents(scenarioService, agentService, nil)
+	store := service.NewStoreService(agentService, gameService, scenarioService, nil)
+	storeHandler := NewStoreHandler(store)
+	sessionHandler := NewSessionHandlerWithStore(gameService, nil, store)
+
+	router := gin.New()
+	router.GET("/api/store", storeHandler.ListItems)
+	router.POST("/api/agents/:id/store/purchase", storeHandler.Purchase)
+	router.GET("/api/agents/:id/inventory", storeHandler.GetInventory)
+	router.POST("/api/sessions/:id/items/use", storeHandler.UseItem)
+	router.POST("/api/sessions/:id/phase", sessionHandler.TransitionPhase)
+
+	agent, err := agentService.CreateAgent(&service.CreateAgentRequest{
+		Name:        "商店测试",
+		AnomalyType: domain.AnomalyWhisper,
+		RealityType: domain.RealityCaretaker,
+		CareerType:  domain.CareerPublicRelations,
+	})
+	require.NoError(t, err)
+	session, err := gameService.CreateSession(agent.ID, "eternal-spring")
+	require.NoError(t, err)
+
+	do := func(method, path string, body interface{}) (*httptest.ResponseRecorder, map[string]interface{}) {
+		var reader *bytes.Reader
+		if body != nil {
+			data, _ := json.Marshal(body)
+			reader = bytes.NewReader(data)
+		} else {
+			reader = bytes.NewReader(nil)
+		}
+		req, _ := http.NewRequest(method, path, reader)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return w, response
+	}
+
+	w, response := do("GET", "/api/store?agent_id="+agent.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	data := response["data"].(map[string]interface{})
+	assert.NotEmpty(t, data["items"])
+
+	w, _ = do("GET", "/api/store?agent_id=missing", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	purchase := "/api/agents/" + agent.ID + "/store/purchase"
+	w, _ = do("POST", purchase, map[string]string{})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w, response = do("POST", purchase, map[string]string{"item_id": "ripple-gun"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.NotNil(t, response["details"])
+
+	require.NoError(t, agentService.AddCommendations(agent.ID, 4))
+	w, response = do("POST", purchase, map[string]string{"item_id": "ripple-gun"})
+	require.Equal(t, http.StatusOK, w.Code)
+	item := response["data"].(map[string]interface{})["item"].(map[string]interface{})
+	itemID := item["id"].(string)
+
+	w, response = do("GET", "/api/agents/"+agent.ID+"/inventory", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, float64(2), response["data"].(map[string]interface{})["count"])
+
+	// 晨会阶段不能使用波纹枪
+	use := "/api/sessions/" + session.ID + "/items/use"
+	w, _ = do("POST", use, map[string]string{"item_id": itemID})
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	session.Phase = domain.PhaseEncounter
+	require.NoError(t, gameService.SaveSession(session))
+	w, response = do("POST", use, map[string]string{"item_id": itemID})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotEmpty(t, response["data"].(map[string]interface{})["narration"])
+
+	// 进入余波阶段时发放剧本奖励
+	w, response = do("POST", "/api/sessions/"+session.ID+"/phase", map[string]string{"phase": "aftermath"})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotEmpty(t, response["rewards"])
+}
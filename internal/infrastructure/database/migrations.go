@@ -15,15 +15,32 @@ type AgentModel struct {
 	CareerType    string `gorm:"type:varchar(50);not null"`
 	QA            string `gorm:"type:jsonb;not null"`
 	Relationships string `gorm:"type:jsonb;not null"`
+	Inventory     string `gorm:"type:jsonb;default:'[]'"`
 	Commendations int    `gorm:"default:0"`
 	Reprimands    int    `gorm:"default:0"`
 	Rating        string `gorm:"type:varchar(50);default:'评级良好'"`
 	Alive         bool   `gorm:"default:true"`
 	InDebt        bool   `gorm:"default:false"`
-	CreatedAt     int64  `gorm:"autoCreateTime"`
-	UpdatedAt     int64  `gorm:"autoUpdateTime"`
+
+	// 完整的ARC数据，ARCVersion为0的旧数据只有上面的类型字段
+	ARCVersion           int    `gorm:"column:arc_version;default:0;index"`
+	Anomaly              string `gorm:"type:jsonb"`
+	Reality              string `gorm:"type:jsonb"`
+	Career               string `gorm:"type:jsonb"`
+	Assessment           string `gorm:"type:jsonb;default:'[]'"`
+	PendingRealityChange bool   `gorm:"default:false"`
+	DegradationHistory   string `gorm:"type:jsonb;default:'[]'"`
+	Deaths               string `gorm:"type:jsonb;default:'[]'"`
+	LooseEnds            int    `gorm:"default:0"`
+
+	CreatedAt int64 `gorm:"autoCreateTime"`
+	UpdatedAt int64 `gorm:"autoUpdateTime"`
 }
 
+// AgentARCVersion 当前写入的ARC数据版本
+// 0: 只保存类型字符串；1: 保存完整的异常体、现实和职能
+const AgentARCVersion = 1
+
 func (AgentModel) TableName() string {
 	return "agents"
 }
@@ -35,6 +52,9 @@ type GameSessionModel struct {
 	ScenarioID string `gorm:"type:varchar(100);not null"`
 	Phase      string `gorm:"type:varchar(50);not null;index"`
 	State      string `gorm:"type:jsonb;not null"`
+	DiceSeed   int64  `gorm:"default:0"`
+	DiceCursor int64  `gorm:"default:0"`
+	Rules      string `gorm:"type:jsonb;default:'null'"`
 	CreatedAt  int64  `gorm:"autoCreateTime"`
 	UpdatedAt  int64  `gorm:"autoUpdateTime"`
 }
@@ -43,6 +63,31 @@ func (GameSessionModel) TableName() string {
 	return "game_sessions"
 }
 
+// RollModel 掷骰账本数据库模型（只追加）
+type RollModel struct {
+	ID        string `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	SessionID string `gorm:"type:uuid;not null;index"`
+	AgentID   string `gorm:"type:varchar(100);index"`
+	Kind      string `gorm:"type:varchar(20);not null"`
+	Quality   string `gorm:"type:varchar(50);index"`
+	AbilityID string `gorm:"type:varchar(100)"`
+	Request   string `gorm:"type:text"`
+	RawDice   string `gorm:"type:jsonb;not null"`
+	Dice      string `gorm:"type:jsonb;not null"`
+	Threes    int    `gorm:"default:0"`
+	Success   bool   `gorm:"default:false"`
+	QASpent   int    `gorm:"default:0"`
+	Overload  int    `gorm:"default:0"`
+	Chaos     int    `gorm:"default:0"`
+	TripleAsc bool   `gorm:"default:false"`
+	Effect    string `gorm:"type:varchar(30)"`
+	CreatedAt int64  `gorm:"autoCreateTime:nano"`
+}
+
+func (RollModel) TableName() string {
+	return "dice_rolls"
+}
+
 // SaveModel 存档数据库模型
 type SaveModel struct {
 	ID        string `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
@@ -56,6 +101,39 @@ func (SaveModel) TableName() string {
 	return "saves"
 }
 
+// AgentDraftModel 角色草稿数据库模型
+type AgentDraftModel struct {
+	ID        string `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Step      string `gorm:"type:varchar(30);not null"`
+	AgentID   string `gorm:"type:varchar(100)"`
+	Data      string `gorm:"type:jsonb;not null"`
+	CreatedAt int64  `gorm:"autoCreateTime"`
+	UpdatedAt int64  `gorm:"autoUpdateTime"`
+}
+
+func (AgentDraftModel) TableName() string {
+	return "agent_drafts"
+}
+
+// LedgerEntryModel 角色绩效账本数据库模型（只追加）
+type LedgerEntryModel struct {
+	ID            string `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	AgentID       string `gorm:"type:uuid;not null;index"`
+	SessionID     string `gorm:"type:varchar(100);index"`
+	SceneID       string `gorm:"type:varchar(100)"`
+	Reason        string `gorm:"type:varchar(50);not null;index"`
+	Behavior      string `gorm:"type:text"`
+	Justification string `gorm:"type:text"`
+	FlaggedBy     string `gorm:"type:varchar(10)"`
+	Commendations int    `gorm:"default:0"`
+	Reprimands    int    `gorm:"default:0"`
+	CreatedAt     int64  `gorm:"autoCreateTime:nano"`
+}
+
+func (LedgerEntryModel) TableName() string {
+	return "agent_ledger_entries"
+}
+
 // RunMigrations 执行数据库迁移
 func RunMigrations(db *gorm.DB, log *zap.Logger) error {
 	log.Info("running database migrations...")
@@ -64,7 +142,10 @@ func RunMigrations(db *gorm.DB, log *zap.Logger) error {
 	if err := db.AutoMigrate(
 		&AgentModel{},
 		&GameSessionModel{},
+		&RollModel{},
 		&SaveModel{},
+		&AgentDraftModel{},
+		&LedgerEntryModel{},
 	); err != nil {
 		return err
 	}
@@ -93,6 +174,11 @@ func createIndexes(db *gorm.DB) error {
 		return err
 	}
 
+	// 为dice_rolls表创建索引
+	if err := db.Exec("CREATE INDEX IF NOT EXISTS idx_dice_rolls_session ON dice_rolls(session_id, created_at)").Error; err != nil {
+		return err
+	}
+
 	// 为saves表创建索引
 	if err := db.Exec("CREATE INDEX IF NOT EXISTS idx_saves_session ON saves(session_id)").Error; err != nil {
 		return err
@@ -7,10 +7,12 @@ import (
 	"time"
 
 	"github.com/redis/go-redis/v9"
+	"github.com/trpg-solo-engine/backend/internal/catalog"
 	"github.com/trpg-solo-engine/backend/internal/domain"
 	"github.com/trpg-solo-engine/backend/internal/infrastructure/database"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // AgentRepository 角色仓储接口
@@ -22,24 +24,42 @@ type AgentRepository interface {
 	Delete(ctx context.Context, id string) error
 	List(ctx context.Context) ([]*domain.Agent, error)
 
+	// 在事务中读取并修改角色，读取时锁定行，fn返回错误时回滚
+	Modify(ctx context.Context, id string, fn func(agent *domain.Agent) error) (*domain.Agent, error)
+
+	// 数据迁移：为只保存了ARC类型的旧角色补全完整ARC数据，返回迁移的行数
+	MigrateLegacyARC(ctx context.Context) (int, error)
+
 	// 事务支持
 	WithTx(tx *gorm.DB) AgentRepository
 }
 
+// agentCacheKeyPrefix 缓存键前缀，带版本以丢弃旧版本写入的不完整缓存
+const agentCacheKeyPrefix = "agent:v2:"
+
 type agentRepository struct {
-	db     *gorm.DB
-	redis  *redis.Client
-	logger *zap.Logger
-	ttl    time.Duration
+	db      *gorm.DB
+	redis   *redis.Client
+	logger  *zap.Logger
+	ttl     time.Duration
+	ledger  LedgerRepository
+	catalog *catalog.Catalog
 }
 
-// NewAgentRepository 创建角色仓储实例
+// NewAgentRepository 创建角色仓储实例，旧数据使用内嵌ARC配置重建
 func NewAgentRepository(db *gorm.DB, redis *redis.Client, logger *zap.Logger) AgentRepository {
+	return NewAgentRepositoryWithCatalog(db, redis, logger, catalog.Default())
+}
+
+// NewAgentRepositoryWithCatalog 创建使用指定ARC目录重建旧数据的角色仓储实例
+func NewAgentRepositoryWithCatalog(db *gorm.DB, redis *redis.Client, logger *zap.Logger, arcCatalog *catalog.Catalog) AgentRepository {
 	return &agentRepository{
-		db:     db,
-		redis:  redis,
-		logger: logger,
-		ttl:    1 * time.Hour, // 缓存1小时
+		db:      db,
+		redis:   redis,
+		logger:  logger,
+		ttl:     1 * time.Hour, // 缓存1小时
+		ledger:  NewLedgerRepository(db, logger),
+		catalog: arcCatalog,
 	}
 }
 
@@ -51,9 +71,15 @@ func (r *agentRepository) Create(ctx context.Context, agent *domain.Agent) error
 		return fmt.Errorf("failed to convert agent to model: %w", err)
 	}
 
-	// 保存到数据库
-	if err := r.db.WithContext(ctx).Create(model).Error; err != nil {
-		return fmt.Errorf("failed to create agent: %w", err)
+	// 保存到数据库，账本条目与角色在同一事务中写入
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(model).Error; err != nil {
+			return fmt.Errorf("failed to create agent: %w", err)
+		}
+		return r.ledger.WithTx(tx).Append(ctx, agent.Ledger)
+	})
+	if err != nil {
+		return err
 	}
 
 	// 更新ID（如果数据库生成了新ID）
@@ -91,6 +117,11 @@ func (r *agentRepository) GetByID(ctx context.Context, id string) (*domain.Agent
 		return nil, fmt.Errorf("failed to convert model to agent: %w", err)
 	}
 
+	// 加载绩效账本
+	if agent.Ledger, err = r.ledger.ListByAgent(ctx, id, nil); err != nil {
+		return nil, err
+	}
+
 	// 缓存到Redis
 	if err := r.cacheAgent(ctx, agent); err != nil {
 		r.logger.Warn("failed to cache agent", zap.Error(err), zap.String("agent_id", id))
@@ -107,32 +138,23 @@ func (r *agentRepository) Update(ctx context.Context, agent *domain.Agent) error
 		return fmt.Errorf("failed to convert agent to model: %w", err)
 	}
 
-	// 更新数据库
-	result := r.db.WithContext(ctx).Model(&database.AgentModel{}).
-		Where("id = ?", agent.ID).
-		Updates(map[string]interface{}{
-			"name":          model.Name,
-			"pronouns":      model.Pronouns,
-			"anomaly_type":  model.AnomalyType,
-			"reality_type":  model.RealityType,
-			"career_type":   model.CareerType,
-			"qa":            model.QA,
-			"relationships": model.Relationships,
-			"commendations": model.Commendations,
-			"reprimands":    model.Reprimands,
-			"rating":        model.Rating,
-			"alive":         model.Alive,
-			"in_debt":       model.InDebt,
-			"updated_at":    time.Now().Unix(),
-		})
+	// 更新数据库，新的账本条目与计数在同一事务中写入
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := updateAgentColumns(tx, model)
 
-	if result.Error != nil {
-		return fmt.Errorf("failed to update agent: %w", result.Error)
-	}
+		if result.Error != nil {
+			return fmt.Errorf("failed to update agent: %w", result.Error)
+		}
 
-	if result.RowsAffected == 0 {
-		return domain.NewGameError(domain.ErrNotFound, "角色不存在").
-			WithDetails("agent_id", agent.ID)
+		if result.RowsAffected == 0 {
+			return domain.NewGameError(domain.ErrNotFound, "角色不存在").
+				WithDetails("agent_id", agent.ID)
+		}
+
+		return r.ledger.WithTx(tx).Append(ctx, agent.Ledger)
+	})
+	if err != nil {
+		return err
 	}
 
 	// 使缓存失效
@@ -184,13 +206,118 @@ func (r *agentRepository) List(ctx context.Context) ([]*domain.Agent, error) {
 	return agents, nil
 }
 
+// Modify 在事务中读取并修改角色
+// 读取绕过缓存，Postgres下使用行锁，避免并发的嘉奖花费互相覆盖
+func (r *agentRepository) Modify(ctx context.Context, id string, fn func(agent *domain.Agent) error) (*domain.Agent, error) {
+	var agent *domain.Agent
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx
+		if tx.Dialector.Name() == "postgres" {
+			query = tx.Clauses(clause.Locking{Strength: "UPDATE"})
+		}
+
+		var model database.AgentModel
+		if err := query.Where("id = ?", id).First(&model).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return domain.NewGameError(domain.ErrNotFound, "角色不存在").
+					WithDetails("agent_id", id)
+			}
+			return fmt.Errorf("failed to get agent: %w", err)
+		}
+
+		var err error
+		if agent, err = r.toDomain(&model); err != nil {
+			return fmt.Errorf("failed to convert model to agent: %w", err)
+		}
+		txRepo := r.WithTx(tx).(*agentRepository)
+		if agent.Ledger, err = txRepo.ledger.ListByAgent(ctx, id, nil); err != nil {
+			return err
+		}
+
+		if err := fn(agent); err != nil {
+			return err
+		}
+		agent.UpdatedAt = time.Now()
+
+		updated, err := r.toModel(agent)
+		if err != nil {
+			return fmt.Errorf("failed to convert agent to model: %w", err)
+		}
+		if err := updateAgentColumns(tx, updated).Error; err != nil {
+			return fmt.Errorf("failed to update agent: %w", err)
+		}
+
+		return txRepo.ledger.Append(ctx, agent.Ledger)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 使缓存失效
+	if err := r.invalidateCache(ctx, id); err != nil {
+		r.logger.Warn("failed to invalidate cache", zap.Error(err), zap.String("agent_id", id))
+	}
+
+	return agent, nil
+}
+
+// MigrateLegacyARC 为只保存了ARC类型的旧角色补全完整ARC数据
+// 旧数据无法恢复的部分（自定义能力、退化进度等）按ARC目录的初始值填充，之后按完整数据读写
+func (r *agentRepository) MigrateLegacyARC(ctx context.Context) (int, error) {
+	var models []database.AgentModel
+	if err := r.db.WithContext(ctx).
+		Where("arc_version < ?", database.AgentARCVersion).
+		Find(&models).Error; err != nil {
+		return 0, fmt.Errorf("failed to list legacy agents: %w", err)
+	}
+
+	migrated := 0
+	for i := range models {
+		agent, err := r.toDomain(&models[i])
+		if err != nil {
+			r.logger.Warn("failed to convert legacy agent", zap.Error(err), zap.String("agent_id", models[i].ID))
+			continue
+		}
+
+		updated, err := r.toModel(agent)
+		if err != nil {
+			return migrated, fmt.Errorf("failed to convert agent to model: %w", err)
+		}
+
+		// 只在版本未变化时写入，避免覆盖迁移期间的并发更新
+		result := updateAgentColumns(
+			r.db.WithContext(ctx).Where("arc_version < ?", database.AgentARCVersion), updated)
+		if result.Error != nil {
+			return migrated, fmt.Errorf("failed to migrate agent: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+		migrated++
+
+		if err := r.invalidateCache(ctx, agent.ID); err != nil {
+			r.logger.Warn("failed to invalidate cache", zap.Error(err), zap.String("agent_id", agent.ID))
+		}
+	}
+
+	return migrated, nil
+}
+
+// updateAgentColumns 按主键写回角色的全部列（创建时间除外）
+// 所有写入路径共用同一份列集合，新增字段时不会因漏写某条路径而丢失数据
+func updateAgentColumns(db *gorm.DB, model *database.AgentModel) *gorm.DB {
+	return db.Model(model).Select("*").Omit("id", "created_at").Updates(model)
+}
+
 // WithTx 使用事务
 func (r *agentRepository) WithTx(tx *gorm.DB) AgentRepository {
 	return &agentRepository{
-		db:     tx,
-		redis:  r.redis,
-		logger: r.logger,
-		ttl:    r.ttl,
+		db:      tx,
+		redis:   r.redis,
+		logger:  r.logger,
+		ttl:     r.ttl,
+		ledger:  r.ledger.WithTx(tx),
+		catalog: r.catalog,
 	}
 }
 
@@ -208,6 +335,58 @@ func (r *agentRepository) toModel(agent *domain.Agent) (*database.AgentModel, er
 		return nil, fmt.Errorf("failed to marshal relationships: %w", err)
 	}
 
+	// 序列化物品栏
+	inventory := agent.Inventory
+	if inventory == nil {
+		inventory = []*domain.InventoryItem{}
+	}
+	inventoryJSON, err := json.Marshal(inventory)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal inventory: %w", err)
+	}
+
+	// 序列化完整的ARC
+	anomalyJSON, err := json.Marshal(agent.Anomaly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal anomaly: %w", err)
+	}
+	realityJSON, err := json.Marshal(agent.Reality)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal reality: %w", err)
+	}
+	careerJSON, err := json.Marshal(agent.Career)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal career: %w", err)
+	}
+
+	// 序列化职能评估和退化记录
+	assessment := agent.Assessment
+	if assessment == nil {
+		assessment = []*domain.AssessmentAnswer{}
+	}
+	assessmentJSON, err := json.Marshal(assessment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal assessment: %w", err)
+	}
+	history := agent.DegradationHistory
+	if history == nil {
+		history = []*domain.DegradationEntry{}
+	}
+	historyJSON, err := json.Marshal(history)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal degradation history: %w", err)
+	}
+
+	// 序列化死亡记录
+	deaths := agent.Deaths
+	if deaths == nil {
+		deaths = []*domain.DeathRecord{}
+	}
+	deathsJSON, err := json.Marshal(deaths)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal deaths: %w", err)
+	}
+
 	return &database.AgentModel{
 		ID:            agent.ID,
 		Name:          agent.Name,
@@ -217,13 +396,25 @@ func (r *agentRepository) toModel(agent *domain.Agent) (*database.AgentModel, er
 		CareerType:    agent.Career.Type,
 		QA:            string(qaJSON),
 		Relationships: string(relsJSON),
+		Inventory:     string(inventoryJSON),
 		Commendations: agent.Commendations,
 		Reprimands:    agent.Reprimands,
 		Rating:        agent.Rating,
 		Alive:         agent.Alive,
 		InDebt:        agent.InDebt,
-		CreatedAt:     agent.CreatedAt.Unix(),
-		UpdatedAt:     agent.UpdatedAt.Unix(),
+
+		ARCVersion:           database.AgentARCVersion,
+		Anomaly:              string(anomalyJSON),
+		Reality:              string(realityJSON),
+		Career:               string(careerJSON),
+		Assessment:           string(assessmentJSON),
+		PendingRealityChange: agent.PendingRealityChange,
+		DegradationHistory:   string(historyJSON),
+		Deaths:               string(deathsJSON),
+		LooseEnds:            agent.LooseEnds,
+
+		CreatedAt: agent.CreatedAt.Unix(),
+		UpdatedAt: agent.UpdatedAt.Unix(),
 	}, nil
 }
 
@@ -241,35 +432,52 @@ func (r *agentRepository) toDomain(model *database.AgentModel) (*domain.Agent, e
 		return nil, fmt.Errorf("failed to unmarshal relationships: %w", err)
 	}
 
-	// 创建基本的ARC组件（简化版本，实际应该从配置加载完整数据）
-	anomaly := &domain.Anomaly{
-		Type:      model.AnomalyType,
-		Abilities: createDefaultAbilities(model.AnomalyType),
+	// 反序列化物品栏（旧数据可能为空）
+	var inventory []*domain.InventoryItem
+	if model.Inventory != "" {
+		if err := json.Unmarshal([]byte(model.Inventory), &inventory); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal inventory: %w", err)
+		}
 	}
 
-	reality := &domain.Reality{
-		Type: model.RealityType,
-		Trigger: &domain.RealityTrigger{
-			Name:        "现实触发",
-			Cost:        0,
-			Effect:      "触发效果",
-			Consequence: "忽视后果",
-		},
-		OverloadRelief: &domain.OverloadRelief{
-			Name:      "过载解除",
-			Condition: "满足条件",
-			Effect:    "无视所有过载",
-		},
-		DegradationTrack: &domain.DegradationTrack{
-			Name:   "退化轨道",
-			Filled: 0,
-			Total:  4,
-		},
+	// 反序列化完整的ARC，旧数据按类型从ARC目录重建
+	var anomaly *domain.Anomaly
+	var reality *domain.Reality
+	var career *domain.Career
+	if model.ARCVersion >= 1 {
+		if err := json.Unmarshal([]byte(model.Anomaly), &anomaly); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal anomaly: %w", err)
+		}
+		if err := json.Unmarshal([]byte(model.Reality), &reality); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal reality: %w", err)
+		}
+		if err := json.Unmarshal([]byte(model.Career), &career); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal career: %w", err)
+		}
+	} else {
+		anomaly, reality, career = r.legacyARC(model, qa)
+	}
+
+	// 反序列化职能评估和退化记录（旧数据可能为空）
+	var assessment []*domain.AssessmentAnswer
+	if model.Assessment != "" {
+		if err := json.Unmarshal([]byte(model.Assessment), &assessment); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal assessment: %w", err)
+		}
+	}
+	var history []*domain.DegradationEntry
+	if model.DegradationHistory != "" {
+		if err := json.Unmarshal([]byte(model.DegradationHistory), &history); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal degradation history: %w", err)
+		}
 	}
 
-	career := &domain.Career{
-		Type: model.CareerType,
-		QA:   qa,
+	// 反序列化死亡记录（旧数据可能为空）
+	var deaths []*domain.DeathRecord
+	if model.Deaths != "" {
+		if err := json.Unmarshal([]byte(model.Deaths), &deaths); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal deaths: %w", err)
+		}
 	}
 
 	return &domain.Agent{
@@ -281,16 +489,81 @@ func (r *agentRepository) toDomain(model *database.AgentModel) (*domain.Agent, e
 		Career:        career,
 		QA:            qa,
 		Relationships: relationships,
+		Assessment:    assessment,
+		Inventory:     inventory,
 		Commendations: model.Commendations,
 		Reprimands:    model.Reprimands,
 		Rating:        model.Rating,
 		Alive:         model.Alive,
 		InDebt:        model.InDebt,
-		CreatedAt:     time.Unix(model.CreatedAt, 0),
-		UpdatedAt:     time.Unix(model.UpdatedAt, 0),
+
+		PendingRealityChange: model.PendingRealityChange,
+		DegradationHistory:   history,
+		Deaths:               deaths,
+		LooseEnds:            model.LooseEnds,
+
+		CreatedAt: time.Unix(model.CreatedAt, 0),
+		UpdatedAt: time.Unix(model.UpdatedAt, 0),
 	}, nil
 }
 
+// legacyARC 按类型从ARC目录重建旧数据的ARC组件
+// 目录中没有的类型只能构造占位数据，并记录警告
+func (r *agentRepository) legacyARC(model *database.AgentModel, qa map[string]int) (*domain.Anomaly, *domain.Reality, *domain.Career) {
+	anomaly, err := r.catalog.NewAnomaly(model.AnomalyType)
+	if err != nil {
+		r.logger.Warn("unknown legacy anomaly type", zap.String("agent_id", model.ID), zap.String("type", model.AnomalyType))
+		anomaly = &domain.Anomaly{
+			Type:      model.AnomalyType,
+			Abilities: createDefaultAbilities(model.AnomalyType),
+		}
+	}
+
+	reality, err := r.catalog.NewReality(model.RealityType)
+	if err != nil {
+		r.logger.Warn("unknown legacy reality type", zap.String("agent_id", model.ID), zap.String("type", model.RealityType))
+		reality = &domain.Reality{
+			Type: model.RealityType,
+			Trigger: &domain.RealityTrigger{
+				Name:        "现实触发",
+				Cost:        0,
+				Effect:      "触发效果",
+				Consequence: "忽视后果",
+			},
+			OverloadRelief: &domain.OverloadRelief{
+				Name:      "过载解除",
+				Condition: "满足条件",
+				Effect:    "无视所有过载",
+			},
+			DegradationTrack: &domain.DegradationTrack{
+				Name:   "退化轨道",
+				Filled: 0,
+				Total:  4,
+			},
+		}
+	}
+
+	career, err := r.catalog.NewCareer(model.CareerType)
+	if err != nil {
+		r.logger.Warn("unknown legacy career type", zap.String("agent_id", model.ID), zap.String("type", model.CareerType))
+		career = &domain.Career{
+			Type: model.CareerType,
+			QA:   copyQA(qa),
+		}
+	}
+
+	return anomaly, reality, career
+}
+
+// copyQA 复制资质保证分配
+func copyQA(qa map[string]int) map[string]int {
+	copied := make(map[string]int, len(qa))
+	for quality, value := range qa {
+		copied[quality] = value
+	}
+	return copied
+}
+
 // cacheAgent 缓存角色到Redis
 func (r *agentRepository) cacheAgent(ctx context.Context, agent *domain.Agent) error {
 	if r.redis == nil {
@@ -302,7 +575,7 @@ func (r *agentRepository) cacheAgent(ctx context.Context, agent *domain.Agent) e
 		return fmt.Errorf("failed to marshal agent: %w", err)
 	}
 
-	key := fmt.Sprintf("agent:%s", agent.ID)
+	key := agentCacheKeyPrefix + agent.ID
 	return r.redis.Set(ctx, key, data, r.ttl).Err()
 }
 
@@ -312,7 +585,7 @@ func (r *agentRepository) getFromCache(ctx context.Context, id string) (*domain.
 		return nil, fmt.Errorf("redis client not available")
 	}
 
-	key := fmt.Sprintf("agent:%s", id)
+	key := agentCacheKeyPrefix + id
 	data, err := r.redis.Get(ctx, key).Bytes()
 	if err != nil {
 		return nil, err
@@ -332,7 +605,7 @@ func (r *agentRepository) invalidateCache(ctx context.Context, id string) error
 		return nil
 	}
 
-	key := fmt.Sprintf("agent:%s", id)
+	key := agentCacheKeyPrefix + id
 	return r.redis.Del(ctx, key).Err()
 }
 
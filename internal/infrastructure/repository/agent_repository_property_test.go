@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/gen"
+	"github.com/leanovate/gopter/prop"
+	"github.com/trpg-solo-engine/backend/internal/catalog"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+	"go.uber.org/zap"
+)
+
+// arcCustomization 在目录数据之上对角色ARC做的修改
+type arcCustomization struct {
+	AnomalyIdx     int
+	RealityIdx     int
+	CareerIdx      int
+	AbilityName    string
+	FeatureNote    string
+	Filled         int
+	BehaviorReward int
+	Pending        bool
+}
+
+// genARCCustomization 生成随机的ARC组合和自定义内容
+func genARCCustomization() gopter.Gen {
+	return gopter.CombineGens(
+		gen.IntRange(0, len(domain.AllAnomalyTypes)-1),
+		gen.IntRange(0, len(domain.AllRealityTypes)-1),
+		gen.IntRange(0, len(domain.AllCareerTypes)-1),
+		gen.AlphaString(),
+		gen.AlphaString(),
+		gen.IntRange(0, 4),
+		gen.IntRange(1, 5),
+		gen.Bool(),
+	).Map(func(values []interface{}) *arcCustomization {
+		return &arcCustomization{
+			AnomalyIdx:     values[0].(int),
+			RealityIdx:     values[1].(int),
+			CareerIdx:      values[2].(int),
+			AbilityName:    values[3].(string),
+			FeatureNote:    values[4].(string),
+			Filled:         values[5].(int),
+			BehaviorReward: values[6].(int),
+			Pending:        values[7].(bool),
+		}
+	})
+}
+
+// buildCustomizedAgent 从ARC目录构建角色并加入目录中没有的自定义数据
+func buildCustomizedAgent(c *arcCustomization) (*domain.Agent, error) {
+	arc := catalog.Default()
+
+	anomaly, err := arc.NewAnomaly(domain.AllAnomalyTypes[c.AnomalyIdx])
+	if err != nil {
+		return nil, err
+	}
+	reality, err := arc.NewReality(domain.AllRealityTypes[c.RealityIdx])
+	if err != nil {
+		return nil, err
+	}
+	career, err := arc.NewCareer(domain.AllCareerTypes[c.CareerIdx])
+	if err != nil {
+		return nil, err
+	}
+
+	// 自定义能力、现实特性、退化进度和职能行为
+	anomaly.Abilities[0].Name = "自定义" + c.AbilityName
+	anomaly.Abilities[0].Roll.DiceCount = 6
+	if reality.SpecialFeature == nil {
+		reality.SpecialFeature = map[string]interface{}{}
+	}
+	reality.SpecialFeature["custom_note"] = c.FeatureNote
+	if c.Filled > reality.DegradationTrack.Total {
+		c.Filled = reality.DegradationTrack.Total
+	}
+	reality.DegradationTrack.Filled = c.Filled
+	career.PermittedBehaviors = append(career.PermittedBehaviors, &domain.PermittedBehavior{
+		Action: "自定义行为" + c.AbilityName,
+		Reward: c.BehaviorReward,
+	})
+
+	// 时间只保存到秒
+	now := time.Unix(time.Now().Unix(), 0)
+
+	qa := make(map[string]int, len(career.QA))
+	for quality, value := range career.QA {
+		qa[quality] = value
+	}
+
+	return &domain.Agent{
+		ID:       uuid.New().String(),
+		Name:     "属性测试特工",
+		Pronouns: "他/她",
+		Anomaly:  anomaly,
+		Reality:  reality,
+		Career:   career,
+		QA:       qa,
+		Relationships: []*domain.Relationship{
+			{ID: uuid.New().String(), Name: "关系A", Connection: 4, Notes: []string{}},
+		},
+		Assessment: []*domain.AssessmentAnswer{
+			{Question: "问题", Answer: c.FeatureNote},
+		},
+		Rating:               domain.RatingExcellent,
+		Alive:                true,
+		PendingRealityChange: c.Pending,
+		DegradationHistory: []*domain.DegradationEntry{
+			{
+				Kind:        domain.DegradationMarked,
+				RealityType: reality.Type,
+				Reason:      "属性测试",
+				Boxes:       c.Filled,
+				Filled:      c.Filled,
+				Total:       reality.DegradationTrack.Total,
+				CreatedAt:   now,
+			},
+		},
+		Deaths: []*domain.DeathRecord{
+			{Cause: "属性测试", SessionID: "session-1", LooseEnds: c.Filled, DiedAt: now, RevivedAt: &now, RevivalCost: 5},
+		},
+		LooseEnds: c.Filled,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// sameAgent 按序列化结果比较两个角色
+func sameAgent(a, b *domain.Agent) bool {
+	left, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	right, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return string(left) == string(right)
+}
+
+// TestProperty_AgentPersistenceRoundTrip 属性: 角色持久化round-trip
+// Feature: trpg-solo-engine, Property: 角色持久化round-trip
+//
+// 对于任何ARC组合及其自定义内容（能力、现实特性、退化进度、职能行为）和死亡记录，
+// 写入数据库后读取、以及经过Redis缓存的序列化后，都应该恢复完全相同的角色。
+func TestProperty_AgentPersistenceRoundTrip(t *testing.T) {
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 100
+
+	properties := gopter.NewProperties(parameters)
+
+	db := setupTestDB(t)
+	logger := zap.NewNop()
+	repo := NewAgentRepository(db, setupTestRedis(t), logger).(*agentRepository)
+	ctx := context.Background()
+
+	properties.Property("数据库模型转换后恢复完整的ARC", prop.ForAll(
+		func(c *arcCustomization) bool {
+			agent, err := buildCustomizedAgent(c)
+			if err != nil {
+				return false
+			}
+
+			model, err := repo.toModel(agent)
+			if err != nil {
+				return false
+			}
+			loaded, err := repo.toDomain(model)
+			if err != nil {
+				return false
+			}
+
+			return sameAgent(agent, loaded)
+		},
+		genARCCustomization(),
+	))
+
+	properties.Property("写入数据库后读取恢复完整的角色", prop.ForAll(
+		func(c *arcCustomization) bool {
+			agent, err := buildCustomizedAgent(c)
+			if err != nil {
+				return false
+			}
+			if err := repo.Create(ctx, agent); err != nil {
+				return false
+			}
+
+			loaded, err := repo.GetByID(ctx, agent.ID)
+			if err != nil {
+				return false
+			}
+
+			return sameAgent(agent, loaded)
+		},
+		genARCCustomization(),
+	))
+
+	properties.Property("缓存序列化后恢复完整的角色", prop.ForAll(
+		func(c *arcCustomization) bool {
+			agent, err := buildCustomizedAgent(c)
+			if err != nil {
+				return false
+			}
+
+			// 与cacheAgent/getFromCache使用相同的编码
+			data, err := json.Marshal(agent)
+			if err != nil {
+				return false
+			}
+			var cached domain.Agent
+			if err := json.Unmarshal(data, &cached); err != nil {
+				return false
+			}
+
+			return sameAgent(agent, &cached)
+		},
+		genARCCustomization(),
+	))
+
+	properties.TestingRun(t)
+}
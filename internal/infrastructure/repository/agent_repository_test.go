@@ -27,26 +27,57 @@ type TestAgentModel struct {
 	CareerType    string
 	QA            string
 	Relationships string
+	Inventory     string
 	Commendations int
 	Reprimands    int
 	Rating        string
 	Alive         bool
 	InDebt        bool
-	CreatedAt     int64
-	UpdatedAt     int64
+
+	ARCVersion           int `gorm:"column:arc_version"`
+	Anomaly              string
+	Reality              string
+	Career               string
+	Assessment           string
+	PendingRealityChange bool
+	DegradationHistory   string
+	Deaths               string
+	LooseEnds            int
+
+	CreatedAt int64
+	UpdatedAt int64
 }
 
 func (TestAgentModel) TableName() string {
 	return "agents"
 }
 
+// TestLedgerEntryModel SQLite兼容的账本测试模型
+type TestLedgerEntryModel struct {
+	ID            string `gorm:"primaryKey"`
+	AgentID       string
+	SessionID     string
+	SceneID       string
+	Reason        string
+	Behavior      string
+	Justification string
+	FlaggedBy     string
+	Commendations int
+	Reprimands    int
+	CreatedAt     int64
+}
+
+func (TestLedgerEntryModel) TableName() string {
+	return "agent_ledger_entries"
+}
+
 // setupTestDB 创建测试数据库
 func setupTestDB(t *testing.T) *gorm.DB {
 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 	require.NoError(t, err)
 
 	// 使用SQLite兼容的模型进行迁移
-	err = db.AutoMigrate(&TestAgentModel{})
+	err = db.AutoMigrate(&TestAgentModel{}, &TestLedgerEntryModel{})
 	require.NoError(t, err)
 
 	return db
@@ -223,6 +254,16 @@ func TestAgentRepository_Update(t *testing.T) {
 	assert.Equal(t, "更新后的特工", retrieved.Name)
 	assert.Equal(t, 5, retrieved.Commendations)
 	assert.Equal(t, 2, retrieved.Reprimands)
+
+	// ARC和退化状态同样写回
+	agent.PendingRealityChange = true
+	agent.Reality.DegradationTrack.Filled = 3
+	require.NoError(t, repo.Update(ctx, agent))
+
+	retrieved, err = repo.GetByID(ctx, agent.ID)
+	require.NoError(t, err)
+	assert.True(t, retrieved.PendingRealityChange)
+	assert.Equal(t, 3, retrieved.Reality.DegradationTrack.Filled)
 }
 
 // TestAgentRepository_Update_NotFound 测试更新不存在的角色
@@ -411,3 +452,137 @@ func TestAgentRepository_ToModel_ToDomain(t *testing.T) {
 	assert.Equal(t, agent.QA, converted.QA)
 	assert.Len(t, converted.Relationships, 3)
 }
+
+// TestAgentRepository_Modify 测试在事务中修改角色
+func TestAgentRepository_Modify(t *testing.T) {
+	db := setupTestDB(t)
+	logger, _ := zap.NewDevelopment()
+	repo := NewAgentRepository(db, setupTestRedis(t), logger)
+
+	ctx := context.Background()
+	agent := createTestAgent()
+	agent.Commendations = 5
+	require.NoError(t, repo.Create(ctx, agent))
+
+	t.Run("修改成功时写入计数、物品栏和账本", func(t *testing.T) {
+		modified, err := repo.Modify(ctx, agent.ID, func(a *domain.Agent) error {
+			a.RecordLedger(&domain.LedgerEntry{
+				ID:            uuid.New().String(),
+				Reason:        domain.LedgerReasonStorePurchase,
+				Commendations: -4,
+			})
+			a.AddItem(&domain.InventoryItem{ID: uuid.New().String(), ItemID: "ripple-gun", Name: "波纹枪", Uses: 2})
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 1, modified.Commendations)
+
+		loaded, err := repo.GetByID(ctx, agent.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, loaded.Commendations)
+		require.Len(t, loaded.Inventory, 1)
+		assert.Equal(t, "波纹枪", loaded.Inventory[0].Name)
+		require.Len(t, loaded.Ledger, 1)
+		assert.Equal(t, domain.LedgerReasonStorePurchase, loaded.Ledger[0].Reason)
+	})
+
+	t.Run("修改成功时写入ARC、评估和退化状态", func(t *testing.T) {
+		_, err := repo.Modify(ctx, agent.ID, func(a *domain.Agent) error {
+			a.PendingRealityChange = true
+			a.Reality.DegradationTrack.Filled = 2
+			a.Assessment = []*domain.AssessmentAnswer{{Question: "你为什么加入机构？", Answer: "为了找到真相"}}
+			return nil
+		})
+		require.NoError(t, err)
+
+		loaded, err := repo.GetByID(ctx, agent.ID)
+		require.NoError(t, err)
+		assert.True(t, loaded.PendingRealityChange)
+		assert.Equal(t, 2, loaded.Reality.DegradationTrack.Filled)
+		require.Len(t, loaded.Assessment, 1)
+		assert.Equal(t, "为了找到真相", loaded.Assessment[0].Answer)
+	})
+
+	t.Run("修改成功时写入死亡记录", func(t *testing.T) {
+		_, err := repo.Modify(ctx, agent.ID, func(a *domain.Agent) error {
+			_, err := a.Die("被异常体吞噬", "session-1", 2)
+			return err
+		})
+		require.NoError(t, err)
+
+		loaded, err := repo.GetByID(ctx, agent.ID)
+		require.NoError(t, err)
+		assert.False(t, loaded.Alive)
+		require.Len(t, loaded.Deaths, 1)
+		assert.Equal(t, "被异常体吞噬", loaded.Deaths[0].Cause)
+	})
+
+	t.Run("回调返回错误时回滚", func(t *testing.T) {
+		_, err := repo.Modify(ctx, agent.ID, func(a *domain.Agent) error {
+			a.Commendations = 100
+			return domain.NewGameError(domain.ErrInsufficientCommendations, "嘉奖不足")
+		})
+		require.Error(t, err)
+
+		loaded, err := repo.GetByID(ctx, agent.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, loaded.Commendations)
+	})
+
+	t.Run("角色不存在", func(t *testing.T) {
+		_, err := repo.Modify(ctx, "non-existent-id", func(a *domain.Agent) error { return nil })
+		require.Error(t, err)
+		gameErr, ok := err.(*domain.GameError)
+		require.True(t, ok)
+		assert.Equal(t, domain.ErrNotFound, gameErr.Code)
+	})
+}
+
+// TestAgentRepository_LegacyARC 测试旧数据按类型从ARC目录重建并迁移
+func TestAgentRepository_LegacyARC(t *testing.T) {
+	db := setupTestDB(t)
+	logger, _ := zap.NewDevelopment()
+	repo := NewAgentRepository(db, setupTestRedis(t), logger)
+	ctx := context.Background()
+
+	// 旧版本只写入了ARC类型
+	legacy := &TestAgentModel{
+		ID:            uuid.New().String(),
+		Name:          "旧特工",
+		AnomalyType:   domain.AnomalyWhisper,
+		RealityType:   domain.RealityCaretaker,
+		CareerType:    domain.CareerPublicRelations,
+		QA:            `{"专注":1}`,
+		Relationships: `[]`,
+		Alive:         true,
+	}
+	require.NoError(t, db.Create(legacy).Error)
+
+	t.Run("读取旧数据时使用ARC目录的完整数据", func(t *testing.T) {
+		agent, err := repo.GetByID(ctx, legacy.ID)
+		require.NoError(t, err)
+
+		require.Len(t, agent.Anomaly.Abilities, 3)
+		assert.NotEqual(t, "ability-1", agent.Anomaly.Abilities[0].ID)
+		assert.NotEmpty(t, agent.Reality.SpecialFeature)
+		assert.NotEmpty(t, agent.Career.PermittedBehaviors)
+		assert.NotNil(t, agent.Career.PrimeDirective)
+	})
+
+	t.Run("迁移后写入完整ARC且不重复迁移", func(t *testing.T) {
+		migrated, err := repo.MigrateLegacyARC(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, migrated)
+
+		var stored TestAgentModel
+		require.NoError(t, db.First(&stored, "id = ?", legacy.ID).Error)
+		assert.Equal(t, database.AgentARCVersion, stored.ARCVersion)
+		assert.NotEmpty(t, stored.Anomaly)
+		assert.NotEmpty(t, stored.Reality)
+		assert.NotEmpty(t, stored.Career)
+
+		migrated, err = repo.MigrateLegacyARC(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 0, migrated)
+	})
+}
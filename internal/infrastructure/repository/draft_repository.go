@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/trpg-solo-engine/backend/internal/domain"
+	"github.com/trpg-solo-engine/backend/internal/infrastructure/database"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// DraftRepository 角色草稿仓储接口
+type DraftRepository interface {
+	// CRUD操作
+	Create(ctx context.Context, draft *domain.AgentDraft) error
+	GetByID(ctx context.Context, id string) (*domain.AgentDraft, error)
+	Update(ctx context.Context, draft *domain.AgentDraft) error
+	Delete(ctx context.Context, id string) error
+
+	// 事务支持
+	WithTx(tx *gorm.DB) DraftRepository
+}
+
+type draftRepository struct {
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+// NewDraftRepository 创建角色草稿仓储实例
+func NewDraftRepository(db *gorm.DB, logger *zap.Logger) DraftRepository {
+	return &draftRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create 创建草稿
+func (r *draftRepository) Create(ctx context.Context, draft *domain.AgentDraft) error {
+	model, err := r.toModel(draft)
+	if err != nil {
+		return fmt.Errorf("failed to convert draft to model: %w", err)
+	}
+
+	if err := r.db.WithContext(ctx).Create(model).Error; err != nil {
+		return fmt.Errorf("failed to create draft: %w", err)
+	}
+
+	draft.ID = model.ID
+
+	return nil
+}
+
+// GetByID 根据ID获取草稿
+func (r *draftRepository) GetByID(ctx context.Context, id string) (*domain.AgentDraft, error) {
+	var model database.AgentDraftModel
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&model).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.NewGameError(domain.ErrNotFound, "草稿不存在").
+				WithDetails("draft_id", id)
+		}
+		return nil, fmt.Errorf("failed to get draft: %w", err)
+	}
+
+	draft, err := r.toDomain(&model)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert model to draft: %w", err)
+	}
+
+	return draft, nil
+}
+
+// Update 更新草稿
+func (r *draftRepository) Update(ctx context.Context, draft *domain.AgentDraft) error {
+	model, err := r.toModel(draft)
+	if err != nil {
+		return fmt.Errorf("failed to convert draft to model: %w", err)
+	}
+
+	result := r.db.WithContext(ctx).Model(&database.AgentDraftModel{}).
+		Where("id = ?", draft.ID).
+		Updates(map[string]any{
+			"step":       model.Step,
+			"agent_id":   model.AgentID,
+			"data":       model.Data,
+			"updated_at": time.Now().Unix(),
+		})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to update draft: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.NewGameError(domain.ErrNotFound, "草稿不存在").
+			WithDetails("draft_id", draft.ID)
+	}
+
+	return nil
+}
+
+// Delete 删除草稿
+func (r *draftRepository) Delete(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).Where("id = ?", id).Delete(&database.AgentDraftModel{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete draft: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.NewGameError(domain.ErrNotFound, "草稿不存在").
+			WithDetails("draft_id", id)
+	}
+
+	return nil
+}
+
+// WithTx 使用事务
+func (r *draftRepository) WithTx(tx *gorm.DB) DraftRepository {
+	return &draftRepository{
+		db:     tx,
+		logger: r.logger,
+	}
+}
+
+// toModel 将草稿转换为数据库模型，草稿内容整体存为JSON
+func (r *draftRepository) toModel(draft *domain.AgentDraft) (*database.AgentDraftModel, error) {
+	data, err := json.Marshal(draft)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal draft: %w", err)
+	}
+
+	return &database.AgentDraftModel{
+		ID:        draft.ID,
+		Step:      string(draft.Step),
+		AgentID:   draft.AgentID,
+		Data:      string(data),
+		CreatedAt: draft.CreatedAt.Unix(),
+		UpdatedAt: draft.UpdatedAt.Unix(),
+	}, nil
+}
+
+// toDomain 将数据库模型转换为草稿
+func (r *draftRepository) toDomain(model *database.AgentDraftModel) (*domain.AgentDraft, error) {
+	var draft domain.AgentDraft
+	if err := json.Unmarshal([]byte(model.Data), &draft); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal draft: %w", err)
+	}
+
+	draft.ID = model.ID
+	draft.Step = domain.DraftStep(model.Step)
+	draft.AgentID = model.AgentID
+
+	return &draft, nil
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+	"go.uber.org/zap"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// TestAgentDraftModel SQLite兼容的测试模型
+type TestAgentDraftModel struct {
+	ID        string `gorm:"primaryKey"`
+	Step      string
+	AgentID   string
+	Data      string
+	CreatedAt int64
+	UpdatedAt int64
+}
+
+func (TestAgentDraftModel) TableName() string {
+	return "agent_drafts"
+}
+
+// setupDraftTestDB 创建测试数据库
+func setupDraftTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(&TestAgentDraftModel{})
+	require.NoError(t, err)
+
+	return db
+}
+
+// TestDraftRepository_Persist 测试草稿在新的仓储实例（模拟重启）中仍可读取
+func TestDraftRepository_Persist(t *testing.T) {
+	db := setupDraftTestDB(t)
+	logger := zap.NewNop()
+	ctx := context.Background()
+
+	draft := &domain.AgentDraft{
+		ID:          uuid.New().String(),
+		Name:        "草稿特工",
+		Step:        domain.DraftStepCareer,
+		AnomalyType: domain.AnomalyWhisper,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+	require.NoError(t, NewDraftRepository(db, logger).Create(ctx, draft))
+
+	repo := NewDraftRepository(db, logger)
+	loaded, err := repo.GetByID(ctx, draft.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.DraftStepCareer, loaded.Step)
+	assert.Equal(t, domain.AnomalyWhisper, loaded.AnomalyType)
+
+	loaded.CareerType = domain.CareerClown
+	loaded.Assessment = []*domain.AssessmentAnswer{{Question: "问题", Answer: "回答"}}
+	loaded.Step = domain.DraftStepReality
+	require.NoError(t, repo.Update(ctx, loaded))
+
+	reloaded, err := NewDraftRepository(db, logger).GetByID(ctx, draft.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.DraftStepReality, reloaded.Step)
+	assert.Equal(t, domain.CareerClown, reloaded.CareerType)
+	require.Len(t, reloaded.Assessment, 1)
+	assert.Equal(t, "回答", reloaded.Assessment[0].Answer)
+}
+
+// TestDraftRepository_NotFound 测试草稿不存在
+func TestDraftRepository_NotFound(t *testing.T) {
+	db := setupDraftTestDB(t)
+	repo := NewDraftRepository(db, zap.NewNop())
+	ctx := context.Background()
+
+	_, err := repo.GetByID(ctx, uuid.New().String())
+	require.Error(t, err)
+	assert.Equal(t, domain.ErrNotFound, err.(*domain.GameError).Code)
+
+	err = repo.Update(ctx, &domain.AgentDraft{ID: uuid.New().String()})
+	require.Error(t, err)
+	assert.Equal(t, domain.ErrNotFound, err.(*domain.GameError).Code)
+}
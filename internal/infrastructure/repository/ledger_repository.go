@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+	"github.com/trpg-solo-engine/backend/internal/infrastructure/database"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LedgerRepository 角色绩效账本仓储接口（只追加）
+type LedgerRepository interface {
+	// 追加账本条目，已存在的条目会被跳过
+	Append(ctx context.Context, entries []*domain.LedgerEntry) error
+
+	// 按时间顺序列出角色的账本条目
+	ListByAgent(ctx context.Context, agentID string, filter *domain.LedgerFilter) ([]*domain.LedgerEntry, error)
+
+	// 事务支持
+	WithTx(tx *gorm.DB) LedgerRepository
+}
+
+type ledgerRepository struct {
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+// NewLedgerRepository 创建绩效账本仓储实例
+func NewLedgerRepository(db *gorm.DB, logger *zap.Logger) LedgerRepository {
+	return &ledgerRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Append 追加账本条目
+// 角色保存时会带上全部账本，按ID忽略冲突保证条目只写入一次
+func (r *ledgerRepository) Append(ctx context.Context, entries []*domain.LedgerEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	models := make([]*database.LedgerEntryModel, 0, len(entries))
+	for _, entry := range entries {
+		if entry.ID == "" {
+			entry.ID = uuid.New().String()
+		}
+		models = append(models, r.toModel(entry))
+	}
+
+	if err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models).Error; err != nil {
+		return fmt.Errorf("failed to append ledger entries: %w", err)
+	}
+
+	return nil
+}
+
+// ListByAgent 按时间顺序列出角色的账本条目
+func (r *ledgerRepository) ListByAgent(ctx context.Context, agentID string, filter *domain.LedgerFilter) ([]*domain.LedgerEntry, error) {
+	query := r.db.WithContext(ctx).Where("agent_id = ?", agentID)
+
+	if filter != nil {
+		if filter.Reason != "" {
+			query = query.Where("reason = ?", string(filter.Reason))
+		}
+		if filter.SessionID != "" {
+			query = query.Where("session_id = ?", filter.SessionID)
+		}
+	}
+
+	var models []database.LedgerEntryModel
+	if err := query.Order("created_at ASC").Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to list ledger entries: %w", err)
+	}
+
+	entries := make([]*domain.LedgerEntry, 0, len(models))
+	for i := range models {
+		entries = append(entries, r.toDomain(&models[i]))
+	}
+
+	return entries, nil
+}
+
+// WithTx 使用事务
+func (r *ledgerRepository) WithTx(tx *gorm.DB) LedgerRepository {
+	return &ledgerRepository{
+		db:     tx,
+		logger: r.logger,
+	}
+}
+
+// toModel 将账本条目转换为数据库模型
+func (r *ledgerRepository) toModel(entry *domain.LedgerEntry) *database.LedgerEntryModel {
+	return &database.LedgerEntryModel{
+		ID:            entry.ID,
+		AgentID:       entry.AgentID,
+		SessionID:     entry.SessionID,
+		SceneID:       entry.SceneID,
+		Reason:        string(entry.Reason),
+		Behavior:      entry.Behavior,
+		Justification: entry.Justification,
+		FlaggedBy:     entry.FlaggedBy,
+		Commendations: entry.Commendations,
+		Reprimands:    entry.Reprimands,
+		CreatedAt:     entry.CreatedAt.UnixNano(),
+	}
+}
+
+// toDomain 将数据库模型转换为账本条目
+func (r *ledgerRepository) toDomain(model *database.LedgerEntryModel) *domain.LedgerEntry {
+	return &domain.LedgerEntry{
+		ID:            model.ID,
+		AgentID:       model.AgentID,
+		SessionID:     model.SessionID,
+		SceneID:       model.SceneID,
+		Reason:        domain.LedgerReason(model.Reason),
+		Behavior:      model.Behavior,
+		Justification: model.Justification,
+		FlaggedBy:     model.FlaggedBy,
+		Commendations: model.Commendations,
+		Reprimands:    model.Reprimands,
+		CreatedAt:     time.Unix(0, model.CreatedAt),
+	}
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+	"go.uber.org/zap"
+)
+
+// TestLedgerRepository_AppendIdempotent 测试重复追加只写入一次
+func TestLedgerRepository_AppendIdempotent(t *testing.T) {
+	db := setupTestDB(t)
+	logger, _ := zap.NewDevelopment()
+	repo := NewLedgerRepository(db, logger)
+
+	ctx := context.Background()
+	base := time.Now()
+	entries := []*domain.LedgerEntry{
+		{AgentID: "agent-1", SessionID: "session-1", Reason: domain.LedgerReasonCaptureBonus, Commendations: 3, CreatedAt: base},
+		{AgentID: "agent-1", SessionID: "session-2", Reason: domain.LedgerReasonDirectiveViolation, Reprimands: 3, FlaggedBy: domain.FlaggedByAI, CreatedAt: base.Add(time.Millisecond)},
+		{AgentID: "agent-2", Reason: domain.LedgerReasonAdjustment, Commendations: 1, CreatedAt: base},
+	}
+
+	require.NoError(t, repo.Append(ctx, entries))
+	for _, entry := range entries {
+		assert.NotEmpty(t, entry.ID)
+	}
+
+	// 角色每次保存都会带上完整账本
+	require.NoError(t, repo.Append(ctx, entries))
+
+	listed, err := repo.ListByAgent(ctx, "agent-1", nil)
+	require.NoError(t, err)
+	require.Len(t, listed, 2)
+	assert.Equal(t, entries[0].ID, listed[0].ID)
+	assert.Equal(t, domain.LedgerReasonDirectiveViolation, listed[1].Reason)
+	assert.Equal(t, domain.FlaggedByAI, listed[1].FlaggedBy)
+	assert.Equal(t, 3, listed[1].Reprimands)
+
+	listed, err = repo.ListByAgent(ctx, "agent-1", &domain.LedgerFilter{SessionID: "session-2"})
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	assert.Equal(t, entries[1].ID, listed[0].ID)
+
+	listed, err = repo.ListByAgent(ctx, "agent-1", &domain.LedgerFilter{Reason: domain.LedgerReasonDeath})
+	require.NoError(t, err)
+	assert.Empty(t, listed)
+}
+
+// TestAgentRepository_PersistsLedger 测试角色保存时写入账本并在读取时加载
+func TestAgentRepository_PersistsLedger(t *testing.T) {
+	db := setupTestDB(t)
+	logger, _ := zap.NewDevelopment()
+	repo := NewAgentRepository(db, setupTestRedis(t), logger)
+
+	ctx := context.Background()
+	agent := createTestAgent()
+	require.NoError(t, repo.Create(ctx, agent))
+
+	agent.RecordLedger(&domain.LedgerEntry{Reason: domain.LedgerReasonCaptureBonus, Commendations: 3})
+	require.NoError(t, repo.Update(ctx, agent))
+
+	agent.RecordLedger(&domain.LedgerEntry{Reason: domain.LedgerReasonEscapePenalty, Reprimands: 3})
+	require.NoError(t, repo.Update(ctx, agent))
+
+	loaded, err := repo.GetByID(ctx, agent.ID)
+	require.NoError(t, err)
+	require.Len(t, loaded.Ledger, 2)
+	assert.Equal(t, domain.LedgerReasonCaptureBonus, loaded.Ledger[0].Reason)
+	assert.Equal(t, 3, loaded.Commendations)
+	assert.Equal(t, 3, loaded.Reprimands)
+	assert.True(t, loaded.ReconcileLedger().Balanced)
+}
@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/trpg-solo-engine/backend/internal/domain"
+	"github.com/trpg-solo-engine/backend/internal/infrastructure/database"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// RollRepository 掷骰账本仓储接口（只追加）
+type RollRepository interface {
+	// 账本操作
+	Create(ctx context.Context, record *domain.RollRecord) error
+	ListBySession(ctx context.Context, sessionID string, filter *domain.RollFilter) ([]*domain.RollRecord, error)
+
+	// 事务支持
+	WithTx(tx *gorm.DB) RollRepository
+}
+
+type rollRepository struct {
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+// NewRollRepository 创建掷骰账本仓储实例
+func NewRollRepository(db *gorm.DB, logger *zap.Logger) RollRepository {
+	return &rollRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create 追加账本条目
+func (r *rollRepository) Create(ctx context.Context, record *domain.RollRecord) error {
+	model, err := r.toModel(record)
+	if err != nil {
+		return fmt.Errorf("failed to convert roll record to model: %w", err)
+	}
+
+	if err := r.db.WithContext(ctx).Create(model).Error; err != nil {
+		return fmt.Errorf("failed to create roll record: %w", err)
+	}
+
+	record.ID = model.ID
+
+	return nil
+}
+
+// ListBySession 按时间顺序列出会话的账本条目
+func (r *rollRepository) ListBySession(ctx context.Context, sessionID string, filter *domain.RollFilter) ([]*domain.RollRecord, error) {
+	query := r.db.WithContext(ctx).Where("session_id = ?", sessionID)
+
+	if filter != nil {
+		if filter.Quality != "" {
+			query = query.Where("quality = ?", filter.Quality)
+		}
+		switch filter.Outcome {
+		case domain.RollOutcomeSuccess:
+			query = query.Where("success = ?", true)
+		case domain.RollOutcomeFailure:
+			query = query.Where("success = ?", false)
+		case domain.RollOutcomeTripleAsc:
+			query = query.Where("triple_asc = ?", true)
+		}
+	}
+
+	var models []database.RollModel
+	if err := query.Order("created_at ASC").Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to list roll records: %w", err)
+	}
+
+	records := make([]*domain.RollRecord, 0, len(models))
+	for _, model := range models {
+		record, err := r.toDomain(&model)
+		if err != nil {
+			r.logger.Warn("failed to convert model to roll record",
+				zap.Error(err),
+				zap.String("roll_id", model.ID))
+			continue
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// WithTx 使用事务
+func (r *rollRepository) WithTx(tx *gorm.DB) RollRepository {
+	return &rollRepository{
+		db:     tx,
+		logger: r.logger,
+	}
+}
+
+// toModel 将账本条目转换为数据库模型
+func (r *rollRepository) toModel(record *domain.RollRecord) (*database.RollModel, error) {
+	rawJSON, err := json.Marshal(record.RawDice)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal raw dice: %w", err)
+	}
+
+	diceJSON, err := json.Marshal(record.Dice)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal dice: %w", err)
+	}
+
+	return &database.RollModel{
+		ID:        record.ID,
+		SessionID: record.SessionID,
+		AgentID:   record.AgentID,
+		Kind:      string(record.Kind),
+		Quality:   record.Quality,
+		AbilityID: record.AbilityID,
+		Request:   record.Request,
+		RawDice:   string(rawJSON),
+		Dice:      string(diceJSON),
+		Threes:    record.Threes,
+		Success:   record.Success,
+		QASpent:   record.QASpent,
+		Overload:  record.Overload,
+		Chaos:     record.Chaos,
+		TripleAsc: record.TripleAsc,
+		Effect:    record.Effect,
+		CreatedAt: record.CreatedAt.UnixNano(),
+	}, nil
+}
+
+// toDomain 将数据库模型转换为账本条目
+func (r *rollRepository) toDomain(model *database.RollModel) (*domain.RollRecord, error) {
+	var rawDice []int
+	if err := json.Unmarshal([]byte(model.RawDice), &rawDice); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal raw dice: %w", err)
+	}
+
+	var dice []int
+	if err := json.Unmarshal([]byte(model.Dice), &dice); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal dice: %w", err)
+	}
+
+	return &domain.RollRecord{
+		ID:        model.ID,
+		SessionID: model.SessionID,
+		AgentID:   model.AgentID,
+		Kind:      domain.RollKind(model.Kind),
+		Quality:   model.Quality,
+		AbilityID: model.AbilityID,
+		Request:   model.Request,
+		RawDice:   rawDice,
+		Dice:      dice,
+		Threes:    model.Threes,
+		Success:   model.Success,
+		QASpent:   model.QASpent,
+		Overload:  model.Overload,
+		Chaos:     model.Chaos,
+		TripleAsc: model.TripleAsc,
+		Effect:    model.Effect,
+		CreatedAt: time.Unix(0, model.CreatedAt),
+	}, nil
+}
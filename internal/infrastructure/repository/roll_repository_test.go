@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+	"go.uber.org/zap"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// TestRollModel SQLite兼容的测试模型
+type TestRollModel struct {
+	ID        string `gorm:"primaryKey"`
+	SessionID string
+	AgentID   string
+	Kind      string
+	Quality   string
+	AbilityID string
+	Request   string
+	RawDice   string
+	Dice      string
+	Threes    int
+	Success   bool
+	QASpent   int
+	Overload  int
+	Chaos     int
+	TripleAsc bool
+	Effect    string
+	CreatedAt int64
+}
+
+func (TestRollModel) TableName() string {
+	return "dice_rolls"
+}
+
+// setupRollTestDB 创建测试数据库
+func setupRollTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(&TestRollModel{})
+	require.NoError(t, err)
+
+	return db
+}
+
+// createTestRoll 创建测试账本条目
+func createTestRoll(sessionID, quality string, success, tripleAsc bool, at time.Time) *domain.RollRecord {
+	return &domain.RollRecord{
+		ID:        uuid.New().String(),
+		SessionID: sessionID,
+		AgentID:   "agent-1",
+		Kind:      domain.RollKindBasic,
+		Quality:   quality,
+		RawDice:   []int{1, 2, 3, 4, 3, 3},
+		Dice:      []int{1, 2, 3, 4, 3, 3},
+		Threes:    3,
+		Success:   success,
+		Chaos:     3,
+		TripleAsc: tripleAsc,
+		CreatedAt: at,
+	}
+}
+
+// TestRollRepository_CreateAndList 测试追加与按会话查询
+func TestRollRepository_CreateAndList(t *testing.T) {
+	db := setupRollTestDB(t)
+	logger, _ := zap.NewDevelopment()
+	repo := NewRollRepository(db, logger)
+
+	ctx := context.Background()
+	sessionID := uuid.New().String()
+	base := time.Now()
+
+	second := createTestRoll(sessionID, "专注", false, false, base.Add(time.Millisecond))
+	first := createTestRoll(sessionID, "共情", true, true, base)
+	first.RawDice = []int{1, 1, 3, 3, 3, 2}
+	first.Effect = domain.TripleAscEffectCommendation
+	other := createTestRoll(uuid.New().String(), "专注", true, false, base)
+
+	require.NoError(t, repo.Create(ctx, second))
+	require.NoError(t, repo.Create(ctx, first))
+	require.NoError(t, repo.Create(ctx, other))
+
+	records, err := repo.ListBySession(ctx, sessionID, nil)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+
+	// 按时间顺序返回
+	assert.Equal(t, first.ID, records[0].ID)
+	assert.Equal(t, second.ID, records[1].ID)
+	assert.Equal(t, []int{1, 1, 3, 3, 3, 2}, records[0].RawDice)
+	assert.Equal(t, first.Dice, records[0].Dice)
+	assert.Equal(t, domain.RollKindBasic, records[0].Kind)
+	assert.True(t, records[0].TripleAsc)
+	assert.Equal(t, domain.TripleAscEffectCommendation, records[0].Effect)
+}
+
+// TestRollRepository_ListWithFilter 测试按品质和结果筛选
+func TestRollRepository_ListWithFilter(t *testing.T) {
+	db := setupRollTestDB(t)
+	logger, _ := zap.NewDevelopment()
+	repo := NewRollRepository(db, logger)
+
+	ctx := context.Background()
+	sessionID := uuid.New().String()
+	base := time.Now()
+
+	require.NoError(t, repo.Create(ctx, createTestRoll(sessionID, "专注", true, false, base)))
+	require.NoError(t, repo.Create(ctx, createTestRoll(sessionID, "专注", false, false, base.Add(time.Millisecond))))
+	require.NoError(t, repo.Create(ctx, createTestRoll(sessionID, "共情", true, true, base.Add(2*time.Millisecond))))
+
+	records, err := repo.ListBySession(ctx, sessionID, &domain.RollFilter{Quality: "专注"})
+	require.NoError(t, err)
+	assert.Len(t, records, 2)
+
+	records, err = repo.ListBySession(ctx, sessionID, &domain.RollFilter{Outcome: domain.RollOutcomeFailure})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.False(t, records[0].Success)
+
+	records, err = repo.ListBySession(ctx, sessionID, &domain.RollFilter{Quality: "专注", Outcome: domain.RollOutcomeSuccess})
+	require.NoError(t, err)
+	assert.Len(t, records, 1)
+
+	records, err = repo.ListBySession(ctx, sessionID, &domain.RollFilter{Outcome: domain.RollOutcomeTripleAsc})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "共情", records[0].Quality)
+}
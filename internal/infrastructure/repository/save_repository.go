@@ -162,7 +162,7 @@ func (r *saveRepository) toModel(save *SaveSnapshot) (*database.SaveModel, error
 	// 创建完整的快照数据结构
 	snapshotData := map[string]interface{}{
 		"version":  save.Version,
-		"snapshot": save.Snapshot,
+		"snapshot": domain.StoredSession{GameSession: save.Snapshot},
 		"metadata": save.Metadata,
 	}
 
@@ -186,7 +186,7 @@ func (r *saveRepository) toDomain(model *database.SaveModel) (*SaveSnapshot, err
 	// 反序列化快照数据
 	var snapshotData struct {
 		Version  string                 `json:"version"`
-		Snapshot *domain.GameSession    `json:"snapshot"`
+		Snapshot domain.StoredSession   `json:"snapshot"`
 		Metadata map[string]interface{} `json:"metadata"`
 	}
 
@@ -199,7 +199,7 @@ func (r *saveRepository) toDomain(model *database.SaveModel) (*SaveSnapshot, err
 		SessionID: model.SessionID,
 		Name:      model.Name,
 		Version:   snapshotData.Version,
-		Snapshot:  snapshotData.Snapshot,
+		Snapshot:  snapshotData.Snapshot.GameSession,
 		Metadata:  snapshotData.Metadata,
 		CreatedAt: time.Unix(model.CreatedAt, 0),
 	}, nil
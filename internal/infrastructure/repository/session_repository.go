@@ -123,6 +123,9 @@ func (r *sessionRepository) Update(ctx context.Context, session *domain.GameSess
 			"scenario_id": model.ScenarioID,
 			"phase":       model.Phase,
 			"state":       model.State,
+			"dice_seed":   model.DiceSeed,
+			"dice_cursor": model.DiceCursor,
+			"rules":       model.Rules,
 			"updated_at":  time.Now().Unix(),
 		})
 
@@ -205,12 +208,20 @@ func (r *sessionRepository) toModel(session *domain.GameSession) (*database.Game
 		return nil, fmt.Errorf("failed to marshal state: %w", err)
 	}
 
+	rulesJSON, err := json.Marshal(session.Rules)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal rules: %w", err)
+	}
+
 	return &database.GameSessionModel{
 		ID:         session.ID,
 		AgentID:    session.AgentID,
 		ScenarioID: session.ScenarioID,
 		Phase:      string(session.Phase),
 		State:      string(stateJSON),
+		DiceSeed:   session.DiceSeed,
+		DiceCursor: session.DiceCursor,
+		Rules:      string(rulesJSON),
 		CreatedAt:  session.CreatedAt.Unix(),
 		UpdatedAt:  session.UpdatedAt.Unix(),
 	}, nil
@@ -223,12 +234,22 @@ func (r *sessionRepository) toDomain(model *database.GameSessionModel) (*domain.
 		return nil, fmt.Errorf("failed to unmarshal state: %w", err)
 	}
 
+	var rules *domain.Ruleset
+	if model.Rules != "" {
+		if err := json.Unmarshal([]byte(model.Rules), &rules); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal rules: %w", err)
+		}
+	}
+
 	return &domain.GameSession{
 		ID:         model.ID,
 		AgentID:    model.AgentID,
 		ScenarioID: model.ScenarioID,
 		Phase:      domain.GamePhase(model.Phase),
 		State:      &state,
+		DiceSeed:   model.DiceSeed,
+		DiceCursor: model.DiceCursor,
+		Rules:      rules,
 		CreatedAt:  time.Unix(model.CreatedAt, 0),
 		UpdatedAt:  time.Unix(model.UpdatedAt, 0),
 	}, nil
@@ -240,7 +261,7 @@ func (r *sessionRepository) cacheSession(ctx context.Context, session *domain.Ga
 		return nil
 	}
 
-	data, err := json.Marshal(session)
+	data, err := json.Marshal(domain.StoredSession{GameSession: session})
 	if err != nil {
 		return fmt.Errorf("failed to marshal session: %w", err)
 	}
@@ -261,12 +282,12 @@ func (r *sessionRepository) getFromCache(ctx context.Context, id string) (*domai
 		return nil, err
 	}
 
-	var session domain.GameSession
+	var session domain.StoredSession
 	if err := json.Unmarshal(data, &session); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal session: %w", err)
 	}
 
-	return &session, nil
+	return session.GameSession, nil
 }
 
 // invalidateCache 使缓存失效
@@ -25,6 +25,9 @@ type TestGameSessionModel struct {
 	ScenarioID string
 	Phase      string
 	State      string
+	DiceSeed   int64
+	DiceCursor int64
+	Rules      string
 	CreatedAt  int64
 	UpdatedAt  int64
 }
@@ -79,8 +82,11 @@ func createTestSession() *domain.GameSession {
 			AnomalyStatus:     "active",
 			MissionOutcome:    "",
 		},
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		DiceSeed:   42,
+		DiceCursor: 7,
+		Rules:      &domain.Ruleset{DiceRules: domain.DiceRules{DiceCount: 5, DiceSides: 6, SuccessValue: 6, TripleAscensionCount: 3}, TripleAscensionEffect: domain.TripleAscEffectReroll, TripleAscensionReward: 1},
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
 	}
 }
 
@@ -132,6 +138,9 @@ func TestSessionRepository_GetByID(t *testing.T) {
 	assert.Equal(t, session.State.CurrentSceneID, retrieved.State.CurrentSceneID)
 	assert.Equal(t, session.State.ChaosPool, retrieved.State.ChaosPool)
 	assert.Equal(t, session.State.LooseEnds, retrieved.State.LooseEnds)
+	assert.Equal(t, session.DiceSeed, retrieved.DiceSeed)
+	assert.Equal(t, session.DiceCursor, retrieved.DiceCursor)
+	assert.Equal(t, session.Rules, retrieved.Rules)
 }
 
 // TestSessionRepository_GetByID_NotFound 测试获取不存在的会话
@@ -173,6 +182,7 @@ func TestSessionRepository_Update(t *testing.T) {
 	session.State.CurrentSceneID = "scene-2"
 	session.State.ChaosPool = 10
 	session.State.LooseEnds = 5
+	session.DiceCursor = 12
 
 	// 测试更新
 	err = repo.Update(ctx, session)
@@ -185,6 +195,7 @@ func TestSessionRepository_Update(t *testing.T) {
 	assert.Equal(t, "scene-2", retrieved.State.CurrentSceneID)
 	assert.Equal(t, 10, retrieved.State.ChaosPool)
 	assert.Equal(t, 5, retrieved.State.LooseEnds)
+	assert.Equal(t, int64(12), retrieved.DiceCursor)
 }
 
 // TestSessionRepository_Update_NotFound 测试更新不存在的会话
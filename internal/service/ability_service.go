@@ -9,6 +9,12 @@ type AbilityService interface {
 	// 能力使用
 	UseAbility(agent *domain.Agent, session *domain.GameSession, abilityID string, context *AbilityContext) (*AbilityResult, error)
 
+	// 掷骰并应用能力效果，只推进会话骰子游标和混沌池，不修改角色
+	RollAbility(agent *domain.Agent, session *domain.GameSession, abilityID string, context *AbilityContext) (*AbilityResult, error)
+
+	// 工作外使用时记录申诫
+	RecordOffDutyUsage(agent *domain.Agent, session *domain.GameSession, result *AbilityResult)
+
 	// 能力验证
 	ValidateTrigger(ability *domain.AnomalyAbility, context *AbilityContext) (bool, error)
 	CheckCondition(ability *domain.AnomalyAbility, context *AbilityContext) (bool, error)
@@ -69,6 +75,17 @@ func NewAbilityService(diceService domain.DiceService, qaService QAService, chao
 
 // UseAbility 使用异常能力
 func (s *abilityService) UseAbility(agent *domain.Agent, session *domain.GameSession, abilityID string, context *AbilityContext) (*AbilityResult, error) {
+	result, err := s.RollAbility(agent, session, abilityID, context)
+	if err != nil {
+		return nil, err
+	}
+
+	s.RecordOffDutyUsage(agent, session, result)
+	return result, nil
+}
+
+// RollAbility 掷骰并应用能力效果
+func (s *abilityService) RollAbility(agent *domain.Agent, session *domain.GameSession, abilityID string, context *AbilityContext) (*AbilityResult, error) {
 	// 查找能力
 	var ability *domain.AnomalyAbility
 	for _, a := range agent.Anomaly.Abilities {
@@ -93,8 +110,8 @@ func (s *abilityService) UseAbility(agent *domain.Agent, session *domain.GameSes
 			WithDetails("ability", ability.Name)
 	}
 
-	// 执行掷骰
-	roll := s.diceService.RollForAbility(agent, ability)
+	// 执行掷骰（从会话随机流取数）
+	roll := s.diceService.ForSession(session).RollForAbility(agent, ability)
 
 	// 添加混沌到混沌池（如果失败）
 	if !roll.Success {
@@ -133,13 +150,28 @@ func (s *abilityService) UseAbility(agent *domain.Agent, session *domain.GameSes
 	}
 	result.AdditionalEffects = additionalEffects
 
+	return result, nil
+}
+
+// RecordOffDutyUsage 工作外使用时记录申诫
+func (s *abilityService) RecordOffDutyUsage(agent *domain.Agent, session *domain.GameSession, result *AbilityResult) {
 	// 检查是否在工作外使用
 	if s.CheckOffDutyUsage(agent, session) {
-		agent.AddReprimands(1)
+		entry := &domain.LedgerEntry{
+			Reason:        domain.LedgerReasonOffDutyAbility,
+			Behavior:      result.Ability.Name,
+			Justification: "在工作时间外使用异常能力",
+			Reprimands:    1,
+		}
+		if session != nil {
+			entry.SessionID = session.ID
+			if session.State != nil {
+				entry.SceneID = session.State.CurrentSceneID
+			}
+		}
+		agent.RecordLedger(entry)
 		result.ReprimandAdded = true
 	}
-
-	return result, nil
 }
 
 // ValidateTrigger 验证能力触发条件
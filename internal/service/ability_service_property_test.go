@@ -460,4 +460,23 @@ func TestAbilityService_BasicFunctionality(t *testing.T) {
 		offDuty := abilityService.CheckOffDutyUsage(agent, session)
 		assert.False(t, offDuty)
 	})
+
+	t.Run("RollAbility_DoesNotModifyAgent", func(t *testing.T) {
+		agent := createTestAgentForAbility()
+		ability := createTestAbilityForTest()
+		agent.Anomaly.Abilities = append(agent.Anomaly.Abilities, ability)
+		session := createTestSessionForAbility()
+		session.Phase = domain.PhaseMorning
+
+		result, err := abilityService.RollAbility(agent, session, ability.ID, &AbilityContext{})
+		assert.NoError(t, err)
+		assert.False(t, result.ReprimandAdded)
+		assert.Empty(t, agent.Ledger)
+
+		// 工作外使用的申诫单独记录
+		abilityService.RecordOffDutyUsage(agent, session, result)
+		assert.True(t, result.ReprimandAdded)
+		assert.Equal(t, 1, agent.Reprimands)
+		assert.Equal(t, domain.LedgerReasonOffDutyAbility, agent.Ledger[0].Reason)
+	})
 }
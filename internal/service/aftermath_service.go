@@ -0,0 +1,277 @@
+package service
+
+import (
+	"time"
+
+	"github.com/trpg-solo-engine/backend/internal/domain"
+)
+
+// AftermathService 余波服务接口
+// 根据遭遇的任务结果结算余波：展示剧本的余波描述，按结果和完成的可选目标记录嘉奖与申诫，
+// 发放可申领物，恢复资质保证，并把散逸端保存到角色上带入下次任务
+type AftermathService interface {
+	// 结算余波并返回任务报告，每个会话只能结算一次
+	Resolve(sessionID string, req *ResolveAftermathRequest) (*domain.DebriefReport, error)
+
+	// 查询已结算的任务报告
+	GetDebrief(sessionID string) (*domain.DebriefReport, error)
+}
+
+// ResolveAftermathRequest 结算余波请求
+type ResolveAftermathRequest struct {
+	CompletedGoals []string `json:"completed_goals"` // 完成的可选目标，必须是晨会中选择的目标
+}
+
+// aftermathService 余波服务实现
+type aftermathService struct {
+	agentService       AgentService
+	gameService        GameService
+	scenarioService    ScenarioService
+	storeService       StoreService
+	performanceService PerformanceService
+	qaService          QAService
+}
+
+// NewAftermathService 创建余波服务
+func NewAftermathService(agentService AgentService, gameService GameService, scenarioService ScenarioService, storeService StoreService, performanceService PerformanceService, qaService QAService) AftermathService {
+	return &aftermathService{
+		agentService:       agentService,
+		gameService:        gameService,
+		scenarioService:    scenarioService,
+		storeService:       storeService,
+		performanceService: performanceService,
+		qaService:          qaService,
+	}
+}
+
+// Resolve 结算余波
+// 异常体逃脱时不发放剧本奖励和可申领物
+func (s *aftermathService) Resolve(sessionID string, req *ResolveAftermathRequest) (*domain.DebriefReport, error) {
+	session, err := s.gameService.GetSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.Phase != domain.PhaseAftermath {
+		return nil, domain.NewGameError(domain.ErrInvalidPhase, "当前不在余波阶段").
+			WithDetails("current_phase", session.Phase).
+			WithDetails("expected_phase", domain.PhaseAftermath)
+	}
+
+	// 在会话锁内占用结算，并发或重试的请求不会重复修改角色
+	var outcome string
+	err = s.gameService.UpdateState(sessionID, func(state *domain.GameState) error {
+		if state.Debrief != nil {
+			return domain.NewGameError(domain.ErrInvalidState, "余波已经结算").
+				WithDetails("session_id", sessionID)
+		}
+		if state.DebriefResolving {
+			return domain.NewGameError(domain.ErrInvalidState, "余波正在结算").
+				WithDetails("session_id", sessionID)
+		}
+
+		outcome = state.MissionOutcome
+		switch outcome {
+		case OutcomeCaptured, OutcomeNeutralized, OutcomeEscaped, OutcomeDeal:
+		default:
+			return domain.NewGameError(domain.ErrInvalidState, "遭遇尚未得出任务结果").
+				WithDetails("mission_outcome", outcome)
+		}
+
+		state.DebriefResolving = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := s.resolve(session, outcome, req)
+	if err != nil {
+		// 结算失败时释放占用，允许重试
+		_ = s.gameService.UpdateState(sessionID, func(state *domain.GameState) error {
+			state.DebriefResolving = false
+			return nil
+		})
+		return nil, err
+	}
+
+	err = s.gameService.UpdateState(sessionID, func(state *domain.GameState) error {
+		state.Debrief = report
+		state.DebriefResolving = false
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.gameService.SaveSession(session); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// resolve 发放任务结果和可选目标的奖励，恢复QA并生成任务报告
+func (s *aftermathService) resolve(session *domain.GameSession, outcome string, req *ResolveAftermathRequest) (*domain.DebriefReport, error) {
+	if s.scenarioService == nil {
+		return nil, domain.NewGameError(domain.ErrInvalidState, "没有可用的剧本服务")
+	}
+	scenario, err := s.scenarioService.LoadScenario(session.ScenarioID)
+	if err != nil {
+		return nil, err
+	}
+
+	goals, err := s.goalResults(session.State, scenario, req.CompletedGoals)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.DebriefReport{
+		SessionID:  session.ID,
+		AgentID:    session.AgentID,
+		ScenarioID: session.ScenarioID,
+		Outcome:    outcome,
+		Narrative:  aftermathNarrative(scenario.Aftermath, outcome),
+		Goals:      goals,
+		Claimables: []*domain.InventoryItem{},
+		LooseEnds:  session.State.LooseEnds,
+	}
+
+	var recorded int
+	agent, err := s.agentService.ModifyAgent(session.AgentID, func(agent *domain.Agent) error {
+		recorded = len(agent.Ledger)
+
+		if err := s.awardOutcome(agent, scenario, outcome); err != nil {
+			return err
+		}
+		for _, goal := range goals {
+			if !goal.Completed || goal.Reward <= 0 {
+				continue
+			}
+			err := s.performanceService.Record(agent, &domain.LedgerEntry{
+				Reason:        domain.LedgerReasonOptionalGoal,
+				Behavior:      goal.Description,
+				Justification: "完成可选目标",
+				Commendations: goal.Reward,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		for _, entry := range agent.Ledger[recorded:] {
+			entry.SessionID = session.ID
+		}
+
+		if err := s.qaService.RestoreQA(agent); err != nil {
+			return err
+		}
+		agent.LooseEnds = session.State.LooseEnds
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report.Entries = agent.Ledger[recorded:]
+	report.Commendations = agent.Commendations
+	report.Reprimands = agent.Reprimands
+	report.Rating = agent.Rating
+	report.QARestored = true
+
+	if outcome != OutcomeEscaped {
+		items, err := s.storeService.GrantScenarioRewards(session.ID)
+		if err != nil {
+			return nil, err
+		}
+		report.Claimables = items
+	}
+
+	report.ResolvedAt = time.Now()
+	return report, nil
+}
+
+// GetDebrief 查询任务报告
+func (s *aftermathService) GetDebrief(sessionID string) (*domain.DebriefReport, error) {
+	session, err := s.gameService.GetSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.State.Debrief == nil {
+		return nil, domain.NewGameError(domain.ErrNotFound, "余波尚未结算").
+			WithDetails("session_id", sessionID)
+	}
+
+	return session.State.Debrief, nil
+}
+
+// awardOutcome 按任务结果记录嘉奖或申诫
+// 捕获异常体时，剧本配置了奖励嘉奖则以剧本为准，否则按规则书发放
+func (s *aftermathService) awardOutcome(agent *domain.Agent, scenario *domain.Scenario, outcome string) error {
+	if outcome == OutcomeCaptured && scenario.Rewards != nil && scenario.Rewards.Commendations > 0 {
+		return s.performanceService.Record(agent, &domain.LedgerEntry{
+			Reason:        domain.LedgerReasonMissionReward,
+			Justification: "任务结果: " + OutcomeCaptured,
+			Commendations: scenario.Rewards.Commendations,
+		})
+	}
+	return s.performanceService.AwardMissionSuccess(agent, outcome)
+}
+
+// goalResults 核对晨会选择的可选目标的完成情况
+func (s *aftermathService) goalResults(state *domain.GameState, scenario *domain.Scenario, completed []string) ([]*domain.GoalResult, error) {
+	done := make(map[string]bool, len(completed))
+	for _, goalID := range completed {
+		if !state.GoalChosen(goalID) {
+			return nil, domain.NewGameError(domain.ErrInvalidInput, "只能完成晨会中选择的可选目标").
+				WithDetails("goal_id", goalID)
+		}
+		done[goalID] = true
+	}
+
+	results := make([]*domain.GoalResult, 0, len(state.ChosenGoals))
+	for _, goalID := range state.ChosenGoals {
+		goal := findOptionalGoal(scenario, goalID)
+		if goal == nil {
+			return nil, domain.NewGameError(domain.ErrNotFound, "剧本中没有该可选目标").
+				WithDetails("goal_id", goalID)
+		}
+		results = append(results, &domain.GoalResult{
+			GoalID:      goal.ID,
+			Description: goal.Description,
+			Reward:      goal.Reward,
+			Completed:   done[goal.ID],
+		})
+	}
+	return results, nil
+}
+
+// aftermathNarrative 与任务结果对应的余波描述，剧本没有配置时使用通用描述
+func aftermathNarrative(aftermath *domain.Aftermath, outcome string) string {
+	if aftermath != nil {
+		var text string
+		switch outcome {
+		case OutcomeCaptured:
+			text = aftermath.Captured
+		case OutcomeNeutralized:
+			text = aftermath.Neutralized
+		case OutcomeEscaped:
+			text = aftermath.Escaped
+		case OutcomeDeal:
+			text = aftermath.Deal
+		}
+		if text != "" {
+			return text
+		}
+	}
+
+	switch outcome {
+	case OutcomeCaptured:
+		return "异常体被成功收容，任务结束。"
+	case OutcomeNeutralized:
+		return "异常体被中和，威胁已经解除。"
+	case OutcomeEscaped:
+		return "异常体逃脱了，机构将发起后续追捕。"
+	default:
+		return "特工与异常体达成了协议，机构将持续监视协议的执行。"
+	}
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+)
+
+func setupAftermathTest(t *testing.T) (AgentService, GameService, AftermathService, *domain.Agent, *domain.GameSession) {
+	fx := newTestAgentSession(t, withScenario("eternal-spring"))
+	store := NewStoreService(fx.agentService, fx.gameService, fx.scenarioService, nil)
+	aftermath := NewAftermathService(fx.agentService, fx.gameService, fx.scenarioService, store, NewPerformanceService(), NewQAService(domain.NewDiceService()))
+
+	return fx.agentService, fx.gameService, aftermath, fx.agent, fx.session
+}
+
+// finishMission 以指定结果结束遭遇并进入余波阶段
+func finishMission(t *testing.T, gameService GameService, sessionID, outcome string, looseEnds int) {
+	require.NoError(t, gameService.TransitionPhase(sessionID, domain.PhaseInvestigation))
+	require.NoError(t, gameService.TransitionPhase(sessionID, domain.PhaseEncounter))
+	require.NoError(t, gameService.UpdateState(sessionID, func(state *domain.GameState) error {
+		state.MissionOutcome = outcome
+		state.LooseEnds = looseEnds
+		return nil
+	}))
+	require.NoError(t, gameService.TransitionPhase(sessionID, domain.PhaseAftermath))
+}
+
+func TestAftermathService_ResolveCaptured(t *testing.T) {
+	agentService, gameService, aftermath, agent, session := setupAftermathTest(t)
+
+	_, err := gameService.ChooseGoals(session.ID, []string{"save-serena", "product-recall"})
+	require.NoError(t, err)
+
+	// 任务中花费了资质保证
+	var spent string
+	_, err = agentService.ModifyAgent(agent.ID, func(agent *domain.Agent) error {
+		for _, quality := range domain.AllQualities {
+			if agent.QA[quality] > 0 {
+				spent = quality
+				return agent.SpendQA(quality, 1)
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	before, err := agentService.GetAgent(agent.ID)
+	require.NoError(t, err)
+	commendations, qa, inventory := before.Commendations, before.QA[spent], len(before.Inventory)
+
+	finishMission(t, gameService, session.ID, OutcomeCaptured, 4)
+
+	_, err = aftermath.GetDebrief(session.ID)
+	require.Error(t, err)
+	assert.Equal(t, domain.ErrNotFound, err.(*domain.GameError).Code)
+
+	report, err := aftermath.Resolve(session.ID, &ResolveAftermathRequest{CompletedGoals: []string{"save-serena"}})
+	require.NoError(t, err)
+
+	assert.Equal(t, OutcomeCaptured, report.Outcome)
+	assert.Contains(t, report.Narrative, "永恒之泉被成功收容")
+	require.Len(t, report.Goals, 2)
+	assert.True(t, report.Goals[0].Completed)
+	assert.False(t, report.Goals[1].Completed)
+
+	// 剧本的捕获奖励和完成的可选目标
+	require.Len(t, report.Entries, 2)
+	assert.Equal(t, domain.LedgerReasonMissionReward, report.Entries[0].Reason)
+	assert.Equal(t, domain.LedgerReasonOptionalGoal, report.Entries[1].Reason)
+	for _, entry := range report.Entries {
+		assert.Equal(t, session.ID, entry.SessionID)
+		assert.NotEmpty(t, entry.ID)
+	}
+	assert.Equal(t, commendations+6, report.Commendations)
+	assert.NotEmpty(t, report.Claimables)
+	assert.True(t, report.QARestored)
+	assert.Equal(t, 4, report.LooseEnds)
+
+	stored, err := agentService.GetAgent(agent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, commendations+6, stored.Commendations)
+	assert.Equal(t, qa+1, stored.QA[spent])
+	assert.Equal(t, 4, stored.LooseEnds)
+	assert.Len(t, stored.Inventory, inventory+len(report.Claimables))
+
+	// 每个会话只结算一次
+	_, err = aftermath.Resolve(session.ID, &ResolveAftermathRequest{})
+	require.Error(t, err)
+	assert.Equal(t, domain.ErrInvalidState, err.(*domain.GameError).Code)
+
+	debrief, err := aftermath.GetDebrief(session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, report.ResolvedAt, debrief.ResolvedAt)
+
+	// 散逸端带入下次任务的混沌池
+	next, err := gameService.CreateSession(agent.ID, "eternal-spring")
+	require.NoError(t, err)
+	assert.Equal(t, 4, next.State.ChaosPool)
+	assert.Equal(t, 4, next.State.LooseEnds)
+}
+
+func TestAftermathService_ResolveEscaped(t *testing.T) {
+	agentService, gameService, aftermath, agent, session := setupAftermathTest(t)
+	inventory := len(agent.Inventory)
+	finishMission(t, gameService, session.ID, OutcomeEscaped, 7)
+
+	report, err := aftermath.Resolve(session.ID, &ResolveAftermathRequest{})
+	require.NoError(t, err)
+
+	assert.Contains(t, report.Narrative, "逃脱")
+	require.Len(t, report.Entries, 1)
+	assert.Equal(t, domain.LedgerReasonEscapePenalty, report.Entries[0].Reason)
+	assert.Equal(t, 3, report.Reprimands)
+	assert.Empty(t, report.Claimables, "异常体逃脱时不发放可申领物")
+
+	stored, err := agentService.GetAgent(agent.ID)
+	require.NoError(t, err)
+	assert.Len(t, stored.Inventory, inventory)
+	assert.Equal(t, 7, stored.LooseEnds)
+}
+
+func TestAftermathService_ResolveDeal(t *testing.T) {
+	_, gameService, aftermath, _, session := setupAftermathTest(t)
+	finishMission(t, gameService, session.ID, OutcomeDeal, 0)
+
+	report, err := aftermath.Resolve(session.ID, &ResolveAftermathRequest{})
+	require.NoError(t, err)
+	assert.Contains(t, report.Narrative, "协议")
+	assert.Empty(t, report.Entries, "达成协议无奖惩")
+}
+
+func TestAftermathService_ResolveErrors(t *testing.T) {
+	_, gameService, aftermath, _, session := setupAftermathTest(t)
+
+	_, err := aftermath.Resolve(session.ID, &ResolveAftermathRequest{})
+	require.Error(t, err)
+	assert.Equal(t, domain.ErrInvalidPhase, err.(*domain.GameError).Code)
+
+	_, err = gameService.ChooseGoals(session.ID, []string{"save-serena"})
+	require.NoError(t, err)
+	require.NoError(t, gameService.TransitionPhase(session.ID, domain.PhaseInvestigation))
+	require.NoError(t, gameService.TransitionPhase(session.ID, domain.PhaseEncounter))
+	require.NoError(t, gameService.TransitionPhase(session.ID, domain.PhaseAftermath))
+
+	// 遭遇没有结果
+	_, err = aftermath.Resolve(session.ID, &ResolveAftermathRequest{})
+	require.Error(t, err)
+	assert.Equal(t, domain.ErrInvalidState, err.(*domain.GameError).Code)
+
+	require.NoError(t, gameService.UpdateState(session.ID, func(state *domain.GameState) error {
+		state.MissionOutcome = OutcomeNeutralized
+		return nil
+	}))
+
+	// 只能完成晨会中选择的目标
+	_, err = aftermath.Resolve(session.ID, &ResolveAftermathRequest{CompletedGoals: []string{"product-recall"}})
+	require.Error(t, err)
+	assert.Equal(t, domain.ErrInvalidInput, err.(*domain.GameError).Code)
+
+	_, err = aftermath.Resolve(session.ID, &ResolveAftermathRequest{CompletedGoals: []string{"save-serena"}})
+	require.NoError(t, err)
+}
+
+func TestAftermathService_ResolveConcurrent(t *testing.T) {
+	agentService, gameService, aftermath, agent, session := setupAftermathTest(t)
+	agentID := agent.ID
+
+	before, err := agentService.GetAgent(agentID)
+	require.NoError(t, err)
+	commendations := before.Commendations
+
+	finishMission(t, gameService, session.ID, OutcomeCaptured, 0)
+
+	// 并发重复提交只结算一次
+	var wg sync.WaitGroup
+	var succeeded int32
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := aftermath.Resolve(session.ID, &ResolveAftermathRequest{}); err == nil {
+				atomic.AddInt32(&succeeded, 1)
+			} else {
+				assert.Equal(t, domain.ErrInvalidState, err.(*domain.GameError).Code)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), succeeded)
+
+	report, err := aftermath.GetDebrief(session.ID)
+	require.NoError(t, err)
+	after, err := agentService.GetAgent(agentID)
+	require.NoError(t, err)
+	awarded := 0
+	for _, entry := range report.Entries {
+		awarded += entry.Commendations
+	}
+	assert.Equal(t, commendations+awarded, after.Commendations)
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+)
+
+// 角色包格式
+const (
+	AgentBundleFormat        = "trpg-agent-bundle"
+	AgentBundleSchemaVersion = 1              // 当前写出的版本，也是能读取的最高版本
+	agentBundleSignatureAlgo = "hmac-sha256:" // 使用服务器签名密钥的签名
+)
+
+// 导入时角色ID已存在的处理方式
+const (
+	ImportConflictRename = "rename" // 分配新ID（默认）
+	ImportConflictReject = "reject" // 拒绝导入
+)
+
+// AgentBundleService 角色包服务接口
+// 角色包是带版本和签名的JSON，包含在另一个部署上重建角色所需的全部数据：
+// 异常能力、现实状态、人际关系、绩效账本和物品栏
+// 服务器未配置签名密钥时拒绝导出和导入
+type AgentBundleService interface {
+	// 导出角色包
+	Export(agentID string) (*AgentBundle, error)
+
+	// 导入角色包，校验和不符、版本不支持或账本对不上时拒绝
+	Import(bundle *AgentBundle, opts *ImportOptions) (*ImportResult, error)
+}
+
+// AgentBundle 可移植的角色包
+type AgentBundle struct {
+	Format        string        `json:"format"`
+	SchemaVersion int           `json:"schema_version"`
+	ExportedAt    time.Time     `json:"exported_at"`
+	Checksum      string        `json:"checksum,omitempty"` // 除校验和外整个角色包的HMAC-SHA256签名
+	Agent         *domain.Agent `json:"agent"`
+}
+
+// ImportOptions 导入选项
+type ImportOptions struct {
+	OnConflict string `json:"on_conflict"` // rename 或 reject，默认 rename
+}
+
+// ImportResult 导入结果
+type ImportResult struct {
+	Agent      *domain.Agent `json:"agent"`
+	OriginalID string        `json:"original_id"`
+	Renamed    bool          `json:"renamed"` // 原ID已存在，分配了新ID
+}
+
+// ComputeChecksum 计算角色包的HMAC签名，没有密钥无法伪造
+// 不带密钥的摘要任何人都能重算，所以key为空时返回错误
+func (b *AgentBundle) ComputeChecksum(key []byte) (string, error) {
+	if len(key) == 0 {
+		return "", errors.New("角色包签名密钥为空")
+	}
+
+	unsigned := *b
+	unsigned.Checksum = ""
+
+	data, err := json.Marshal(&unsigned)
+	if err != nil {
+		return "", err
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return agentBundleSignatureAlgo + hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// agentBundleService 角色包服务实现
+type agentBundleService struct {
+	agentService AgentService
+	signingKey   []byte
+}
+
+// NewAgentBundleService 创建角色包服务，只接受同一密钥签名的角色包
+// signingKey为空时服务仍可创建，但导出和导入都会被拒绝
+func NewAgentBundleService(agentService AgentService, signingKey []byte) AgentBundleService {
+	return &agentBundleService{
+		agentService: agentService,
+		signingKey:   signingKey,
+	}
+}
+
+// Export 导出角色包
+func (s *agentBundleService) Export(agentID string) (*AgentBundle, error) {
+	if err := s.checkSigningKey(); err != nil {
+		return nil, err
+	}
+
+	agent, err := s.agentService.GetAgent(agentID)
+	if err != nil {
+		return nil, err
+	}
+	assignLedgerIDs(agent)
+
+	// 导出副本，避免与服务中的角色共享数据
+	copied, err := cloneAgent(agent)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := &AgentBundle{
+		Format:        AgentBundleFormat,
+		SchemaVersion: AgentBundleSchemaVersion,
+		ExportedAt:    time.Now().UTC(),
+		Agent:         copied,
+	}
+	if bundle.Checksum, err = bundle.ComputeChecksum(s.signingKey); err != nil {
+		return nil, err
+	}
+
+	return bundle, nil
+}
+
+// Import 导入角色包
+// 角色ID已存在时按选项分配新ID或拒绝；分配新ID时账本条目也使用新ID，避免与原角色的账本冲突
+func (s *agentBundleService) Import(bundle *AgentBundle, opts *ImportOptions) (*ImportResult, error) {
+	if err := s.checkSigningKey(); err != nil {
+		return nil, err
+	}
+	if err := s.verify(bundle); err != nil {
+		return nil, err
+	}
+
+	onConflict := ImportConflictRename
+	if opts != nil && opts.OnConflict != "" {
+		onConflict = opts.OnConflict
+	}
+	if onConflict != ImportConflictRename && onConflict != ImportConflictReject {
+		return nil, domain.NewGameError(domain.ErrInvalidInput, "无效的ID冲突处理方式").
+			WithDetails("on_conflict", onConflict)
+	}
+
+	agent := bundle.Agent
+	result := &ImportResult{Agent: agent, OriginalID: agent.ID}
+
+	if agent.ID == "" {
+		agent.ID = uuid.New().String()
+	} else if _, err := s.agentService.GetAgent(agent.ID); err == nil {
+		if onConflict == ImportConflictReject {
+			return nil, domain.NewGameError(domain.ErrAlreadyExists, "角色已存在").
+				WithDetails("agent_id", agent.ID)
+		}
+		agent.ID = uuid.New().String()
+		result.Renamed = true
+	}
+
+	for _, entry := range agent.Ledger {
+		if result.Renamed {
+			entry.ID = uuid.New().String()
+		}
+		entry.AgentID = agent.ID
+	}
+	agent.UpdatedAt = time.Now()
+
+	if err := s.agentService.ImportAgent(agent); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// checkSigningKey 未配置签名密钥时拒绝导出和导入，避免签出或接受任何人都能伪造的角色包
+func (s *agentBundleService) checkSigningKey() error {
+	if len(s.signingKey) == 0 {
+		return domain.NewGameError(domain.ErrInvalidState, "服务器未配置角色包签名密钥，不能导出或导入角色包").
+			WithDetails("config", "auth.bundle_signing_key")
+	}
+	return nil
+}
+
+// verify 检查角色包格式、版本、校验和、ARC是否完整以及嘉奖/申诫与账本是否一致
+func (s *agentBundleService) verify(bundle *AgentBundle) error {
+	if bundle == nil || bundle.Format != AgentBundleFormat {
+		return domain.NewGameError(domain.ErrInvalidInput, "不是角色包")
+	}
+
+	if bundle.SchemaVersion < 1 || bundle.SchemaVersion > AgentBundleSchemaVersion {
+		return domain.NewGameError(domain.ErrInvalidInput, "不支持的角色包版本").
+			WithDetails("schema_version", bundle.SchemaVersion).
+			WithDetails("supported", AgentBundleSchemaVersion)
+	}
+
+	checksum, err := bundle.ComputeChecksum(s.signingKey)
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal([]byte(bundle.Checksum), []byte(checksum)) {
+		return domain.NewGameError(domain.ErrDataCorrupted, "角色包校验和不匹配，内容可能被篡改").
+			WithDetails("checksum", bundle.Checksum)
+	}
+
+	agent := bundle.Agent
+	if agent == nil || agent.Anomaly == nil || agent.Reality == nil || agent.Career == nil {
+		return domain.NewGameError(domain.ErrInvalidARC, "角色包缺少ARC数据")
+	}
+
+	// 嘉奖和申诫只能经由账本变化，计数与账本合计不符说明被改过
+	if reconciliation := agent.ReconcileLedger(); !reconciliation.Balanced {
+		return domain.NewGameError(domain.ErrDataCorrupted, "角色包的嘉奖/申诫与账本不一致").
+			WithDetails("reconciliation", reconciliation)
+	}
+
+	return nil
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+)
+
+var testBundleKey = []byte("test-signing-key")
+
+// exportBundle 从一个服务导出角色包，经过JSON序列化模拟在部署之间传输
+func exportBundle(t *testing.T) (AgentService, *domain.Agent, []byte) {
+	agentService := NewAgentService()
+	agent, err := agentService.CreateAgent(&CreateAgentRequest{
+		Name:        "迁移测试",
+		AnomalyType: domain.AnomalyWhisper,
+		RealityType: domain.RealityCaretaker,
+		CareerType:  domain.CareerPublicRelations,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, agentService.AddCommendations(agent.ID, 3))
+	agent.Relationships[0].AddNote("喜欢猫")
+	agent.Relationships[0].ChangeConnection(1, "一起吃了晚饭", "")
+	agent.Reality.DegradationTrack.Filled = 2
+
+	bundle, err := NewAgentBundleService(agentService, testBundleKey).Export(agent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, AgentBundleSchemaVersion, bundle.SchemaVersion)
+	assert.NotEmpty(t, bundle.Checksum)
+
+	data, err := json.Marshal(bundle)
+	require.NoError(t, err)
+	return agentService, agent, data
+}
+
+func decodeBundle(t *testing.T, data []byte) *AgentBundle {
+	var bundle AgentBundle
+	require.NoError(t, json.Unmarshal(data, &bundle))
+	return &bundle
+}
+
+func TestAgentBundleService_RoundTrip(t *testing.T) {
+	_, original, data := exportBundle(t)
+
+	// 导入到另一个部署
+	target := NewAgentService()
+	result, err := NewAgentBundleService(target, testBundleKey).Import(decodeBundle(t, data), nil)
+	require.NoError(t, err)
+	assert.False(t, result.Renamed)
+	assert.Equal(t, original.ID, result.Agent.ID)
+
+	imported, err := target.GetAgent(original.ID)
+	require.NoError(t, err)
+	assert.Equal(t, original.Anomaly, imported.Anomaly)
+	assert.Equal(t, 2, imported.Reality.DegradationTrack.Filled)
+	assert.Equal(t, []string{"喜欢猫"}, imported.Relationships[0].Notes)
+	require.Len(t, imported.Relationships[0].History, 1)
+	assert.Equal(t, 3, imported.Commendations)
+	require.Len(t, imported.Ledger, 1)
+	assert.True(t, imported.ReconcileLedger().Balanced)
+	require.Len(t, imported.Inventory, 1)
+	assert.Equal(t, original.Inventory[0].ID, imported.Inventory[0].ID)
+}
+
+func TestAgentBundleService_IDCollision(t *testing.T) {
+	agentService, original, data := exportBundle(t)
+	bundles := NewAgentBundleService(agentService, testBundleKey)
+
+	// 导回同一个部署时默认分配新ID
+	result, err := bundles.Import(decodeBundle(t, data), nil)
+	require.NoError(t, err)
+	assert.True(t, result.Renamed)
+	assert.Equal(t, original.ID, result.OriginalID)
+	assert.NotEqual(t, original.ID, result.Agent.ID)
+	assert.NotEqual(t, original.Ledger[0].ID, result.Agent.Ledger[0].ID)
+	assert.Equal(t, result.Agent.ID, result.Agent.Ledger[0].AgentID)
+
+	agents, err := agentService.ListAgents()
+	require.NoError(t, err)
+	assert.Len(t, agents, 2)
+
+	_, err = bundles.Import(decodeBundle(t, data), &ImportOptions{OnConflict: ImportConflictReject})
+	require.Error(t, err)
+	assert.Equal(t, domain.ErrAlreadyExists, err.(*domain.GameError).Code)
+
+	_, err = bundles.Import(decodeBundle(t, data), &ImportOptions{OnConflict: "replace"})
+	require.Error(t, err)
+	assert.Equal(t, domain.ErrInvalidInput, err.(*domain.GameError).Code)
+}
+
+func TestAgentBundleService_RejectsInvalidBundles(t *testing.T) {
+	_, _, data := exportBundle(t)
+	bundles := NewAgentBundleService(NewAgentService(), testBundleKey)
+
+	t.Run("内容被篡改", func(t *testing.T) {
+		bundle := decodeBundle(t, data)
+		bundle.Agent.Commendations = 99
+
+		_, err := bundles.Import(bundle, nil)
+		require.Error(t, err)
+		assert.Equal(t, domain.ErrDataCorrupted, err.(*domain.GameError).Code)
+	})
+
+	t.Run("缺少校验和", func(t *testing.T) {
+		bundle := decodeBundle(t, data)
+		bundle.Checksum = ""
+
+		_, err := bundles.Import(bundle, nil)
+		require.Error(t, err)
+		assert.Equal(t, domain.ErrDataCorrupted, err.(*domain.GameError).Code)
+	})
+
+	t.Run("更高的版本", func(t *testing.T) {
+		bundle := decodeBundle(t, data)
+		bundle.SchemaVersion = AgentBundleSchemaVersion + 1
+		bundle.Checksum, _ = bundle.ComputeChecksum(testBundleKey)
+
+		_, err := bundles.Import(bundle, nil)
+		require.Error(t, err)
+		assert.Equal(t, domain.ErrInvalidInput, err.(*domain.GameError).Code)
+	})
+
+	t.Run("不是角色包", func(t *testing.T) {
+		_, err := bundles.Import(&AgentBundle{SchemaVersion: 1}, nil)
+		require.Error(t, err)
+		assert.Equal(t, domain.ErrInvalidInput, err.(*domain.GameError).Code)
+	})
+
+	t.Run("账本对不上", func(t *testing.T) {
+		// 即使签名有效，只改计数也能被账本核对发现
+		bundle := decodeBundle(t, data)
+		bundle.Agent.Commendations = 99
+		bundle.Checksum, _ = bundle.ComputeChecksum(testBundleKey)
+
+		_, err := bundles.Import(bundle, nil)
+		require.Error(t, err)
+		assert.Equal(t, domain.ErrDataCorrupted, err.(*domain.GameError).Code)
+	})
+
+	t.Run("缺少ARC", func(t *testing.T) {
+		bundle := decodeBundle(t, data)
+		bundle.Agent.Career = nil
+		bundle.Checksum, _ = bundle.ComputeChecksum(testBundleKey)
+
+		_, err := bundles.Import(bundle, nil)
+		require.Error(t, err)
+		assert.Equal(t, domain.ErrInvalidARC, err.(*domain.GameError).Code)
+	})
+}
+
+func TestAgentBundleService_SigningKey(t *testing.T) {
+	agentService, original, _ := exportBundle(t)
+	key := []byte("server-signing-key")
+	signed := NewAgentBundleService(agentService, key)
+
+	bundle, err := signed.Export(original.ID)
+	require.NoError(t, err)
+	assert.Contains(t, bundle.Checksum, agentBundleSignatureAlgo)
+	data, err := json.Marshal(bundle)
+	require.NoError(t, err)
+
+	target := NewAgentService()
+	_, err = NewAgentBundleService(target, key).Import(decodeBundle(t, data), nil)
+	require.NoError(t, err)
+
+	t.Run("没有密钥无法伪造", func(t *testing.T) {
+		forged := decodeBundle(t, data)
+		forged.Agent.Reality.DegradationTrack.Filled = 0
+		forged.Checksum, _ = forged.ComputeChecksum([]byte("guessed-key"))
+
+		_, err := NewAgentBundleService(NewAgentService(), key).Import(forged, nil)
+		require.Error(t, err)
+		assert.Equal(t, domain.ErrDataCorrupted, err.(*domain.GameError).Code)
+	})
+
+	t.Run("其他密钥签名的角色包", func(t *testing.T) {
+		_, err := NewAgentBundleService(NewAgentService(), []byte("other-key")).Import(decodeBundle(t, data), nil)
+		require.Error(t, err)
+		assert.Equal(t, domain.ErrDataCorrupted, err.(*domain.GameError).Code)
+	})
+}
+
+func TestAgentBundleService_RequiresSigningKey(t *testing.T) {
+	agentService, original, data := exportBundle(t)
+	unsigned := NewAgentBundleService(agentService, nil)
+
+	_, err := unsigned.Export(original.ID)
+	require.Error(t, err)
+	assert.Equal(t, domain.ErrInvalidState, err.(*domain.GameError).Code)
+
+	_, err = unsigned.Import(decodeBundle(t, data), nil)
+	require.Error(t, err)
+	assert.Equal(t, domain.ErrInvalidState, err.(*domain.GameError).Code)
+
+	_, err = decodeBundle(t, data).ComputeChecksum(nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,398 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/trpg-solo-engine/backend/internal/catalog"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+	"github.com/trpg-solo-engine/backend/internal/infrastructure/repository"
+)
+
+// AgentDraftService 角色创建向导服务接口
+// 按顺序完成异常体、职能评估、现实、人际关系四个步骤后确认生成角色
+type AgentDraftService interface {
+	CreateDraft(req *CreateDraftRequest) (*domain.AgentDraft, error)
+	GetDraft(draftID string) (*domain.AgentDraft, error)
+
+	// 向导步骤
+	ChooseAnomaly(draftID string, req *ChooseAnomalyRequest) (*domain.AgentDraft, error)
+	AnswerAssessment(draftID string, req *AnswerAssessmentRequest) (*domain.AgentDraft, error)
+	ChooseReality(draftID string, req *ChooseRealityRequest) (*domain.AgentDraft, error)
+	DistributeConnection(draftID string, req *DistributeConnectionRequest) (*domain.AgentDraft, error)
+	Finalize(draftID string) (*domain.Agent, error)
+}
+
+// CreateDraftRequest 创建草稿请求
+type CreateDraftRequest struct {
+	Name     string `json:"name" binding:"required"`
+	Pronouns string `json:"pronouns"`
+}
+
+// ChooseAnomalyRequest 选择异常体请求（ID或类型名称）
+type ChooseAnomalyRequest struct {
+	AnomalyType string `json:"anomaly_type" binding:"required"`
+}
+
+// AnswerAssessmentRequest 选择职能并按顺序回答评估问题
+type AnswerAssessmentRequest struct {
+	CareerType string   `json:"career_type" binding:"required"`
+	Answers    []string `json:"answers"`
+}
+
+// ChooseRealityRequest 选择现实请求（ID或类型名称）
+type ChooseRealityRequest struct {
+	RealityType string `json:"reality_type" binding:"required"`
+}
+
+// DistributeConnectionRequest 按现实的人际关系问题顺序填写关系并分配连结
+type DistributeConnectionRequest struct {
+	Relationships []*DraftRelationshipInput `json:"relationships"`
+}
+
+// DraftRelationshipInput 单段人际关系
+type DraftRelationshipInput struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Connection  int    `json:"connection"`
+}
+
+// draftStore 草稿存储
+type draftStore interface {
+	create(draft *domain.AgentDraft) error
+	get(draftID string) (*domain.AgentDraft, error)
+	update(draft *domain.AgentDraft) error
+}
+
+type agentDraftService struct {
+	drafts       draftStore
+	agentService AgentService
+	catalog      *catalog.Catalog
+	mu           sync.Mutex
+}
+
+// NewAgentDraftService 创建使用内存存储的角色向导服务
+func NewAgentDraftService(agentService AgentService, arc *catalog.Catalog) AgentDraftService {
+	return newAgentDraftService(&memoryDraftStore{drafts: make(map[string]*domain.AgentDraft)}, agentService, arc)
+}
+
+// NewAgentDraftServiceWithRepo 创建使用仓储的角色向导服务，草稿在重启后仍可继续
+func NewAgentDraftServiceWithRepo(repo repository.DraftRepository, agentService AgentService, arc *catalog.Catalog) AgentDraftService {
+	return newAgentDraftService(&repoDraftStore{repo: repo}, agentService, arc)
+}
+
+func newAgentDraftService(drafts draftStore, agentService AgentService, arc *catalog.Catalog) AgentDraftService {
+	if arc == nil {
+		arc = catalog.Default()
+	}
+
+	return &agentDraftService{
+		drafts:       drafts,
+		agentService: agentService,
+		catalog:      arc,
+	}
+}
+
+// CreateDraft 创建草稿，从选择异常体开始
+func (s *agentDraftService) CreateDraft(req *CreateDraftRequest) (*domain.AgentDraft, error) {
+	if strings.TrimSpace(req.Name) == "" {
+		return nil, domain.NewGameError(domain.ErrInvalidInput, "角色名称不能为空")
+	}
+
+	now := time.Now()
+	draft := &domain.AgentDraft{
+		ID:        uuid.New().String(),
+		Name:      req.Name,
+		Pronouns:  req.Pronouns,
+		Step:      domain.DraftStepAnomaly,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := s.drafts.create(draft); err != nil {
+		return nil, err
+	}
+
+	return draft, nil
+}
+
+// GetDraft 获取草稿
+func (s *agentDraftService) GetDraft(draftID string) (*domain.AgentDraft, error) {
+	return s.drafts.get(draftID)
+}
+
+// ChooseAnomaly 步骤1：选择异常体
+func (s *agentDraftService) ChooseAnomaly(draftID string, req *ChooseAnomalyRequest) (*domain.AgentDraft, error) {
+	return s.step(draftID, domain.DraftStepAnomaly, func(draft *domain.AgentDraft) error {
+		anomaly, ok := s.catalog.Anomaly(req.AnomalyType)
+		if !ok {
+			return stepError(domain.DraftStepAnomaly, "无效的异常体类型").
+				WithDetails("anomaly_type", req.AnomalyType)
+		}
+
+		draft.AnomalyType = anomaly.Name
+		return nil
+	})
+}
+
+// AnswerAssessment 步骤2：选择职能并回答该职能的全部评估问题
+func (s *agentDraftService) AnswerAssessment(draftID string, req *AnswerAssessmentRequest) (*domain.AgentDraft, error) {
+	return s.step(draftID, domain.DraftStepCareer, func(draft *domain.AgentDraft) error {
+		career, ok := s.catalog.Career(req.CareerType)
+		if !ok {
+			return stepError(domain.DraftStepCareer, "无效的职能类型").
+				WithDetails("career_type", req.CareerType)
+		}
+
+		questions := career.AssessmentQuestions
+		if len(req.Answers) != len(questions) {
+			return stepError(domain.DraftStepCareer, "评估问题回答数量不正确").
+				WithDetails("expected", len(questions)).
+				WithDetails("actual", len(req.Answers)).
+				WithDetails("questions", questions)
+		}
+
+		unanswered := make([]string, 0)
+		assessment := make([]*domain.AssessmentAnswer, 0, len(questions))
+		for i, question := range questions {
+			answer := strings.TrimSpace(req.Answers[i])
+			if answer == "" {
+				unanswered = append(unanswered, question)
+				continue
+			}
+			assessment = append(assessment, &domain.AssessmentAnswer{
+				Question: question,
+				Answer:   answer,
+			})
+		}
+		if len(unanswered) > 0 {
+			return stepError(domain.DraftStepCareer, "评估问题未全部回答").
+				WithDetails("unanswered", unanswered)
+		}
+
+		draft.CareerType = career.Name
+		draft.Assessment = assessment
+		return nil
+	})
+}
+
+// ChooseReality 步骤3：选择现实
+// 更换现实后人际关系问题随之变化，已分配的关系需要重新填写
+func (s *agentDraftService) ChooseReality(draftID string, req *ChooseRealityRequest) (*domain.AgentDraft, error) {
+	return s.step(draftID, domain.DraftStepReality, func(draft *domain.AgentDraft) error {
+		reality, ok := s.catalog.Reality(req.RealityType)
+		if !ok {
+			return stepError(domain.DraftStepReality, "无效的现实类型").
+				WithDetails("reality_type", req.RealityType)
+		}
+
+		if draft.RealityType != reality.Name && len(draft.Relationships) > 0 {
+			draft.Relationships = nil
+			draft.Rewind(domain.DraftStepRelationships)
+		}
+
+		draft.RealityType = reality.Name
+		return nil
+	})
+}
+
+// DistributeConnection 步骤4：按现实的人际关系问题填写关系并分配连结点数
+func (s *agentDraftService) DistributeConnection(draftID string, req *DistributeConnectionRequest) (*domain.AgentDraft, error) {
+	return s.step(draftID, domain.DraftStepRelationships, func(draft *domain.AgentDraft) error {
+		reality, ok := s.catalog.Reality(draft.RealityType)
+		if !ok {
+			return stepError(domain.DraftStepRelationships, "草稿的现实类型无效").
+				WithDetails("reality_type", draft.RealityType)
+		}
+
+		rules := reality.Relationships
+		if len(req.Relationships) != rules.Count {
+			return stepError(domain.DraftStepRelationships, "人际关系数量不正确").
+				WithDetails("expected", rules.Count).
+				WithDetails("actual", len(req.Relationships)).
+				WithDetails("questions", rules.Questions)
+		}
+
+		total := 0
+		relationships := make([]*domain.DraftRelationship, 0, len(req.Relationships))
+		for i, input := range req.Relationships {
+			question := ""
+			if i < len(rules.Questions) {
+				question = rules.Questions[i]
+			}
+
+			if input == nil || strings.TrimSpace(input.Name) == "" {
+				return stepError(domain.DraftStepRelationships, "人际关系缺少名称").
+					WithDetails("index", i).
+					WithDetails("question", question)
+			}
+			if input.Connection < 1 {
+				return stepError(domain.DraftStepRelationships, "每段人际关系至少需要1点连结").
+					WithDetails("index", i).
+					WithDetails("connection", input.Connection)
+			}
+
+			total += input.Connection
+			relationships = append(relationships, &domain.DraftRelationship{
+				Question:    question,
+				Name:        strings.TrimSpace(input.Name),
+				Description: input.Description,
+				Connection:  input.Connection,
+			})
+		}
+
+		if total != rules.TotalConnection {
+			return stepError(domain.DraftStepRelationships, "连结点数分配总和不正确").
+				WithDetails("expected", rules.TotalConnection).
+				WithDetails("actual", total).
+				WithDetails("suggested_distribution", rules.SuggestedDistribution)
+		}
+
+		draft.Relationships = relationships
+		return nil
+	})
+}
+
+// Finalize 步骤5：确认并生成角色
+func (s *agentDraftService) Finalize(draftID string) (*domain.Agent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	draft, err := s.drafts.get(draftID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := draft.CheckStep(domain.DraftStepFinalize); err != nil {
+		return nil, err
+	}
+
+	relationships := draft.AgentRelationships()
+	for _, rel := range relationships {
+		rel.ID = uuid.New().String()
+	}
+
+	agent, err := s.agentService.CreateAgent(&CreateAgentRequest{
+		Name:          draft.Name,
+		Pronouns:      draft.Pronouns,
+		AnomalyType:   draft.AnomalyType,
+		RealityType:   draft.RealityType,
+		CareerType:    draft.CareerType,
+		Relationships: relationships,
+		Assessment:    draft.Assessment,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 草稿保存失败时删除刚生成的角色，避免重试时重复生成
+	previous := *draft
+	draft.Step = domain.DraftStepCompleted
+	draft.AgentID = agent.ID
+	draft.UpdatedAt = time.Now()
+	if err := s.drafts.update(draft); err != nil {
+		*draft = previous
+		if deleteErr := s.agentService.DeleteAgent(agent.ID); deleteErr != nil {
+			return nil, fmt.Errorf("%w（删除已生成的角色 %s 失败: %v）", err, agent.ID, deleteErr)
+		}
+		return nil, err
+	}
+
+	return agent, nil
+}
+
+// step 校验步骤顺序，执行步骤并保存草稿
+func (s *agentDraftService) step(draftID string, step domain.DraftStep, apply func(draft *domain.AgentDraft) error) (*domain.AgentDraft, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	draft, err := s.drafts.get(draftID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := draft.CheckStep(step); err != nil {
+		return nil, err
+	}
+
+	if err := apply(draft); err != nil {
+		return nil, err
+	}
+
+	draft.Advance(step)
+	if err := s.drafts.update(draft); err != nil {
+		return nil, err
+	}
+
+	return draft, nil
+}
+
+// stepError 创建步骤校验错误
+func stepError(step domain.DraftStep, message string) *domain.GameError {
+	return domain.NewGameError(domain.ErrInvalidInput, message).
+		WithDetails("step", step)
+}
+
+// memoryDraftStore 内存草稿存储
+type memoryDraftStore struct {
+	drafts map[string]*domain.AgentDraft
+}
+
+func (m *memoryDraftStore) create(draft *domain.AgentDraft) error {
+	m.drafts[draft.ID] = draft
+	return nil
+}
+
+func (m *memoryDraftStore) get(draftID string) (*domain.AgentDraft, error) {
+	draft, exists := m.drafts[draftID]
+	if !exists {
+		return nil, domain.NewGameError(domain.ErrNotFound, "草稿不存在").
+			WithDetails("draft_id", draftID)
+	}
+	return draft, nil
+}
+
+func (m *memoryDraftStore) update(draft *domain.AgentDraft) error {
+	m.drafts[draft.ID] = draft
+	return nil
+}
+
+// repoDraftStore 仓储草稿存储
+type repoDraftStore struct {
+	repo repository.DraftRepository
+}
+
+func (r *repoDraftStore) create(draft *domain.AgentDraft) error {
+	if err := r.repo.Create(context.Background(), draft); err != nil {
+		return domain.NewGameError(domain.ErrInternal, "保存草稿失败").
+			WithDetails("error", err.Error())
+	}
+	return nil
+}
+
+func (r *repoDraftStore) get(draftID string) (*domain.AgentDraft, error) {
+	draft, err := r.repo.GetByID(context.Background(), draftID)
+	if err != nil {
+		if _, ok := err.(*domain.GameError); ok {
+			return nil, err
+		}
+		return nil, domain.NewGameError(domain.ErrInternal, "读取草稿失败").
+			WithDetails("error", err.Error())
+	}
+	return draft, nil
+}
+
+func (r *repoDraftStore) update(draft *domain.AgentDraft) error {
+	if err := r.repo.Update(context.Background(), draft); err != nil {
+		if _, ok := err.(*domain.GameError); ok {
+			return err
+		}
+		return domain.NewGameError(domain.ErrInternal, "保存草稿失败").
+			WithDetails("error", err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+)
+
+// completeDraftSteps 依次完成向导的四个步骤
+func completeDraftSteps(t *testing.T, drafts AgentDraftService, draftID string) *domain.AgentDraft {
+	_, err := drafts.ChooseAnomaly(draftID, &ChooseAnomalyRequest{AnomalyType: "whisper"})
+	require.NoError(t, err)
+
+	_, err = drafts.AnswerAssessment(draftID, &AnswerAssessmentRequest{
+		CareerType: domain.CareerPublicRelations,
+		Answers:    []string{"酒会", "先稳住媒体", "我其实怕镜头"},
+	})
+	require.NoError(t, err)
+
+	_, err = drafts.ChooseReality(draftID, &ChooseRealityRequest{RealityType: domain.RealityCaretaker})
+	require.NoError(t, err)
+
+	draft, err := drafts.DistributeConnection(draftID, &DistributeConnectionRequest{
+		Relationships: []*DraftRelationshipInput{
+			{Name: "姐姐", Connection: 6},
+			{Name: "前室友", Connection: 3},
+			{Name: "邻居小孩", Connection: 3},
+		},
+	})
+	require.NoError(t, err)
+
+	return draft
+}
+
+func TestAgentDraftService_Wizard(t *testing.T) {
+	agentService := NewAgentService()
+	drafts := NewAgentDraftService(agentService, nil)
+
+	draft, err := drafts.CreateDraft(&CreateDraftRequest{Name: "新特工", Pronouns: "她"})
+	require.NoError(t, err)
+	assert.Equal(t, domain.DraftStepAnomaly, draft.Step)
+
+	draft = completeDraftSteps(t, drafts, draft.ID)
+	assert.Equal(t, domain.DraftStepFinalize, draft.Step)
+	assert.Equal(t, domain.AnomalyWhisper, draft.AnomalyType)
+	require.Len(t, draft.Assessment, 3)
+	assert.Equal(t, "你最擅长哪种社交场合？", draft.Assessment[0].Question)
+	assert.Equal(t, "如果你不在了，谁会获得受照料者的监护权？", draft.Relationships[0].Question)
+
+	agent, err := drafts.Finalize(draft.ID)
+	require.NoError(t, err)
+	assert.NoError(t, agent.ValidateARC())
+	assert.Equal(t, "新特工", agent.Name)
+	assert.Equal(t, 12, agent.TotalConnection())
+	assert.Len(t, agent.Assessment, 3)
+	for _, rel := range agent.Relationships {
+		assert.NotEmpty(t, rel.ID)
+	}
+
+	stored, err := agentService.GetAgent(agent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, agent.ID, stored.ID)
+
+	draft, err = drafts.GetDraft(draft.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.DraftStepCompleted, draft.Step)
+	assert.Equal(t, agent.ID, draft.AgentID)
+
+	// 已完成的草稿不能重复生成角色
+	_, err = drafts.Finalize(draft.ID)
+	require.Error(t, err)
+	assert.Equal(t, domain.ErrInvalidState, err.(*domain.GameError).Code)
+}
+
+// failingDraftStore 在开启失败后拒绝保存草稿
+type failingDraftStore struct {
+	*memoryDraftStore
+	failUpdate bool
+}
+
+func (f *failingDraftStore) update(draft *domain.AgentDraft) error {
+	if f.failUpdate {
+		return errors.New("草稿存储不可用")
+	}
+	return f.memoryDraftStore.update(draft)
+}
+
+func TestAgentDraftService_FinalizeRollback(t *testing.T) {
+	agentService := NewAgentService()
+	store := &failingDraftStore{memoryDraftStore: &memoryDraftStore{drafts: make(map[string]*domain.AgentDraft)}}
+	drafts := newAgentDraftService(store, agentService, nil)
+
+	draft, err := drafts.CreateDraft(&CreateDraftRequest{Name: "新特工"})
+	require.NoError(t, err)
+	completeDraftSteps(t, drafts, draft.ID)
+
+	// 草稿保存失败时不留下角色，草稿仍可重新确认
+	store.failUpdate = true
+	_, err = drafts.Finalize(draft.ID)
+	require.Error(t, err)
+
+	agents, err := agentService.ListAgents()
+	require.NoError(t, err)
+	assert.Empty(t, agents)
+
+	draft, err = drafts.GetDraft(draft.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.DraftStepFinalize, draft.Step)
+	assert.Empty(t, draft.AgentID)
+
+	store.failUpdate = false
+	agent, err := drafts.Finalize(draft.ID)
+	require.NoError(t, err)
+
+	agents, err = agentService.ListAgents()
+	require.NoError(t, err)
+	require.Len(t, agents, 1)
+	assert.Equal(t, agent.ID, agents[0].ID)
+}
+
+func TestAgentDraftService_StepOrder(t *testing.T) {
+	drafts := NewAgentDraftService(NewAgentService(), nil)
+
+	draft, err := drafts.CreateDraft(&CreateDraftRequest{Name: "新特工"})
+	require.NoError(t, err)
+
+	t.Run("不能跳过步骤", func(t *testing.T) {
+		_, err := drafts.ChooseReality(draft.ID, &ChooseRealityRequest{RealityType: domain.RealityStar})
+		require.Error(t, err)
+		gameErr := err.(*domain.GameError)
+		assert.Equal(t, domain.ErrInvalidState, gameErr.Code)
+		assert.Equal(t, domain.DraftStepAnomaly, gameErr.Details["current_step"])
+
+		_, err = drafts.Finalize(draft.ID)
+		require.Error(t, err)
+		assert.Equal(t, domain.ErrInvalidState, err.(*domain.GameError).Code)
+	})
+
+	t.Run("更换现实后需要重新分配连结", func(t *testing.T) {
+		completeDraftSteps(t, drafts, draft.ID)
+
+		updated, err := drafts.ChooseReality(draft.ID, &ChooseRealityRequest{RealityType: domain.RealityStar})
+		require.NoError(t, err)
+		assert.Equal(t, domain.DraftStepRelationships, updated.Step)
+		assert.Empty(t, updated.Relationships)
+	})
+
+	t.Run("重新提交前面的步骤不回退进度", func(t *testing.T) {
+		updated, err := drafts.ChooseAnomaly(draft.ID, &ChooseAnomalyRequest{AnomalyType: domain.AnomalyGun})
+		require.NoError(t, err)
+		assert.Equal(t, domain.AnomalyGun, updated.AnomalyType)
+		assert.Equal(t, domain.DraftStepRelationships, updated.Step)
+	})
+
+	t.Run("草稿不存在", func(t *testing.T) {
+		_, err := drafts.GetDraft("non-existent")
+		require.Error(t, err)
+		assert.Equal(t, domain.ErrNotFound, err.(*domain.GameError).Code)
+	})
+}
+
+func TestAgentDraftService_StepErrors(t *testing.T) {
+	drafts := NewAgentDraftService(NewAgentService(), nil)
+
+	draft, err := drafts.CreateDraft(&CreateDraftRequest{Name: "新特工"})
+	require.NoError(t, err)
+
+	assertStepError := func(t *testing.T, err error, step domain.DraftStep) *domain.GameError {
+		require.Error(t, err)
+		gameErr := err.(*domain.GameError)
+		assert.Equal(t, domain.ErrInvalidInput, gameErr.Code)
+		assert.Equal(t, step, gameErr.Details["step"])
+		return gameErr
+	}
+
+	_, err = drafts.ChooseAnomaly(draft.ID, &ChooseAnomalyRequest{AnomalyType: "不存在"})
+	assertStepError(t, err, domain.DraftStepAnomaly)
+
+	_, err = drafts.ChooseAnomaly(draft.ID, &ChooseAnomalyRequest{AnomalyType: domain.AnomalyDream})
+	require.NoError(t, err)
+
+	_, err = drafts.AnswerAssessment(draft.ID, &AnswerAssessmentRequest{
+		CareerType: domain.CareerIntern,
+		Answers:    []string{"只答了一个"},
+	})
+	gameErr := assertStepError(t, err, domain.DraftStepCareer)
+	assert.Equal(t, 3, gameErr.Details["expected"])
+
+	_, err = drafts.AnswerAssessment(draft.ID, &AnswerAssessmentRequest{
+		CareerType: domain.CareerIntern,
+		Answers:    []string{"一", " ", "三"},
+	})
+	gameErr = assertStepError(t, err, domain.DraftStepCareer)
+	assert.Len(t, gameErr.Details["unanswered"], 1)
+
+	_, err = drafts.AnswerAssessment(draft.ID, &AnswerAssessmentRequest{
+		CareerType: domain.CareerIntern,
+		Answers:    []string{"一", "二", "三"},
+	})
+	require.NoError(t, err)
+
+	_, err = drafts.ChooseReality(draft.ID, &ChooseRealityRequest{RealityType: "hunted"})
+	require.NoError(t, err)
+
+	_, err = drafts.DistributeConnection(draft.ID, &DistributeConnectionRequest{
+		Relationships: []*DraftRelationshipInput{
+			{Name: "甲", Connection: 6},
+			{Name: "乙", Connection: 6},
+		},
+	})
+	assertStepError(t, err, domain.DraftStepRelationships)
+
+	_, err = drafts.DistributeConnection(draft.ID, &DistributeConnectionRequest{
+		Relationships: []*DraftRelationshipInput{
+			{Name: "甲", Connection: 6},
+			{Name: "乙", Connection: 4},
+			{Name: "丙", Connection: 3},
+		},
+	})
+	gameErr = assertStepError(t, err, domain.DraftStepRelationships)
+	assert.Equal(t, 13, gameErr.Details["actual"])
+
+	_, err = drafts.DistributeConnection(draft.ID, &DistributeConnectionRequest{
+		Relationships: []*DraftRelationshipInput{
+			{Name: "甲", Connection: 12},
+			{Name: "乙", Connection: 0},
+			{Name: "丙", Connection: 0},
+		},
+	})
+	assertStepError(t, err, domain.DraftStepRelationships)
+
+	// 校验失败不会推进进度
+	current, err := drafts.GetDraft(draft.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.DraftStepRelationships, current.Step)
+}
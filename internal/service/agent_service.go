@@ -1,9 +1,12 @@
 package service
 
 import (
+	"encoding/json"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/trpg-solo-engine/backend/internal/catalog"
 	"github.com/trpg-solo-engine/backend/internal/domain"
 )
 
@@ -15,6 +18,9 @@ type AgentService interface {
 	DeleteAgent(agentID string) error
 	ListAgents() ([]*domain.Agent, error)
 
+	// 保存外部构造的完整角色（如导入的角色包），ID已存在时返回ErrAlreadyExists
+	ImportAgent(agent *domain.Agent) error
+
 	// ARC管理
 	SetAnomaly(agentID string, anomalyType string) error
 	SetReality(agentID string, realityType string) error
@@ -31,79 +37,51 @@ type AgentService interface {
 	// 绩效
 	AddCommendations(agentID string, amount int) error
 	AddReprimands(agentID string, amount int) error
+	RecordLedger(agentID string, entry *domain.LedgerEntry) error
 	UpdateRating(agentID string) error
+
+	// 原子修改：fn在角色副本上执行，返回错误时不保存任何变化
+	ModifyAgent(agentID string, fn func(agent *domain.Agent) error) (*domain.Agent, error)
 }
 
 type CreateAgentRequest struct {
-	Name          string                 `json:"name" binding:"required"`
-	Pronouns      string                 `json:"pronouns"`
-	AnomalyType   string                 `json:"anomaly_type" binding:"required"`
-	RealityType   string                 `json:"reality_type" binding:"required"`
-	CareerType    string                 `json:"career_type" binding:"required"`
-	Relationships []*domain.Relationship `json:"relationships"`
+	Name          string                     `json:"name" binding:"required"`
+	Pronouns      string                     `json:"pronouns"`
+	AnomalyType   string                     `json:"anomaly_type" binding:"required"`
+	RealityType   string                     `json:"reality_type" binding:"required"`
+	CareerType    string                     `json:"career_type" binding:"required"`
+	Relationships []*domain.Relationship     `json:"relationships"`
+	Assessment    []*domain.AssessmentAnswer `json:"assessment"`
 }
 
 type agentService struct {
-	agents map[string]*domain.Agent // 简化实现，使用内存存储
+	mu      sync.RWMutex             // 保护agents及其中角色的读-改-写
+	agents  map[string]*domain.Agent // 简化实现，使用内存存储
+	catalog *catalog.Catalog
 }
 
+// NewAgentService 创建使用内嵌ARC配置的角色服务
 func NewAgentService() AgentService {
+	return NewAgentServiceWithCatalog(catalog.Default())
+}
+
+// NewAgentServiceWithCatalog 创建使用指定ARC目录的角色服务
+func NewAgentServiceWithCatalog(arc *catalog.Catalog) AgentService {
+	if arc == nil {
+		arc = catalog.Default()
+	}
+
 	return &agentService{
-		agents: make(map[string]*domain.Agent),
+		agents:  make(map[string]*domain.Agent),
+		catalog: arc,
 	}
 }
 
 func (s *agentService) CreateAgent(req *CreateAgentRequest) (*domain.Agent, error) {
 	// 创建角色
-	agent := &domain.Agent{
-		ID:       uuid.New().String(),
-		Name:     req.Name,
-		Pronouns: req.Pronouns,
-		Anomaly: &domain.Anomaly{
-			Type:      req.AnomalyType,
-			Abilities: createDefaultAbilities(req.AnomalyType),
-		},
-		Reality: &domain.Reality{
-			Type: req.RealityType,
-			Trigger: &domain.RealityTrigger{
-				Name:        "现实触发",
-				Cost:        0,
-				Effect:      "触发效果",
-				Consequence: "忽视后果",
-			},
-			OverloadRelief: &domain.OverloadRelief{
-				Name:      "过载解除",
-				Condition: "满足条件",
-				Effect:    "无视所有过载",
-			},
-			DegradationTrack: &domain.DegradationTrack{
-				Name:   "退化轨道",
-				Filled: 0,
-				Total:  4,
-			},
-		},
-		Career: &domain.Career{
-			Type: req.CareerType,
-			QA:   getDefaultQA(req.CareerType),
-		},
-		QA:            getDefaultQA(req.CareerType),
-		Relationships: req.Relationships,
-		Commendations: 0,
-		Reprimands:    0,
-		Rating:        domain.RatingExcellent,
-		Alive:         true,
-		InDebt:        false,
-		CreatedAt:     time.Now(),
-		UpdatedAt:     time.Now(),
-	}
-
-	// 如果没有提供人际关系，创建默认的
-	if len(agent.Relationships) == 0 {
-		agent.Relationships = []*domain.Relationship{
-			{ID: uuid.New().String(), Name: "关系1", Connection: 6},
-			{ID: uuid.New().String(), Name: "关系2", Connection: 3},
-			{ID: uuid.New().String(), Name: "关系3", Connection: 3},
-		}
+	agent, err := newAgentFromCatalog(s.catalog, req)
+	if err != nil {
+		return nil, err
 	}
 
 	// 验证ARC
@@ -112,12 +90,21 @@ func (s *agentService) CreateAgent(req *CreateAgentRequest) (*domain.Agent, erro
 	}
 
 	// 保存
+	s.mu.Lock()
 	s.agents[agent.ID] = agent
+	s.mu.Unlock()
 
 	return agent, nil
 }
 
 func (s *agentService) GetAgent(agentID string) (*domain.Agent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.lookup(agentID)
+}
+
+// lookup 查找角色，调用方需持有锁
+func (s *agentService) lookup(agentID string) (*domain.Agent, error) {
 	agent, exists := s.agents[agentID]
 	if !exists {
 		return nil, domain.NewGameError(domain.ErrNotFound, "角色不存在")
@@ -126,21 +113,47 @@ func (s *agentService) GetAgent(agentID string) (*domain.Agent, error) {
 }
 
 func (s *agentService) UpdateAgent(agent *domain.Agent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	if _, exists := s.agents[agent.ID]; !exists {
 		return domain.NewGameError(domain.ErrNotFound, "角色不存在")
 	}
 
-	// 验证ARC
-	if err := agent.ValidateARC(); err != nil {
+	// 验证ARC（连结在游戏中会变化，不检查总数）
+	if err := agent.ValidateInPlay(); err != nil {
 		return err
 	}
 
+	assignLedgerIDs(agent)
 	agent.UpdatedAt = time.Now()
 	s.agents[agent.ID] = agent
 	return nil
 }
 
+func (s *agentService) ImportAgent(agent *domain.Agent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.agents[agent.ID]; exists {
+		return domain.NewGameError(domain.ErrAlreadyExists, "角色已存在").
+			WithDetails("agent_id", agent.ID)
+	}
+
+	// 验证ARC（导入的角色可能已经过游戏，不检查总数）
+	if err := agent.ValidateInPlay(); err != nil {
+		return err
+	}
+
+	assignLedgerIDs(agent)
+	s.agents[agent.ID] = agent
+	return nil
+}
+
 func (s *agentService) DeleteAgent(agentID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	if _, exists := s.agents[agentID]; !exists {
 		return domain.NewGameError(domain.ErrNotFound, "角色不存在")
 	}
@@ -150,6 +163,9 @@ func (s *agentService) DeleteAgent(agentID string) error {
 }
 
 func (s *agentService) ListAgents() ([]*domain.Agent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
 	agents := make([]*domain.Agent, 0, len(s.agents))
 	for _, agent := range s.agents {
 		agents = append(agents, agent)
@@ -159,28 +175,20 @@ func (s *agentService) ListAgents() ([]*domain.Agent, error) {
 
 // SetAnomaly 设置异常体类型
 func (s *agentService) SetAnomaly(agentID string, anomalyType string) error {
-	agent, err := s.GetAgent(agentID)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	agent, err := s.lookup(agentID)
 	if err != nil {
 		return err
 	}
 
-	// 验证异常体类型
-	validType := false
-	for _, t := range domain.AllAnomalyTypes {
-		if t == anomalyType {
-			validType = true
-			break
-		}
-	}
-	if !validType {
-		return domain.NewGameError(domain.ErrInvalidARC, "无效的异常体类型").
-			WithDetails("type", anomalyType)
+	anomaly, err := s.catalog.NewAnomaly(anomalyType)
+	if err != nil {
+		return err
 	}
 
-	agent.Anomaly = &domain.Anomaly{
-		Type:      anomalyType,
-		Abilities: createDefaultAbilities(anomalyType),
-	}
+	agent.Anomaly = anomaly
 	agent.UpdatedAt = time.Now()
 
 	return nil
@@ -188,42 +196,21 @@ func (s *agentService) SetAnomaly(agentID string, anomalyType string) error {
 
 // SetReality 设置现实类型
 func (s *agentService) SetReality(agentID string, realityType string) error {
-	agent, err := s.GetAgent(agentID)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	agent, err := s.lookup(agentID)
 	if err != nil {
 		return err
 	}
 
-	// 验证现实类型
-	validType := false
-	for _, t := range domain.AllRealityTypes {
-		if t == realityType {
-			validType = true
-			break
-		}
+	reality, err := s.catalog.NewReality(realityType)
+	if err != nil {
+		return err
 	}
-	if !validType {
-		return domain.NewGameError(domain.ErrInvalidARC, "无效的现实类型").
-			WithDetails("type", realityType)
-	}
-
-	agent.Reality = &domain.Reality{
-		Type: realityType,
-		Trigger: &domain.RealityTrigger{
-			Name:        "现实触发",
-			Cost:        0,
-			Effect:      "触发效果",
-			Consequence: "忽视后果",
-		},
-		OverloadRelief: &domain.OverloadRelief{
-			Name:      "过载解除",
-			Condition: "满足条件",
-			Effect:    "无视所有过载",
-		},
-		DegradationTrack: &domain.DegradationTrack{
-			Name:   "退化轨道",
-			Filled: 0,
-			Total:  4,
-		},
+
+	if err := agent.ChangeReality(reality); err != nil {
+		return err
 	}
 	agent.UpdatedAt = time.Now()
 
@@ -232,30 +219,21 @@ func (s *agentService) SetReality(agentID string, realityType string) error {
 
 // SetCareer 设置职能类型
 func (s *agentService) SetCareer(agentID string, careerType string) error {
-	agent, err := s.GetAgent(agentID)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	agent, err := s.lookup(agentID)
 	if err != nil {
 		return err
 	}
 
-	// 验证职能类型
-	validType := false
-	for _, t := range domain.AllCareerTypes {
-		if t == careerType {
-			validType = true
-			break
-		}
-	}
-	if !validType {
-		return domain.NewGameError(domain.ErrInvalidARC, "无效的职能类型").
-			WithDetails("type", careerType)
+	career, err := s.catalog.NewCareer(careerType)
+	if err != nil {
+		return err
 	}
 
-	qa := getDefaultQA(careerType)
-	agent.Career = &domain.Career{
-		Type: careerType,
-		QA:   qa,
-	}
-	agent.QA = qa
+	agent.Career = career
+	agent.QA = copyQA(career.QA)
 	agent.UpdatedAt = time.Now()
 
 	return nil
@@ -263,7 +241,10 @@ func (s *agentService) SetCareer(agentID string, careerType string) error {
 
 // SpendQA 花费资质保证
 func (s *agentService) SpendQA(agentID, quality string, amount int) error {
-	agent, err := s.GetAgent(agentID)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	agent, err := s.lookup(agentID)
 	if err != nil {
 		return err
 	}
@@ -278,7 +259,10 @@ func (s *agentService) SpendQA(agentID, quality string, amount int) error {
 
 // RestoreQA 恢复资质保证
 func (s *agentService) RestoreQA(agentID string) error {
-	agent, err := s.GetAgent(agentID)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	agent, err := s.lookup(agentID)
 	if err != nil {
 		return err
 	}
@@ -290,7 +274,10 @@ func (s *agentService) RestoreQA(agentID string) error {
 
 // AddRelationship 添加人际关系
 func (s *agentService) AddRelationship(agentID string, rel *domain.Relationship) error {
-	agent, err := s.GetAgent(agentID)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	agent, err := s.lookup(agentID)
 	if err != nil {
 		return err
 	}
@@ -307,7 +294,10 @@ func (s *agentService) AddRelationship(agentID string, rel *domain.Relationship)
 
 // UpdateRelationship 更新人际关系连结点数
 func (s *agentService) UpdateRelationship(agentID, relID string, connection int) error {
-	agent, err := s.GetAgent(agentID)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	agent, err := s.lookup(agentID)
 	if err != nil {
 		return err
 	}
@@ -332,31 +322,58 @@ func (s *agentService) UpdateRelationship(agentID, relID string, connection int)
 
 // AddCommendations 添加嘉奖
 func (s *agentService) AddCommendations(agentID string, amount int) error {
-	agent, err := s.GetAgent(agentID)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	agent, err := s.lookup(agentID)
 	if err != nil {
 		return err
 	}
 
 	agent.AddCommendations(amount)
+	assignLedgerIDs(agent)
 	agent.UpdatedAt = time.Now()
 	return nil
 }
 
 // AddReprimands 添加申诫
 func (s *agentService) AddReprimands(agentID string, amount int) error {
-	agent, err := s.GetAgent(agentID)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	agent, err := s.lookup(agentID)
 	if err != nil {
 		return err
 	}
 
 	agent.AddReprimands(amount)
+	assignLedgerIDs(agent)
+	agent.UpdatedAt = time.Now()
+	return nil
+}
+
+// RecordLedger 按原因记录嘉奖/申诫变化
+func (s *agentService) RecordLedger(agentID string, entry *domain.LedgerEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	agent, err := s.lookup(agentID)
+	if err != nil {
+		return err
+	}
+
+	agent.RecordLedger(entry)
+	assignLedgerIDs(agent)
 	agent.UpdatedAt = time.Now()
 	return nil
 }
 
 // UpdateRating 更新机构评级
 func (s *agentService) UpdateRating(agentID string) error {
-	agent, err := s.GetAgent(agentID)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	agent, err := s.lookup(agentID)
 	if err != nil {
 		return err
 	}
@@ -366,46 +383,121 @@ func (s *agentService) UpdateRating(agentID string) error {
 	return nil
 }
 
-func createDefaultAbilities(anomalyType string) []*domain.AnomalyAbility {
-	return []*domain.AnomalyAbility{
-		{
-			ID:          uuid.New().String(),
-			Name:        "能力1",
-			AnomalyType: anomalyType,
-			Trigger: &domain.AbilityTrigger{
-				Type:        domain.TriggerAction,
-				Description: "主动使用",
-			},
-			Roll: &domain.AbilityRoll{
-				Quality:   domain.QualityFocus,
-				DiceCount: 6,
-				DiceType:  4,
-			},
-		},
-		{
-			ID:          uuid.New().String(),
-			Name:        "能力2",
-			AnomalyType: anomalyType,
-		},
-		{
-			ID:          uuid.New().String(),
-			Name:        "能力3",
-			AnomalyType: anomalyType,
-		},
+// ModifyAgent 在角色副本上执行修改，成功后整体写回
+// 整个读-改-写持有服务锁，并发修改（如购买）不会基于过期的余额；fn中不能再调用角色服务
+func (s *agentService) ModifyAgent(agentID string, fn func(agent *domain.Agent) error) (*domain.Agent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, err := s.lookup(agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	working, err := cloneAgent(stored)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := fn(working); err != nil {
+		return nil, err
+	}
+	if err := working.ValidateInPlay(); err != nil {
+		return nil, err
+	}
+
+	assignLedgerIDs(working)
+	working.UpdatedAt = time.Now()
+
+	// 写回原指针，其他持有该角色的调用方能看到变化
+	*stored = *working
+	return stored, nil
+}
+
+// newAgentFromCatalog 根据ARC目录创建角色（尚未验证和保存）
+func newAgentFromCatalog(arc *catalog.Catalog, req *CreateAgentRequest) (*domain.Agent, error) {
+	anomaly, err := arc.NewAnomaly(req.AnomalyType)
+	if err != nil {
+		return nil, err
+	}
+
+	reality, err := arc.NewReality(req.RealityType)
+	if err != nil {
+		return nil, err
+	}
+
+	career, err := arc.NewCareer(req.CareerType)
+	if err != nil {
+		return nil, err
+	}
+
+	agent := &domain.Agent{
+		ID:            uuid.New().String(),
+		Name:          req.Name,
+		Pronouns:      req.Pronouns,
+		Anomaly:       anomaly,
+		Reality:       reality,
+		Career:        career,
+		QA:            copyQA(career.QA),
+		Relationships: req.Relationships,
+		Assessment:    req.Assessment,
+		Commendations: 0,
+		Reprimands:    0,
+		Rating:        domain.RatingExcellent,
+		Alive:         true,
+		InDebt:        false,
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}
+
+	// 职能初始可申领物放入物品栏
+	item, err := arc.NewInitialItem(req.CareerType)
+	if err != nil {
+		return nil, err
+	}
+	item.ID = uuid.New().String()
+	agent.AddItem(item)
+
+	// 如果没有提供人际关系，创建默认的
+	if len(agent.Relationships) == 0 {
+		agent.Relationships = []*domain.Relationship{
+			{ID: uuid.New().String(), Name: "关系1", Connection: 6},
+			{ID: uuid.New().String(), Name: "关系2", Connection: 3},
+			{ID: uuid.New().String(), Name: "关系3", Connection: 3},
+		}
+	}
+
+	return agent, nil
+}
+
+// copyQA 复制资质保证分配，角色当前QA与职能初始QA不能共享同一个map
+func copyQA(qa map[string]int) map[string]int {
+	copied := make(map[string]int, len(qa))
+	for quality, points := range qa {
+		copied[quality] = points
 	}
+	return copied
 }
 
-func getDefaultQA(careerType string) map[string]int {
-	// 简化实现：所有职能都是平均分配
-	return map[string]int{
-		domain.QualityFocus:      1,
-		domain.QualityEmpathy:    1,
-		domain.QualityPresence:   1,
-		domain.QualityDeception:  1,
-		domain.QualityInitiative: 1,
-		domain.QualityProfession: 1,
-		domain.QualityVitality:   1,
-		domain.QualityGrit:       1,
-		domain.QualitySubtlety:   1,
+// assignLedgerIDs 为尚未保存的账本条目分配ID
+func assignLedgerIDs(agent *domain.Agent) {
+	for _, entry := range agent.Ledger {
+		if entry.ID == "" {
+			entry.ID = uuid.New().String()
+		}
+	}
+}
+
+// cloneAgent 深拷贝角色
+func cloneAgent(agent *domain.Agent) (*domain.Agent, error) {
+	data, err := json.Marshal(agent)
+	if err != nil {
+		return nil, err
+	}
+
+	var copied domain.Agent
+	if err := json.Unmarshal(data, &copied); err != nil {
+		return nil, err
 	}
+	return &copied, nil
 }
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"sync"
 	"testing"
 
 	"github.com/trpg-solo-engine/backend/internal/domain"
@@ -91,6 +92,49 @@ func TestAgentService_CreateAgent(t *testing.T) {
 	}
 }
 
+func TestAgentService_CreateAgentUsesCatalog(t *testing.T) {
+	service := NewAgentService()
+
+	agent, err := service.CreateAgent(&CreateAgentRequest{
+		Name:        "测试特工",
+		AnomalyType: domain.AnomalyWhisper,
+		RealityType: domain.RealityCaretaker,
+		CareerType:  domain.CareerPublicRelations,
+	})
+	if err != nil {
+		t.Fatalf("创建角色失败: %v", err)
+	}
+
+	// 异常能力来自 anomalies.json
+	if agent.Anomaly.Abilities[0].ID != "whisper-say-again" || agent.Anomaly.Abilities[0].Roll.Quality != domain.QualityPresence {
+		t.Errorf("期望第一个能力为配置中的\"再说一遍？\", 得到 %+v", agent.Anomaly.Abilities[0])
+	}
+
+	// 现实来自 realities.json
+	if agent.Reality.OverloadRelief.Condition != "做某件能让受照料者开心的事" {
+		t.Errorf("期望过载解除条件来自配置, 得到 %s", agent.Reality.OverloadRelief.Condition)
+	}
+	if agent.Reality.DegradationTrack.Name != "独立" || agent.Reality.DegradationTrack.Total != 4 {
+		t.Errorf("期望退化轨道来自配置, 得到 %+v", agent.Reality.DegradationTrack)
+	}
+
+	// 职能资质保证来自 careers.json
+	if agent.QA[domain.QualityEmpathy] != 2 || agent.QA[domain.QualityVitality] != 0 {
+		t.Errorf("期望公关的QA分配来自配置, 得到 %v", agent.QA)
+	}
+	if agent.Career.PrimeDirective == nil || len(agent.Career.PermittedBehaviors) == 0 {
+		t.Error("期望职能包含许可行为和首要指令")
+	}
+
+	// 当前QA与职能初始QA互不影响
+	if err := service.SpendQA(agent.ID, domain.QualityEmpathy, 1); err != nil {
+		t.Fatalf("花费QA失败: %v", err)
+	}
+	if agent.Career.QA[domain.QualityEmpathy] != 2 {
+		t.Errorf("花费QA不应修改职能初始QA, 得到 %d", agent.Career.QA[domain.QualityEmpathy])
+	}
+}
+
 func TestAgentService_CreateAgentWithInvalidARC(t *testing.T) {
 	service := NewAgentService()
 
@@ -705,3 +749,59 @@ func TestAgentService_ListAgents(t *testing.T) {
 		t.Errorf("期望3个角色, 得到 %d", len(agents))
 	}
 }
+
+func TestAgentService_ModifyAgentConcurrent(t *testing.T) {
+	service := NewAgentService()
+
+	agent, err := service.CreateAgent(&CreateAgentRequest{
+		Name:        "测试特工",
+		AnomalyType: domain.AnomalyWhisper,
+		RealityType: domain.RealityCaretaker,
+		CareerType:  domain.CareerPublicRelations,
+	})
+	if err != nil {
+		t.Fatalf("创建角色失败: %v", err)
+	}
+	if _, err := service.ModifyAgent(agent.ID, func(a *domain.Agent) error {
+		a.Commendations = 5
+		return nil
+	}); err != nil {
+		t.Fatalf("设置嘉奖失败: %v", err)
+	}
+
+	// 并发花费嘉奖，余额不足时失败，不能透支
+	agentID := agent.ID
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	spent := 0
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := service.ModifyAgent(agentID, func(a *domain.Agent) error {
+				if a.Commendations < 1 {
+					return domain.NewGameError(domain.ErrInvalidAction, "嘉奖不足")
+				}
+				a.Commendations--
+				return nil
+			})
+			if err == nil {
+				mu.Lock()
+				spent++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	updated, err := service.GetAgent(agent.ID)
+	if err != nil {
+		t.Fatalf("获取角色失败: %v", err)
+	}
+	if spent != 5 {
+		t.Errorf("期望成功花费5次, 得到 %d", spent)
+	}
+	if updated.Commendations != 0 {
+		t.Errorf("期望嘉奖为0, 得到 %d", updated.Commendations)
+	}
+}
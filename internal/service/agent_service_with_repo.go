@@ -5,19 +5,27 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/trpg-solo-engine/backend/internal/catalog"
 	"github.com/trpg-solo-engine/backend/internal/domain"
 	"github.com/trpg-solo-engine/backend/internal/infrastructure/repository"
 )
 
 // agentServiceWithRepo 使用仓储的角色服务实现
 type agentServiceWithRepo struct {
-	repo repository.AgentRepository
+	repo    repository.AgentRepository
+	catalog *catalog.Catalog
 }
 
 // NewAgentServiceWithRepo 创建使用仓储的角色服务
-func NewAgentServiceWithRepo(repo repository.AgentRepository) AgentService {
+// arc为nil时使用内嵌的ARC配置
+func NewAgentServiceWithRepo(repo repository.AgentRepository, arc *catalog.Catalog) AgentService {
+	if arc == nil {
+		arc = catalog.Default()
+	}
+
 	return &agentServiceWithRepo{
-		repo: repo,
+		repo:    repo,
+		catalog: arc,
 	}
 }
 
@@ -25,55 +33,9 @@ func (s *agentServiceWithRepo) CreateAgent(req *CreateAgentRequest) (*domain.Age
 	ctx := context.Background()
 
 	// 创建角色
-	agent := &domain.Agent{
-		ID:       uuid.New().String(),
-		Name:     req.Name,
-		Pronouns: req.Pronouns,
-		Anomaly: &domain.Anomaly{
-			Type:      req.AnomalyType,
-			Abilities: createDefaultAbilities(req.AnomalyType),
-		},
-		Reality: &domain.Reality{
-			Type: req.RealityType,
-			Trigger: &domain.RealityTrigger{
-				Name:        "现实触发",
-				Cost:        0,
-				Effect:      "触发效果",
-				Consequence: "忽视后果",
-			},
-			OverloadRelief: &domain.OverloadRelief{
-				Name:      "过载解除",
-				Condition: "满足条件",
-				Effect:    "无视所有过载",
-			},
-			DegradationTrack: &domain.DegradationTrack{
-				Name:   "退化轨道",
-				Filled: 0,
-				Total:  4,
-			},
-		},
-		Career: &domain.Career{
-			Type: req.CareerType,
-			QA:   getDefaultQA(req.CareerType),
-		},
-		QA:            getDefaultQA(req.CareerType),
-		Relationships: req.Relationships,
-		Commendations: 0,
-		Reprimands:    0,
-		Rating:        domain.RatingExcellent,
-		Alive:         true,
-		InDebt:        false,
-		CreatedAt:     time.Now(),
-		UpdatedAt:     time.Now(),
-	}
-
-	// 如果没有提供人际关系，创建默认的
-	if len(agent.Relationships) == 0 {
-		agent.Relationships = []*domain.Relationship{
-			{ID: uuid.New().String(), Name: "关系1", Connection: 6},
-			{ID: uuid.New().String(), Name: "关系2", Connection: 3},
-			{ID: uuid.New().String(), Name: "关系3", Connection: 3},
-		}
+	agent, err := newAgentFromCatalog(s.catalog, req)
+	if err != nil {
+		return nil, err
 	}
 
 	// 验证ARC
@@ -97,15 +59,37 @@ func (s *agentServiceWithRepo) GetAgent(agentID string) (*domain.Agent, error) {
 func (s *agentServiceWithRepo) UpdateAgent(agent *domain.Agent) error {
 	ctx := context.Background()
 
-	// 验证ARC
-	if err := agent.ValidateARC(); err != nil {
+	// 验证ARC（连结在游戏中会变化，不检查总数）
+	if err := agent.ValidateInPlay(); err != nil {
 		return err
 	}
 
+	assignLedgerIDs(agent)
 	agent.UpdatedAt = time.Now()
 	return s.repo.Update(ctx, agent)
 }
 
+func (s *agentServiceWithRepo) ImportAgent(agent *domain.Agent) error {
+	ctx := context.Background()
+
+	_, err := s.repo.GetByID(ctx, agent.ID)
+	if err == nil {
+		return domain.NewGameError(domain.ErrAlreadyExists, "角色已存在").
+			WithDetails("agent_id", agent.ID)
+	}
+	if gameErr, ok := err.(*domain.GameError); !ok || gameErr.Code != domain.ErrNotFound {
+		return err
+	}
+
+	// 验证ARC（导入的角色可能已经过游戏，不检查总数）
+	if err := agent.ValidateInPlay(); err != nil {
+		return err
+	}
+
+	assignLedgerIDs(agent)
+	return s.repo.Create(ctx, agent)
+}
+
 func (s *agentServiceWithRepo) DeleteAgent(agentID string) error {
 	ctx := context.Background()
 	return s.repo.Delete(ctx, agentID)
@@ -122,23 +106,12 @@ func (s *agentServiceWithRepo) SetAnomaly(agentID string, anomalyType string) er
 		return err
 	}
 
-	// 验证异常体类型
-	validType := false
-	for _, t := range domain.AllAnomalyTypes {
-		if t == anomalyType {
-			validType = true
-			break
-		}
-	}
-	if !validType {
-		return domain.NewGameError(domain.ErrInvalidARC, "无效的异常体类型").
-			WithDetails("type", anomalyType)
+	anomaly, err := s.catalog.NewAnomaly(anomalyType)
+	if err != nil {
+		return err
 	}
 
-	agent.Anomaly = &domain.Anomaly{
-		Type:      anomalyType,
-		Abilities: createDefaultAbilities(anomalyType),
-	}
+	agent.Anomaly = anomaly
 	agent.UpdatedAt = time.Now()
 
 	return s.UpdateAgent(agent)
@@ -150,37 +123,13 @@ func (s *agentServiceWithRepo) SetReality(agentID string, realityType string) er
 		return err
 	}
 
-	// 验证现实类型
-	validType := false
-	for _, t := range domain.AllRealityTypes {
-		if t == realityType {
-			validType = true
-			break
-		}
-	}
-	if !validType {
-		return domain.NewGameError(domain.ErrInvalidARC, "无效的现实类型").
-			WithDetails("type", realityType)
+	reality, err := s.catalog.NewReality(realityType)
+	if err != nil {
+		return err
 	}
 
-	agent.Reality = &domain.Reality{
-		Type: realityType,
-		Trigger: &domain.RealityTrigger{
-			Name:        "现实触发",
-			Cost:        0,
-			Effect:      "触发效果",
-			Consequence: "忽视后果",
-		},
-		OverloadRelief: &domain.OverloadRelief{
-			Name:      "过载解除",
-			Condition: "满足条件",
-			Effect:    "无视所有过载",
-		},
-		DegradationTrack: &domain.DegradationTrack{
-			Name:   "退化轨道",
-			Filled: 0,
-			Total:  4,
-		},
+	if err := agent.ChangeReality(reality); err != nil {
+		return err
 	}
 	agent.UpdatedAt = time.Now()
 
@@ -193,25 +142,13 @@ func (s *agentServiceWithRepo) SetCareer(agentID string, careerType string) erro
 		return err
 	}
 
-	// 验证职能类型
-	validType := false
-	for _, t := range domain.AllCareerTypes {
-		if t == careerType {
-			validType = true
-			break
-		}
-	}
-	if !validType {
-		return domain.NewGameError(domain.ErrInvalidARC, "无效的职能类型").
-			WithDetails("type", careerType)
+	career, err := s.catalog.NewCareer(careerType)
+	if err != nil {
+		return err
 	}
 
-	qa := getDefaultQA(careerType)
-	agent.Career = &domain.Career{
-		Type: careerType,
-		QA:   qa,
-	}
-	agent.QA = qa
+	agent.Career = career
+	agent.QA = copyQA(career.QA)
 	agent.UpdatedAt = time.Now()
 
 	return s.UpdateAgent(agent)
@@ -304,6 +241,17 @@ func (s *agentServiceWithRepo) AddReprimands(agentID string, amount int) error {
 	return s.UpdateAgent(agent)
 }
 
+func (s *agentServiceWithRepo) RecordLedger(agentID string, entry *domain.LedgerEntry) error {
+	agent, err := s.GetAgent(agentID)
+	if err != nil {
+		return err
+	}
+
+	agent.RecordLedger(entry)
+	agent.UpdatedAt = time.Now()
+	return s.UpdateAgent(agent)
+}
+
 func (s *agentServiceWithRepo) UpdateRating(agentID string) error {
 	agent, err := s.GetAgent(agentID)
 	if err != nil {
@@ -314,3 +262,21 @@ func (s *agentServiceWithRepo) UpdateRating(agentID string) error {
 	agent.UpdatedAt = time.Now()
 	return s.UpdateAgent(agent)
 }
+
+func (s *agentServiceWithRepo) ModifyAgent(agentID string, fn func(agent *domain.Agent) error) (*domain.Agent, error) {
+	ctx := context.Background()
+
+	return s.repo.Modify(ctx, agentID, func(agent *domain.Agent) error {
+		if err := fn(agent); err != nil {
+			return err
+		}
+
+		// 验证ARC（连结在游戏中会变化，不检查总数）
+		if err := agent.ValidateInPlay(); err != nil {
+			return err
+		}
+
+		assignLedgerIDs(agent)
+		return nil
+	})
+}
@@ -49,6 +49,7 @@ type ActionResult struct {
 	Success    bool                   `json:"success"`
 	Threes     int                    `json:"threes"`
 	Chaos      int                    `json:"chaos"`
+	TripleAsc  bool                   `json:"triple_asc"`
 	Effects    []string               `json:"effects"`
 	CustomData map[string]interface{} `json:"custom_data"`
 }
@@ -257,6 +258,11 @@ func (s *aiService) NarrateResult(action *Action, result *ActionResult) (string,
 		narration.WriteString(" - 失败。\n\n")
 	}
 
+	// 三重升华
+	if result.TripleAsc {
+		narration.WriteString("三重升华！现实在这一瞬间完全听从你的安排。\n")
+	}
+
 	// 混沌生成
 	if result.Chaos > 0 {
 		narration.WriteString(fmt.Sprintf("产生了 %d 点混沌。\n", result.Chaos))
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/trpg-solo-engine/backend/internal/domain"
+)
+
+// BehaviorService 职能行为服务接口
+// 玩家在会话中申报许可行为获得嘉奖，主持人（人类或AI）标记首要指令违规给予申诫，
+// 两者都记入角色的绩效账本
+type BehaviorService interface {
+	// 申报许可行为
+	ClaimBehavior(sessionID string, req *ClaimBehaviorRequest) (*domain.LedgerEntry, error)
+
+	// 标记违反首要指令
+	FlagViolation(sessionID string, req *FlagViolationRequest) (*domain.LedgerEntry, error)
+}
+
+// ClaimBehaviorRequest 申报许可行为请求
+type ClaimBehaviorRequest struct {
+	Behavior      string `json:"behavior" binding:"required"`      // 职能许可行为的描述
+	Justification string `json:"justification" binding:"required"` // 角色如何做到了该行为
+}
+
+// FlagViolationRequest 标记首要指令违规请求
+type FlagViolationRequest struct {
+	Justification string `json:"justification" binding:"required"` // 违规经过
+	FlaggedBy     string `json:"flagged_by"`                       // gm 或 ai，默认 gm
+}
+
+// behaviorService 职能行为服务实现
+type behaviorService struct {
+	agentService       AgentService
+	gameService        GameService
+	performanceService PerformanceService
+}
+
+// NewBehaviorService 创建职能行为服务
+func NewBehaviorService(agentService AgentService, gameService GameService, performanceService PerformanceService) BehaviorService {
+	if performanceService == nil {
+		performanceService = NewPerformanceService()
+	}
+
+	return &behaviorService{
+		agentService:       agentService,
+		gameService:        gameService,
+		performanceService: performanceService,
+	}
+}
+
+// ClaimBehavior 申报许可行为
+// 同一场景中每种许可行为只能申报一次
+func (s *behaviorService) ClaimBehavior(sessionID string, req *ClaimBehaviorRequest) (*domain.LedgerEntry, error) {
+	if strings.TrimSpace(req.Justification) == "" {
+		return nil, domain.NewGameError(domain.ErrInvalidInput, "必须说明角色如何做到了该行为")
+	}
+
+	session, agent, err := s.sessionAgent(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	behavior := agent.Career.FindPermittedBehavior(req.Behavior)
+	if behavior == nil {
+		actions := make([]string, 0, len(agent.Career.PermittedBehaviors))
+		for _, b := range agent.Career.PermittedBehaviors {
+			actions = append(actions, b.Action)
+		}
+		return nil, domain.NewGameError(domain.ErrInvalidAction, "该行为不在职能许可范围内").
+			WithDetails("behavior", req.Behavior).
+			WithDetails("permitted_behaviors", actions)
+	}
+
+	sceneID := session.State.CurrentSceneID
+	if agent.HasClaimedBehavior(sessionID, sceneID, behavior.Action) {
+		return nil, domain.NewGameError(domain.ErrInvalidState, "本场景已申报过该许可行为").
+			WithDetails("behavior", behavior.Action).
+			WithDetails("scene_id", sceneID)
+	}
+
+	entry := &domain.LedgerEntry{
+		SessionID:     sessionID,
+		SceneID:       sceneID,
+		Reason:        domain.LedgerReasonPermittedBehavior,
+		Behavior:      behavior.Action,
+		Justification: req.Justification,
+		Commendations: behavior.Reward,
+	}
+
+	return s.record(agent, entry)
+}
+
+// FlagViolation 标记违反首要指令，按职能配置给予申诫
+func (s *behaviorService) FlagViolation(sessionID string, req *FlagViolationRequest) (*domain.LedgerEntry, error) {
+	if strings.TrimSpace(req.Justification) == "" {
+		return nil, domain.NewGameError(domain.ErrInvalidInput, "必须说明违规经过")
+	}
+
+	flaggedBy := req.FlaggedBy
+	if flaggedBy == "" {
+		flaggedBy = domain.FlaggedByGM
+	}
+	if flaggedBy != domain.FlaggedByGM && flaggedBy != domain.FlaggedByAI {
+		return nil, domain.NewGameError(domain.ErrInvalidInput, "违规只能由主持人标记").
+			WithDetails("flagged_by", req.FlaggedBy)
+	}
+
+	session, agent, err := s.sessionAgent(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	directive := agent.Career.PrimeDirective
+	if directive == nil {
+		return nil, domain.NewGameError(domain.ErrInvalidState, "角色职能没有首要指令").
+			WithDetails("career", agent.Career.Type)
+	}
+
+	entry := &domain.LedgerEntry{
+		SessionID:     sessionID,
+		SceneID:       session.State.CurrentSceneID,
+		Reason:        domain.LedgerReasonDirectiveViolation,
+		Behavior:      directive.Description,
+		Justification: req.Justification,
+		FlaggedBy:     flaggedBy,
+		Reprimands:    directive.Violation,
+	}
+
+	return s.record(agent, entry)
+}
+
+// sessionAgent 获取会话及其角色，角色必须具有职能
+func (s *behaviorService) sessionAgent(sessionID string) (*domain.GameSession, *domain.Agent, error) {
+	session, err := s.gameService.GetSession(sessionID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	agent, err := s.agentService.GetAgent(session.AgentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if agent.Career == nil {
+		return nil, nil, domain.NewGameError(domain.ErrInvalidState, "角色没有职能").
+			WithDetails("agent_id", agent.ID)
+	}
+
+	return session, agent, nil
+}
+
+// record 通过绩效服务记账并保存角色
+func (s *behaviorService) record(agent *domain.Agent, entry *domain.LedgerEntry) (*domain.LedgerEntry, error) {
+	if err := s.performanceService.Record(agent, entry); err != nil {
+		return nil, err
+	}
+
+	if err := s.agentService.UpdateAgent(agent); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
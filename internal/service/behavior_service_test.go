@@ -0,0 +1,103 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+)
+
+func setupBehaviorTest(t *testing.T) (AgentService, GameService, BehaviorService, *domain.Agent, *domain.GameSession) {
+	fx := newTestAgentSession(t)
+	behaviors := NewBehaviorService(fx.agentService, fx.gameService, NewPerformanceService())
+
+	return fx.agentService, fx.gameService, behaviors, fx.agent, fx.session
+}
+
+func TestBehaviorService_ClaimBehavior(t *testing.T) {
+	agentService, gameService, behaviors, agent, session := setupBehaviorTest(t)
+	require.NoError(t, gameService.UpdateState(session.ID, func(state *domain.GameState) error {
+		state.CurrentSceneID = "scene-1"
+		return nil
+	}))
+
+	behavior := agent.Career.PermittedBehaviors[1]
+	entry, err := behaviors.ClaimBehavior(session.ID, &ClaimBehaviorRequest{
+		Behavior:      behavior.Action,
+		Justification: "说服目击者删掉了视频",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, domain.LedgerReasonPermittedBehavior, entry.Reason)
+	assert.Equal(t, session.ID, entry.SessionID)
+	assert.Equal(t, "scene-1", entry.SceneID)
+	assert.Equal(t, behavior.Reward, entry.Commendations)
+
+	stored, err := agentService.GetAgent(agent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, behavior.Reward, stored.Commendations)
+	require.Len(t, stored.Ledger, 1)
+
+	// 同一场景不能重复申报
+	_, err = behaviors.ClaimBehavior(session.ID, &ClaimBehaviorRequest{
+		Behavior:      behavior.Action,
+		Justification: "再来一次",
+	})
+	require.Error(t, err)
+	assert.Equal(t, domain.ErrInvalidState, err.(*domain.GameError).Code)
+
+	// 换场景后可以再次申报
+	require.NoError(t, gameService.UpdateState(session.ID, func(state *domain.GameState) error {
+		state.CurrentSceneID = "scene-2"
+		return nil
+	}))
+	_, err = behaviors.ClaimBehavior(session.ID, &ClaimBehaviorRequest{
+		Behavior:      behavior.Action,
+		Justification: "又处理了一个散逸端",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, behavior.Reward*2, stored.Commendations)
+}
+
+func TestBehaviorService_ClaimUnknownBehavior(t *testing.T) {
+	_, _, behaviors, _, session := setupBehaviorTest(t)
+
+	_, err := behaviors.ClaimBehavior(session.ID, &ClaimBehaviorRequest{
+		Behavior:      "拯救世界",
+		Justification: "顺手",
+	})
+	require.Error(t, err)
+	gameErr := err.(*domain.GameError)
+	assert.Equal(t, domain.ErrInvalidAction, gameErr.Code)
+	assert.NotEmpty(t, gameErr.Details["permitted_behaviors"])
+}
+
+func TestBehaviorService_FlagViolation(t *testing.T) {
+	agentService, _, behaviors, agent, session := setupBehaviorTest(t)
+	violation := agent.Career.PrimeDirective.Violation
+
+	entry, err := behaviors.FlagViolation(session.ID, &FlagViolationRequest{
+		Justification: "在直播中提到了机构",
+		FlaggedBy:     domain.FlaggedByAI,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, domain.LedgerReasonDirectiveViolation, entry.Reason)
+	assert.Equal(t, domain.FlaggedByAI, entry.FlaggedBy)
+	assert.Equal(t, violation, entry.Reprimands)
+	assert.Equal(t, agent.Career.PrimeDirective.Description, entry.Behavior)
+
+	stored, err := agentService.GetAgent(agent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, violation, stored.Reprimands)
+
+	require.Len(t, stored.Ledger, 1)
+	assert.Equal(t, entry.ID, stored.Ledger[0].ID)
+	assert.True(t, stored.ReconcileLedger().Balanced)
+
+	_, err = behaviors.FlagViolation(session.ID, &FlagViolationRequest{
+		Justification: "自己举报自己",
+		FlaggedBy:     "player",
+	})
+	require.Error(t, err)
+	assert.Equal(t, domain.ErrInvalidInput, err.(*domain.GameError).Code)
+}
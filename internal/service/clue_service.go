@@ -403,10 +403,12 @@ func (s *clueService) GetClueProgress(sessionID string) (*ClueProgress, error) {
 	}
 	totalClues := len(allClues)
 
-	// 统计已收集线索数
+	// 统计已收集线索数，不计入请求机构确立的既定事实
 	collectedCount := 0
-	if session.State.CollectedClues != nil {
-		collectedCount = len(session.State.CollectedClues)
+	for _, clueID := range session.State.CollectedClues {
+		if allClues[clueID] {
+			collectedCount++
+		}
 	}
 
 	// 计算百分比
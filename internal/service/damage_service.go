@@ -1,10 +1,13 @@
 package service
 
 import (
+	"fmt"
+
 	"github.com/trpg-solo-engine/backend/internal/domain"
 )
 
 // DamageService 伤害服务接口
+// 伤害致死只记录死亡，复活按房规花费嘉奖，统一经由 DeathService.Revive
 type DamageService interface {
 	// ApplyDamage 应用伤害
 	// 返回是否死亡、是否使用了人寿保险、产生的散逸端数量
@@ -13,11 +16,8 @@ type DamageService interface {
 	// UseLifeInsurance 使用人寿保险无视伤害
 	UseLifeInsurance(agent *domain.Agent, damage int) error
 
-	// HandleDeath 处理死亡
-	HandleDeath(agent *domain.Agent) error
-
-	// Revive 复活角色
-	Revive(agent *domain.Agent) error
+	// HandleDeath 记录死亡，角色保持死亡直到复活或由继任者接替
+	HandleDeath(agent *domain.Agent, cause, sessionID string, looseEnds int) error
 
 	// GenerateLooseEnds 生成散逸端
 	GenerateLooseEnds(damage int, hasWitnesses bool) int
@@ -54,15 +54,13 @@ func (s *damageService) ApplyDamage(agent *domain.Agent, damage int, hasWitnesse
 		return false, true, 0, nil
 	}
 
-	// 无法或不愿使用人寿保险，角色死亡
-	err = s.HandleDeath(agent)
+	// 无法或不愿使用人寿保险，角色死亡，散逸端记入死亡记录
+	looseEnds = s.GenerateLooseEnds(damage, hasWitnesses)
+	err = s.HandleDeath(agent, fmt.Sprintf("受到%d点伤害且无法支付人寿保险", damage), "", looseEnds)
 	if err != nil {
 		return true, false, 0, err
 	}
 
-	// 生成散逸端
-	looseEnds = s.GenerateLooseEnds(damage, hasWitnesses)
-
 	return true, false, looseEnds, nil
 }
 
@@ -112,28 +110,11 @@ func (s *damageService) UseLifeInsurance(agent *domain.Agent, damage int) error
 }
 
 // HandleDeath 处理死亡
-func (s *damageService) HandleDeath(agent *domain.Agent) error {
-	// 标记为死亡
-	agent.Alive = false
-
-	// 扣除5次嘉奖
-	agent.Commendations -= 5
-
-	// 检查是否进入嘉奖负债
-	if agent.Commendations < 0 {
-		agent.InDebt = true
-	}
-
-	// 立即复活
-	return s.Revive(agent)
-}
-
-// Revive 复活角色
-func (s *damageService) Revive(agent *domain.Agent) error {
-	// 在分部休息室复活
-	agent.Alive = true
-	// 保留死亡前的记忆和状态
-	return nil
+// 与 DeathService.RecordDeath 使用同一条死亡记录；这里不扣嘉奖也不复活，
+// 复活时由 DeathService.Revive 按房规扣除嘉奖，嘉奖不足时不能复活，不会进入负债
+func (s *damageService) HandleDeath(agent *domain.Agent, cause, sessionID string, looseEnds int) error {
+	_, err := agent.Die(cause, sessionID, looseEnds)
+	return err
 }
 
 // GenerateLooseEnds 生成散逸端
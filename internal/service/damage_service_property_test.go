@@ -6,6 +6,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/trpg-solo-engine/backend/internal/domain"
 )
 
@@ -14,7 +15,7 @@ import (
 //
 // 属性11: 人寿保险机制
 // 对于任何伤害，玩家可以花费等量QA（任意资质）来无视伤害。
-// 如果无法或不愿花费，玩家死亡并扣除5次嘉奖，然后在休息室复活。
+// 如果无法或不愿花费，玩家死亡，之后按房规花费嘉奖复活，嘉奖不足时不能复活。
 func TestProperty_LifeInsuranceMechanism(t *testing.T) {
 	damageService := NewDamageService()
 
@@ -86,8 +87,8 @@ func TestProperty_LifeInsuranceMechanism(t *testing.T) {
 		}
 	})
 
-	// 测试3: 死亡时扣除5次嘉奖
-	t.Run("DeathDeducts5Commendations", func(t *testing.T) {
+	// 测试3: 死亡只记录死亡，不扣嘉奖也不立即复活
+	t.Run("DeathWaitsForRevival", func(t *testing.T) {
 		f := func(initialCommendations uint8) bool {
 			agent := createTestAgentForDamage()
 			agent.Commendations = int(initialCommendations % 20) // 0-19
@@ -95,18 +96,18 @@ func TestProperty_LifeInsuranceMechanism(t *testing.T) {
 			initialComm := agent.Commendations
 
 			// 处理死亡
-			err := damageService.HandleDeath(agent)
+			err := damageService.HandleDeath(agent, "测试死亡", "", 0)
 			if err != nil {
 				return false
 			}
 
-			// 验证扣除了5次嘉奖
-			if agent.Commendations != initialComm-5 {
+			// 嘉奖在复活时才扣除
+			if agent.Commendations != initialComm || len(agent.Ledger) != 0 {
 				return false
 			}
 
-			// 验证角色复活
-			if !agent.Alive {
+			// 角色保持死亡
+			if agent.Alive || agent.LastDeath() == nil || agent.LastDeath().RevivedAt != nil {
 				return false
 			}
 
@@ -118,24 +119,26 @@ func TestProperty_LifeInsuranceMechanism(t *testing.T) {
 		}
 	})
 
-	// 测试4: 嘉奖为负时进入负债状态
-	t.Run("NegativeCommendationsEntersDebt", func(t *testing.T) {
+	// 测试4: 嘉奖不足时不能复活，死亡不会让嘉奖变负
+	t.Run("DeathNeverEntersDebt", func(t *testing.T) {
+		cost := domain.DefaultRuleset().DeathCommendationCost
 		f := func(initialCommendations uint8) bool {
 			agent := createTestAgentForDamage()
-			// 设置较少的嘉奖，确保死亡后会变负
-			agent.Commendations = int(initialCommendations % 5) // 0-4
+			// 设置少于复活花费的嘉奖
+			agent.Commendations = int(initialCommendations) % cost
 
 			// 处理死亡
-			err := damageService.HandleDeath(agent)
+			err := damageService.HandleDeath(agent, "测试死亡", "", 0)
 			if err != nil {
 				return false
 			}
 
-			// 如果嘉奖为负，应该进入负债状态
-			if agent.Commendations < 0 {
-				if !agent.InDebt {
-					return false
-				}
+			// 复活被拒绝，嘉奖保持不变
+			if _, err := agent.Revive(cost); err == nil {
+				return false
+			}
+			if agent.Alive || agent.Commendations < 0 || agent.InDebt {
+				return false
 			}
 
 			return true
@@ -146,8 +149,9 @@ func TestProperty_LifeInsuranceMechanism(t *testing.T) {
 		}
 	})
 
-	// 测试5: 复活后保留记忆（状态不变）
+	// 测试5: 复活后保留记忆（除复活花费外状态不变）
 	t.Run("RevivePreservesState", func(t *testing.T) {
+		cost := domain.DefaultRuleset().DeathCommendationCost
 		f := func() bool {
 			agent := createTestAgentForDamage()
 			agent.Commendations = 10
@@ -155,13 +159,14 @@ func TestProperty_LifeInsuranceMechanism(t *testing.T) {
 
 			initialComm := agent.Commendations
 			initialRep := agent.Reprimands
+			initialQA := agent.TotalQA()
 
-			// 标记为死亡
-			agent.Alive = false
+			if err := damageService.HandleDeath(agent, "测试死亡", "", 0); err != nil {
+				return false
+			}
 
 			// 复活
-			err := damageService.Revive(agent)
-			if err != nil {
+			if _, err := agent.Revive(cost); err != nil {
 				return false
 			}
 
@@ -170,11 +175,11 @@ func TestProperty_LifeInsuranceMechanism(t *testing.T) {
 				return false
 			}
 
-			// 验证其他状态保留（除了Alive标志）
-			if agent.Commendations != initialComm {
+			// 验证其他状态保留（嘉奖只扣除复活花费）
+			if agent.Commendations != initialComm-cost {
 				return false
 			}
-			if agent.Reprimands != initialRep {
+			if agent.Reprimands != initialRep || agent.TotalQA() != initialQA {
 				return false
 			}
 
@@ -256,13 +261,11 @@ func TestProperty_LifeInsuranceMechanism(t *testing.T) {
 				return false
 			}
 
-			// 应该扣除5次嘉奖
-			if agent.Commendations != initialComm-5 {
+			// 嘉奖不变，角色等待复活
+			if agent.Commendations != initialComm {
 				return false
 			}
-
-			// 应该复活
-			if !agent.Alive {
+			if agent.Alive {
 				return false
 			}
 
@@ -540,10 +543,45 @@ func TestDamageService_BasicFunctionality(t *testing.T) {
 		agent := createTestAgentForDamage()
 		agent.Commendations = 10
 
-		err := damageService.HandleDeath(agent)
+		err := damageService.HandleDeath(agent, "测试死亡", "session-1", 2)
 		assert.NoError(t, err)
-		assert.Equal(t, 5, agent.Commendations)
-		assert.True(t, agent.Alive) // 应该复活
+		assert.Equal(t, 10, agent.Commendations)
+		assert.False(t, agent.Alive) // 不会立即复活
+
+		// 死亡记入角色的死亡记录
+		death := agent.LastDeath()
+		require.NotNil(t, death)
+		assert.Equal(t, "测试死亡", death.Cause)
+		assert.Equal(t, "session-1", death.SessionID)
+		assert.Equal(t, 2, death.LooseEnds)
+		assert.Nil(t, death.RevivedAt)
+
+		// 已经死亡的角色不能再次死亡
+		assert.Error(t, damageService.HandleDeath(agent, "再次死亡", "", 0))
+	})
+
+	t.Run("ReviveThroughDeathService", func(t *testing.T) {
+		agentService := NewAgentService()
+		agent, err := agentService.CreateAgent(&CreateAgentRequest{
+			Name:        "伤害致死",
+			AnomalyType: domain.AnomalyWhisper,
+			RealityType: domain.RealityCaretaker,
+			CareerType:  domain.CareerPublicRelations,
+		})
+		require.NoError(t, err)
+		require.NoError(t, agentService.AddCommendations(agent.ID, 5))
+
+		_, err = agentService.ModifyAgent(agent.ID, func(agent *domain.Agent) error {
+			return damageService.HandleDeath(agent, "测试死亡", "", 0)
+		})
+		require.NoError(t, err)
+
+		result, err := NewDeathService(agentService, nil, nil).Revive(agent.ID)
+		require.NoError(t, err)
+		assert.True(t, result.Agent.Alive)
+		assert.Equal(t, 0, result.Agent.Commendations)
+		assert.Equal(t, 5, result.Death.RevivalCost)
+		assert.NotNil(t, result.Death.RevivedAt)
 	})
 
 	t.Run("GenerateLooseEnds", func(t *testing.T) {
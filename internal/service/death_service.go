@@ -0,0 +1,311 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+)
+
+// DeathService 角色死亡服务接口
+// 死亡的角色不能开始新任务，必须花费嘉奖复活，或者由继承部分人际关系和嘉奖的继任者接替
+type DeathService interface {
+	// 记录死亡的原因、会话和散逸端
+	RecordDeath(agentID string, req *RecordDeathRequest) (*domain.DeathRecord, error)
+
+	// 按房规花费嘉奖复活
+	Revive(agentID string) (*ReviveResult, error)
+
+	// 创建接替死亡角色的继任者
+	CreateSuccessor(agentID string, req *CreateSuccessorRequest) (*SuccessorResult, error)
+
+	// 纪念名单：所有死亡的角色，最近死亡的在前
+	ListMemorial() ([]*MemorialEntry, error)
+}
+
+// RecordDeathRequest 记录死亡请求
+type RecordDeathRequest struct {
+	Cause     string `json:"cause" binding:"required"`
+	SessionID string `json:"session_id"` // 死亡发生的会话，散逸端计入该会话
+	LooseEnds int    `json:"loose_ends"`
+}
+
+// ReviveResult 复活结果
+type ReviveResult struct {
+	Agent *domain.Agent       `json:"agent"`
+	Death *domain.DeathRecord `json:"death"`
+	Entry *domain.LedgerEntry `json:"ledger_entry"`
+}
+
+// CreateSuccessorRequest 创建继任者请求
+// 继任者按新角色创建，再继承前任选定的人际关系和一部分嘉奖
+type CreateSuccessorRequest struct {
+	CreateAgentRequest
+	InheritRelationships []string `json:"inherit_relationships"` // 继承的前任人际关系ID
+}
+
+// SuccessorResult 创建继任者结果
+type SuccessorResult struct {
+	Successor              *domain.Agent `json:"successor"`
+	PredecessorID          string        `json:"predecessor_id"`
+	InheritedRelationships []string      `json:"inherited_relationships"` // 继任者中对应的人际关系ID
+	InheritedCommendations int           `json:"inherited_commendations"`
+}
+
+// MemorialEntry 纪念名单条目
+type MemorialEntry struct {
+	AgentID       string              `json:"agent_id"`
+	Name          string              `json:"name"`
+	AnomalyType   string              `json:"anomaly_type"`
+	RealityType   string              `json:"reality_type"`
+	CareerType    string              `json:"career_type"`
+	Commendations int                 `json:"commendations"`
+	Reprimands    int                 `json:"reprimands"`
+	Rating        string              `json:"rating"`
+	Death         *domain.DeathRecord `json:"death"`
+	TotalDeaths   int                 `json:"total_deaths"`
+}
+
+// deathService 角色死亡服务实现
+type deathService struct {
+	agentService AgentService
+	gameService  GameService
+	rules        *domain.Ruleset
+}
+
+// NewDeathService 创建角色死亡服务
+func NewDeathService(agentService AgentService, gameService GameService, rules *domain.Ruleset) DeathService {
+	if rules == nil {
+		rules = domain.DefaultRuleset()
+	}
+
+	return &deathService{
+		agentService: agentService,
+		gameService:  gameService,
+		rules:        rules,
+	}
+}
+
+// RecordDeath 记录死亡
+// 指定会话时会话必须属于该角色，散逸端计入会话状态
+func (s *deathService) RecordDeath(agentID string, req *RecordDeathRequest) (*domain.DeathRecord, error) {
+	cause := strings.TrimSpace(req.Cause)
+	if cause == "" {
+		return nil, domain.NewGameError(domain.ErrInvalidInput, "必须说明死亡原因")
+	}
+
+	if req.SessionID != "" {
+		session, err := s.gameService.GetSession(req.SessionID)
+		if err != nil {
+			return nil, err
+		}
+		if session.AgentID != agentID {
+			return nil, domain.NewGameError(domain.ErrInvalidInput, "会话不属于该角色").
+				WithDetails("session_id", req.SessionID).
+				WithDetails("agent_id", agentID)
+		}
+	}
+
+	var record *domain.DeathRecord
+	_, err := s.agentService.ModifyAgent(agentID, func(agent *domain.Agent) error {
+		var err error
+		record, err = agent.Die(cause, req.SessionID, req.LooseEnds)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if req.SessionID != "" && req.LooseEnds > 0 {
+		err := s.gameService.UpdateState(req.SessionID, func(state *domain.GameState) error {
+			state.LooseEnds += req.LooseEnds
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return record, nil
+}
+
+// Revive 按房规花费嘉奖复活
+func (s *deathService) Revive(agentID string) (*ReviveResult, error) {
+	result := &ReviveResult{}
+	agent, err := s.agentService.ModifyAgent(agentID, func(agent *domain.Agent) error {
+		var err error
+		result.Entry, err = agent.Revive(s.rules.DeathCommendationCost)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.Agent = agent
+	result.Death = agent.LastDeath()
+	return result, nil
+}
+
+// CreateSuccessor 创建继任者
+// 只有死亡且尚无继任者的角色可以被接替，接替后前任不能再复活
+func (s *deathService) CreateSuccessor(agentID string, req *CreateSuccessorRequest) (*SuccessorResult, error) {
+	predecessor, err := s.agentService.GetAgent(agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	death := predecessor.LastDeath()
+	if predecessor.Alive || death == nil {
+		return nil, domain.NewGameError(domain.ErrInvalidState, "只能为死亡的角色创建继任者").
+			WithDetails("agent_id", agentID)
+	}
+	if death.SuccessorID != "" {
+		return nil, domain.NewGameError(domain.ErrAlreadyExists, "角色已有继任者").
+			WithDetails("successor_id", death.SuccessorID)
+	}
+
+	inherited, err := s.selectRelationships(predecessor, req.InheritRelationships)
+	if err != nil {
+		return nil, err
+	}
+
+	share := 0
+	if predecessor.Commendations > 0 {
+		share = predecessor.Commendations * s.rules.SuccessorCommendationShare / 100
+	}
+
+	successor, err := s.agentService.CreateAgent(&req.CreateAgentRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SuccessorResult{
+		PredecessorID:          predecessor.ID,
+		InheritedRelationships: []string{},
+		InheritedCommendations: share,
+	}
+	successor, err = s.agentService.ModifyAgent(successor.ID, func(agent *domain.Agent) error {
+		for _, rel := range inherited {
+			rel.ID = uuid.New().String()
+			rel.AddNote(fmt.Sprintf("继承自前任特工%s", predecessor.Name))
+			agent.Relationships = append(agent.Relationships, rel)
+			result.InheritedRelationships = append(result.InheritedRelationships, rel.ID)
+		}
+
+		if share > 0 {
+			agent.RecordLedger(&domain.LedgerEntry{
+				Reason:        domain.LedgerReasonInheritance,
+				Justification: fmt.Sprintf("继承前任特工%s的嘉奖", predecessor.Name),
+				Commendations: share,
+			})
+		}
+		return nil
+	})
+	if err == nil {
+		_, err = s.agentService.ModifyAgent(predecessor.ID, func(agent *domain.Agent) error {
+			last := agent.LastDeath()
+			if agent.Alive || last == nil || last.SuccessorID != "" {
+				return domain.NewGameError(domain.ErrInvalidState, "前任角色的状态已变化").
+					WithDetails("agent_id", agent.ID)
+			}
+			last.SuccessorID = successor.ID
+
+			// 继任者继承的嘉奖从前任的账本中转出，两边账本合计一致
+			if share > 0 {
+				agent.RecordLedger(&domain.LedgerEntry{
+					Reason:        domain.LedgerReasonInheritance,
+					Justification: fmt.Sprintf("嘉奖由继任者特工%s继承", successor.Name),
+					Commendations: -share,
+				})
+			}
+			return nil
+		})
+	}
+	if err != nil {
+		// 继承失败时删除刚创建的继任者，避免留下半成品
+		_ = s.agentService.DeleteAgent(successor.ID)
+		return nil, err
+	}
+
+	result.Successor = successor
+	return result, nil
+}
+
+// ListMemorial 列出所有死亡的角色
+func (s *deathService) ListMemorial() ([]*MemorialEntry, error) {
+	agents, err := s.agentService.ListAgents()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []*MemorialEntry{}
+	for _, agent := range agents {
+		death := agent.LastDeath()
+		if agent.Alive || death == nil {
+			continue
+		}
+
+		entry := &MemorialEntry{
+			AgentID:       agent.ID,
+			Name:          agent.Name,
+			Commendations: agent.Commendations,
+			Reprimands:    agent.Reprimands,
+			Rating:        agent.Rating,
+			Death:         death,
+			TotalDeaths:   len(agent.Deaths),
+		}
+		if agent.Anomaly != nil {
+			entry.AnomalyType = agent.Anomaly.Type
+		}
+		if agent.Reality != nil {
+			entry.RealityType = agent.Reality.Type
+		}
+		if agent.Career != nil {
+			entry.CareerType = agent.Career.Type
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Death.DiedAt.After(entries[j].Death.DiedAt)
+	})
+
+	return entries, nil
+}
+
+// selectRelationships 按房规检查并复制要继承的人际关系
+func (s *deathService) selectRelationships(predecessor *domain.Agent, ids []string) ([]*domain.Relationship, error) {
+	if len(ids) > s.rules.SuccessorRelationships {
+		return nil, domain.NewGameError(domain.ErrInvalidInput, "继承的人际关系超过房规上限").
+			WithDetails("requested", len(ids)).
+			WithDetails("limit", s.rules.SuccessorRelationships)
+	}
+
+	seen := make(map[string]bool, len(ids))
+	inherited := make([]*domain.Relationship, 0, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			return nil, domain.NewGameError(domain.ErrInvalidInput, "重复的人际关系").
+				WithDetails("relationship_id", id)
+		}
+		seen[id] = true
+
+		rel, err := findRelationship(predecessor, id)
+		if err != nil {
+			return nil, err
+		}
+		if rel.IsLost() {
+			return nil, domain.NewGameError(domain.ErrInvalidInput, "已失去的人际关系不能继承").
+				WithDetails("relationship_id", id)
+		}
+
+		copied := *rel
+		copied.Notes = append([]string(nil), rel.Notes...)
+		copied.History = append([]*domain.ConnectionChange(nil), rel.History...)
+		inherited = append(inherited, &copied)
+	}
+
+	return inherited, nil
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+)
+
+func setupDeathTest(t *testing.T) (AgentService, GameService, DeathService, *domain.Agent) {
+	fx := newTestAgentSession(t, withoutSession())
+	deaths := NewDeathService(fx.agentService, fx.gameService, nil)
+	require.NoError(t, fx.agentService.AddCommendations(fx.agent.ID, 8))
+
+	return fx.agentService, fx.gameService, deaths, fx.agent
+}
+
+func TestDeathService_RecordDeath(t *testing.T) {
+	agentService, gameService, deaths, agent := setupDeathTest(t)
+
+	session, err := gameService.CreateSession(agent.ID, "test-scenario")
+	require.NoError(t, err)
+
+	record, err := deaths.RecordDeath(agent.ID, &RecordDeathRequest{
+		Cause:     "在商场被异常体击中",
+		SessionID: session.ID,
+		LooseEnds: 3,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, session.ID, record.SessionID)
+	assert.Equal(t, 3, record.LooseEnds)
+
+	stored, err := agentService.GetAgent(agent.ID)
+	require.NoError(t, err)
+	assert.False(t, stored.Alive)
+
+	state, err := gameService.GetState(session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 3, state.LooseEnds, "散逸端计入死亡发生的会话")
+
+	// 死亡的角色不能开始新任务
+	_, err = gameService.CreateSession(agent.ID, "test-scenario")
+	require.Error(t, err)
+	assert.Equal(t, domain.ErrInvalidState, err.(*domain.GameError).Code)
+
+	t.Run("缺少原因或会话不属于角色时拒绝", func(t *testing.T) {
+		_, err := deaths.RecordDeath(agent.ID, &RecordDeathRequest{Cause: " "})
+		require.Error(t, err)
+		assert.Equal(t, domain.ErrInvalidInput, err.(*domain.GameError).Code)
+
+		other, err := agentService.CreateAgent(&CreateAgentRequest{
+			Name:        "旁观者",
+			AnomalyType: domain.AnomalyWhisper,
+			RealityType: domain.RealityCaretaker,
+			CareerType:  domain.CareerPublicRelations,
+		})
+		require.NoError(t, err)
+		_, err = deaths.RecordDeath(other.ID, &RecordDeathRequest{Cause: "意外", SessionID: session.ID})
+		require.Error(t, err)
+		assert.Equal(t, domain.ErrInvalidInput, err.(*domain.GameError).Code)
+	})
+}
+
+func TestDeathService_Revive(t *testing.T) {
+	_, gameService, deaths, agent := setupDeathTest(t)
+
+	_, err := deaths.Revive(agent.ID)
+	require.Error(t, err)
+	assert.Equal(t, domain.ErrInvalidState, err.(*domain.GameError).Code, "活着的角色不需要复活")
+
+	_, err = deaths.RecordDeath(agent.ID, &RecordDeathRequest{Cause: "坠楼"})
+	require.NoError(t, err)
+
+	result, err := deaths.Revive(agent.ID)
+	require.NoError(t, err)
+	assert.True(t, result.Agent.Alive)
+	assert.Equal(t, 3, result.Agent.Commendations, "默认房规复活花费5次嘉奖")
+	assert.Equal(t, domain.LedgerReasonDeath, result.Entry.Reason)
+	require.NotNil(t, result.Death.RevivedAt)
+
+	_, err = gameService.CreateSession(agent.ID, "test-scenario")
+	assert.NoError(t, err, "复活后可以开始任务")
+
+	// 再次死亡时嘉奖不足
+	_, err = deaths.RecordDeath(agent.ID, &RecordDeathRequest{Cause: "再次坠楼"})
+	require.NoError(t, err)
+	_, err = deaths.Revive(agent.ID)
+	require.Error(t, err)
+	assert.Equal(t, domain.ErrInsufficientCommendations, err.(*domain.GameError).Code)
+}
+
+func TestDeathService_CreateSuccessor(t *testing.T) {
+	agentService, _, deaths, agent := setupDeathTest(t)
+	inheritedID := agent.Relationships[0].ID
+
+	req := &CreateSuccessorRequest{
+		CreateAgentRequest: CreateAgentRequest{
+			Name:        "继任者",
+			AnomalyType: domain.AnomalyWhisper,
+			RealityType: domain.RealityCaretaker,
+			CareerType:  domain.CareerPublicRelations,
+		},
+		InheritRelationships: []string{inheritedID},
+	}
+
+	_, err := deaths.CreateSuccessor(agent.ID, req)
+	require.Error(t, err)
+	assert.Equal(t, domain.ErrInvalidState, err.(*domain.GameError).Code, "活着的角色不能被接替")
+
+	_, err = deaths.RecordDeath(agent.ID, &RecordDeathRequest{Cause: "被吞噬"})
+	require.NoError(t, err)
+
+	t.Run("超过房规上限时拒绝", func(t *testing.T) {
+		tooMany := *req
+		tooMany.InheritRelationships = []string{agent.Relationships[0].ID, agent.Relationships[1].ID}
+		_, err := deaths.CreateSuccessor(agent.ID, &tooMany)
+		require.Error(t, err)
+		assert.Equal(t, domain.ErrInvalidInput, err.(*domain.GameError).Code)
+	})
+
+	result, err := deaths.CreateSuccessor(agent.ID, req)
+	require.NoError(t, err)
+	assert.Equal(t, agent.ID, result.PredecessorID)
+	assert.Equal(t, 4, result.InheritedCommendations, "默认房规继承一半嘉奖")
+	assert.Equal(t, 4, result.Successor.Commendations)
+	require.Len(t, result.InheritedRelationships, 1)
+	assert.Len(t, result.Successor.Relationships, 4)
+
+	rel := result.Successor.FindRelationship(result.InheritedRelationships[0])
+	require.NotNil(t, rel)
+	assert.NotEqual(t, inheritedID, rel.ID)
+	assert.Equal(t, agent.Relationships[0].Connection, rel.Connection)
+
+	predecessor, err := agentService.GetAgent(agent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, result.Successor.ID, predecessor.LastDeath().SuccessorID)
+
+	// 继承的嘉奖从前任账本转出
+	assert.Equal(t, 4, predecessor.Commendations)
+	last := predecessor.Ledger[len(predecessor.Ledger)-1]
+	assert.Equal(t, domain.LedgerReasonInheritance, last.Reason)
+	assert.Equal(t, -4, last.Commendations)
+	assert.True(t, predecessor.ReconcileLedger().Balanced)
+
+	// 已接替的角色不能复活，也不能再创建继任者
+	_, err = deaths.Revive(agent.ID)
+	require.Error(t, err)
+	assert.Equal(t, domain.ErrInvalidState, err.(*domain.GameError).Code)
+	_, err = deaths.CreateSuccessor(agent.ID, req)
+	require.Error(t, err)
+	assert.Equal(t, domain.ErrAlreadyExists, err.(*domain.GameError).Code)
+}
+
+func TestDeathService_ListMemorial(t *testing.T) {
+	agentService, _, deaths, agent := setupDeathTest(t)
+
+	memorial, err := deaths.ListMemorial()
+	require.NoError(t, err)
+	assert.Empty(t, memorial)
+
+	other, err := agentService.CreateAgent(&CreateAgentRequest{
+		Name:        "另一位特工",
+		AnomalyType: domain.AnomalyWhisper,
+		RealityType: domain.RealityCaretaker,
+		CareerType:  domain.CareerPublicRelations,
+	})
+	require.NoError(t, err)
+
+	_, err = deaths.RecordDeath(agent.ID, &RecordDeathRequest{Cause: "第一位"})
+	require.NoError(t, err)
+	_, err = deaths.RecordDeath(other.ID, &RecordDeathRequest{Cause: "第二位"})
+	require.NoError(t, err)
+
+	memorial, err = deaths.ListMemorial()
+	require.NoError(t, err)
+	require.Len(t, memorial, 2)
+	assert.Equal(t, other.ID, memorial[0].AgentID, "最近死亡的在前")
+	assert.Equal(t, "第二位", memorial[0].Death.Cause)
+	assert.Equal(t, 1, memorial[1].TotalDeaths)
+
+	// 复活后从纪念名单中移除
+	_, err = deaths.Revive(agent.ID)
+	require.NoError(t, err)
+	memorial, err = deaths.ListMemorial()
+	require.NoError(t, err)
+	assert.Len(t, memorial, 1)
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"github.com/trpg-solo-engine/backend/internal/catalog"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+)
+
+// DegradationService 退化轨道服务接口
+type DegradationService interface {
+	// 标记退化格，轨道填满后角色必须选择新现实
+	MarkDegradation(agentID string, req *MarkDegradationRequest) (*DegradationStatus, error)
+
+	// 查询退化轨道和历史
+	GetDegradation(agentID string) (*DegradationStatus, error)
+}
+
+// MarkDegradationRequest 标记退化请求
+type MarkDegradationRequest struct {
+	Boxes     int    `json:"boxes"` // 默认为1
+	Reason    string `json:"reason" binding:"required"`
+	SessionID string `json:"session_id"`
+}
+
+// DegradationStatus 退化轨道状态
+type DegradationStatus struct {
+	AgentID              string                     `json:"agent_id"`
+	RealityType          string                     `json:"reality_type"`
+	Track                *domain.DegradationTrack   `json:"track"`
+	Trigger              string                     `json:"trigger"`     // 何时标记退化
+	Consequence          string                     `json:"consequence"` // 填满后的后果
+	PendingRealityChange bool                       `json:"pending_reality_change"`
+	Entry                *domain.DegradationEntry   `json:"entry,omitempty"` // 本次标记的条目
+	History              []*domain.DegradationEntry `json:"history"`
+}
+
+type degradationService struct {
+	agentService AgentService
+	catalog      *catalog.Catalog
+}
+
+// NewDegradationService 创建退化轨道服务
+func NewDegradationService(agentService AgentService, arc *catalog.Catalog) DegradationService {
+	if arc == nil {
+		arc = catalog.Default()
+	}
+
+	return &degradationService{
+		agentService: agentService,
+		catalog:      arc,
+	}
+}
+
+// MarkDegradation 标记退化格
+func (s *degradationService) MarkDegradation(agentID string, req *MarkDegradationRequest) (*DegradationStatus, error) {
+	agent, err := s.agentService.GetAgent(agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	boxes := req.Boxes
+	if boxes == 0 {
+		boxes = 1
+	}
+
+	entry, err := agent.MarkDegradation(boxes, req.Reason, req.SessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.agentService.UpdateAgent(agent); err != nil {
+		return nil, err
+	}
+
+	status := s.status(agent)
+	status.Entry = entry
+	return status, nil
+}
+
+// GetDegradation 查询退化轨道和历史
+func (s *degradationService) GetDegradation(agentID string) (*DegradationStatus, error) {
+	agent, err := s.agentService.GetAgent(agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.status(agent), nil
+}
+
+// status 组装退化状态，触发条件和后果取自现实配置
+func (s *degradationService) status(agent *domain.Agent) *DegradationStatus {
+	status := &DegradationStatus{
+		AgentID:              agent.ID,
+		PendingRealityChange: agent.PendingRealityChange,
+		History:              agent.DegradationHistory,
+	}
+	if status.History == nil {
+		status.History = []*domain.DegradationEntry{}
+	}
+
+	if agent.Reality != nil {
+		status.RealityType = agent.Reality.Type
+		status.Track = agent.Reality.DegradationTrack
+		if entry, ok := s.catalog.Reality(agent.Reality.Type); ok {
+			status.Trigger = entry.DegradationTrack.Trigger
+			status.Consequence = entry.DegradationTrack.Consequence
+		}
+	}
+
+	return status
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+)
+
+func TestDegradationService_FillTrackAndChooseReality(t *testing.T) {
+	agentService := NewAgentService()
+	degradation := NewDegradationService(agentService, nil)
+	gameService := NewGameServiceWithAgents(nil, agentService, nil)
+
+	agent, err := agentService.CreateAgent(&CreateAgentRequest{
+		Name:        "退化测试",
+		AnomalyType: domain.AnomalyWhisper,
+		RealityType: domain.RealityCaretaker,
+		CareerType:  domain.CareerPublicRelations,
+	})
+	require.NoError(t, err)
+
+	status, err := degradation.GetDegradation(agent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 4, status.Track.Total)
+	assert.NotEmpty(t, status.Trigger)
+	assert.Contains(t, status.Consequence, "必须选择新现实")
+	assert.Empty(t, status.History)
+
+	t.Run("标记必须说明原因", func(t *testing.T) {
+		_, err := degradation.MarkDegradation(agent.ID, &MarkDegradationRequest{})
+		require.Error(t, err)
+		assert.Equal(t, domain.ErrInvalidInput, err.(*domain.GameError).Code)
+	})
+
+	t.Run("默认标记1格", func(t *testing.T) {
+		status, err := degradation.MarkDegradation(agent.ID, &MarkDegradationRequest{Reason: "让受照料者独自处理问题"})
+		require.NoError(t, err)
+		assert.Equal(t, 1, status.Track.Filled)
+		assert.Equal(t, 1, status.Entry.Boxes)
+		assert.False(t, status.PendingRealityChange)
+	})
+
+	t.Run("填满后阻止开始新任务", func(t *testing.T) {
+		status, err := degradation.MarkDegradation(agent.ID, &MarkDegradationRequest{Boxes: 3, Reason: "交给他人监管", SessionID: "session-1"})
+		require.NoError(t, err)
+		assert.True(t, status.PendingRealityChange)
+		assert.Len(t, status.History, 3)
+
+		_, err = gameService.CreateSession(agent.ID, "eternal-spring")
+		require.Error(t, err)
+		assert.Equal(t, domain.ErrInvalidState, err.(*domain.GameError).Code)
+	})
+
+	t.Run("选择新现实后解除", func(t *testing.T) {
+		err := agentService.SetReality(agent.ID, domain.RealityCaretaker)
+		require.Error(t, err)
+
+		require.NoError(t, agentService.SetReality(agent.ID, domain.RealityStar))
+
+		status, err := degradation.GetDegradation(agent.ID)
+		require.NoError(t, err)
+		assert.False(t, status.PendingRealityChange)
+		assert.Equal(t, domain.RealityStar, status.RealityType)
+		assert.Equal(t, 0, status.Track.Filled)
+		assert.Len(t, status.History, 4)
+
+		_, err = gameService.CreateSession(agent.ID, "eternal-spring")
+		assert.NoError(t, err)
+	})
+
+	t.Run("角色不存在", func(t *testing.T) {
+		_, err := degradation.GetDegradation("non-existent")
+		require.Error(t, err)
+		assert.Equal(t, domain.ErrNotFound, err.(*domain.GameError).Code)
+	})
+}
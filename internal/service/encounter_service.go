@@ -0,0 +1,344 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/trpg-solo-engine/backend/internal/domain"
+)
+
+// EncounterService 遭遇服务接口
+// 遭遇按剧本的阶段依次推进：玩家从当前阶段的行动中选择一项并进行资质判定，
+// 失败产生的混沌进入混沌池，异常体在玩家回合之间花费混沌使用混沌效应；
+// 最终阶段判定成功时以所选行动的结果（捕获、中和或协议）结束遭遇，
+// 回合数达到规则上限仍未解决时异常体逃脱
+type EncounterService interface {
+	// 开始遭遇，已开始时返回当前进度
+	Start(sessionID string) (*EncounterView, error)
+	GetEncounter(sessionID string) (*EncounterView, error)
+
+	// 采取当前阶段的行动
+	TakeAction(sessionID string, req *EncounterActionRequest) (*EncounterActionResult, error)
+}
+
+// EncounterView 遭遇进度及当前可选的行动
+type EncounterView struct {
+	SessionID   string                    `json:"session_id"`
+	AnomalyName string                    `json:"anomaly_name"`
+	Description string                    `json:"description"`
+	Phase       *domain.Phase             `json:"phase,omitempty"` // 当前阶段，遭遇结束后为空
+	Choices     []*domain.EncounterChoice `json:"choices"`
+	ChaosPool   int                       `json:"chaos_pool"`
+	TurnLimit   int                       `json:"turn_limit"`
+	State       *domain.EncounterState    `json:"state"`
+}
+
+// EncounterActionRequest 采取遭遇行动请求
+type EncounterActionRequest struct {
+	ChoiceID string `json:"choice_id" binding:"required"`
+}
+
+// EncounterActionResult 遭遇行动结果
+type EncounterActionResult struct {
+	Turn            *domain.EncounterTurn   `json:"turn"`
+	Entry           *domain.LedgerEntry     `json:"entry,omitempty"` // 行动带来的申诫
+	TripleAscension *domain.TripleAscension `json:"triple_ascension,omitempty"`
+	Encounter       *EncounterView          `json:"encounter"`
+}
+
+// encounterService 遭遇服务实现
+type encounterService struct {
+	gameService     GameService
+	agentService    AgentService
+	scenarioService ScenarioService // 可选，未配置时使用通用的遭遇内容
+	diceService     domain.DiceService
+	chaosService    ChaosService
+	sessionRolls    SessionRollService
+	rules           *domain.Ruleset
+}
+
+// NewEncounterService 创建遭遇服务
+// 会话没有自身规则集时使用rules决定回合上限
+func NewEncounterService(gameService GameService, agentService AgentService, scenarioService ScenarioService, diceService domain.DiceService, chaosService ChaosService, sessionRolls SessionRollService, rules *domain.Ruleset) EncounterService {
+	if rules == nil {
+		rules = domain.DefaultRuleset()
+	}
+
+	return &encounterService{
+		gameService:     gameService,
+		agentService:    agentService,
+		scenarioService: scenarioService,
+		diceService:     diceService,
+		chaosService:    chaosService,
+		sessionRolls:    sessionRolls,
+		rules:           rules,
+	}
+}
+
+// Start 开始遭遇
+func (s *encounterService) Start(sessionID string) (*EncounterView, error) {
+	session, scenario, err := s.load(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.State.Encounter == nil {
+		session.State.Encounter = &domain.EncounterState{
+			EncounterID: scenario.Encounter.ID,
+			Turns:       []*domain.EncounterTurn{},
+			StartedAt:   time.Now(),
+		}
+		if err := s.gameService.SaveSession(session); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.view(session, scenario), nil
+}
+
+// GetEncounter 查询遭遇进度
+func (s *encounterService) GetEncounter(sessionID string) (*EncounterView, error) {
+	session, err := s.gameService.GetSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.State.Encounter == nil {
+		return nil, domain.NewGameError(domain.ErrNotFound, "遭遇尚未开始").
+			WithDetails("session_id", sessionID)
+	}
+
+	scenario, err := s.scenario(session)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.view(session, scenario), nil
+}
+
+// TakeAction 采取当前阶段的行动
+func (s *encounterService) TakeAction(sessionID string, req *EncounterActionRequest) (*EncounterActionResult, error) {
+	session, scenario, err := s.load(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	state := session.State.Encounter
+	if state == nil {
+		return nil, domain.NewGameError(domain.ErrInvalidState, "遭遇尚未开始")
+	}
+	if state.Ended() {
+		return nil, domain.NewGameError(domain.ErrInvalidState, "遭遇已经结束").
+			WithDetails("outcome", state.Outcome)
+	}
+
+	choice := findEncounterChoice(s.choices(scenario.Encounter, state), req.ChoiceID)
+	if choice == nil {
+		return nil, domain.NewGameError(domain.ErrNotFound, "当前阶段没有该行动").
+			WithDetails("choice_id", req.ChoiceID).
+			WithDetails("phase_id", state.CurrentPhase(scenario.Encounter).ID)
+	}
+
+	agent, err := s.agentService.GetAgent(session.AgentID)
+	if err != nil {
+		return nil, err
+	}
+
+	roll := s.diceService.ForSession(session).RollForQuality(agent, choice.Quality)
+
+	// 与其他会话掷骰一样写入账本并结算三重升华
+	record := domain.NewRollRecord(session.ID, agent.ID, domain.RollKindEncounter, roll)
+	record.Quality = choice.Quality
+	record.Request = choice.Action
+	tripleAscension, err := s.sessionRolls.Settle(session, record)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.chaosService.AddChaosFromRoll(session, roll); err != nil {
+		return nil, err
+	}
+
+	turn := &domain.EncounterTurn{
+		Turn:      len(state.Turns) + 1,
+		PhaseID:   choice.PhaseID,
+		ChoiceID:  choice.ID,
+		Action:    choice.Action,
+		Quality:   choice.Quality,
+		Roll:      roll,
+		Success:   roll.Success,
+		CreatedAt: time.Now(),
+	}
+	if !roll.Success {
+		turn.ChaosGenerated = roll.Chaos
+	}
+	state.Turns = append(state.Turns, turn)
+
+	// 判定成功时推进阶段，最终阶段以所选行动的结果结束遭遇
+	if roll.Success {
+		if choice.Outcome != "" {
+			state.End(choice.Outcome)
+		} else {
+			state.PhaseIndex++
+		}
+	}
+
+	if limit := s.turnLimit(session); !state.Ended() && limit > 0 && len(state.Turns) >= limit {
+		state.End(OutcomeEscaped)
+	}
+
+	if state.Ended() {
+		turn.Outcome = state.Outcome
+		session.State.MissionOutcome = state.Outcome
+		session.State.AnomalyStatus = state.Outcome
+	} else if effect := pickChaosEffect(scenario.Anomaly, session.State.ChaosPool); effect != nil {
+		// 异常体在玩家回合之间花费混沌
+		if err := s.chaosService.SpendChaos(session, effect.Cost); err != nil {
+			return nil, err
+		}
+		used := *effect
+		turn.AnomalyEffect = &used
+	}
+
+	result := &EncounterActionResult{Turn: turn, TripleAscension: tripleAscension}
+	if choice.Reprimand {
+		_, err := s.agentService.ModifyAgent(agent.ID, func(agent *domain.Agent) error {
+			result.Entry = agent.RecordLedger(&domain.LedgerEntry{
+				SessionID:     session.ID,
+				Reason:        domain.LedgerReasonEncounterAction,
+				Behavior:      choice.Action,
+				Justification: "遭遇中采取了会受到申诫的行动",
+				Reprimands:    1,
+			})
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.gameService.SaveSession(session); err != nil {
+		return nil, err
+	}
+
+	result.Encounter = s.view(session, scenario)
+	return result, nil
+}
+
+// load 获取处于遭遇阶段的会话及其剧本
+func (s *encounterService) load(sessionID string) (*domain.GameSession, *domain.Scenario, error) {
+	session, err := s.gameService.GetSession(sessionID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if session.Phase != domain.PhaseEncounter {
+		return nil, nil, domain.NewGameError(domain.ErrInvalidPhase, "当前不在遭遇阶段").
+			WithDetails("current_phase", session.Phase).
+			WithDetails("expected_phase", domain.PhaseEncounter)
+	}
+
+	scenario, err := s.scenario(session)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return session, scenario, nil
+}
+
+// scenario 读取会话的剧本，剧本没有遭遇时无法进行遭遇
+func (s *encounterService) scenario(session *domain.GameSession) (*domain.Scenario, error) {
+	if s.scenarioService == nil {
+		return defaultEncounterScenario(), nil
+	}
+
+	scenario, err := s.scenarioService.LoadScenario(session.ScenarioID)
+	if err != nil {
+		return nil, err
+	}
+
+	if scenario.Encounter == nil || len(scenario.Encounter.Phases) == 0 {
+		return nil, domain.NewGameError(domain.ErrInvalidState, "剧本没有配置遭遇").
+			WithDetails("scenario_id", session.ScenarioID)
+	}
+
+	return scenario, nil
+}
+
+// turnLimit 会话的遭遇回合上限
+func (s *encounterService) turnLimit(session *domain.GameSession) int {
+	if session.Rules != nil {
+		return session.Rules.EncounterTurnLimit
+	}
+	return s.rules.EncounterTurnLimit
+}
+
+// view 创建遭遇进度视图
+func (s *encounterService) view(session *domain.GameSession, scenario *domain.Scenario) *EncounterView {
+	phase := newEncounterPhaseResult(session.ID, scenario)
+	state := session.State.Encounter
+
+	return &EncounterView{
+		SessionID:   session.ID,
+		AnomalyName: phase.AnomalyName,
+		Description: phase.Description,
+		Phase:       state.CurrentPhase(scenario.Encounter),
+		Choices:     s.choices(scenario.Encounter, state),
+		ChaosPool:   session.State.ChaosPool,
+		TurnLimit:   s.turnLimit(session),
+		State:       state,
+	}
+}
+
+// choices 当前阶段可以采取的行动
+func (s *encounterService) choices(encounter *domain.Encounter, state *domain.EncounterState) []*domain.EncounterChoice {
+	phase := state.CurrentPhase(encounter)
+	if phase == nil {
+		return []*domain.EncounterChoice{}
+	}
+
+	final := state.PhaseIndex == len(encounter.Phases)-1
+	choices := make([]*domain.EncounterChoice, 0, len(phase.Actions))
+	for i, action := range phase.Actions {
+		choice := &domain.EncounterChoice{
+			ID:        fmt.Sprintf("%s-%d", phase.ID, i+1),
+			PhaseID:   phase.ID,
+			Action:    action.Description,
+			Quality:   action.Quality,
+			Reprimand: action.Reprimand,
+		}
+		if choice.Quality == "" {
+			choice.Quality = domain.QualityPresence
+		}
+		if final {
+			choice.Outcome = action.Outcome
+		}
+		choices = append(choices, choice)
+	}
+	return choices
+}
+
+// findEncounterChoice 按ID查找行动
+func findEncounterChoice(choices []*domain.EncounterChoice, choiceID string) *domain.EncounterChoice {
+	for _, choice := range choices {
+		if choice.ID == choiceID {
+			return choice
+		}
+	}
+	return nil
+}
+
+// pickChaosEffect 选择混沌池能够支付的最昂贵的混沌效应，无法支付任何效应时返回nil
+func pickChaosEffect(anomaly *domain.AnomalyProfile, chaosPool int) *domain.ChaosEffect {
+	if anomaly == nil {
+		return nil
+	}
+
+	var picked *domain.ChaosEffect
+	for _, effect := range anomaly.ChaosEffects {
+		if effect.Cost <= chaosPool && (picked == nil || effect.Cost > picked.Cost) {
+			picked = effect
+		}
+	}
+	return picked
+}
@@ -0,0 +1,288 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+)
+
+// scriptedDice 按预设的成败顺序返回资质判定结果
+type scriptedDice struct {
+	domain.DiceService
+	results []bool
+}
+
+func (d *scriptedDice) ForSession(session *domain.GameSession) domain.DiceService {
+	return d
+}
+
+func (d *scriptedDice) RollForQuality(agent *domain.Agent, quality string) *domain.RollResult {
+	success := d.results[0]
+	d.results = d.results[1:]
+
+	if success {
+		return &domain.RollResult{Dice: []int{3, 1, 1, 2, 4, 4}, Threes: 1, Success: true}
+	}
+	return &domain.RollResult{Dice: []int{1, 1, 2, 2, 4, 4}, Chaos: 6}
+}
+
+func setupEncounterTest(t *testing.T, results ...bool) (AgentService, GameService, EncounterService, *domain.Agent, *domain.GameSession) {
+	agentService := NewAgentService()
+	scenarioService := NewScenarioService("../../scenarios")
+	gameService := NewGameServiceWithAgents(scenarioService, agentService, nil)
+	dice := &scriptedDice{DiceService: domain.NewDiceService(), results: results}
+	sessionRolls := NewSessionRollService(NewRollLedgerService(), NewTripleAscensionService(agentService, gameService, NewAIService(), nil))
+	encounterService := NewEncounterService(gameService, agentService, scenarioService, dice, NewChaosService(), sessionRolls, nil)
+
+	agent, err := agentService.CreateAgent(&CreateAgentRequest{
+		Name:        "遭遇测试",
+		AnomalyType: domain.AnomalyWhisper,
+		RealityType: domain.RealityCaretaker,
+		CareerType:  domain.CareerPublicRelations,
+	})
+	require.NoError(t, err)
+
+	session, err := gameService.CreateSession(agent.ID, "eternal-spring")
+	require.NoError(t, err)
+	require.NoError(t, gameService.TransitionPhase(session.ID, domain.PhaseInvestigation))
+	require.NoError(t, gameService.TransitionPhase(session.ID, domain.PhaseEncounter))
+
+	return agentService, gameService, encounterService, agent, session
+}
+
+func TestGameService_StartEncounterPhase_UsesScenario(t *testing.T) {
+	_, gameService, _, _, session := setupEncounterTest(t)
+
+	result, err := gameService.StartEncounterPhase(session.ID)
+	require.NoError(t, err)
+
+	assert.Equal(t, "永恒之泉", result.AnomalyName)
+	assert.Contains(t, result.Description, "永恒之泉")
+	require.Len(t, result.Phases, 3)
+	assert.Equal(t, "initial-contact", result.Phases[0].ID)
+	assert.Len(t, result.ChaosEffects, 5)
+}
+
+func TestEncounterService_Start(t *testing.T) {
+	_, gameService, encounterService, _, session := setupEncounterTest(t)
+
+	_, err := encounterService.GetEncounter(session.ID)
+	require.Error(t, err)
+	assert.Equal(t, domain.ErrNotFound, err.(*domain.GameError).Code)
+
+	view, err := encounterService.Start(session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "永恒之泉", view.AnomalyName)
+	assert.Equal(t, "fountain-confrontation", view.State.EncounterID)
+	assert.Equal(t, domain.DefaultRuleset().EncounterTurnLimit, view.TurnLimit)
+	require.NotNil(t, view.Phase)
+	assert.Equal(t, "initial-contact", view.Phase.ID)
+
+	require.Len(t, view.Choices, 3)
+	assert.Equal(t, "initial-contact-2", view.Choices[1].ID)
+	assert.Equal(t, domain.QualityGrit, view.Choices[1].Quality)
+	assert.Empty(t, view.Choices[1].Outcome, "只有最终阶段的行动决定任务结果")
+
+	// 重复开始返回当前进度
+	again, err := encounterService.Start(session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, view.State.StartedAt, again.State.StartedAt)
+
+	// 不在遭遇阶段
+	require.NoError(t, gameService.TransitionPhase(session.ID, domain.PhaseAftermath))
+	_, err = encounterService.Start(session.ID)
+	require.Error(t, err)
+	assert.Equal(t, domain.ErrInvalidPhase, err.(*domain.GameError).Code)
+}
+
+func TestEncounterService_TakeAction(t *testing.T) {
+	agentService, gameService, encounterService, agent, session := setupEncounterTest(t, false, true, true, true)
+
+	_, err := encounterService.TakeAction(session.ID, &EncounterActionRequest{ChoiceID: "initial-contact-1"})
+	require.Error(t, err)
+	assert.Equal(t, domain.ErrInvalidState, err.(*domain.GameError).Code)
+
+	_, err = encounterService.Start(session.ID)
+	require.NoError(t, err)
+
+	// 不是当前阶段的行动
+	_, err = encounterService.TakeAction(session.ID, &EncounterActionRequest{ChoiceID: "final-resolution-1"})
+	require.Error(t, err)
+	assert.Equal(t, domain.ErrNotFound, err.(*domain.GameError).Code)
+
+	t.Run("失败产生混沌，异常体在回合之间花费混沌", func(t *testing.T) {
+		result, err := encounterService.TakeAction(session.ID, &EncounterActionRequest{ChoiceID: "initial-contact-1"})
+		require.NoError(t, err)
+
+		assert.False(t, result.Turn.Success)
+		assert.Equal(t, 6, result.Turn.ChaosGenerated)
+		require.NotNil(t, result.Turn.AnomalyEffect)
+		assert.Equal(t, "identity-blur", result.Turn.AnomalyEffect.ID, "选择混沌池能够支付的最昂贵效应")
+		assert.Equal(t, 1, result.Encounter.ChaosPool)
+		assert.Equal(t, "initial-contact", result.Encounter.Phase.ID)
+	})
+
+	t.Run("成功进入下一阶段", func(t *testing.T) {
+		result, err := encounterService.TakeAction(session.ID, &EncounterActionRequest{ChoiceID: "initial-contact-3"})
+		require.NoError(t, err)
+
+		assert.True(t, result.Turn.Success)
+		assert.Nil(t, result.Turn.AnomalyEffect)
+		assert.Equal(t, "serena-intervention", result.Encounter.Phase.ID)
+	})
+
+	t.Run("会增加申诫的行动记入账本", func(t *testing.T) {
+		before, err := agentService.GetAgent(agent.ID)
+		require.NoError(t, err)
+		reprimands := before.Reprimands
+
+		result, err := encounterService.TakeAction(session.ID, &EncounterActionRequest{ChoiceID: "serena-intervention-3"})
+		require.NoError(t, err)
+		assert.Equal(t, domain.QualityInitiative, result.Turn.Quality)
+		require.NotNil(t, result.Entry)
+		assert.Equal(t, domain.LedgerReasonEncounterAction, result.Entry.Reason)
+		assert.Equal(t, session.ID, result.Entry.SessionID)
+
+		after, err := agentService.GetAgent(agent.ID)
+		require.NoError(t, err)
+		assert.Equal(t, reprimands+1, after.Reprimands)
+		assert.Equal(t, "final-resolution", result.Encounter.Phase.ID)
+	})
+
+	t.Run("最终阶段以所选结果结束遭遇", func(t *testing.T) {
+		result, err := encounterService.TakeAction(session.ID, &EncounterActionRequest{ChoiceID: "final-resolution-4"})
+		require.NoError(t, err)
+
+		assert.Equal(t, OutcomeDeal, result.Turn.Outcome)
+		assert.Nil(t, result.Encounter.Phase)
+		assert.Empty(t, result.Encounter.Choices)
+		require.NotNil(t, result.Encounter.State.EndedAt)
+
+		state, err := gameService.GetState(session.ID)
+		require.NoError(t, err)
+		assert.Equal(t, OutcomeDeal, state.MissionOutcome)
+		assert.Len(t, state.Encounter.Turns, 4)
+
+		_, err = encounterService.TakeAction(session.ID, &EncounterActionRequest{ChoiceID: "final-resolution-1"})
+		require.Error(t, err)
+		assert.Equal(t, domain.ErrInvalidState, err.(*domain.GameError).Code)
+	})
+}
+
+func TestEncounterService_TurnLimit(t *testing.T) {
+	_, gameService, encounterService, _, session := setupEncounterTest(t, false, false)
+
+	stored, err := gameService.GetSession(session.ID)
+	require.NoError(t, err)
+	stored.Rules.EncounterTurnLimit = 2
+
+	_, err = encounterService.Start(session.ID)
+	require.NoError(t, err)
+
+	result, err := encounterService.TakeAction(session.ID, &EncounterActionRequest{ChoiceID: "initial-contact-1"})
+	require.NoError(t, err)
+	assert.Empty(t, result.Turn.Outcome)
+
+	result, err = encounterService.TakeAction(session.ID, &EncounterActionRequest{ChoiceID: "initial-contact-1"})
+	require.NoError(t, err)
+	assert.Equal(t, OutcomeEscaped, result.Turn.Outcome)
+	assert.Nil(t, result.Turn.AnomalyEffect, "遭遇结束后异常体不再行动")
+
+	state, err := gameService.GetState(session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, OutcomeEscaped, state.MissionOutcome)
+	assert.Equal(t, OutcomeEscaped, state.AnomalyStatus)
+}
+
+// tripleAscensionDice 每次资质判定都出现三重升华
+type tripleAscensionDice struct {
+	domain.DiceService
+}
+
+func (d *tripleAscensionDice) ForSession(session *domain.GameSession) domain.DiceService {
+	return d
+}
+
+func (d *tripleAscensionDice) RollForQuality(agent *domain.Agent, quality string) *domain.RollResult {
+	return &domain.RollResult{Dice: []int{3, 3, 3, 1, 2, 4}, Threes: 3, Success: true, TripleAsc: true}
+}
+
+func TestEncounterService_SettlesRolls(t *testing.T) {
+	agentService := NewAgentService()
+	scenarioService := NewScenarioService("../../scenarios")
+	gameService := NewGameServiceWithAgents(scenarioService, agentService, nil)
+	ledger := NewRollLedgerService()
+	sessionRolls := NewSessionRollService(ledger, NewTripleAscensionService(agentService, gameService, NewAIService(), nil))
+	encounterService := NewEncounterService(gameService, agentService, scenarioService, &tripleAscensionDice{DiceService: domain.NewDiceService()}, NewChaosService(), sessionRolls, nil)
+
+	agent, err := agentService.CreateAgent(&CreateAgentRequest{
+		Name:        "遭遇测试",
+		AnomalyType: domain.AnomalyWhisper,
+		RealityType: domain.RealityCaretaker,
+		CareerType:  domain.CareerPublicRelations,
+	})
+	require.NoError(t, err)
+	session, err := gameService.CreateSession(agent.ID, "eternal-spring")
+	require.NoError(t, err)
+	require.NoError(t, gameService.TransitionPhase(session.ID, domain.PhaseInvestigation))
+	require.NoError(t, gameService.TransitionPhase(session.ID, domain.PhaseEncounter))
+
+	_, err = encounterService.Start(session.ID)
+	require.NoError(t, err)
+	commendations := agent.Commendations
+
+	result, err := encounterService.TakeAction(session.ID, &EncounterActionRequest{ChoiceID: "initial-contact-1"})
+	require.NoError(t, err)
+	require.NotNil(t, result.TripleAscension)
+
+	// 遭遇掷骰和其他会话掷骰一样写入账本并结算三重升华
+	rolls, err := ledger.ListRolls(session.ID, nil)
+	require.NoError(t, err)
+	require.Len(t, rolls, 1)
+	assert.Equal(t, domain.RollKindEncounter, rolls[0].Kind)
+	assert.Equal(t, result.Turn.Quality, rolls[0].Quality)
+	assert.Equal(t, rolls[0].ID, result.TripleAscension.RollID)
+
+	stored, err := agentService.GetAgent(agent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, commendations+domain.DefaultRuleset().TripleAscensionReward, stored.Commendations)
+}
+
+func TestEncounterChoiceRules(t *testing.T) {
+	encounter := &domain.Encounter{Phases: []*domain.Phase{
+		{ID: "contact", Actions: []*domain.PhaseAction{
+			{Description: "默默等待"},
+			{Description: "物理制服Serena", Quality: domain.QualityInitiative, Reprimand: true},
+		}},
+		{ID: "resolution", Actions: []*domain.PhaseAction{
+			{Description: "说服异常体自愿进入收容", Quality: domain.QualityDeception, Outcome: OutcomeCaptured},
+		}},
+	}}
+	encounters := &encounterService{}
+
+	// 行动的资质、申诫和任务结果来自剧本，未指定资质时使用气场
+	choices := encounters.choices(encounter, &domain.EncounterState{})
+	require.Len(t, choices, 2)
+	assert.Equal(t, domain.QualityPresence, choices[0].Quality)
+	assert.False(t, choices[0].Reprimand)
+	assert.Equal(t, domain.QualityInitiative, choices[1].Quality)
+	assert.True(t, choices[1].Reprimand)
+	assert.Empty(t, choices[1].Outcome)
+
+	choices = encounters.choices(encounter, &domain.EncounterState{PhaseIndex: 1})
+	require.Len(t, choices, 1)
+	assert.Equal(t, "resolution-1", choices[0].ID)
+	assert.Equal(t, domain.QualityDeception, choices[0].Quality)
+	assert.Equal(t, OutcomeCaptured, choices[0].Outcome)
+
+	anomaly := &domain.AnomalyProfile{ChaosEffects: []*domain.ChaosEffect{
+		{ID: "small", Cost: 2},
+		{ID: "large", Cost: 4},
+	}}
+	assert.Nil(t, pickChaosEffect(anomaly, 1))
+	assert.Equal(t, "small", pickChaosEffect(anomaly, 3).ID)
+	assert.Equal(t, "large", pickChaosEffect(anomaly, 4).ID)
+	assert.Nil(t, pickChaosEffect(nil, 10))
+}
@@ -1,6 +1,8 @@
 package service
 
 import (
+	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -23,6 +25,14 @@ type GameService interface {
 	StartInvestigationPhase(sessionID string) (*InvestigationPhaseResult, error)
 	StartEncounterPhase(sessionID string) (*EncounterPhaseResult, error)
 
+	// 晨会：选择可选目标，依次完成晨会场景
+	ChooseGoals(sessionID string, goalIDs []string) ([]*domain.OptionalGoal, error)
+	ResolveMorningScene(sessionID, sceneID string, req *MorningSceneRequest) (*MorningSceneResult, error)
+
+	// 调查：沿剧本的场景图移动，在当前场景收集线索
+	MoveToScene(sessionID, sceneID string) (*SceneMoveResult, error)
+	CollectClue(sessionID, clueID string) (*ClueCollectResult, error)
+
 	// 阶段转换
 	TransitionPhase(sessionID string, toPhase domain.GamePhase) error
 
@@ -37,6 +47,27 @@ type MorningPhaseResult struct {
 	Briefing    *domain.Briefing       `json:"briefing"`
 	Goals       []*domain.OptionalGoal `json:"goals"`
 	Description string                 `json:"description"`
+	Scenes      []*domain.MorningScene `json:"scenes"`               // 剧本的晨会场景
+	NextScene   *domain.MorningScene   `json:"next_scene,omitempty"` // 下一个要完成的晨会场景
+	ChosenGoals []string               `json:"chosen_goals"`
+}
+
+// MorningSceneRequest 完成晨会场景请求
+// 人际关系场景必须指定人际关系，Delta为正表示获得连结，为负表示花费连结
+type MorningSceneRequest struct {
+	RelationshipID string `json:"relationship_id"`
+	Delta          int    `json:"delta"`
+	Note           string `json:"note"`
+}
+
+// MorningSceneResult 完成晨会场景结果
+type MorningSceneResult struct {
+	Scene        *domain.MorningScene       `json:"scene"`
+	Record       *domain.MorningSceneRecord `json:"record"`
+	Relationship *domain.Relationship       `json:"relationship,omitempty"`
+	Change       *domain.ConnectionChange   `json:"change,omitempty"`
+	Lost         bool                       `json:"lost,omitempty"` // 连结降至0，永久失去这段人际关系
+	NextScene    *domain.MorningScene       `json:"next_scene,omitempty"`
 }
 
 // InvestigationPhaseResult 调查阶段结果
@@ -47,25 +78,69 @@ type InvestigationPhaseResult struct {
 	Description     string   `json:"description"`
 }
 
+// SceneMoveResult 移动到场景结果
+type SceneMoveResult struct {
+	FromSceneID     string        `json:"from_scene_id"`
+	Scene           *domain.Scene `json:"scene"`
+	FirstVisit      bool          `json:"first_visit"`
+	ReachableScenes []string      `json:"reachable_scenes"` // 从新场景可以前往的场景
+}
+
+// ClueCollectResult 收集线索结果
+type ClueCollectResult struct {
+	Clue     *domain.Clue `json:"clue"`
+	Unlocked []string     `json:"unlocked"` // 本次新解锁的地点
+}
+
 // EncounterPhaseResult 遭遇阶段结果
 type EncounterPhaseResult struct {
-	SessionID   string `json:"session_id"`
-	AnomalyName string `json:"anomaly_name"`
-	Description string `json:"description"`
+	SessionID    string                `json:"session_id"`
+	AnomalyName  string                `json:"anomaly_name"`
+	Description  string                `json:"description"`
+	Phases       []*domain.Phase       `json:"phases"`        // 剧本的遭遇阶段
+	ChaosEffects []*domain.ChaosEffect `json:"chaos_effects"` // 异常体可以花费混沌使用的效应
 }
 
 // gameService 游戏会话服务实现
 type gameService struct {
-	sessions map[string]*domain.GameSession
-	agents   map[string]*domain.Agent // 用于测试的角色存储
-	mu       sync.RWMutex             // 并发控制
+	sessions        map[string]*domain.GameSession
+	agents          map[string]*domain.Agent // 用于测试的角色存储
+	scenarioService ScenarioService          // 可选，用于读取剧本配置
+	agentService    AgentService             // 可选，用于开始任务前检查角色状态
+	chaosService    ChaosService             // 用上次任务留下的散逸端初始化混沌池
+	rules           *domain.Ruleset          // 全局规则集
+	mu              sync.RWMutex             // 并发控制
 }
 
 // NewGameService 创建游戏会话服务
 func NewGameService() GameService {
 	return &gameService{
-		sessions: make(map[string]*domain.GameSession),
-		agents:   make(map[string]*domain.Agent),
+		sessions:     make(map[string]*domain.GameSession),
+		agents:       make(map[string]*domain.Agent),
+		chaosService: NewChaosService(),
+	}
+}
+
+// NewGameServiceWithScenarios 创建读取剧本配置的游戏会话服务
+// 创建会话时会加载剧本，并用剧本的规则覆盖全局规则集
+func NewGameServiceWithScenarios(scenarioService ScenarioService, rules *domain.Ruleset) GameService {
+	return NewGameServiceWithAgents(scenarioService, nil, rules)
+}
+
+// NewGameServiceWithAgents 创建读取剧本配置并检查角色状态的游戏会话服务
+// 角色不存在或处于不能开始任务的状态（如退化轨道已满）时拒绝创建会话和加载存档
+func NewGameServiceWithAgents(scenarioService ScenarioService, agentService AgentService, rules *domain.Ruleset) GameService {
+	if rules == nil {
+		rules = domain.DefaultRuleset()
+	}
+
+	return &gameService{
+		sessions:        make(map[string]*domain.GameSession),
+		agents:          make(map[string]*domain.Agent),
+		scenarioService: scenarioService,
+		agentService:    agentService,
+		chaosService:    NewChaosService(),
+		rules:           rules,
 	}
 }
 
@@ -84,6 +159,20 @@ func (s *gameService) SaveAgent(agent *domain.Agent) error {
 
 // CreateSession 创建游戏会话
 func (s *gameService) CreateSession(agentID, scenarioID string) (*domain.GameSession, error) {
+	agent, err := s.checkCanStartSession(agentID)
+	if err != nil {
+		return nil, err
+	}
+	looseEnds := 0
+	if agent != nil {
+		looseEnds = agent.LooseEnds
+	}
+
+	rules, err := s.resolveRuleset(scenarioID)
+	if err != nil {
+		return nil, err
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -109,16 +198,48 @@ func (s *gameService) CreateSession(agentID, scenarioID string) (*domain.GameSes
 		ScenarioID: scenarioID,
 		Phase:      domain.PhaseMorning,
 		State:      state,
+		DiceSeed:   domain.NewDiceSeed(),
+		DiceCursor: 0,
+		Rules:      rules,
 		CreatedAt:  time.Now(),
 		UpdatedAt:  time.Now(),
 	}
 
+	// 上次任务留下的散逸端进入混沌池
+	if err := s.chaosService.InitializeChaosPool(session, looseEnds); err != nil {
+		return nil, err
+	}
+	// 带入的混沌是起点而不是激增，不应在第一次行动时触发现实触发器
+	session.State.ChaosBaseline = session.State.ChaosPool
+
 	// 保存会话
 	s.sessions[session.ID] = session
 
 	return session, nil
 }
 
+// resolveRuleset 计算会话使用的规则集（全局规则经剧本覆盖）
+// 未配置剧本服务时返回nil，由骰子服务使用自身规则集
+func (s *gameService) resolveRuleset(scenarioID string) (*domain.Ruleset, error) {
+	if s.scenarioService == nil {
+		return s.rules, nil
+	}
+
+	scenario, err := s.scenarioService.LoadScenario(scenarioID)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := s.rules.Override(scenario.Rules)
+	if err := rules.Validate(); err != nil {
+		return nil, domain.NewGameError(domain.ErrInvalidInput, "剧本规则集无效").
+			WithDetails("scenario_id", scenarioID).
+			WithDetails("error", err.Error())
+	}
+
+	return rules, nil
+}
+
 // GetSession 获取游戏会话
 func (s *gameService) GetSession(sessionID string) (*domain.GameSession, error) {
 	s.mu.RLock()
@@ -154,20 +275,42 @@ func (s *gameService) SaveSession(session *domain.GameSession) error {
 }
 
 // RegisterSession 注册一个新会话（用于加载存档）
+// 与创建会话相同，角色必须能够开始任务
 func (s *gameService) RegisterSession(session *domain.GameSession) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
 	if session == nil {
 		return domain.NewGameError(domain.ErrInvalidInput, "游戏会话不能为空")
 	}
 
+	if _, err := s.checkCanStartSession(session.AgentID); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	session.UpdatedAt = time.Now()
 	s.sessions[session.ID] = session
 
 	return nil
 }
 
+// checkCanStartSession 检查角色能否开始任务，未配置角色服务时不检查并返回nil
+func (s *gameService) checkCanStartSession(agentID string) (*domain.Agent, error) {
+	if s.agentService == nil {
+		return nil, nil
+	}
+
+	agent, err := s.agentService.GetAgent(agentID)
+	if err != nil {
+		return nil, err
+	}
+	if err := agent.CheckCanStartSession(); err != nil {
+		return nil, err
+	}
+
+	return agent, nil
+}
+
 // DeleteSession 删除游戏会话
 func (s *gameService) DeleteSession(sessionID string) error {
 	s.mu.Lock()
@@ -196,38 +339,284 @@ func (s *gameService) ListSessions() ([]*domain.GameSession, error) {
 }
 
 // StartMorningPhase 开始晨会阶段
+// 简报、可选目标和晨会场景来自会话的剧本
 func (s *gameService) StartMorningPhase(sessionID string) (*MorningPhaseResult, error) {
+	session, err := s.morningSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	scenario, err := s.morningScenario(session)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := &MorningPhaseResult{
+		SessionID:   sessionID,
+		Briefing:    scenario.Briefing,
+		Goals:       scenario.OptionalGoals,
+		Description: fmt.Sprintf("晨会开始，总经理正在介绍「%s」的任务详情...", scenario.Name),
+		Scenes:      scenario.MorningScenes,
+		NextScene:   session.State.NextMorningScene(scenario.MorningScenes),
+		ChosenGoals: append([]string{}, session.State.ChosenGoals...),
+	}
+	if result.Briefing == nil {
+		result.Briefing = &domain.Briefing{Summary: scenario.Description}
+	}
+	if result.Goals == nil {
+		result.Goals = []*domain.OptionalGoal{}
+	}
+	if result.Scenes == nil {
+		result.Scenes = []*domain.MorningScene{}
+	}
+
+	return result, nil
+}
+
+// ChooseGoals 选择本次任务的可选目标，替换之前的选择
+func (s *gameService) ChooseGoals(sessionID string, goalIDs []string) ([]*domain.OptionalGoal, error) {
+	session, err := s.morningSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	scenario, err := s.morningScenario(session)
+	if err != nil {
+		return nil, err
+	}
+
+	chosen := make([]*domain.OptionalGoal, 0, len(goalIDs))
+	for _, id := range goalIDs {
+		goal := findOptionalGoal(scenario, id)
+		if goal == nil {
+			return nil, domain.NewGameError(domain.ErrNotFound, "可选目标不存在").
+				WithDetails("goal_id", id)
+		}
+		for _, picked := range chosen {
+			if picked.ID == id {
+				return nil, domain.NewGameError(domain.ErrInvalidInput, "重复选择可选目标").
+					WithDetails("goal_id", id)
+			}
+		}
+		chosen = append(chosen, goal)
+	}
+
+	err = s.UpdateState(sessionID, func(state *domain.GameState) error {
+		state.ChosenGoals = append([]string{}, goalIDs...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return chosen, nil
+}
+
+// ResolveMorningScene 按剧本顺序完成晨会场景
+// 人际关系场景中角色可以与一段人际关系互动，获得或花费连结
+func (s *gameService) ResolveMorningScene(sessionID, sceneID string, req *MorningSceneRequest) (*MorningSceneResult, error) {
+	session, err := s.morningSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	scenario, err := s.morningScenario(session)
+	if err != nil {
+		return nil, err
+	}
+
+	var scene *domain.MorningScene
+	for _, candidate := range scenario.MorningScenes {
+		if candidate.ID == sceneID {
+			scene = candidate
+			break
+		}
+	}
+	if scene == nil {
+		return nil, domain.NewGameError(domain.ErrNotFound, "晨会场景不存在").
+			WithDetails("scene_id", sceneID)
+	}
+
+	s.mu.RLock()
+	next := session.State.NextMorningScene(scenario.MorningScenes)
+	s.mu.RUnlock()
+	if next == nil || next.ID != sceneID {
+		err := domain.NewGameError(domain.ErrInvalidState, "晨会场景需要按顺序完成").
+			WithDetails("scene_id", sceneID)
+		if next != nil {
+			err = err.WithDetails("next_scene_id", next.ID)
+		}
+		return nil, err
+	}
+
+	if scene.Type != domain.MorningSceneRelationship && (req.RelationshipID != "" || req.Delta != 0) {
+		return nil, domain.NewGameError(domain.ErrInvalidInput, "只有人际关系场景可以改变连结").
+			WithDetails("scene_id", sceneID).
+			WithDetails("type", scene.Type)
+	}
+
+	record := &domain.MorningSceneRecord{
+		SceneID:     scene.ID,
+		Type:        scene.Type,
+		Note:        strings.TrimSpace(req.Note),
+		CompletedAt: time.Now(),
+	}
+	result := &MorningSceneResult{Scene: scene, Record: record}
+
+	// 在锁内再次检查并先占用场景，并发的重复请求不会都改变连结
+	err = s.UpdateState(sessionID, func(state *domain.GameState) error {
+		if state.MorningSceneDone(sceneID) {
+			return domain.NewGameError(domain.ErrInvalidState, "晨会场景已经完成").
+				WithDetails("scene_id", sceneID)
+		}
+		if next := state.NextMorningScene(scenario.MorningScenes); next == nil || next.ID != sceneID {
+			return domain.NewGameError(domain.ErrInvalidState, "晨会场景需要按顺序完成").
+				WithDetails("scene_id", sceneID)
+		}
+		state.MorningScenes = append(state.MorningScenes, record)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if scene.Type == domain.MorningSceneRelationship {
+		if err := s.resolveRelationshipScene(session, scene, req, result); err != nil {
+			// 连结没有改变，释放占用的场景
+			_ = s.UpdateState(sessionID, func(state *domain.GameState) error {
+				for i, done := range state.MorningScenes {
+					if done == record {
+						state.MorningScenes = append(state.MorningScenes[:i], state.MorningScenes[i+1:]...)
+						break
+					}
+				}
+				return nil
+			})
+			return nil, err
+		}
+	}
+
+	err = s.UpdateState(sessionID, func(state *domain.GameState) error {
+		if scene.Type == domain.MorningSceneRelationship {
+			record.RelationshipID = req.RelationshipID
+		}
+		if result.Change != nil {
+			record.Delta = result.Change.Delta
+		}
+		result.NextScene = state.NextMorningScene(scenario.MorningScenes)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// resolveRelationshipScene 在人际关系场景中改变连结
+func (s *gameService) resolveRelationshipScene(session *domain.GameSession, scene *domain.MorningScene, req *MorningSceneRequest, result *MorningSceneResult) error {
+	if req.RelationshipID == "" {
+		return domain.NewGameError(domain.ErrInvalidInput, "人际关系场景必须选择一段人际关系").
+			WithDetails("scene_id", scene.ID)
+	}
+	rules := s.rules
+	if session.Rules != nil {
+		rules = session.Rules
+	}
+	if req.Delta < -domain.MaxConnection || req.Delta > rules.MorningSceneConnectionGain {
+		return domain.NewGameError(domain.ErrInvalidInput, "连结变化超出范围").
+			WithDetails("delta", req.Delta).
+			WithDetails("max_gain", rules.MorningSceneConnectionGain)
+	}
+	if s.agentService == nil {
+		return domain.NewGameError(domain.ErrInvalidState, "未配置角色服务，无法改变连结")
+	}
+
+	reason := result.Record.Note
+	if reason == "" {
+		reason = "晨会人际关系场景"
+	}
+
+	_, err := s.agentService.ModifyAgent(session.AgentID, func(agent *domain.Agent) error {
+		rel, err := findRelationship(agent, req.RelationshipID)
+		if err != nil {
+			return err
+		}
+		if rel.IsLost() {
+			return domain.NewGameError(domain.ErrInvalidState, "已经失去这段人际关系").
+				WithDetails("relationship_id", rel.ID)
+		}
+		if req.Delta < 0 && rel.Connection < -req.Delta {
+			return domain.NewGameError(domain.ErrInsufficientConnection, "连结不足").
+				WithDetails("relationship_id", rel.ID).
+				WithDetails("connection", rel.Connection).
+				WithDetails("amount", -req.Delta)
+		}
+
+		if req.Delta != 0 {
+			result.Change = rel.ChangeConnection(req.Delta, reason, session.ID)
+		}
+		result.Relationship = rel
+		result.Lost = rel.IsLost()
+		return nil
+	})
+	return err
+}
+
+// morningSession 获取会话并检查处于晨会阶段
+func (s *gameService) morningSession(sessionID string) (*domain.GameSession, error) {
 	session, err := s.GetSession(sessionID)
 	if err != nil {
 		return nil, err
 	}
 
-	// 验证阶段转换
 	if session.Phase != domain.PhaseMorning {
 		return nil, domain.NewGameError(domain.ErrInvalidPhase, "当前不在晨会阶段").
 			WithDetails("current_phase", session.Phase).
 			WithDetails("expected_phase", domain.PhaseMorning)
 	}
 
-	// 创建晨会结果
-	result := &MorningPhaseResult{
-		SessionID: sessionID,
+	return session, nil
+}
+
+// morningScenario 读取会话的剧本，未配置剧本服务时使用通用的晨会内容
+func (s *gameService) morningScenario(session *domain.GameSession) (*domain.Scenario, error) {
+	if s.scenarioService == nil {
+		return defaultMorningScenario(), nil
+	}
+	return s.scenarioService.LoadScenario(session.ScenarioID)
+}
+
+// defaultMorningScenario 通用的晨会内容
+func defaultMorningScenario() *domain.Scenario {
+	return &domain.Scenario{
+		Name: "常规任务",
 		Briefing: &domain.Briefing{
 			Summary:    "任务简报",
 			Objectives: []string{"捕获异常体", "最小化散逸端"},
 			Warnings:   []string{"注意安全", "遵守规则"},
 		},
-		Goals: []*domain.OptionalGoal{
+		OptionalGoals: []*domain.OptionalGoal{
 			{
-				ID:          uuid.New().String(),
+				ID:          "complete-optional-goal",
 				Description: "完成可选目标",
 				Reward:      3,
 			},
 		},
-		Description: "晨会开始，总经理正在介绍任务详情...",
 	}
+}
 
-	return result, nil
+// findOptionalGoal 按ID查找剧本的可选目标
+func findOptionalGoal(scenario *domain.Scenario, goalID string) *domain.OptionalGoal {
+	for _, goal := range scenario.OptionalGoals {
+		if goal.ID == goalID {
+			return goal
+		}
+	}
+	return nil
 }
 
 // StartInvestigationPhase 开始调查阶段
@@ -255,6 +644,155 @@ func (s *gameService) StartInvestigationPhase(sessionID string) (*InvestigationP
 	return result, nil
 }
 
+// MoveToScene 移动到与当前场景相连或已解锁的场景
+func (s *gameService) MoveToScene(sessionID, sceneID string) (*SceneMoveResult, error) {
+	session, scenario, err := s.investigationScenario(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	scene, exists := scenario.Scenes[sceneID]
+	if !exists {
+		return nil, domain.NewGameError(domain.ErrNotFound, "场景不存在").
+			WithDetails("scene_id", sceneID)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state := session.State
+	from := state.CurrentSceneID
+	if sceneID == from {
+		return nil, domain.NewGameError(domain.ErrInvalidAction, "已经在该场景中").
+			WithDetails("scene_id", sceneID)
+	}
+
+	reachable := reachableScenes(scenario, state)
+	if !contains(reachable, sceneID) {
+		return nil, domain.NewGameError(domain.ErrInvalidAction, "无法前往该场景，场景既不与当前场景相连也未解锁").
+			WithDetails("scene_id", sceneID).
+			WithDetails("current_scene_id", from).
+			WithDetails("missing_requirements", []string{"connected_to_current_scene", "unlocked"}).
+			WithDetails("reachable_scenes", reachable)
+	}
+
+	if state.VisitedScenes == nil {
+		state.VisitedScenes = make(map[string]bool)
+	}
+	result := &SceneMoveResult{
+		FromSceneID: from,
+		Scene:       scene,
+		FirstVisit:  !state.VisitedScenes[sceneID],
+	}
+	state.EnterScene(sceneID)
+	state.VisitedScenes[sceneID] = true
+	session.UpdatedAt = time.Now()
+
+	result.ReachableScenes = reachableScenes(scenario, state)
+	return result, nil
+}
+
+// CollectClue 收集当前场景中满足需求的线索，并自动解锁线索指向的地点
+func (s *gameService) CollectClue(sessionID, clueID string) (*ClueCollectResult, error) {
+	session, scenario, err := s.investigationScenario(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state := session.State
+	var clue *domain.Clue
+	if scene, exists := scenario.Scenes[state.CurrentSceneID]; exists {
+		for _, candidate := range scene.Clues {
+			if candidate.ID == clueID {
+				clue = candidate
+				break
+			}
+		}
+	}
+	if clue == nil {
+		if _, err := s.scenarioService.GetClue(session.ScenarioID, clueID); err != nil {
+			return nil, err
+		}
+		return nil, domain.NewGameError(domain.ErrInvalidAction, "线索不在当前场景中").
+			WithDetails("clue_id", clueID).
+			WithDetails("current_scene_id", state.CurrentSceneID)
+	}
+
+	if contains(state.CollectedClues, clueID) {
+		return nil, domain.NewGameError(domain.ErrInvalidAction, "线索已收集").
+			WithDetails("clue_id", clueID)
+	}
+
+	if !s.scenarioService.CheckClueRequirements(clue, state) {
+		missing := make([]string, 0, len(clue.Requirements))
+		for _, req := range clue.Requirements {
+			if !contains(state.CollectedClues, req) {
+				missing = append(missing, req)
+			}
+		}
+		return nil, domain.NewGameError(domain.ErrInvalidAction, "线索需求未满足").
+			WithDetails("clue_id", clueID).
+			WithDetails("missing_requirements", missing)
+	}
+
+	result := &ClueCollectResult{Clue: clue, Unlocked: []string{}}
+	state.CollectedClues = append(state.CollectedClues, clueID)
+	for _, unlockID := range clue.Unlocks {
+		if !contains(state.UnlockedLocations, unlockID) {
+			state.UnlockedLocations = append(state.UnlockedLocations, unlockID)
+			result.Unlocked = append(result.Unlocked, unlockID)
+		}
+	}
+	session.UpdatedAt = time.Now()
+
+	return result, nil
+}
+
+// investigationScenario 获取调查阶段的会话及其剧本
+// 场景移动和线索收集需要依据剧本的场景图校验，未配置剧本服务时无法进行
+func (s *gameService) investigationScenario(sessionID string) (*domain.GameSession, *domain.Scenario, error) {
+	session, err := s.GetSession(sessionID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if session.Phase != domain.PhaseInvestigation {
+		return nil, nil, domain.NewGameError(domain.ErrInvalidPhase, "当前不在调查阶段").
+			WithDetails("current_phase", session.Phase).
+			WithDetails("expected_phase", domain.PhaseInvestigation)
+	}
+	if s.scenarioService == nil {
+		return nil, nil, domain.NewGameError(domain.ErrInvalidState, "没有可用的剧本服务")
+	}
+
+	scenario, err := s.scenarioService.LoadScenario(session.ScenarioID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return session, scenario, nil
+}
+
+// reachableScenes 从当前场景可以前往的场景：与当前场景相连或已解锁的场景
+func reachableScenes(scenario *domain.Scenario, state *domain.GameState) []string {
+	reachable := []string{}
+	if current, exists := scenario.Scenes[state.CurrentSceneID]; exists {
+		for _, id := range current.Connections {
+			if id != state.CurrentSceneID && !contains(reachable, id) {
+				reachable = append(reachable, id)
+			}
+		}
+	}
+	for _, id := range state.UnlockedLocations {
+		if _, exists := scenario.Scenes[id]; exists && id != state.CurrentSceneID && !contains(reachable, id) {
+			reachable = append(reachable, id)
+		}
+	}
+	return reachable
+}
+
 // StartEncounterPhase 开始遭遇阶段
 func (s *gameService) StartEncounterPhase(sessionID string) (*EncounterPhaseResult, error) {
 	session, err := s.GetSession(sessionID)
@@ -269,14 +807,86 @@ func (s *gameService) StartEncounterPhase(sessionID string) (*EncounterPhaseResu
 			WithDetails("expected_phase", domain.PhaseEncounter)
 	}
 
-	// 创建遭遇结果
+	scenario, err := s.encounterScenario(session)
+	if err != nil {
+		return nil, err
+	}
+
+	return newEncounterPhaseResult(sessionID, scenario), nil
+}
+
+// encounterScenario 读取会话的剧本，未配置剧本服务时使用通用的遭遇内容
+func (s *gameService) encounterScenario(session *domain.GameSession) (*domain.Scenario, error) {
+	if s.scenarioService == nil {
+		return defaultEncounterScenario(), nil
+	}
+	return s.scenarioService.LoadScenario(session.ScenarioID)
+}
+
+// newEncounterPhaseResult 根据剧本的异常体档案和遭遇创建遭遇阶段结果
+func newEncounterPhaseResult(sessionID string, scenario *domain.Scenario) *EncounterPhaseResult {
 	result := &EncounterPhaseResult{
 		SessionID:   sessionID,
 		AnomalyName: "未知异常体",
 		Description: "遭遇阶段开始，你进入了异常体的领域...",
 	}
 
-	return result, nil
+	if scenario.Anomaly != nil {
+		if scenario.Anomaly.Name != "" {
+			result.AnomalyName = scenario.Anomaly.Name
+		}
+		result.ChaosEffects = scenario.Anomaly.ChaosEffects
+	}
+	if scenario.Encounter != nil {
+		if scenario.Encounter.Description != "" {
+			result.Description = scenario.Encounter.Description
+		}
+		result.Phases = scenario.Encounter.Phases
+	}
+
+	return result
+}
+
+// defaultEncounterScenario 通用的遭遇内容
+func defaultEncounterScenario() *domain.Scenario {
+	return &domain.Scenario{
+		Name: "常规任务",
+		Anomaly: &domain.AnomalyProfile{
+			Name: "未知异常体",
+			ChaosEffects: []*domain.ChaosEffect{
+				{
+					ID:          "reality-distortion",
+					Name:        "现实扭曲",
+					Cost:        2,
+					Description: "异常体扭曲周围的现实",
+					Effect:      "特工的下一次判定更加困难",
+				},
+			},
+		},
+		Encounter: &domain.Encounter{
+			ID:          "encounter",
+			Description: "遭遇阶段开始，你进入了异常体的领域...",
+			Phases: []*domain.Phase{
+				{
+					ID:          "contact",
+					Description: "特工接触异常体，观察它的行为",
+					Actions: []*domain.PhaseAction{
+						{Description: "观察异常体的行为模式", Quality: domain.QualityFocus},
+						{Description: "与异常体交流", Quality: domain.QualityEmpathy},
+					},
+				},
+				{
+					ID:          "resolution",
+					Description: "决定异常体的命运",
+					Actions: []*domain.PhaseAction{
+						{Description: "捕获异常体", Quality: domain.QualityInitiative, Outcome: OutcomeCaptured},
+						{Description: "摧毁异常体", Quality: domain.QualityVitality, Outcome: OutcomeNeutralized},
+						{Description: "达成协议", Quality: domain.QualityEmpathy, Outcome: OutcomeDeal},
+					},
+				},
+			},
+		},
+	}
 }
 
 // TransitionPhase 转换游戏阶段
@@ -293,11 +903,25 @@ func (s *gameService) TransitionPhase(sessionID string, toPhase domain.GamePhase
 			WithDetails("to_phase", toPhase)
 	}
 
+	// 进入调查阶段时特工从剧本的起始场景开始
+	var startingSceneID string
+	if toPhase == domain.PhaseInvestigation && session.State.CurrentSceneID == "" && s.scenarioService != nil {
+		scenario, err := s.scenarioService.LoadScenario(session.ScenarioID)
+		if err != nil {
+			return err
+		}
+		startingSceneID = scenario.StartingSceneID
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	// 更新阶段
 	session.Phase = toPhase
+	if startingSceneID != "" {
+		session.State.EnterScene(startingSceneID)
+		session.State.VisitedScenes[startingSceneID] = true
+	}
 	session.UpdatedAt = time.Now()
 
 	return nil
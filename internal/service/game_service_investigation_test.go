@@ -0,0 +1,143 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+)
+
+func setupInvestigationTest(t *testing.T) (GameService, *domain.GameSession) {
+	gameService := NewGameServiceWithScenarios(NewScenarioService("../../scenarios"), nil)
+
+	session, err := gameService.CreateSession("agent-1", "eternal-spring")
+	require.NoError(t, err)
+
+	return gameService, session
+}
+
+func TestGameService_MoveToScene(t *testing.T) {
+	gameService, session := setupInvestigationTest(t)
+
+	// 晨会阶段不能移动
+	_, err := gameService.MoveToScene(session.ID, "the-source")
+	require.Error(t, err)
+	assert.Equal(t, domain.ErrInvalidPhase, err.(*domain.GameError).Code)
+
+	require.NoError(t, gameService.TransitionPhase(session.ID, domain.PhaseInvestigation))
+
+	_, err = gameService.MoveToScene(session.ID, "non-existent")
+	require.Error(t, err)
+	assert.Equal(t, domain.ErrNotFound, err.(*domain.GameError).Code)
+
+	_, err = gameService.MoveToScene(session.ID, "commercial-avenue")
+	require.Error(t, err)
+	assert.Equal(t, domain.ErrInvalidAction, err.(*domain.GameError).Code)
+
+	// 既不相连也未解锁
+	_, err = gameService.MoveToScene(session.ID, "domain-entrance")
+	require.Error(t, err)
+	gameErr := err.(*domain.GameError)
+	assert.Equal(t, domain.ErrInvalidAction, gameErr.Code)
+	assert.Equal(t, "commercial-avenue", gameErr.Details["current_scene_id"])
+	assert.ElementsMatch(t, []string{"the-source", "okafi-warehouse"}, gameErr.Details["reachable_scenes"])
+
+	result, err := gameService.MoveToScene(session.ID, "the-source")
+	require.NoError(t, err)
+	assert.Equal(t, "commercial-avenue", result.FromSceneID)
+	assert.Equal(t, "the-source", result.Scene.ID)
+	assert.True(t, result.FirstVisit)
+	assert.ElementsMatch(t, []string{"commercial-avenue", "underground-access"}, result.ReachableScenes)
+
+	state, err := gameService.GetState(session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "the-source", state.CurrentSceneID)
+	assert.True(t, state.VisitedScenes["the-source"])
+
+	result, err = gameService.MoveToScene(session.ID, "commercial-avenue")
+	require.NoError(t, err)
+	assert.False(t, result.FirstVisit)
+
+	// 已解锁的地点不相连也可以前往
+	require.NoError(t, gameService.UpdateState(session.ID, func(state *domain.GameState) error {
+		state.UnlockedLocations = append(state.UnlockedLocations, "domain-entrance")
+		return nil
+	}))
+	_, err = gameService.MoveToScene(session.ID, "domain-entrance")
+	require.NoError(t, err)
+
+	// 遭遇阶段不能移动
+	require.NoError(t, gameService.TransitionPhase(session.ID, domain.PhaseEncounter))
+	_, err = gameService.MoveToScene(session.ID, "underground-access")
+	require.Error(t, err)
+	assert.Equal(t, domain.ErrInvalidPhase, err.(*domain.GameError).Code)
+}
+
+func TestGameService_CollectClue(t *testing.T) {
+	gameService, session := setupInvestigationTest(t)
+
+	_, err := gameService.CollectClue(session.ID, "similar-appearances")
+	require.Error(t, err)
+	assert.Equal(t, domain.ErrInvalidPhase, err.(*domain.GameError).Code)
+
+	require.NoError(t, gameService.TransitionPhase(session.ID, domain.PhaseInvestigation))
+
+	t.Run("收集线索并自动解锁地点", func(t *testing.T) {
+		result, err := gameService.CollectClue(session.ID, "similar-appearances")
+		require.NoError(t, err)
+		assert.Equal(t, "similar-appearances", result.Clue.ID)
+		assert.Equal(t, []string{"the-source"}, result.Unlocked)
+
+		state, err := gameService.GetState(session.ID)
+		require.NoError(t, err)
+		assert.Contains(t, state.CollectedClues, "similar-appearances")
+		assert.Contains(t, state.UnlockedLocations, "the-source")
+
+		_, err = gameService.CollectClue(session.ID, "similar-appearances")
+		require.Error(t, err)
+		assert.Equal(t, domain.ErrInvalidAction, err.(*domain.GameError).Code)
+	})
+
+	t.Run("需求未满足时列出缺少的前置条件", func(t *testing.T) {
+		_, err := gameService.CollectClue(session.ID, "okafi-products")
+		require.Error(t, err)
+		gameErr := err.(*domain.GameError)
+		assert.Equal(t, domain.ErrInvalidAction, gameErr.Code)
+		assert.Equal(t, []string{"talk-to-maya"}, gameErr.Details["missing_requirements"])
+
+		require.NoError(t, gameService.UpdateState(session.ID, func(state *domain.GameState) error {
+			state.CollectedClues = append(state.CollectedClues, "talk-to-maya")
+			return nil
+		}))
+
+		// the-source已经由上一条线索解锁，不再重复解锁
+		result, err := gameService.CollectClue(session.ID, "okafi-products")
+		require.NoError(t, err)
+		assert.Empty(t, result.Unlocked)
+	})
+
+	t.Run("线索必须在当前场景中", func(t *testing.T) {
+		_, err := gameService.CollectClue(session.ID, "plant-growth")
+		require.Error(t, err)
+		gameErr := err.(*domain.GameError)
+		assert.Equal(t, domain.ErrInvalidAction, gameErr.Code)
+		assert.Equal(t, "commercial-avenue", gameErr.Details["current_scene_id"])
+
+		_, err = gameService.CollectClue(session.ID, "non-existent")
+		require.Error(t, err)
+		assert.Equal(t, domain.ErrNotFound, err.(*domain.GameError).Code)
+	})
+}
+
+func TestGameService_InvestigationRequiresScenarios(t *testing.T) {
+	gameService := NewGameService()
+
+	session, err := gameService.CreateSession("agent-1", "scenario-1")
+	require.NoError(t, err)
+	require.NoError(t, gameService.TransitionPhase(session.ID, domain.PhaseInvestigation))
+
+	_, err = gameService.MoveToScene(session.ID, "scene-1")
+	require.Error(t, err)
+	assert.Equal(t, domain.ErrInvalidState, err.(*domain.GameError).Code)
+}
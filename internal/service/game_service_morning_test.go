@@ -0,0 +1,172 @@
+package service
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+)
+
+func setupMorningTest(t *testing.T) (AgentService, GameService, *domain.Agent, *domain.GameSession) {
+	fx := newTestAgentSession(t, withScenario("eternal-spring"))
+
+	return fx.agentService, fx.gameService, fx.agent, fx.session
+}
+
+func TestGameService_StartMorningPhase_UsesScenario(t *testing.T) {
+	_, gameService, _, session := setupMorningTest(t)
+
+	result, err := gameService.StartMorningPhase(session.ID)
+	require.NoError(t, err)
+
+	assert.Contains(t, result.Briefing.Summary, "奥可菲美容公司")
+	require.Len(t, result.Goals, 3)
+	assert.Equal(t, "save-serena", result.Goals[0].ID)
+	require.Len(t, result.Scenes, 4)
+	require.NotNil(t, result.NextScene)
+	assert.Equal(t, "morning-routine", result.NextScene.ID)
+	assert.Empty(t, result.ChosenGoals)
+}
+
+func TestGameService_ChooseGoals(t *testing.T) {
+	_, gameService, _, session := setupMorningTest(t)
+
+	chosen, err := gameService.ChooseGoals(session.ID, []string{"save-serena", "product-recall"})
+	require.NoError(t, err)
+	require.Len(t, chosen, 2)
+	assert.Equal(t, 2, chosen[1].Reward)
+
+	state, err := gameService.GetState(session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"save-serena", "product-recall"}, state.ChosenGoals)
+	assert.True(t, state.GoalChosen("save-serena"))
+
+	_, err = gameService.ChooseGoals(session.ID, []string{"no-such-goal"})
+	require.Error(t, err)
+	assert.Equal(t, domain.ErrNotFound, err.(*domain.GameError).Code)
+
+	_, err = gameService.ChooseGoals(session.ID, []string{"save-serena", "save-serena"})
+	require.Error(t, err)
+	assert.Equal(t, domain.ErrInvalidInput, err.(*domain.GameError).Code)
+
+	// 选择保存在会话状态中，离开晨会后不能再修改
+	require.NoError(t, gameService.TransitionPhase(session.ID, domain.PhaseInvestigation))
+	_, err = gameService.ChooseGoals(session.ID, nil)
+	require.Error(t, err)
+	assert.Equal(t, domain.ErrInvalidPhase, err.(*domain.GameError).Code)
+	state, err = gameService.GetState(session.ID)
+	require.NoError(t, err)
+	assert.Len(t, state.ChosenGoals, 2)
+}
+
+func TestGameService_ResolveMorningScene(t *testing.T) {
+	agentService, gameService, agent, session := setupMorningTest(t)
+	rel := agent.Relationships[1]
+	initial := rel.Connection
+
+	// 必须按顺序完成
+	_, err := gameService.ResolveMorningScene(session.ID, "relationship-moment", &MorningSceneRequest{})
+	require.Error(t, err)
+	assert.Equal(t, domain.ErrInvalidState, err.(*domain.GameError).Code)
+
+	// 普通场景不能改变连结
+	_, err = gameService.ResolveMorningScene(session.ID, "morning-routine", &MorningSceneRequest{RelationshipID: rel.ID, Delta: 1})
+	require.Error(t, err)
+	assert.Equal(t, domain.ErrInvalidInput, err.(*domain.GameError).Code)
+
+	result, err := gameService.ResolveMorningScene(session.ID, "morning-routine", &MorningSceneRequest{})
+	require.NoError(t, err)
+	assert.Nil(t, result.Change)
+	require.NotNil(t, result.NextScene)
+	assert.Equal(t, "relationship-moment", result.NextScene.ID)
+
+	t.Run("人际关系场景必须选择人际关系且连结足够", func(t *testing.T) {
+		_, err := gameService.ResolveMorningScene(session.ID, "relationship-moment", &MorningSceneRequest{})
+		require.Error(t, err)
+		assert.Equal(t, domain.ErrInvalidInput, err.(*domain.GameError).Code)
+
+		_, err = gameService.ResolveMorningScene(session.ID, "relationship-moment", &MorningSceneRequest{RelationshipID: rel.ID, Delta: -(initial + 1)})
+		require.Error(t, err)
+		assert.Equal(t, domain.ErrInsufficientConnection, err.(*domain.GameError).Code)
+	})
+
+	t.Run("获得的连结不超过规则集上限", func(t *testing.T) {
+		_, err := gameService.ResolveMorningScene(session.ID, "relationship-moment", &MorningSceneRequest{RelationshipID: rel.ID, Delta: 2})
+		require.Error(t, err)
+		gameErr := err.(*domain.GameError)
+		assert.Equal(t, domain.ErrInvalidInput, gameErr.Code)
+		assert.Equal(t, 1, gameErr.Details["max_gain"])
+
+		// 失败的请求不占用场景
+		state, err := gameService.GetState(session.ID)
+		require.NoError(t, err)
+		assert.False(t, state.MorningSceneDone("relationship-moment"))
+	})
+
+	result, err = gameService.ResolveMorningScene(session.ID, "relationship-moment", &MorningSceneRequest{
+		RelationshipID: rel.ID,
+		Delta:          1,
+		Note:           "一起吃早餐，听对方抱怨美容产品",
+	})
+	require.NoError(t, err)
+	require.NotNil(t, result.Change)
+	assert.Equal(t, 1, result.Change.Delta)
+	assert.Equal(t, session.ID, result.Change.SessionID)
+	assert.Equal(t, rel.ID, result.Record.RelationshipID)
+	assert.Equal(t, 1, result.Record.Delta)
+
+	stored, err := agentService.GetAgent(agent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, initial+1, stored.FindRelationship(rel.ID).Connection)
+
+	// 已完成的场景不能重复
+	_, err = gameService.ResolveMorningScene(session.ID, "relationship-moment", &MorningSceneRequest{RelationshipID: rel.ID, Delta: 1})
+	require.Error(t, err)
+	assert.Equal(t, domain.ErrInvalidState, err.(*domain.GameError).Code)
+
+	state, err := gameService.GetState(session.ID)
+	require.NoError(t, err)
+	assert.Len(t, state.MorningScenes, 2)
+
+	morning, err := gameService.StartMorningPhase(session.ID)
+	require.NoError(t, err)
+	require.NotNil(t, morning.NextScene)
+	assert.Equal(t, "news-report", morning.NextScene.ID)
+}
+
+func TestGameService_ResolveMorningScene_Concurrent(t *testing.T) {
+	agentService, gameService, agent, session := setupMorningTest(t)
+	relID := agent.Relationships[1].ID
+	initial := agent.Relationships[1].Connection
+
+	_, err := gameService.ResolveMorningScene(session.ID, "morning-routine", &MorningSceneRequest{})
+	require.NoError(t, err)
+
+	// 同一场景的并发请求只有一个能改变连结
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := gameService.ResolveMorningScene(session.ID, "relationship-moment", &MorningSceneRequest{RelationshipID: relID, Delta: 1})
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	succeeded := 0
+	for err := range errs {
+		if err == nil {
+			succeeded++
+		}
+	}
+	assert.Equal(t, 1, succeeded)
+
+	stored, err := agentService.GetAgent(agent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, initial+1, stored.FindRelationship(relID).Connection)
+}
@@ -1,6 +1,10 @@
 package service
 
 import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
 	"sync"
 	"testing"
 
@@ -62,6 +66,61 @@ func TestGameService_CreateSession(t *testing.T) {
 	}
 }
 
+func TestGameService_CreateSessionWithScenarioRules(t *testing.T) {
+	tempDir := t.TempDir()
+
+	// 剧本只覆盖骰子面数和成功值
+	scenario := CreateTestScenario()
+	sides, success := 6, 6
+	scenario.Rules = &domain.RulesetOverride{DiceSides: &sides, SuccessValue: &success}
+	data, err := json.Marshal(scenario)
+	if err != nil {
+		t.Fatalf("序列化剧本失败: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, scenario.ID+".json"), data, 0644); err != nil {
+		t.Fatalf("写入剧本失败: %v", err)
+	}
+
+	service := NewGameServiceWithScenarios(NewScenarioService(tempDir), domain.DefaultRuleset())
+
+	session, err := service.CreateSession("test-agent-id", scenario.ID)
+	if err != nil {
+		t.Fatalf("创建会话失败: %v", err)
+	}
+
+	expected := domain.DefaultRuleset()
+	expected.DiceSides = 6
+	expected.SuccessValue = 6
+	if !reflect.DeepEqual(session.Rules, expected) {
+		t.Errorf("期望规则集 %+v，实际 %+v", expected, session.Rules)
+	}
+
+	// 会话骰子使用剧本规则集
+	roll := domain.NewDiceService().ForSession(session).Roll(0)
+	for _, d := range roll.Dice {
+		if d < 1 || d > 6 {
+			t.Errorf("骰子超出d6范围: %d", d)
+		}
+	}
+
+	// 剧本不存在时拒绝创建
+	if _, err := service.CreateSession("test-agent-id", "missing-scenario"); err == nil {
+		t.Error("期望剧本不存在时返回错误")
+	}
+
+	// 覆盖后无效的规则集被拒绝
+	scenario.ID = "invalid-rules"
+	invalid := 9
+	scenario.Rules = &domain.RulesetOverride{SuccessValue: &invalid}
+	data, _ = json.Marshal(scenario)
+	if err := os.WriteFile(filepath.Join(tempDir, scenario.ID+".json"), data, 0644); err != nil {
+		t.Fatalf("写入剧本失败: %v", err)
+	}
+	if _, err := service.CreateSession("test-agent-id", scenario.ID); err == nil {
+		t.Error("期望无效规则集返回错误")
+	}
+}
+
 func TestGameService_GetSession(t *testing.T) {
 	service := NewGameService()
 
@@ -243,6 +302,30 @@ func TestGameService_TransitionPhase(t *testing.T) {
 	}
 }
 
+func TestGameService_TransitionPhase_StartingScene(t *testing.T) {
+	service := NewGameServiceWithScenarios(NewScenarioService("../../scenarios"), nil)
+
+	session, err := service.CreateSession("agent-1", "eternal-spring")
+	if err != nil {
+		t.Fatalf("创建会话失败: %v", err)
+	}
+	if session.State.CurrentSceneID != "" {
+		t.Errorf("晨会阶段不应有当前场景, 得到 %s", session.State.CurrentSceneID)
+	}
+
+	if err := service.TransitionPhase(session.ID, domain.PhaseInvestigation); err != nil {
+		t.Fatalf("阶段转换失败: %v", err)
+	}
+
+	// 进入调查阶段时位于剧本的起始场景
+	if session.State.CurrentSceneID != "commercial-avenue" {
+		t.Errorf("期望当前场景为 commercial-avenue, 得到 %s", session.State.CurrentSceneID)
+	}
+	if !session.State.VisitedScenes["commercial-avenue"] {
+		t.Error("期望起始场景被标记为已访问")
+	}
+}
+
 func TestGameService_StartMorningPhase(t *testing.T) {
 	service := NewGameService()
 
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+)
+
+// testAgentSession 服务测试共用的内存服务、角色和会话
+type testAgentSession struct {
+	agentService    AgentService
+	scenarioService ScenarioService // 未指定剧本时为nil
+	gameService     GameService
+	agent           *domain.Agent
+	session         *domain.GameSession // withoutSession时为nil
+}
+
+type testAgentSessionConfig struct {
+	realityType string
+	scenarioID  string
+	noSession   bool
+}
+
+// testAgentSessionOption 调整共用测试夹具
+type testAgentSessionOption func(cfg *testAgentSessionConfig)
+
+// withReality 使用指定现实创建角色（默认看护者）
+func withReality(realityType string) testAgentSessionOption {
+	return func(cfg *testAgentSessionConfig) {
+		cfg.realityType = realityType
+	}
+}
+
+// withScenario 加载 scenarios 目录并以指定剧本创建会话
+func withScenario(scenarioID string) testAgentSessionOption {
+	return func(cfg *testAgentSessionConfig) {
+		cfg.scenarioID = scenarioID
+	}
+}
+
+// withoutSession 只创建角色，不创建会话
+func withoutSession() testAgentSessionOption {
+	return func(cfg *testAgentSessionConfig) {
+		cfg.noSession = true
+	}
+}
+
+// newTestAgentSession 创建内存服务、一个低语/看护者/公关角色和一个晨会阶段的会话
+func newTestAgentSession(t *testing.T, opts ...testAgentSessionOption) *testAgentSession {
+	t.Helper()
+
+	cfg := &testAgentSessionConfig{realityType: domain.RealityCaretaker}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	fixture := &testAgentSession{agentService: NewAgentService()}
+	scenarioID := "test-scenario"
+	if cfg.scenarioID != "" {
+		fixture.scenarioService = NewScenarioService("../../scenarios")
+		scenarioID = cfg.scenarioID
+	}
+	fixture.gameService = NewGameServiceWithAgents(fixture.scenarioService, fixture.agentService, nil)
+
+	agent, err := fixture.agentService.CreateAgent(&CreateAgentRequest{
+		Name:        "测试特工",
+		AnomalyType: domain.AnomalyWhisper,
+		RealityType: cfg.realityType,
+		CareerType:  domain.CareerPublicRelations,
+	})
+	require.NoError(t, err)
+	fixture.agent = agent
+
+	if !cfg.noSession {
+		session, err := fixture.gameService.CreateSession(agent.ID, scenarioID)
+		require.NoError(t, err)
+		fixture.session = session
+	}
+
+	return fixture
+}
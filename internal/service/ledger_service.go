@@ -0,0 +1,60 @@
+package service
+
+import (
+	"github.com/trpg-solo-engine/backend/internal/domain"
+)
+
+// LedgerService 绩效账本服务接口
+// 每次嘉奖/申诫变化都记入角色账本，查询时附带计数核对结果
+type LedgerService interface {
+	// 查询角色的绩效账本（按时间顺序）
+	GetLedger(agentID string, filter *domain.LedgerFilter) (*AgentLedger, error)
+}
+
+// AgentLedger 角色绩效账本查询结果
+type AgentLedger struct {
+	AgentID        string                       `json:"agent_id"`
+	Entries        []*domain.LedgerEntry        `json:"entries"`
+	Count          int                          `json:"count"`
+	Reconciliation *domain.LedgerReconciliation `json:"reconciliation"` // 基于完整账本，不受筛选影响
+}
+
+// ledgerService 绩效账本服务实现
+type ledgerService struct {
+	agentService AgentService
+}
+
+// NewLedgerService 创建绩效账本服务
+func NewLedgerService(agentService AgentService) LedgerService {
+	return &ledgerService{
+		agentService: agentService,
+	}
+}
+
+// GetLedger 查询角色的绩效账本
+func (s *ledgerService) GetLedger(agentID string, filter *domain.LedgerFilter) (*AgentLedger, error) {
+	if filter != nil && filter.Reason != "" && !domain.IsValidLedgerReason(filter.Reason) {
+		return nil, domain.NewGameError(domain.ErrInvalidInput, "无效的原因筛选条件").
+			WithDetails("reason", filter.Reason)
+	}
+
+	agent, err := s.agentService.GetAgent(agentID)
+	if err != nil {
+		return nil, err
+	}
+	assignLedgerIDs(agent)
+
+	entries := make([]*domain.LedgerEntry, 0)
+	for _, entry := range agent.Ledger {
+		if filter.Matches(entry) {
+			entries = append(entries, entry)
+		}
+	}
+
+	return &AgentLedger{
+		AgentID:        agent.ID,
+		Entries:        entries,
+		Count:          len(entries),
+		Reconciliation: agent.ReconcileLedger(),
+	}, nil
+}
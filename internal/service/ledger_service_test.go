@@ -0,0 +1,87 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+)
+
+func TestLedgerService_TracksEveryChange(t *testing.T) {
+	agentService := NewAgentService()
+	ledger := NewLedgerService(agentService)
+	performance := NewPerformanceService()
+
+	agent, err := agentService.CreateAgent(&CreateAgentRequest{
+		Name:        "账本测试",
+		AnomalyType: domain.AnomalyWhisper,
+		RealityType: domain.RealityCaretaker,
+		CareerType:  domain.CareerPublicRelations,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, performance.AwardMissionSuccess(agent, OutcomeCaptured))
+	require.NoError(t, performance.AwardMissionSuccess(agent, OutcomeEscaped))
+	require.NoError(t, agentService.RecordLedger(agent.ID, &domain.LedgerEntry{
+		SessionID:     "session-1",
+		Reason:        domain.LedgerReasonTripleAscension,
+		Commendations: 2,
+	}))
+	require.NoError(t, NewDamageService().HandleDeath(agent, "测试死亡", "", 0))
+	_, err = NewDeathService(agentService, nil, nil).Revive(agent.ID)
+	require.NoError(t, err)
+
+	result, err := ledger.GetLedger(agent.ID, nil)
+	require.NoError(t, err)
+	require.Equal(t, 4, result.Count)
+	assert.Equal(t, domain.LedgerReasonCaptureBonus, result.Entries[0].Reason)
+	assert.Equal(t, domain.LedgerReasonEscapePenalty, result.Entries[1].Reason)
+	assert.Equal(t, domain.LedgerReasonTripleAscension, result.Entries[2].Reason)
+	assert.Equal(t, domain.LedgerReasonDeath, result.Entries[3].Reason)
+	assert.Equal(t, -5, result.Entries[3].Commendations)
+	for _, entry := range result.Entries {
+		assert.NotEmpty(t, entry.ID)
+		assert.Equal(t, agent.ID, entry.AgentID)
+	}
+
+	assert.True(t, result.Reconciliation.Balanced)
+	assert.Equal(t, 0, result.Reconciliation.Commendations)
+	assert.Equal(t, 3, result.Reconciliation.Reprimands)
+
+	// 筛选不影响核对结果
+	result, err = ledger.GetLedger(agent.ID, &domain.LedgerFilter{SessionID: "session-1"})
+	require.NoError(t, err)
+	require.Equal(t, 1, result.Count)
+	assert.Equal(t, 0, result.Reconciliation.LedgerCommendations)
+
+	_, err = ledger.GetLedger(agent.ID, &domain.LedgerFilter{Reason: "bribe"})
+	require.Error(t, err)
+	assert.Equal(t, domain.ErrInvalidInput, err.(*domain.GameError).Code)
+
+	_, err = ledger.GetLedger("missing", nil)
+	require.Error(t, err)
+	assert.Equal(t, domain.ErrNotFound, err.(*domain.GameError).Code)
+}
+
+func TestPerformanceService_Record(t *testing.T) {
+	performance := NewPerformanceService()
+	agent := &domain.Agent{ID: "agent-1"}
+
+	err := performance.Record(agent, &domain.LedgerEntry{Reason: "bribe", Commendations: 1})
+	require.Error(t, err)
+
+	err = performance.Record(agent, &domain.LedgerEntry{Reason: domain.LedgerReasonAdjustment, Reprimands: -1})
+	require.Error(t, err)
+
+	err = performance.Record(agent, &domain.LedgerEntry{Reason: domain.LedgerReasonAdjustment})
+	require.Error(t, err)
+	assert.Empty(t, agent.Ledger)
+
+	require.NoError(t, performance.Record(agent, &domain.LedgerEntry{
+		Reason:        domain.LedgerReasonAdjustment,
+		Commendations: -2,
+	}))
+	assert.True(t, agent.InDebt)
+	assert.Len(t, agent.Ledger, 1)
+}
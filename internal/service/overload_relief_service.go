@@ -0,0 +1,123 @@
+package service
+
+import (
+	"strings"
+	"time"
+
+	"github.com/trpg-solo-engine/backend/internal/domain"
+)
+
+// OverloadReliefService 过载解除服务接口
+// 玩家援引现实的过载解除条件后，在规则集配置的范围内掷骰不再应用过载
+type OverloadReliefService interface {
+	// 申请过载解除
+	ClaimRelief(sessionID string, req *ClaimReliefRequest) (*domain.OverloadReliefClaim, error)
+
+	// 查询当前生效的过载解除，没有时返回nil
+	GetRelief(sessionID string) (*domain.OverloadReliefClaim, error)
+}
+
+// ClaimReliefRequest 申请过载解除请求
+type ClaimReliefRequest struct {
+	Condition     string `json:"condition" binding:"required"`     // 援引的过载解除条件
+	Justification string `json:"justification" binding:"required"` // 如何满足了该条件
+}
+
+// overloadReliefService 过载解除服务实现
+type overloadReliefService struct {
+	agentService AgentService
+	gameService  GameService
+	rules        *domain.Ruleset
+}
+
+// NewOverloadReliefService 创建过载解除服务
+// 会话没有自身规则集时使用rules决定持续范围
+func NewOverloadReliefService(agentService AgentService, gameService GameService, rules *domain.Ruleset) OverloadReliefService {
+	if rules == nil {
+		rules = domain.DefaultRuleset()
+	}
+
+	return &overloadReliefService{
+		agentService: agentService,
+		gameService:  gameService,
+		rules:        rules,
+	}
+}
+
+// ClaimRelief 申请过载解除
+func (s *overloadReliefService) ClaimRelief(sessionID string, req *ClaimReliefRequest) (*domain.OverloadReliefClaim, error) {
+	session, err := s.gameService.GetSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	agent, err := s.agentService.GetAgent(session.AgentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if agent.Reality == nil || agent.Reality.OverloadRelief == nil {
+		return nil, domain.NewGameError(domain.ErrInvalidState, "角色的现实没有过载解除").
+			WithDetails("agent_id", agent.ID)
+	}
+
+	relief := agent.Reality.OverloadRelief
+	if strings.TrimSpace(req.Condition) != relief.Condition {
+		return nil, domain.NewGameError(domain.ErrInvalidAction, "援引的条件与现实的过载解除条件不符").
+			WithDetails("condition", req.Condition).
+			WithDetails("expected", relief.Condition)
+	}
+
+	if strings.TrimSpace(req.Justification) == "" {
+		return nil, domain.NewGameError(domain.ErrInvalidInput, "必须说明如何满足了过载解除条件")
+	}
+
+	if active := session.ActiveOverloadRelief(agent); active != nil {
+		return nil, domain.NewGameError(domain.ErrInvalidState, "过载解除已经生效").
+			WithDetails("scope", active.Scope)
+	}
+
+	rules := s.rules
+	if session.Rules != nil {
+		rules = session.Rules
+	}
+
+	// 重复援引同一条件不能反复获得过载解除
+	if session.State.OverloadReliefClaimed(rules.OverloadReliefScope) {
+		return nil, domain.NewGameError(domain.ErrInvalidState, "本范围内已经申请过过载解除").
+			WithDetails("scope", rules.OverloadReliefScope).
+			WithDetails("scene_id", session.State.CurrentSceneID)
+	}
+
+	claim := &domain.OverloadReliefClaim{
+		AgentID:       agent.ID,
+		Name:          relief.Name,
+		Condition:     relief.Condition,
+		Justification: req.Justification,
+		Scope:         rules.OverloadReliefScope,
+		SceneID:       session.State.CurrentSceneID,
+		ClaimedAt:     time.Now(),
+	}
+	session.State.ClaimOverloadRelief(claim)
+
+	if err := s.gameService.SaveSession(session); err != nil {
+		return nil, err
+	}
+
+	return claim, nil
+}
+
+// GetRelief 查询当前生效的过载解除
+func (s *overloadReliefService) GetRelief(sessionID string) (*domain.OverloadReliefClaim, error) {
+	session, err := s.gameService.GetSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	agent, err := s.agentService.GetAgent(session.AgentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return session.ActiveOverloadRelief(agent), nil
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+)
+
+func setupOverloadReliefTest(t *testing.T) (OverloadReliefService, GameService, *domain.Agent, *domain.GameSession) {
+	agentService := NewAgentService()
+	agent, err := agentService.CreateAgent(&CreateAgentRequest{
+		Name:        "测试特工",
+		AnomalyType: domain.AnomalyWhisper,
+		RealityType: domain.RealityCaretaker,
+		CareerType:  domain.CareerPublicRelations,
+	})
+	require.NoError(t, err)
+
+	gameService := NewGameService()
+	session, err := gameService.CreateSession(agent.ID, "test-scenario")
+	require.NoError(t, err)
+	session.State.CurrentSceneID = "scene-1"
+
+	svc := NewOverloadReliefService(agentService, gameService, nil)
+	return svc, gameService, agent, session
+}
+
+func TestOverloadReliefService_ClaimRelief(t *testing.T) {
+	t.Run("援引现实条件后生效", func(t *testing.T) {
+		svc, gameService, agent, session := setupOverloadReliefTest(t)
+
+		claim, err := svc.ClaimRelief(session.ID, &ClaimReliefRequest{
+			Condition:     agent.Reality.OverloadRelief.Condition,
+			Justification: "给受照料者做了最爱吃的菜",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, domain.OverloadReliefScopeScene, claim.Scope)
+		assert.Equal(t, "scene-1", claim.SceneID)
+
+		active, err := svc.GetRelief(session.ID)
+		require.NoError(t, err)
+		require.NotNil(t, active)
+
+		stored, _ := gameService.GetSession(session.ID)
+		assert.Equal(t, claim, stored.State.OverloadRelief)
+	})
+
+	t.Run("条件不符时拒绝", func(t *testing.T) {
+		svc, _, _, session := setupOverloadReliefTest(t)
+
+		_, err := svc.ClaimRelief(session.ID, &ClaimReliefRequest{
+			Condition:     "随便做点什么",
+			Justification: "说明",
+		})
+		require.Error(t, err)
+		assert.Equal(t, domain.ErrInvalidAction, err.(*domain.GameError).Code)
+
+		active, err := svc.GetRelief(session.ID)
+		require.NoError(t, err)
+		assert.Nil(t, active)
+	})
+
+	t.Run("已生效时不能重复申请", func(t *testing.T) {
+		svc, _, agent, session := setupOverloadReliefTest(t)
+		req := &ClaimReliefRequest{
+			Condition:     agent.Reality.OverloadRelief.Condition,
+			Justification: "说明",
+		}
+
+		_, err := svc.ClaimRelief(session.ID, req)
+		require.NoError(t, err)
+
+		_, err = svc.ClaimRelief(session.ID, req)
+		require.Error(t, err)
+		assert.Equal(t, domain.ErrInvalidState, err.(*domain.GameError).Code)
+	})
+
+	t.Run("会话规则集决定持续范围", func(t *testing.T) {
+		svc, _, agent, session := setupOverloadReliefTest(t)
+		session.Rules = domain.DefaultRuleset()
+		session.Rules.OverloadReliefScope = domain.OverloadReliefScopeRoll
+
+		claim, err := svc.ClaimRelief(session.ID, &ClaimReliefRequest{
+			Condition:     agent.Reality.OverloadRelief.Condition,
+			Justification: "说明",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, domain.OverloadReliefScopeRoll, claim.Scope)
+	})
+
+	t.Run("每个场景只能申请一次", func(t *testing.T) {
+		svc, _, agent, session := setupOverloadReliefTest(t)
+		session.Rules = domain.DefaultRuleset()
+		session.Rules.OverloadReliefScope = domain.OverloadReliefScopeRoll
+		req := &ClaimReliefRequest{
+			Condition:     agent.Reality.OverloadRelief.Condition,
+			Justification: "说明",
+		}
+
+		_, err := svc.ClaimRelief(session.ID, req)
+		require.NoError(t, err)
+		require.True(t, session.ConsumeOverloadRelief(agent))
+
+		// 用掉后重新提交条件不能再次获得
+		_, err = svc.ClaimRelief(session.ID, req)
+		require.Error(t, err)
+		assert.Equal(t, domain.ErrInvalidState, err.(*domain.GameError).Code)
+
+		// 进入新场景后可以再次申请
+		session.State.EnterScene("scene-2")
+		_, err = svc.ClaimRelief(session.ID, req)
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,281 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+)
+
+// DefaultPendingRollTTL 待确认掷骰的默认有效期
+const DefaultPendingRollTTL = 5 * time.Minute
+
+// PendingRollService 待确认掷骰服务接口
+// 掷骰后先返回令牌，玩家查看骰子后再决定是否花费QA调整
+type PendingRollService interface {
+	// 创建待确认掷骰，返回带令牌的记录
+	// 同一角色或会话已有未结算的掷骰时拒绝，玩家不能同时保留多个掷骰择优提交
+	CreatePendingRoll(pending *PendingRoll) (*PendingRoll, error)
+
+	// 提交具体的骰子调整（此时才扣除QA），结算成功后令牌随即失效
+	CommitRoll(token string, adjustments []DiceAdjustment, settle SettleFunc) (*PendingRoll, error)
+
+	// 放弃调整，按原始结果结算，结算成功后令牌随即失效
+	DeclineRoll(token string, settle SettleFunc) (*PendingRoll, error)
+
+	// 查询和重掷（重掷后令牌仍然有效）
+	GetPendingRoll(token string) (*PendingRoll, error)
+	RerollRoll(token string, dice domain.DiceService) (*PendingRoll, error)
+
+	// 检查角色和会话没有未结算的待确认掷骰，供掷骰前预先检查
+	CheckNoOpenRoll(agentID, sessionID string) error
+
+	// 按放弃调整结算所有过期令牌
+	SettleExpired()
+}
+
+// PendingRoll 待确认的掷骰
+type PendingRoll struct {
+	Token     string             `json:"roll_token"`
+	AgentID   string             `json:"agent_id"`
+	SessionID string             `json:"session_id,omitempty"`
+	Kind      domain.RollKind    `json:"kind"`
+	Quality   string             `json:"quality"`
+	AbilityID string             `json:"ability_id,omitempty"`
+	Request   string             `json:"request,omitempty"`
+	Roll      *domain.RollResult `json:"roll"`
+	QASpent   int                `json:"qa_spent"`
+	ExpiresAt time.Time          `json:"expires_at"`
+	Rules     *domain.Ruleset    `json:"-"` // 掷骰时使用的规则集，提交调整时按其校验
+	Expire    SettleFunc         `json:"-"` // 令牌过期时按放弃调整结算的回调，与放弃时的结算相同
+
+	TripleAscension *domain.TripleAscension `json:"triple_ascension,omitempty"` // 结算时触发的三重升华
+}
+
+// SettleFunc 待确认掷骰的结算回调（三重升华、写入账本等），在令牌失效前调用
+// 返回错误时已扣除的QA会被退还，令牌保持有效，玩家可以重试
+type SettleFunc func(settled *PendingRoll) error
+
+// pendingRollService 待确认掷骰服务实现（令牌仅保存在内存中）
+type pendingRollService struct {
+	pending      map[string]*PendingRoll
+	agentService AgentService
+	qaService    QAService
+	ttl          time.Duration
+	now          func() time.Time
+	mu           sync.Mutex
+}
+
+// NewPendingRollService 创建待确认掷骰服务
+func NewPendingRollService(agentService AgentService, qaService QAService, ttl time.Duration) PendingRollService {
+	if ttl <= 0 {
+		ttl = DefaultPendingRollTTL
+	}
+
+	return &pendingRollService{
+		pending:      make(map[string]*PendingRoll),
+		agentService: agentService,
+		qaService:    qaService,
+		ttl:          ttl,
+		now:          time.Now,
+	}
+}
+
+// CreatePendingRoll 创建待确认掷骰
+func (s *pendingRollService) CreatePendingRoll(pending *PendingRoll) (*PendingRoll, error) {
+	if pending == nil || pending.Roll == nil || pending.AgentID == "" {
+		return nil, domain.NewGameError(domain.ErrInvalidInput, "待确认掷骰缺少必要信息")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.purgeExpired()
+
+	if err := s.checkNoOpenRoll(pending.AgentID, pending.SessionID); err != nil {
+		return nil, err
+	}
+
+	pending.Token = uuid.New().String()
+	pending.QASpent = 0
+	pending.ExpiresAt = s.now().Add(s.ttl)
+	s.pending[pending.Token] = pending
+
+	return pending, nil
+}
+
+// CommitRoll 提交骰子调整
+// 调整校验失败、QA不足或结算失败时令牌保持有效，玩家可以重新提交
+func (s *pendingRollService) CommitRoll(token string, adjustments []DiceAdjustment, settle SettleFunc) (*PendingRoll, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pending, err := s.lookup(token)
+	if err != nil {
+		return nil, err
+	}
+
+	// 在副本上结算，失败时令牌中的原始掷骰不受影响
+	settled := *pending
+	if len(adjustments) > 0 {
+		if _, err := s.agentService.ModifyAgent(pending.AgentID, func(agent *domain.Agent) error {
+			adjusted, err := s.qaService.WithRuleset(pending.Rules).AdjustDiceWithQA(agent, pending.Quality, pending.Roll, adjustments)
+			if err != nil {
+				return err
+			}
+			settled.Roll = adjusted
+			settled.QASpent = len(adjustments)
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.settle(&settled, settle); err != nil {
+		return nil, err
+	}
+
+	delete(s.pending, token)
+
+	return &settled, nil
+}
+
+// DeclineRoll 放弃调整
+func (s *pendingRollService) DeclineRoll(token string, settle SettleFunc) (*PendingRoll, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pending, err := s.lookup(token)
+	if err != nil {
+		return nil, err
+	}
+
+	settled := *pending
+	if err := s.settle(&settled, settle); err != nil {
+		return nil, err
+	}
+
+	delete(s.pending, token)
+
+	return &settled, nil
+}
+
+// settle 执行结算回调，失败时退还已扣除的QA（调用方需持有锁）
+func (s *pendingRollService) settle(settled *PendingRoll, settle SettleFunc) error {
+	if settle == nil {
+		return nil
+	}
+
+	err := settle(settled)
+	if err == nil || settled.QASpent == 0 {
+		return err
+	}
+
+	if _, refundErr := s.agentService.ModifyAgent(settled.AgentID, func(agent *domain.Agent) error {
+		agent.QA[settled.Quality] += settled.QASpent
+		return nil
+	}); refundErr != nil {
+		return fmt.Errorf("%w（退还资质保证失败: %v）", err, refundErr)
+	}
+
+	return err
+}
+
+// GetPendingRoll 查询待确认掷骰
+func (s *pendingRollService) GetPendingRoll(token string) (*PendingRoll, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.lookup(token)
+}
+
+// RerollRoll 使用给定骰子服务重掷，骰子数量和过载与原掷骰相同
+func (s *pendingRollService) RerollRoll(token string, dice domain.DiceService) (*PendingRoll, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pending, err := s.lookup(token)
+	if err != nil {
+		return nil, err
+	}
+
+	roll := dice.Roll(len(pending.Roll.Dice))
+	if pending.Roll.Overload > 0 {
+		roll = dice.ApplyOverload(roll, pending.Roll.Overload)
+	}
+	pending.Roll = roll
+
+	return pending, nil
+}
+
+// CheckNoOpenRoll 检查角色和会话没有未结算的待确认掷骰
+func (s *pendingRollService) CheckNoOpenRoll(agentID, sessionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.purgeExpired()
+	return s.checkNoOpenRoll(agentID, sessionID)
+}
+
+// checkNoOpenRoll 检查角色和会话没有未结算的待确认掷骰（调用方需持有锁）
+func (s *pendingRollService) checkNoOpenRoll(agentID, sessionID string) error {
+	for _, open := range s.pending {
+		if open.AgentID == agentID || (sessionID != "" && open.SessionID == sessionID) {
+			return domain.NewGameError(domain.ErrInvalidState, "已有未结算的待确认掷骰，请先提交或放弃").
+				WithDetails("roll_token", open.Token).
+				WithDetails("expires_at", open.ExpiresAt)
+		}
+	}
+
+	return nil
+}
+
+// SettleExpired 按放弃调整结算所有过期令牌
+func (s *pendingRollService) SettleExpired() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.purgeExpired()
+}
+
+// lookup 查找有效令牌，过期令牌按放弃调整结算（调用方需持有锁）
+func (s *pendingRollService) lookup(token string) (*PendingRoll, error) {
+	pending, exists := s.pending[token]
+	if !exists {
+		return nil, domain.NewGameError(domain.ErrNotFound, "掷骰令牌不存在或已使用").
+			WithDetails("roll_token", token)
+	}
+
+	if !s.now().Before(pending.ExpiresAt) {
+		_ = s.expire(token, pending)
+		return nil, domain.NewGameError(domain.ErrInvalidState, "掷骰令牌已过期，已按放弃调整结算").
+			WithDetails("roll_token", token).
+			WithDetails("expired_at", pending.ExpiresAt)
+	}
+
+	return pending, nil
+}
+
+// purgeExpired 按放弃调整结算过期令牌（调用方需持有锁）
+func (s *pendingRollService) purgeExpired() {
+	now := s.now()
+	for token, pending := range s.pending {
+		if !now.Before(pending.ExpiresAt) {
+			_ = s.expire(token, pending)
+		}
+	}
+}
+
+// expire 按放弃调整结算过期令牌并移除（调用方需持有锁）
+// 结算失败时保留令牌，下次清理时重试，过期的掷骰不会绕过账本
+func (s *pendingRollService) expire(token string, pending *PendingRoll) error {
+	settled := *pending
+	if err := s.settle(&settled, pending.Expire); err != nil {
+		return err
+	}
+
+	delete(s.pending, token)
+	return nil
+}
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+)
+
+func setupPendingRollTest(t *testing.T) (*pendingRollService, AgentService, *domain.Agent) {
+	agentService := NewAgentService()
+	agent, err := agentService.CreateAgent(&CreateAgentRequest{
+		Name:        "测试特工",
+		AnomalyType: domain.AnomalyWhisper,
+		RealityType: domain.RealityCaretaker,
+		CareerType:  domain.CareerPublicRelations,
+	})
+	require.NoError(t, err)
+
+	svc := NewPendingRollService(agentService, NewQAService(domain.NewDiceService()), time.Minute)
+	return svc.(*pendingRollService), agentService, agent
+}
+
+func newTestPendingRoll(agentID string) *PendingRoll {
+	return &PendingRoll{
+		AgentID: agentID,
+		Kind:    domain.RollKindRequest,
+		Quality: domain.QualityFocus,
+		Roll: &domain.RollResult{
+			RawDice: []int{1, 2, 4, 1, 4, 2},
+			Dice:    []int{1, 2, 4, 1, 4, 2},
+			Chaos:   6,
+		},
+	}
+}
+
+func TestPendingRollService_Commit(t *testing.T) {
+	t.Run("提交调整时才扣除QA", func(t *testing.T) {
+		svc, agentService, agent := setupPendingRollTest(t)
+		initialQA := agent.QA[domain.QualityFocus]
+
+		pending, err := svc.CreatePendingRoll(newTestPendingRoll(agent.ID))
+		require.NoError(t, err)
+		assert.NotEmpty(t, pending.Token)
+
+		stored, _ := agentService.GetAgent(agent.ID)
+		assert.Equal(t, initialQA, stored.QA[domain.QualityFocus])
+
+		committed, err := svc.CommitRoll(pending.Token, []DiceAdjustment{{DiceIndex: 2, NewValue: 3}}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []int{1, 2, 3, 1, 4, 2}, committed.Roll.Dice)
+		assert.Equal(t, []int{1, 2, 4, 1, 4, 2}, committed.Roll.RawDice)
+		assert.True(t, committed.Roll.Success)
+		assert.Equal(t, 1, committed.QASpent)
+
+		stored, _ = agentService.GetAgent(agent.ID)
+		assert.Equal(t, initialQA-1, stored.QA[domain.QualityFocus])
+	})
+
+	t.Run("令牌只能使用一次", func(t *testing.T) {
+		svc, _, agent := setupPendingRollTest(t)
+
+		pending, err := svc.CreatePendingRoll(newTestPendingRoll(agent.ID))
+		require.NoError(t, err)
+
+		_, err = svc.CommitRoll(pending.Token, nil, nil)
+		require.NoError(t, err)
+
+		_, err = svc.CommitRoll(pending.Token, nil, nil)
+		require.Error(t, err)
+		assert.Equal(t, domain.ErrNotFound, err.(*domain.GameError).Code)
+
+		_, err = svc.DeclineRoll(pending.Token, nil)
+		require.Error(t, err)
+	})
+
+	t.Run("无效调整不消耗令牌", func(t *testing.T) {
+		svc, _, agent := setupPendingRollTest(t)
+
+		pending, err := svc.CreatePendingRoll(newTestPendingRoll(agent.ID))
+		require.NoError(t, err)
+
+		_, err = svc.CommitRoll(pending.Token, []DiceAdjustment{{DiceIndex: 9, NewValue: 3}}, nil)
+		require.Error(t, err)
+		assert.Equal(t, domain.ErrInvalidInput, err.(*domain.GameError).Code)
+
+		_, err = svc.CommitRoll(pending.Token, []DiceAdjustment{{DiceIndex: 0, NewValue: 3}}, nil)
+		assert.NoError(t, err)
+	})
+
+	t.Run("结算失败时退还QA且令牌保持有效", func(t *testing.T) {
+		svc, agentService, agent := setupPendingRollTest(t)
+		initialQA := agent.QA[domain.QualityFocus]
+
+		pending, err := svc.CreatePendingRoll(newTestPendingRoll(agent.ID))
+		require.NoError(t, err)
+
+		failing := func(settled *PendingRoll) error {
+			assert.Equal(t, 1, settled.QASpent)
+			return domain.NewGameError(domain.ErrInternal, "账本写入失败")
+		}
+		_, err = svc.CommitRoll(pending.Token, []DiceAdjustment{{DiceIndex: 2, NewValue: 3}}, failing)
+		require.Error(t, err)
+
+		stored, _ := agentService.GetAgent(agent.ID)
+		assert.Equal(t, initialQA, stored.QA[domain.QualityFocus])
+
+		// 原始掷骰不受失败的提交影响，可以重新提交
+		var recorded *PendingRoll
+		committed, err := svc.CommitRoll(pending.Token, []DiceAdjustment{{DiceIndex: 2, NewValue: 3}}, func(settled *PendingRoll) error {
+			recorded = settled
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Same(t, recorded, committed)
+		assert.Equal(t, []int{1, 2, 3, 1, 4, 2}, committed.Roll.Dice)
+
+		stored, _ = agentService.GetAgent(agent.ID)
+		assert.Equal(t, initialQA-1, stored.QA[domain.QualityFocus])
+	})
+
+	t.Run("过期令牌被拒绝", func(t *testing.T) {
+		svc, agentService, agent := setupPendingRollTest(t)
+		initialQA := agent.QA[domain.QualityFocus]
+
+		pending, err := svc.CreatePendingRoll(newTestPendingRoll(agent.ID))
+		require.NoError(t, err)
+
+		svc.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+
+		_, err = svc.CommitRoll(pending.Token, []DiceAdjustment{{DiceIndex: 0, NewValue: 3}}, nil)
+		require.Error(t, err)
+		assert.Equal(t, domain.ErrInvalidState, err.(*domain.GameError).Code)
+
+		stored, _ := agentService.GetAgent(agent.ID)
+		assert.Equal(t, initialQA, stored.QA[domain.QualityFocus])
+	})
+
+	t.Run("同一角色只能保留一个待确认掷骰", func(t *testing.T) {
+		svc, _, agent := setupPendingRollTest(t)
+
+		first, err := svc.CreatePendingRoll(newTestPendingRoll(agent.ID))
+		require.NoError(t, err)
+
+		_, err = svc.CreatePendingRoll(newTestPendingRoll(agent.ID))
+		require.Error(t, err)
+		assert.Equal(t, domain.ErrInvalidState, err.(*domain.GameError).Code)
+		assert.Error(t, svc.CheckNoOpenRoll(agent.ID, ""))
+
+		_, err = svc.DeclineRoll(first.Token, nil)
+		require.NoError(t, err)
+
+		_, err = svc.CreatePendingRoll(newTestPendingRoll(agent.ID))
+		assert.NoError(t, err)
+	})
+}
+
+func TestPendingRollService_Decline(t *testing.T) {
+	svc, agentService, agent := setupPendingRollTest(t)
+	initialQA := agent.QA[domain.QualityFocus]
+
+	pending, err := svc.CreatePendingRoll(newTestPendingRoll(agent.ID))
+	require.NoError(t, err)
+
+	declined, err := svc.DeclineRoll(pending.Token, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 4, 1, 4, 2}, declined.Roll.Dice)
+	assert.Equal(t, 0, declined.QASpent)
+
+	stored, _ := agentService.GetAgent(agent.ID)
+	assert.Equal(t, initialQA, stored.QA[domain.QualityFocus])
+
+	_, err = svc.CommitRoll(pending.Token, nil, nil)
+	assert.Error(t, err)
+}
+
+func TestPendingRollService_Expire(t *testing.T) {
+	t.Run("过期令牌按放弃调整结算", func(t *testing.T) {
+		svc, _, agent := setupPendingRollTest(t)
+
+		var expired []*PendingRoll
+		roll := newTestPendingRoll(agent.ID)
+		roll.Expire = func(settled *PendingRoll) error {
+			expired = append(expired, settled)
+			return nil
+		}
+
+		pending, err := svc.CreatePendingRoll(roll)
+		require.NoError(t, err)
+
+		svc.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+		svc.SettleExpired()
+
+		require.Len(t, expired, 1)
+		assert.Equal(t, pending.Token, expired[0].Token)
+		assert.Equal(t, 0, expired[0].QASpent)
+		assert.Equal(t, []int{1, 2, 4, 1, 4, 2}, expired[0].Roll.Dice)
+
+		_, err = svc.GetPendingRoll(pending.Token)
+		assert.Error(t, err)
+
+		// 过期后可以再次掷骰
+		assert.NoError(t, svc.CheckNoOpenRoll(agent.ID, ""))
+	})
+
+	t.Run("结算失败时保留令牌等待重试", func(t *testing.T) {
+		svc, _, agent := setupPendingRollTest(t)
+
+		attempts := 0
+		roll := newTestPendingRoll(agent.ID)
+		roll.Expire = func(settled *PendingRoll) error {
+			attempts++
+			if attempts == 1 {
+				return assert.AnError
+			}
+			return nil
+		}
+
+		pending, err := svc.CreatePendingRoll(roll)
+		require.NoError(t, err)
+
+		svc.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+		svc.SettleExpired()
+		assert.Equal(t, 1, attempts)
+		assert.Contains(t, svc.pending, pending.Token)
+
+		svc.SettleExpired()
+		assert.Equal(t, 2, attempts)
+		assert.NotContains(t, svc.pending, pending.Token)
+	})
+}
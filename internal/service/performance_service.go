@@ -28,13 +28,17 @@ type PerformanceService interface {
 	// 负债检查
 	CheckDebt(agent *domain.Agent) bool
 	UpdateDebtStatus(agent *domain.Agent) error
+
+	// 带原因的账本记录
+	Record(agent *domain.Agent, entry *domain.LedgerEntry) error
 }
 
 // MissionOutcome 任务结果常量
 const (
-	OutcomeCaptured    = "已捕获" // 捕获异常体
-	OutcomeNeutralized = "已中和" // 中和异常体
-	OutcomeEscaped     = "已逃脱" // 异常体逃脱
+	OutcomeCaptured    = "已捕获"   // 捕获异常体
+	OutcomeNeutralized = "已中和"   // 中和异常体
+	OutcomeEscaped     = "已逃脱"   // 异常体逃脱
+	OutcomeDeal        = "已达成协议" // 与异常体达成协议
 )
 
 // performanceService 绩效服务实现
@@ -98,8 +102,8 @@ func (s *performanceService) AwardMissionSuccess(agent *domain.Agent, outcome st
 	switch outcome {
 	case OutcomeCaptured:
 		return s.AwardCaptureBonus(agent)
-	case OutcomeNeutralized:
-		// 中和异常体无奖惩
+	case OutcomeNeutralized, OutcomeDeal:
+		// 中和异常体或达成协议无奖惩
 		return nil
 	case OutcomeEscaped:
 		return s.AwardEscapePenalty(agent)
@@ -111,7 +115,11 @@ func (s *performanceService) AwardMissionSuccess(agent *domain.Agent, outcome st
 
 // AwardCaptureBonus 捕获异常体奖励（3次嘉奖）
 func (s *performanceService) AwardCaptureBonus(agent *domain.Agent) error {
-	return s.AddCommendations(agent, 3)
+	return s.Record(agent, &domain.LedgerEntry{
+		Reason:        domain.LedgerReasonCaptureBonus,
+		Justification: "任务结果: " + OutcomeCaptured,
+		Commendations: 3,
+	})
 }
 
 // AwardNeutralizationPenalty 中和异常体（无奖惩）
@@ -122,7 +130,11 @@ func (s *performanceService) AwardNeutralizationPenalty(agent *domain.Agent) err
 
 // AwardEscapePenalty 异常体逃脱惩罚（3次申诫）
 func (s *performanceService) AwardEscapePenalty(agent *domain.Agent) error {
-	return s.AddReprimands(agent, 3)
+	return s.Record(agent, &domain.LedgerEntry{
+		Reason:        domain.LedgerReasonEscapePenalty,
+		Justification: "任务结果: " + OutcomeEscaped,
+		Reprimands:    3,
+	})
 }
 
 // CheckDebt 检查是否处于负债状态
@@ -135,3 +147,26 @@ func (s *performanceService) UpdateDebtStatus(agent *domain.Agent) error {
 	agent.InDebt = s.CheckDebt(agent)
 	return nil
 }
+
+// Record 按原因记录嘉奖/申诫变化，并更新评级和负债状态
+// 申诫只能增加；嘉奖可以为负，用于花费或扣除
+func (s *performanceService) Record(agent *domain.Agent, entry *domain.LedgerEntry) error {
+	if !domain.IsValidLedgerReason(entry.Reason) {
+		return domain.NewGameError(domain.ErrInvalidInput, "无效的账本原因").
+			WithDetails("reason", entry.Reason)
+	}
+	if entry.Reprimands < 0 {
+		return domain.NewGameError(domain.ErrInvalidInput, "申诫数量不能为负数").
+			WithDetails("amount", entry.Reprimands)
+	}
+	if entry.Commendations == 0 && entry.Reprimands == 0 {
+		return domain.NewGameError(domain.ErrInvalidInput, "账本条目没有任何变化")
+	}
+
+	agent.RecordLedger(entry)
+
+	if err := s.UpdateRating(agent); err != nil {
+		return err
+	}
+	return s.UpdateDebtStatus(agent)
+}
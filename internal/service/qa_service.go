@@ -20,17 +20,24 @@ type QAService interface {
 
 	// 骰子调整
 	AdjustDiceWithQA(agent *domain.Agent, quality string, roll *domain.RollResult, adjustments []DiceAdjustment) (*domain.RollResult, error)
+
+	// 规则集
+	WithRuleset(rules *domain.Ruleset) QAService
+
+	// ForSession 返回绑定会话的资质保证服务，过载判定会使用会话中的过载解除
+	ForSession(session *domain.GameSession) QAService
 }
 
 // DiceAdjustment 骰子调整
 type DiceAdjustment struct {
-	DiceIndex int // 要调整的骰子索引
-	NewValue  int // 新值（1-4）
+	DiceIndex int `json:"dice_index"` // 要调整的骰子索引
+	NewValue  int `json:"new_value"`  // 新值（1-骰子面数）
 }
 
 // qaService 资质保证服务实现
 type qaService struct {
 	diceService domain.DiceService
+	session     *domain.GameSession
 }
 
 // NewQAService 创建资质保证服务
@@ -77,8 +84,9 @@ func (s *qaService) ApplyOverload(agent *domain.Agent, quality string, roll *dom
 		return roll
 	}
 
-	// 检查是否有过载解除
-	if s.CheckOverloadRelief(agent) {
+	// 检查是否有过载解除，解除时保留过载前的骰子
+	if s.session != nil && s.session.ConsumeOverloadRelief(agent) {
+		roll.OverloadRelieved++
 		return roll
 	}
 
@@ -86,41 +94,75 @@ func (s *qaService) ApplyOverload(agent *domain.Agent, quality string, roll *dom
 	return s.diceService.ApplyOverload(roll, 1)
 }
 
-// CheckOverloadRelief 检查过载解除条件
+// CheckOverloadRelief 检查过载解除是否生效
+// 玩家在会话中援引现实的过载解除条件后生效（见 OverloadReliefService），
+// 与骰子服务使用同一判定（GameSession.ActiveOverloadRelief）；未绑定会话时没有可用的过载解除
 func (s *qaService) CheckOverloadRelief(agent *domain.Agent) bool {
-	// 这里简化实现，实际应该根据现实类型的过载解除条件判断
-	// 不同的现实类型有不同的过载解除条件
-	if agent.Reality == nil || agent.Reality.OverloadRelief == nil {
+	if s.session == nil {
 		return false
 	}
 
-	// 实际游戏中，这里应该检查具体的条件
-	// 例如："当你为他人承担风险时"、"当你完成一个重要的个人目标时"等
-	// 这需要游戏状态的上下文信息
-	// 目前返回false，表示没有激活过载解除
-	return false
+	return s.session.ActiveOverloadRelief(agent) != nil
 }
 
 // ClearOverload 清除过载效果（当过载解除条件满足时）
+// 过载只修改Dice，RawDice保留着过载前的骰子，据此恢复并重新结算
 func (s *qaService) ClearOverload(roll *domain.RollResult) *domain.RollResult {
-	// 创建新的结果，清除过载
+	if roll.Overload == 0 {
+		return roll
+	}
+
+	rules := s.diceService.Ruleset()
+
 	newRoll := &domain.RollResult{
-		Dice:      make([]int, len(roll.Dice)),
-		Threes:    roll.Threes,
-		Success:   roll.Success,
-		Chaos:     roll.Chaos,
-		Overload:  0, // 清除过载
-		TripleAsc: roll.TripleAsc,
+		RawDice:          roll.RawDice,
+		Dice:             make([]int, len(roll.RawDice)),
+		Overload:         0, // 清除过载
+		TripleAsc:        roll.TripleAsc,
+		OverloadRelieved: roll.OverloadRelieved + roll.Overload,
+	}
+	copy(newRoll.Dice, roll.RawDice)
+
+	newRoll.Threes = rules.CountSuccesses(newRoll.Dice)
+	newRoll.Success = newRoll.Threes > 0
+	if !newRoll.Success && !newRoll.TripleAsc {
+		newRoll.Chaos = len(newRoll.Dice) - newRoll.Threes
 	}
-	copy(newRoll.Dice, roll.Dice)
 
-	// 如果之前有过载效果，需要恢复
-	// 这里简化处理，实际应该记录过载前的状态
 	return newRoll
 }
 
+// WithRuleset 返回使用指定规则集的资质保证服务
+func (s *qaService) WithRuleset(rules *domain.Ruleset) QAService {
+	if rules == nil {
+		return s
+	}
+	return &qaService{
+		diceService: s.diceService.WithRuleset(rules),
+		session:     s.session,
+	}
+}
+
+// ForSession 返回绑定会话的资质保证服务
+func (s *qaService) ForSession(session *domain.GameSession) QAService {
+	if session == nil {
+		return s
+	}
+	return &qaService{
+		diceService: s.diceService.ForSession(session),
+		session:     session,
+	}
+}
+
 // AdjustDiceWithQA 使用资质保证调整骰子
 func (s *qaService) AdjustDiceWithQA(agent *domain.Agent, quality string, roll *domain.RollResult, adjustments []DiceAdjustment) (*domain.RollResult, error) {
+	rules := s.diceService.Ruleset()
+
+	// 先校验调整，避免无效调整消耗QA
+	if err := validateAdjustments(rules, roll, adjustments); err != nil {
+		return nil, err
+	}
+
 	// 检查是否有足够的QA
 	if len(adjustments) > agent.QA[quality] {
 		return nil, domain.NewGameError(domain.ErrInsufficientQA, "资质保证不足").
@@ -136,6 +178,7 @@ func (s *qaService) AdjustDiceWithQA(agent *domain.Agent, quality string, roll *
 
 	// 创建新的结果
 	newRoll := &domain.RollResult{
+		RawDice:   roll.RawDice,
 		Dice:      make([]int, len(roll.Dice)),
 		Threes:    0,
 		Success:   false,
@@ -147,21 +190,11 @@ func (s *qaService) AdjustDiceWithQA(agent *domain.Agent, quality string, roll *
 
 	// 应用调整
 	for _, adj := range adjustments {
-		if adj.DiceIndex < 0 || adj.DiceIndex >= len(newRoll.Dice) {
-			continue
-		}
-		if adj.NewValue < 1 || adj.NewValue > 4 {
-			continue
-		}
 		newRoll.Dice[adj.DiceIndex] = adj.NewValue
 	}
 
-	// 重新计算"3"的数量
-	for _, d := range newRoll.Dice {
-		if d == 3 {
-			newRoll.Threes++
-		}
-	}
+	// 重新计算"3"（成功值）的数量
+	newRoll.Threes = rules.CountSuccesses(newRoll.Dice)
 
 	// 重新判定成功
 	newRoll.Success = newRoll.Threes > 0
@@ -180,3 +213,27 @@ func (s *qaService) AdjustDiceWithQA(agent *domain.Agent, quality string, roll *
 
 	return newRoll, nil
 }
+
+// validateAdjustments 校验骰子调整：索引有效、数值在1到面数之间、每颗骰子只调整一次
+func validateAdjustments(rules *domain.Ruleset, roll *domain.RollResult, adjustments []DiceAdjustment) error {
+	seen := make(map[int]bool, len(adjustments))
+	for _, adj := range adjustments {
+		if adj.DiceIndex < 0 || adj.DiceIndex >= len(roll.Dice) {
+			return domain.NewGameError(domain.ErrInvalidInput, "骰子索引超出范围").
+				WithDetails("dice_index", adj.DiceIndex).
+				WithDetails("dice_count", len(roll.Dice))
+		}
+		if !rules.IsValidDieValue(adj.NewValue) {
+			return domain.NewGameError(domain.ErrInvalidInput, "骰子数值超出骰子面数范围").
+				WithDetails("dice_index", adj.DiceIndex).
+				WithDetails("new_value", adj.NewValue).
+				WithDetails("dice_sides", rules.DiceSides)
+		}
+		if seen[adj.DiceIndex] {
+			return domain.NewGameError(domain.ErrInvalidInput, "同一颗骰子不能重复调整").
+				WithDetails("dice_index", adj.DiceIndex)
+		}
+		seen[adj.DiceIndex] = true
+	}
+	return nil
+}
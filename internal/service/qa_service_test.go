@@ -155,6 +155,69 @@ func TestQAService_ApplyOverload(t *testing.T) {
 	})
 }
 
+func TestQAService_OverloadRelief(t *testing.T) {
+	diceService := domain.NewDiceService()
+	qaService := NewQAService(diceService)
+
+	newRoll := func() *domain.RollResult {
+		return &domain.RollResult{
+			RawDice: []int{3, 3, 2, 1, 4, 2},
+			Dice:    []int{3, 3, 2, 1, 4, 2},
+			Threes:  2,
+			Success: true,
+		}
+	}
+
+	t.Run("未绑定会话时没有过载解除", func(t *testing.T) {
+		agent := createTestAgentForUnitTest()
+		assert.False(t, qaService.CheckOverloadRelief(agent))
+	})
+
+	t.Run("会话中生效的过载解除跳过过载", func(t *testing.T) {
+		agent := createTestAgentForUnitTest()
+		agent.QA[domain.QualityFocus] = 0
+		session := &domain.GameSession{
+			AgentID: agent.ID,
+			State: &domain.GameState{
+				OverloadRelief: &domain.OverloadReliefClaim{
+					AgentID: agent.ID,
+					Scope:   domain.OverloadReliefScopeRoll,
+				},
+			},
+		}
+
+		bound := qaService.ForSession(session)
+		assert.True(t, bound.CheckOverloadRelief(agent))
+
+		result := bound.ApplyOverload(agent, domain.QualityFocus, newRoll())
+		assert.Equal(t, []int{3, 3, 2, 1, 4, 2}, result.Dice)
+		assert.Equal(t, 2, result.Threes)
+		assert.Equal(t, 0, result.Overload)
+		assert.Equal(t, 1, result.OverloadRelieved)
+
+		// roll范围的过载解除用过一次后失效
+		assert.False(t, bound.CheckOverloadRelief(agent))
+		result = bound.ApplyOverload(agent, domain.QualityFocus, newRoll())
+		assert.Equal(t, 1, result.Overload)
+	})
+
+	t.Run("清除过载恢复过载前的骰子", func(t *testing.T) {
+		agent := createTestAgentForUnitTest()
+		agent.QA[domain.QualityFocus] = 0
+
+		overloaded := qaService.ApplyOverload(agent, domain.QualityFocus, newRoll())
+		assert.Equal(t, 1, overloaded.Threes)
+
+		cleared := qaService.ClearOverload(overloaded)
+		assert.Equal(t, []int{3, 3, 2, 1, 4, 2}, cleared.Dice)
+		assert.Equal(t, 2, cleared.Threes)
+		assert.True(t, cleared.Success)
+		assert.Equal(t, 0, cleared.Chaos)
+		assert.Equal(t, 0, cleared.Overload)
+		assert.Equal(t, 1, cleared.OverloadRelieved)
+	})
+}
+
 func TestQAService_AdjustDiceWithQA(t *testing.T) {
 	diceService := domain.NewDiceService()
 	qaService := NewQAService(diceService)
@@ -230,6 +293,43 @@ func TestQAService_AdjustDiceWithQA(t *testing.T) {
 		assert.True(t, result.Success)
 		assert.Equal(t, 0, result.Chaos) // 成功时混沌为0
 	})
+
+	t.Run("无效调整不消耗QA", func(t *testing.T) {
+		agent := createTestAgentForUnitTest()
+		agent.QA[domain.QualityFocus] = 3
+
+		roll := &domain.RollResult{Dice: []int{1, 2, 4, 1, 4, 2}, Chaos: 6}
+
+		invalid := [][]DiceAdjustment{
+			{{DiceIndex: 6, NewValue: 3}},
+			{{DiceIndex: 0, NewValue: 5}},
+			{{DiceIndex: 0, NewValue: 3}, {DiceIndex: 0, NewValue: 2}},
+		}
+
+		for _, adjustments := range invalid {
+			_, err := qaService.AdjustDiceWithQA(agent, domain.QualityFocus, roll, adjustments)
+			assert.Error(t, err)
+			assert.Equal(t, domain.ErrInvalidInput, err.(*domain.GameError).Code)
+		}
+		assert.Equal(t, 3, agent.QA[domain.QualityFocus])
+		assert.Equal(t, []int{1, 2, 4, 1, 4, 2}, roll.Dice)
+	})
+
+	t.Run("按规则集校验和判定", func(t *testing.T) {
+		agent := createTestAgentForUnitTest()
+		agent.QA[domain.QualityFocus] = 2
+
+		d6 := qaService.WithRuleset(&domain.Ruleset{DiceRules: domain.DiceRules{DiceCount: 6, DiceSides: 6, SuccessValue: 6, TripleAscensionCount: 3}})
+		roll := &domain.RollResult{Dice: []int{1, 2, 3, 5, 4, 2}, Chaos: 6}
+
+		result, err := d6.AdjustDiceWithQA(agent, domain.QualityFocus, roll, []DiceAdjustment{{DiceIndex: 3, NewValue: 6}})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.Threes) // 原有的"3"在d6规则下不算成功
+		assert.True(t, result.Success)
+
+		_, err = d6.AdjustDiceWithQA(agent, domain.QualityFocus, roll, []DiceAdjustment{{DiceIndex: 0, NewValue: 7}})
+		assert.Error(t, err)
+	})
 }
 
 func TestQAService_GetAvailableQA(t *testing.T) {
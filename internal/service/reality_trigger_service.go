@@ -0,0 +1,221 @@
+package service
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+)
+
+// RealityTriggerService 现实触发器服务接口
+// 会话在阶段转换、累计行动或混沌激增时触发角色现实的触发器，
+// 玩家必须回应或忽视；忽视时自动应用现实配置的后果
+type RealityTriggerService interface {
+	// 会话事件后检查是否触发，未触发时返回nil
+	Evaluate(sessionID string, cause domain.RealityTriggerCause) (*domain.RealityTriggerEvent, error)
+
+	// 查询等待回应的现实触发器，没有时返回nil
+	GetTrigger(sessionID string) (*domain.RealityTriggerEvent, error)
+
+	// 回应或忽视现实触发器
+	Answer(sessionID string, req *AnswerTriggerRequest) (*domain.RealityTriggerEvent, error)
+	Ignore(sessionID string) (*domain.RealityTriggerEvent, error)
+}
+
+// AnswerTriggerRequest 回应现实触发器请求
+type AnswerTriggerRequest struct {
+	Response string `json:"response" binding:"required"` // 角色如何照顾了个人生活
+}
+
+// realityTriggerService 现实触发器服务实现
+type realityTriggerService struct {
+	agentService AgentService
+	gameService  GameService
+	rules        *domain.Ruleset
+}
+
+// NewRealityTriggerService 创建现实触发器服务
+// 会话没有自身规则集时使用rules决定触发节奏
+func NewRealityTriggerService(agentService AgentService, gameService GameService, rules *domain.Ruleset) RealityTriggerService {
+	if rules == nil {
+		rules = domain.DefaultRuleset()
+	}
+
+	return &realityTriggerService{
+		agentService: agentService,
+		gameService:  gameService,
+		rules:        rules,
+	}
+}
+
+// Evaluate 检查是否触发现实触发器
+// 触发器有混沌消耗时需要混沌池足够支付，否则本次不触发
+func (s *realityTriggerService) Evaluate(sessionID string, cause domain.RealityTriggerCause) (*domain.RealityTriggerEvent, error) {
+	session, err := s.gameService.GetSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := s.rules
+	if session.Rules != nil {
+		rules = session.Rules
+	}
+
+	fired, ok := session.State.CheckRealityTrigger(rules, cause, session.Phase)
+	if !ok {
+		return nil, s.gameService.SaveSession(session)
+	}
+
+	agent, err := s.agentService.GetAgent(session.AgentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if agent.Reality == nil || agent.Reality.Trigger == nil || session.State.ChaosPool < agent.Reality.Trigger.Cost {
+		return nil, s.gameService.SaveSession(session)
+	}
+
+	trigger := agent.Reality.Trigger
+	event := &domain.RealityTriggerEvent{
+		ID:          uuid.New().String(),
+		AgentID:     agent.ID,
+		RealityType: agent.Reality.Type,
+		Name:        trigger.Name,
+		Cost:        trigger.Cost,
+		Effect:      trigger.Effect,
+		Consequence: trigger.Consequence,
+		Cause:       fired,
+		Phase:       session.Phase,
+		SceneID:     session.State.CurrentSceneID,
+		Status:      domain.TriggerStatusPending,
+		RaisedAt:    time.Now(),
+	}
+	session.State.RaiseRealityTrigger(event)
+
+	if err := s.gameService.SaveSession(session); err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+// GetTrigger 查询等待回应的现实触发器
+func (s *realityTriggerService) GetTrigger(sessionID string) (*domain.RealityTriggerEvent, error) {
+	session, err := s.gameService.GetSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return session.State.PendingRealityTrigger(), nil
+}
+
+// Answer 回应现实触发器，角色暂时放下任务照顾个人生活
+func (s *realityTriggerService) Answer(sessionID string, req *AnswerTriggerRequest) (*domain.RealityTriggerEvent, error) {
+	if strings.TrimSpace(req.Response) == "" {
+		return nil, domain.NewGameError(domain.ErrInvalidInput, "必须说明如何回应现实触发器")
+	}
+
+	session, event, err := s.pending(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	event.Status = domain.TriggerStatusAnswered
+	event.Response = req.Response
+	event.ResolvedAt = &now
+
+	if err := s.gameService.SaveSession(session); err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+// Ignore 忽视现实触发器并应用后果
+func (s *realityTriggerService) Ignore(sessionID string) (*domain.RealityTriggerEvent, error) {
+	session, event, err := s.pending(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	agent, err := s.agentService.GetAgent(event.AgentID)
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := applyIgnoreEffect(agent, event, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.agentService.UpdateAgent(agent); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	event.Status = domain.TriggerStatusIgnored
+	event.Applied = applied
+	event.ResolvedAt = &now
+
+	if err := s.gameService.SaveSession(session); err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+// pending 获取会话和等待回应的现实触发器
+func (s *realityTriggerService) pending(sessionID string) (*domain.GameSession, *domain.RealityTriggerEvent, error) {
+	session, err := s.gameService.GetSession(sessionID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	event := session.State.PendingRealityTrigger()
+	if event == nil {
+		return nil, nil, domain.NewGameError(domain.ErrInvalidState, "没有等待回应的现实触发器").
+			WithDetails("session_id", sessionID)
+	}
+
+	return session, event, nil
+}
+
+// applyIgnoreEffect 按现实配置对角色应用忽视后果
+func applyIgnoreEffect(agent *domain.Agent, event *domain.RealityTriggerEvent, sessionID string) (*domain.AppliedConsequence, error) {
+	if agent.Reality == nil || agent.Reality.Trigger == nil || agent.Reality.Trigger.IgnoreEffect == nil {
+		return &domain.AppliedConsequence{Note: event.Consequence}, nil
+	}
+
+	effect := agent.Reality.Trigger.IgnoreEffect
+	applied := &domain.AppliedConsequence{Type: effect.Type, Note: event.Consequence}
+
+	switch effect.Type {
+	case domain.IgnoreEffectLoseConnection:
+		rel := agent.GetWeakestRelationship()
+		if effect.Target == domain.IgnoreTargetFirst && len(agent.Relationships) > 0 {
+			rel = agent.Relationships[0]
+		}
+		if rel == nil {
+			return applied, nil
+		}
+
+		rel.ChangeConnection(-effect.Amount, "忽视现实触发器："+event.Consequence, sessionID)
+		applied.RelationshipID = rel.ID
+		applied.RelationshipName = rel.Name
+		applied.Connection = rel.Connection
+
+	case domain.IgnoreEffectDegradation:
+		// 退化轨道已满时等待选择新现实，不再重复标记
+		if agent.PendingRealityChange {
+			return applied, nil
+		}
+		if _, err := agent.MarkDegradation(effect.Amount, "忽视现实触发器: "+event.Name, sessionID); err != nil {
+			return nil, err
+		}
+		applied.Boxes = effect.Amount
+	}
+
+	return applied, nil
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+)
+
+func setupRealityTriggerTest(t *testing.T, realityType string) (AgentService, GameService, RealityTriggerService, *domain.Agent, *domain.GameSession) {
+	fx := newTestAgentSession(t, withReality(realityType))
+	triggers := NewRealityTriggerService(fx.agentService, fx.gameService, nil)
+
+	return fx.agentService, fx.gameService, triggers, fx.agent, fx.session
+}
+
+func TestRealityTriggerService_IgnoreLosesConnection(t *testing.T) {
+	agentService, gameService, triggers, agent, session := setupRealityTriggerTest(t, domain.RealityCaretaker)
+
+	// 晨会阶段不触发
+	event, err := triggers.Evaluate(session.ID, domain.TriggerCausePhase)
+	require.NoError(t, err)
+	assert.Nil(t, event)
+
+	require.NoError(t, gameService.TransitionPhase(session.ID, domain.PhaseInvestigation))
+	event, err = triggers.Evaluate(session.ID, domain.TriggerCausePhase)
+	require.NoError(t, err)
+	require.NotNil(t, event)
+	assert.Equal(t, "需要关爱", event.Name)
+	assert.Equal(t, domain.TriggerStatusPending, event.Status)
+	assert.Equal(t, domain.TriggerCausePhase, event.Cause)
+
+	pending, err := triggers.GetTrigger(session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, event.ID, pending.ID)
+
+	weakest := agent.GetWeakestRelationship()
+	before := weakest.Connection
+
+	ignored, err := triggers.Ignore(session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.TriggerStatusIgnored, ignored.Status)
+	require.NotNil(t, ignored.Applied)
+	assert.Equal(t, domain.IgnoreEffectLoseConnection, ignored.Applied.Type)
+	assert.Equal(t, weakest.ID, ignored.Applied.RelationshipID)
+	assert.Equal(t, before-1, ignored.Applied.Connection)
+
+	stored, err := agentService.GetAgent(agent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 11, stored.TotalConnection())
+
+	pending, err = triggers.GetTrigger(session.ID)
+	require.NoError(t, err)
+	assert.Nil(t, pending)
+
+	_, err = triggers.Ignore(session.ID)
+	require.Error(t, err)
+	assert.Equal(t, domain.ErrInvalidState, err.(*domain.GameError).Code)
+}
+
+func TestRealityTriggerService_AnswerAfterActions(t *testing.T) {
+	agentService, _, triggers, agent, session := setupRealityTriggerTest(t, domain.RealityOutsider)
+
+	var event *domain.RealityTriggerEvent
+	for i := 0; i < domain.DefaultRuleset().RealityTriggerActions; i++ {
+		var err error
+		event, err = triggers.Evaluate(session.ID, domain.TriggerCauseAction)
+		require.NoError(t, err)
+	}
+	require.NotNil(t, event)
+	assert.Equal(t, domain.TriggerCauseAction, event.Cause)
+
+	_, err := triggers.Answer(session.ID, &AnswerTriggerRequest{Response: "  "})
+	require.Error(t, err)
+
+	answered, err := triggers.Answer(session.ID, &AnswerTriggerRequest{Response: "当面反驳了对方"})
+	require.NoError(t, err)
+	assert.Equal(t, domain.TriggerStatusAnswered, answered.Status)
+	assert.NotNil(t, answered.ResolvedAt)
+
+	// 回应不影响人际关系
+	stored, err := agentService.GetAgent(agent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 12, stored.TotalConnection())
+}
+
+func TestRealityTriggerService_ChaosCost(t *testing.T) {
+	agentService, gameService, triggers, agent, session := setupRealityTriggerTest(t, domain.RealityScheduleOverload)
+
+	// 混沌池不足以支付消耗时不触发
+	require.NoError(t, gameService.TransitionPhase(session.ID, domain.PhaseInvestigation))
+	event, err := triggers.Evaluate(session.ID, domain.TriggerCausePhase)
+	require.NoError(t, err)
+	assert.Nil(t, event)
+
+	// 混沌激增时触发并扣除消耗
+	require.NoError(t, gameService.UpdateState(session.ID, func(state *domain.GameState) error {
+		state.ChaosPool += 3
+		return nil
+	}))
+	event, err = triggers.Evaluate(session.ID, domain.TriggerCauseAction)
+	require.NoError(t, err)
+	require.NotNil(t, event)
+	assert.Equal(t, domain.TriggerCauseChaos, event.Cause)
+
+	state, err := gameService.GetState(session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, state.ChaosPool)
+
+	// 忽视时标记退化
+	ignored, err := triggers.Ignore(session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.IgnoreEffectDegradation, ignored.Applied.Type)
+
+	stored, err := agentService.GetAgent(agent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stored.Reality.DegradationTrack.Filled)
+	assert.Equal(t, session.ID, stored.DegradationHistory[0].SessionID)
+}
+
+func TestRealityTriggerService_CarriedLooseEndsAreBaseline(t *testing.T) {
+	fx := newTestAgentSession(t, withReality(domain.RealityOutsider), withoutSession())
+	triggers := NewRealityTriggerService(fx.agentService, fx.gameService, nil)
+
+	// 上次任务留下3个散逸端，开局混沌池即为3
+	_, err := fx.agentService.ModifyAgent(fx.agent.ID, func(agent *domain.Agent) error {
+		agent.LooseEnds = 3
+		return nil
+	})
+	require.NoError(t, err)
+	session, err := fx.gameService.CreateSession(fx.agent.ID, "test-scenario")
+	require.NoError(t, err)
+	assert.Equal(t, 3, session.State.ChaosPool)
+	assert.Equal(t, 3, session.State.ChaosBaseline)
+
+	// 带入的混沌不算激增
+	event, err := triggers.Evaluate(session.ID, domain.TriggerCauseAction)
+	require.NoError(t, err)
+	assert.Nil(t, event)
+
+	// 之后的增长仍然触发
+	require.NoError(t, fx.gameService.UpdateState(session.ID, func(state *domain.GameState) error {
+		state.ChaosPool += domain.DefaultRuleset().RealityTriggerChaos
+		return nil
+	}))
+	event, err = triggers.Evaluate(session.ID, domain.TriggerCauseAction)
+	require.NoError(t, err)
+	require.NotNil(t, event)
+	assert.Equal(t, domain.TriggerCauseChaos, event.Cause)
+}
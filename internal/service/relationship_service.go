@@ -0,0 +1,276 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+)
+
+// RelationshipService 人际关系服务接口
+// 创建角色时的3段、12点连结限制由ValidateARC保证，之后的游戏中人际关系可以增删、
+// 连结可以变化，每次连结变化都带原因记入人际关系的变化记录
+type RelationshipService interface {
+	// 人际关系管理
+	ListRelationships(agentID string) ([]*domain.Relationship, error)
+	GetRelationship(agentID, relID string) (*domain.Relationship, error)
+	AddRelationship(agentID string, req *AddRelationshipRequest) (*domain.Relationship, error)
+	UpdateRelationship(agentID, relID string, req *UpdateRelationshipRequest) (*domain.Relationship, error)
+	DeleteRelationship(agentID, relID string) error
+
+	// 笔记和连结变化
+	AddNote(agentID, relID string, req *RelationshipNoteRequest) (*domain.Relationship, error)
+	ChangeConnection(agentID, relID string, req *ChangeConnectionRequest) (*ConnectionChangeResult, error)
+
+	// 在会话中花费连结
+	SpendConnection(sessionID string, req *SpendConnectionRequest) (*ConnectionChangeResult, error)
+}
+
+// AddRelationshipRequest 添加人际关系请求
+type AddRelationshipRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+	PlayedBy    string `json:"played_by"`
+	Connection  int    `json:"connection"` // 初始连结，默认1点
+}
+
+// UpdateRelationshipRequest 编辑人际关系请求，未提供的字段保持不变
+// 连结不能在这里修改，需通过 ChangeConnection 说明原因
+type UpdateRelationshipRequest struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+	PlayedBy    *string `json:"played_by"`
+}
+
+// RelationshipNoteRequest 追加笔记请求
+type RelationshipNoteRequest struct {
+	Note string `json:"note" binding:"required"`
+}
+
+// ChangeConnectionRequest 调整连结请求
+type ChangeConnectionRequest struct {
+	Delta  int    `json:"delta"`
+	Reason string `json:"reason" binding:"required"`
+}
+
+// SpendConnectionRequest 会话中花费连结请求
+type SpendConnectionRequest struct {
+	RelationshipID string `json:"relationship_id" binding:"required"`
+	Amount         int    `json:"amount"` // 默认1点
+	Reason         string `json:"reason" binding:"required"`
+}
+
+// ConnectionChangeResult 连结变化结果
+type ConnectionChangeResult struct {
+	Relationship *domain.Relationship     `json:"relationship"`
+	Change       *domain.ConnectionChange `json:"change"`
+	Lost         bool                     `json:"lost"` // 连结降至0，永久失去这段人际关系
+}
+
+// relationshipService 人际关系服务实现
+type relationshipService struct {
+	agentService AgentService
+	gameService  GameService
+}
+
+// NewRelationshipService 创建人际关系服务
+func NewRelationshipService(agentService AgentService, gameService GameService) RelationshipService {
+	return &relationshipService{
+		agentService: agentService,
+		gameService:  gameService,
+	}
+}
+
+// ListRelationships 列出角色的人际关系
+func (s *relationshipService) ListRelationships(agentID string) ([]*domain.Relationship, error) {
+	agent, err := s.agentService.GetAgent(agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if agent.Relationships == nil {
+		return []*domain.Relationship{}, nil
+	}
+	return agent.Relationships, nil
+}
+
+// GetRelationship 获取一段人际关系
+func (s *relationshipService) GetRelationship(agentID, relID string) (*domain.Relationship, error) {
+	agent, err := s.agentService.GetAgent(agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return findRelationship(agent, relID)
+}
+
+// AddRelationship 添加人际关系
+func (s *relationshipService) AddRelationship(agentID string, req *AddRelationshipRequest) (*domain.Relationship, error) {
+	if strings.TrimSpace(req.Name) == "" {
+		return nil, domain.NewGameError(domain.ErrInvalidInput, "人际关系名称不能为空")
+	}
+
+	connection := req.Connection
+	if connection == 0 {
+		connection = 1
+	}
+	if connection < 1 || connection > domain.MaxConnection {
+		return nil, domain.NewGameError(domain.ErrInvalidInput, "初始连结必须在1到9之间").
+			WithDetails("connection", req.Connection)
+	}
+
+	rel := &domain.Relationship{
+		ID:          uuid.New().String(),
+		Name:        req.Name,
+		Description: req.Description,
+		PlayedBy:    req.PlayedBy,
+		Connection:  connection,
+		Notes:       []string{},
+	}
+
+	_, err := s.agentService.ModifyAgent(agentID, func(agent *domain.Agent) error {
+		agent.Relationships = append(agent.Relationships, rel)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rel, nil
+}
+
+// UpdateRelationship 编辑人际关系的名称、描述和扮演者
+func (s *relationshipService) UpdateRelationship(agentID, relID string, req *UpdateRelationshipRequest) (*domain.Relationship, error) {
+	if req.Name != nil && strings.TrimSpace(*req.Name) == "" {
+		return nil, domain.NewGameError(domain.ErrInvalidInput, "人际关系名称不能为空")
+	}
+
+	return s.modify(agentID, relID, func(rel *domain.Relationship) error {
+		if req.Name != nil {
+			rel.Name = *req.Name
+		}
+		if req.Description != nil {
+			rel.Description = *req.Description
+		}
+		if req.PlayedBy != nil {
+			rel.PlayedBy = *req.PlayedBy
+		}
+		return nil
+	})
+}
+
+// DeleteRelationship 删除人际关系
+func (s *relationshipService) DeleteRelationship(agentID, relID string) error {
+	_, err := s.agentService.ModifyAgent(agentID, func(agent *domain.Agent) error {
+		return agent.RemoveRelationship(relID)
+	})
+	return err
+}
+
+// AddNote 追加人际关系笔记
+func (s *relationshipService) AddNote(agentID, relID string, req *RelationshipNoteRequest) (*domain.Relationship, error) {
+	note := strings.TrimSpace(req.Note)
+	if note == "" {
+		return nil, domain.NewGameError(domain.ErrInvalidInput, "笔记不能为空")
+	}
+
+	return s.modify(agentID, relID, func(rel *domain.Relationship) error {
+		rel.AddNote(note)
+		return nil
+	})
+}
+
+// ChangeConnection 按原因调整连结，结果截断在0到9之间
+func (s *relationshipService) ChangeConnection(agentID, relID string, req *ChangeConnectionRequest) (*ConnectionChangeResult, error) {
+	if req.Delta == 0 {
+		return nil, domain.NewGameError(domain.ErrInvalidInput, "连结变化量不能为0")
+	}
+	if strings.TrimSpace(req.Reason) == "" {
+		return nil, domain.NewGameError(domain.ErrInvalidInput, "必须说明连结变化的原因")
+	}
+
+	result := &ConnectionChangeResult{}
+	rel, err := s.modify(agentID, relID, func(rel *domain.Relationship) error {
+		result.Change = rel.ChangeConnection(req.Delta, req.Reason, "")
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.Relationship = rel
+	result.Lost = rel.IsLost()
+	return result, nil
+}
+
+// SpendConnection 在会话中花费连结
+// 连结不足时拒绝，降至0时永久失去这段人际关系
+func (s *relationshipService) SpendConnection(sessionID string, req *SpendConnectionRequest) (*ConnectionChangeResult, error) {
+	amount := req.Amount
+	if amount == 0 {
+		amount = 1
+	}
+	if amount < 0 {
+		return nil, domain.NewGameError(domain.ErrInvalidInput, "花费的连结必须为正数").
+			WithDetails("amount", req.Amount)
+	}
+	if strings.TrimSpace(req.Reason) == "" {
+		return nil, domain.NewGameError(domain.ErrInvalidInput, "必须说明花费连结的原因")
+	}
+
+	session, err := s.gameService.GetSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ConnectionChangeResult{}
+	rel, err := s.modify(session.AgentID, req.RelationshipID, func(rel *domain.Relationship) error {
+		if rel.Connection < amount {
+			return domain.NewGameError(domain.ErrInsufficientConnection, "连结不足").
+				WithDetails("relationship_id", rel.ID).
+				WithDetails("connection", rel.Connection).
+				WithDetails("amount", amount)
+		}
+
+		result.Change = rel.ChangeConnection(-amount, req.Reason, sessionID)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.Relationship = rel
+	result.Lost = rel.IsLost()
+	return result, nil
+}
+
+// modify 原子修改角色的一段人际关系
+func (s *relationshipService) modify(agentID, relID string, fn func(rel *domain.Relationship) error) (*domain.Relationship, error) {
+	var modified *domain.Relationship
+	_, err := s.agentService.ModifyAgent(agentID, func(agent *domain.Agent) error {
+		rel, err := findRelationship(agent, relID)
+		if err != nil {
+			return err
+		}
+		if err := fn(rel); err != nil {
+			return err
+		}
+		modified = rel
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return modified, nil
+}
+
+// findRelationship 查找人际关系，不存在时返回NotFound错误
+func findRelationship(agent *domain.Agent, relID string) (*domain.Relationship, error) {
+	rel := agent.FindRelationship(relID)
+	if rel == nil {
+		return nil, domain.NewGameError(domain.ErrNotFound, "人际关系不存在").
+			WithDetails("relationship_id", relID)
+	}
+	return rel, nil
+}
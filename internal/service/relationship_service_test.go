@@ -0,0 +1,106 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+)
+
+func setupRelationshipTest(t *testing.T) (AgentService, GameService, RelationshipService, *domain.Agent) {
+	fx := newTestAgentSession(t, withoutSession())
+	relationships := NewRelationshipService(fx.agentService, fx.gameService)
+
+	return fx.agentService, fx.gameService, relationships, fx.agent
+}
+
+func TestRelationshipService_CRUD(t *testing.T) {
+	agentService, _, relationships, agent := setupRelationshipTest(t)
+
+	rel, err := relationships.AddRelationship(agent.ID, &AddRelationshipRequest{
+		Name:     "新邻居",
+		PlayedBy: "玩家2",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, rel.Connection, "新的人际关系以1点连结开始")
+
+	_, err = relationships.AddRelationship(agent.ID, &AddRelationshipRequest{Name: "太亲近", Connection: 10})
+	require.Error(t, err)
+	assert.Equal(t, domain.ErrInvalidInput, err.(*domain.GameError).Code)
+
+	listed, err := relationships.ListRelationships(agent.ID)
+	require.NoError(t, err)
+	assert.Len(t, listed, 4, "游戏中可以超过3段人际关系")
+
+	name := "楼下的邻居"
+	updated, err := relationships.UpdateRelationship(agent.ID, rel.ID, &UpdateRelationshipRequest{Name: &name})
+	require.NoError(t, err)
+	assert.Equal(t, "楼下的邻居", updated.Name)
+	assert.Equal(t, "玩家2", updated.PlayedBy, "未提供的字段保持不变")
+
+	noted, err := relationships.AddNote(agent.ID, rel.ID, &RelationshipNoteRequest{Note: "喜欢在周末烤面包"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"喜欢在周末烤面包"}, noted.Notes)
+
+	require.NoError(t, relationships.DeleteRelationship(agent.ID, rel.ID))
+	stored, err := agentService.GetAgent(agent.ID)
+	require.NoError(t, err)
+	assert.Len(t, stored.Relationships, 3)
+
+	_, err = relationships.GetRelationship(agent.ID, rel.ID)
+	require.Error(t, err)
+	assert.Equal(t, domain.ErrNotFound, err.(*domain.GameError).Code)
+}
+
+func TestRelationshipService_ChangeConnection(t *testing.T) {
+	_, _, relationships, agent := setupRelationshipTest(t)
+	relID := agent.Relationships[0].ID
+
+	_, err := relationships.ChangeConnection(agent.ID, relID, &ChangeConnectionRequest{Delta: 1})
+	require.Error(t, err, "必须说明原因")
+
+	result, err := relationships.ChangeConnection(agent.ID, relID, &ChangeConnectionRequest{
+		Delta:  2,
+		Reason: "在现实上消耗了时间",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 8, result.Relationship.Connection)
+	assert.Equal(t, 2, result.Change.Delta)
+	require.Len(t, result.Relationship.History, 1)
+	assert.False(t, result.Lost)
+}
+
+func TestRelationshipService_SpendConnection(t *testing.T) {
+	_, gameService, relationships, agent := setupRelationshipTest(t)
+	session, err := gameService.CreateSession(agent.ID, "test-scenario")
+	require.NoError(t, err)
+	relID := agent.Relationships[1].ID
+
+	result, err := relationships.SpendConnection(session.ID, &SpendConnectionRequest{
+		RelationshipID: relID,
+		Reason:         "请对方引荐给馆长",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Relationship.Connection)
+	assert.Equal(t, session.ID, result.Change.SessionID)
+
+	_, err = relationships.SpendConnection(session.ID, &SpendConnectionRequest{
+		RelationshipID: relID,
+		Amount:         3,
+		Reason:         "再次求助",
+	})
+	require.Error(t, err)
+	assert.Equal(t, domain.ErrInsufficientConnection, err.(*domain.GameError).Code)
+
+	result, err = relationships.SpendConnection(session.ID, &SpendConnectionRequest{
+		RelationshipID: relID,
+		Amount:         2,
+		Reason:         "借走了对方的设备并弄丢了",
+	})
+	require.NoError(t, err)
+	assert.True(t, result.Lost)
+
+	_, err = relationships.SpendConnection("missing", &SpendConnectionRequest{RelationshipID: relID, Reason: "x"})
+	require.Error(t, err)
+}
@@ -26,19 +26,19 @@ type RequestService interface {
 
 // RealityChangeRequest 现实变更请求
 type RealityChangeRequest struct {
-	Effect      string // 既定效果
-	CausalChain string // 因果链
-	Quality     string // 相关资质
-	LocationID  string // 当前地点
+	Effect      string `json:"effect" binding:"required"`       // 既定效果
+	CausalChain string `json:"causal_chain" binding:"required"` // 因果链
+	Quality     string `json:"quality" binding:"required"`      // 相关资质
+	LocationID  string `json:"location_id"`                     // 当前地点
 }
 
 // RequestResult 请求结果
 type RequestResult struct {
-	Success         bool   // 是否成功
-	AppliedEffect   string // 应用的效果
-	EstablishedFact string // 确立的事实
-	Chaos           int    // 产生的混沌
-	Overload        int    // 地点过载
+	Success         bool   `json:"success"`                    // 是否成功
+	AppliedEffect   string `json:"applied_effect"`             // 应用的效果
+	EstablishedFact string `json:"established_fact,omitempty"` // 确立的事实
+	Chaos           int    `json:"chaos"`                      // 产生的混沌
+	Overload        int    `json:"overload"`                   // 地点过载
 }
 
 // requestService 请求机构服务实现
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+)
+
+// RollLedgerService 掷骰账本服务接口
+type RollLedgerService interface {
+	// 追加掷骰记录
+	RecordRoll(record *domain.RollRecord) error
+
+	// 查询会话掷骰记录（按时间顺序）
+	ListRolls(sessionID string, filter *domain.RollFilter) ([]*domain.RollRecord, error)
+}
+
+// rollLedgerService 内存掷骰账本实现
+type rollLedgerService struct {
+	rolls map[string][]*domain.RollRecord
+	mu    sync.RWMutex
+}
+
+// NewRollLedgerService 创建掷骰账本服务
+func NewRollLedgerService() RollLedgerService {
+	return &rollLedgerService{
+		rolls: make(map[string][]*domain.RollRecord),
+	}
+}
+
+// RecordRoll 追加掷骰记录
+func (s *rollLedgerService) RecordRoll(record *domain.RollRecord) error {
+	if err := validateRollRecord(record); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rolls[record.SessionID] = append(s.rolls[record.SessionID], record)
+
+	return nil
+}
+
+// ListRolls 查询会话掷骰记录
+func (s *rollLedgerService) ListRolls(sessionID string, filter *domain.RollFilter) ([]*domain.RollRecord, error) {
+	if filter != nil && !domain.IsValidRollOutcome(filter.Outcome) {
+		return nil, domain.NewGameError(domain.ErrInvalidInput, "无效的结果筛选条件").
+			WithDetails("outcome", filter.Outcome)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	records := make([]*domain.RollRecord, 0)
+	for _, record := range s.rolls[sessionID] {
+		if filter.Matches(record) {
+			records = append(records, record)
+		}
+	}
+
+	return records, nil
+}
+
+// validateRollRecord 校验并补全账本条目
+func validateRollRecord(record *domain.RollRecord) error {
+	if record == nil || record.SessionID == "" {
+		return domain.NewGameError(domain.ErrInvalidInput, "掷骰记录缺少会话ID")
+	}
+
+	if record.ID == "" {
+		record.ID = uuid.New().String()
+	}
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = time.Now()
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+)
+
+func TestRollLedgerService_RecordAndList(t *testing.T) {
+	ledger := NewRollLedgerService()
+	dice := domain.NewSeededDiceService(7)
+
+	t.Run("按时间顺序返回会话记录", func(t *testing.T) {
+		first := domain.NewRollRecord("session-1", "agent-1", domain.RollKindBasic, dice.Roll(6))
+		second := domain.NewRollRecord("session-1", "agent-1", domain.RollKindBasic, dice.Roll(6))
+		other := domain.NewRollRecord("session-2", "agent-2", domain.RollKindBasic, dice.Roll(6))
+
+		require.NoError(t, ledger.RecordRoll(first))
+		require.NoError(t, ledger.RecordRoll(second))
+		require.NoError(t, ledger.RecordRoll(other))
+
+		records, err := ledger.ListRolls("session-1", nil)
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+		assert.NotEmpty(t, records[0].ID)
+		assert.Equal(t, first.ID, records[0].ID)
+		assert.Equal(t, second.ID, records[1].ID)
+	})
+
+	t.Run("缺少会话ID时拒绝记录", func(t *testing.T) {
+		err := ledger.RecordRoll(domain.NewRollRecord("", "agent-1", domain.RollKindBasic, dice.Roll(6)))
+		require.Error(t, err)
+		assert.Equal(t, domain.ErrInvalidInput, err.(*domain.GameError).Code)
+	})
+}
+
+func TestRollLedgerService_ListWithFilter(t *testing.T) {
+	ledger := NewRollLedgerService()
+
+	records := []*domain.RollRecord{
+		{SessionID: "s", Quality: domain.QualityFocus, Success: true},
+		{SessionID: "s", Quality: domain.QualityFocus, Success: false},
+		{SessionID: "s", Quality: domain.QualityEmpathy, Success: true, TripleAsc: true},
+	}
+	for _, record := range records {
+		require.NoError(t, ledger.RecordRoll(record))
+	}
+
+	t.Run("按品质筛选", func(t *testing.T) {
+		result, err := ledger.ListRolls("s", &domain.RollFilter{Quality: domain.QualityFocus})
+		require.NoError(t, err)
+		assert.Len(t, result, 2)
+	})
+
+	t.Run("按结果筛选", func(t *testing.T) {
+		result, err := ledger.ListRolls("s", &domain.RollFilter{Outcome: domain.RollOutcomeSuccess})
+		require.NoError(t, err)
+		assert.Len(t, result, 2)
+
+		result, err = ledger.ListRolls("s", &domain.RollFilter{Outcome: domain.RollOutcomeTripleAsc})
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Equal(t, domain.QualityEmpathy, result[0].Quality)
+	})
+
+	t.Run("无效结果筛选返回错误", func(t *testing.T) {
+		_, err := ledger.ListRolls("s", &domain.RollFilter{Outcome: "maybe"})
+		require.Error(t, err)
+		assert.Equal(t, domain.ErrInvalidInput, err.(*domain.GameError).Code)
+	})
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+
+	"github.com/trpg-solo-engine/backend/internal/domain"
+	"github.com/trpg-solo-engine/backend/internal/infrastructure/repository"
+)
+
+// rollLedgerServiceWithRepo 使用仓储的掷骰账本实现
+type rollLedgerServiceWithRepo struct {
+	rollRepo repository.RollRepository
+}
+
+// NewRollLedgerServiceWithRepo 创建使用仓储的掷骰账本服务
+func NewRollLedgerServiceWithRepo(rollRepo repository.RollRepository) RollLedgerService {
+	return &rollLedgerServiceWithRepo{
+		rollRepo: rollRepo,
+	}
+}
+
+// RecordRoll 追加掷骰记录
+func (s *rollLedgerServiceWithRepo) RecordRoll(record *domain.RollRecord) error {
+	if err := validateRollRecord(record); err != nil {
+		return err
+	}
+
+	if err := s.rollRepo.Create(context.Background(), record); err != nil {
+		return domain.NewGameError(domain.ErrInternal, "保存掷骰记录失败").
+			WithDetails("error", err.Error())
+	}
+
+	return nil
+}
+
+// ListRolls 查询会话掷骰记录
+func (s *rollLedgerServiceWithRepo) ListRolls(sessionID string, filter *domain.RollFilter) ([]*domain.RollRecord, error) {
+	if filter != nil && !domain.IsValidRollOutcome(filter.Outcome) {
+		return nil, domain.NewGameError(domain.ErrInvalidInput, "无效的结果筛选条件").
+			WithDetails("outcome", filter.Outcome)
+	}
+
+	records, err := s.rollRepo.ListBySession(context.Background(), sessionID, filter)
+	if err != nil {
+		return nil, domain.NewGameError(domain.ErrInternal, "查询掷骰记录失败").
+			WithDetails("error", err.Error())
+	}
+
+	return records, nil
+}
@@ -184,18 +184,7 @@ func (s *saveService) LoadSave(saveID string) (*domain.GameSession, error) {
 			WithDetails("current_version", s.version)
 	}
 
-	// 创建会话的深拷贝
-	sessionCopy := &domain.GameSession{
-		ID:         uuid.New().String(), // 生成新的会话ID
-		AgentID:    snapshot.Snapshot.AgentID,
-		ScenarioID: snapshot.Snapshot.ScenarioID,
-		Phase:      snapshot.Snapshot.Phase,
-		State:      copyGameState(snapshot.Snapshot.State),
-		CreatedAt:  time.Now(),
-		UpdatedAt:  time.Now(),
-	}
-
-	return sessionCopy, nil
+	return copySessionForLoad(snapshot.Snapshot), nil
 }
 
 // SerializeSession 序列化游戏会话
@@ -206,11 +195,11 @@ func (s *saveService) SerializeSession(session *domain.GameSession) ([]byte, err
 
 	// 创建包含版本信息的包装结构
 	wrapper := struct {
-		Version string              `json:"version"`
-		Session *domain.GameSession `json:"session"`
+		Version string               `json:"version"`
+		Session domain.StoredSession `json:"session"`
 	}{
 		Version: s.version,
-		Session: session,
+		Session: domain.StoredSession{GameSession: session},
 	}
 
 	data, err := json.Marshal(wrapper)
@@ -235,8 +224,8 @@ func (s *saveService) DeserializeSession(data []byte) (*domain.GameSession, erro
 
 	// 解析包装结构
 	var wrapper struct {
-		Version string              `json:"version"`
-		Session *domain.GameSession `json:"session"`
+		Version string               `json:"version"`
+		Session domain.StoredSession `json:"session"`
 	}
 
 	if err := json.Unmarshal(data, &wrapper); err != nil {
@@ -244,11 +233,11 @@ func (s *saveService) DeserializeSession(data []byte) (*domain.GameSession, erro
 			WithDetails("error", err.Error())
 	}
 
-	if wrapper.Session == nil {
+	if wrapper.Session.GameSession == nil {
 		return nil, domain.NewGameError(domain.ErrDataCorrupted, "存档数据损坏")
 	}
 
-	return wrapper.Session, nil
+	return wrapper.Session.GameSession, nil
 }
 
 // ValidateVersion 验证版本兼容性
@@ -276,6 +265,28 @@ func (s *saveService) ValidateVersion(data []byte) error {
 	return nil
 }
 
+// copySessionForLoad 以新的会话ID深拷贝存档中的会话
+// 骰子种子和游标一并恢复，加载后的掷骰从存档时的随机流位置继续
+func copySessionForLoad(snapshot *domain.GameSession) *domain.GameSession {
+	var rules *domain.Ruleset
+	if snapshot.Rules != nil {
+		rules = snapshot.Rules.Override(nil)
+	}
+
+	return &domain.GameSession{
+		ID:         uuid.New().String(), // 生成新的会话ID
+		AgentID:    snapshot.AgentID,
+		ScenarioID: snapshot.ScenarioID,
+		Phase:      snapshot.Phase,
+		State:      copyGameState(snapshot.State),
+		DiceSeed:   snapshot.DiceSeed,
+		DiceCursor: snapshot.DiceCursor,
+		Rules:      rules,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+}
+
 // copyGameState 深拷贝游戏状态
 func copyGameState(state *domain.GameState) *domain.GameState {
 	if state == nil {
@@ -319,6 +330,54 @@ func copyGameState(state *domain.GameState) *domain.GameState {
 		locationOverloads[k] = v
 	}
 
+	// 拷贝三重升华记录
+	tripleAscensions := make([]*domain.TripleAscension, 0, len(state.TripleAscensions))
+	for _, ta := range state.TripleAscensions {
+		copied := *ta
+		tripleAscensions = append(tripleAscensions, &copied)
+	}
+
+	// 拷贝过载解除
+	var overloadRelief *domain.OverloadReliefClaim
+	if state.OverloadRelief != nil {
+		copied := *state.OverloadRelief
+		overloadRelief = &copied
+	}
+
+	// 拷贝现实触发器
+	realityTriggers := make([]*domain.RealityTriggerEvent, 0, len(state.RealityTriggers))
+	for _, event := range state.RealityTriggers {
+		copied := *event
+		if event.Applied != nil {
+			applied := *event.Applied
+			copied.Applied = &applied
+		}
+		realityTriggers = append(realityTriggers, &copied)
+	}
+
+	// 拷贝晨会记录
+	var chosenGoals []string
+	if state.ChosenGoals != nil {
+		chosenGoals = append([]string{}, state.ChosenGoals...)
+	}
+	var morningScenes []*domain.MorningSceneRecord
+	for _, record := range state.MorningScenes {
+		copied := *record
+		morningScenes = append(morningScenes, &copied)
+	}
+
+	// 拷贝遭遇进度
+	var encounter *domain.EncounterState
+	if state.Encounter != nil {
+		encounter = state.Encounter.Clone()
+	}
+
+	// 拷贝任务报告
+	var debrief *domain.DebriefReport
+	if state.Debrief != nil {
+		debrief = state.Debrief.Clone()
+	}
+
 	return &domain.GameState{
 		CurrentSceneID:    state.CurrentSceneID,
 		VisitedScenes:     visitedScenes,
@@ -331,5 +390,20 @@ func copyGameState(state *domain.GameState) *domain.GameState {
 		LocationOverloads: locationOverloads,
 		AnomalyStatus:     state.AnomalyStatus,
 		MissionOutcome:    state.MissionOutcome,
+		TripleAscensions:  tripleAscensions,
+		RerollTokens:      state.RerollTokens,
+		OverloadRelief:    overloadRelief,
+		ReliefScenes:      append([]string(nil), state.ReliefScenes...),
+		RewardsGranted:    state.RewardsGranted,
+
+		ChosenGoals:   chosenGoals,
+		MorningScenes: morningScenes,
+
+		Encounter: encounter,
+		Debrief:   debrief,
+
+		RealityTriggers:     realityTriggers,
+		ActionsSinceTrigger: state.ActionsSinceTrigger,
+		ChaosBaseline:       state.ChaosBaseline,
 	}
 }
@@ -226,6 +226,126 @@ func TestSaveService_LoadSave(t *testing.T) {
 	assert.True(t, loadedSession.State.DomainUnlocked)
 }
 
+// TestSaveService_LoadSave_TripleAscensions 测试加载存档保留三重升华记录和重掷令牌
+func TestSaveService_LoadSave_TripleAscensions(t *testing.T) {
+	gameService := NewGameService()
+	agentService := NewAgentService()
+	saveService := NewSaveService(gameService, agentService)
+
+	agent, err := agentService.CreateAgent(&CreateAgentRequest{
+		Name:        "测试特工",
+		AnomalyType: domain.AnomalyWhisper,
+		RealityType: domain.RealityCaretaker,
+		CareerType:  domain.CareerPublicRelations,
+	})
+	require.NoError(t, err)
+
+	session, err := gameService.CreateSession(agent.ID, "test-scenario")
+	require.NoError(t, err)
+
+	session.State.TripleAscensions = []*domain.TripleAscension{
+		{RollID: "roll-1", AgentID: agent.ID, Effect: domain.TripleAscEffectReroll, Amount: 1},
+	}
+	session.State.RerollTokens = 2
+	require.NoError(t, gameService.SaveSession(session))
+
+	snapshot, err := saveService.CreateSave(session.ID, "Test Save")
+	require.NoError(t, err)
+
+	loadedSession, err := saveService.LoadSave(snapshot.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, loadedSession.State.RerollTokens)
+	require.Len(t, loadedSession.State.TripleAscensions, 1)
+	assert.Equal(t, "roll-1", loadedSession.State.TripleAscensions[0].RollID)
+
+	// 加载的记录是独立副本
+	loadedSession.State.TripleAscensions[0].Amount = 5
+	assert.Equal(t, 1, session.State.TripleAscensions[0].Amount)
+}
+
+// TestSaveService_LoadSave_OverloadRelief 测试加载存档保留过载解除
+func TestSaveService_LoadSave_OverloadRelief(t *testing.T) {
+	gameService := NewGameService()
+	agentService := NewAgentService()
+	saveService := NewSaveService(gameService, agentService)
+
+	agent, err := agentService.CreateAgent(&CreateAgentRequest{
+		Name:        "测试特工",
+		AnomalyType: domain.AnomalyWhisper,
+		RealityType: domain.RealityCaretaker,
+		CareerType:  domain.CareerPublicRelations,
+	})
+	require.NoError(t, err)
+
+	session, err := gameService.CreateSession(agent.ID, "test-scenario")
+	require.NoError(t, err)
+
+	session.State.OverloadRelief = &domain.OverloadReliefClaim{
+		AgentID: agent.ID,
+		Scope:   domain.OverloadReliefScopeScene,
+		SceneID: "scene-1",
+		Used:    1,
+	}
+	require.NoError(t, gameService.SaveSession(session))
+
+	snapshot, err := saveService.CreateSave(session.ID, "Test Save")
+	require.NoError(t, err)
+
+	loadedSession, err := saveService.LoadSave(snapshot.ID)
+	require.NoError(t, err)
+	require.NotNil(t, loadedSession.State.OverloadRelief)
+	assert.Equal(t, domain.OverloadReliefScopeScene, loadedSession.State.OverloadRelief.Scope)
+	assert.Equal(t, 1, loadedSession.State.OverloadRelief.Used)
+
+	// 加载的过载解除是独立副本
+	loadedSession.State.OverloadRelief.Used = 3
+	assert.Equal(t, 1, session.State.OverloadRelief.Used)
+}
+
+// TestSaveService_LoadSave_DiceStream 测试加载存档保留骰子随机流和会话规则
+func TestSaveService_LoadSave_DiceStream(t *testing.T) {
+	gameService := NewGameService()
+	agentService := NewAgentService()
+	saveService := NewSaveService(gameService, agentService)
+
+	agent, err := agentService.CreateAgent(&CreateAgentRequest{
+		Name:        "测试特工",
+		AnomalyType: domain.AnomalyWhisper,
+		RealityType: domain.RealityCaretaker,
+		CareerType:  domain.CareerPublicRelations,
+	})
+	require.NoError(t, err)
+
+	session, err := gameService.CreateSession(agent.ID, "test-scenario")
+	require.NoError(t, err)
+
+	session.Rules = domain.DefaultRuleset()
+	session.Rules.DiceCount = 4
+	dice := domain.NewDiceService().ForSession(session)
+	dice.Roll(0)
+	require.NotZero(t, session.DiceCursor)
+	require.NoError(t, gameService.SaveSession(session))
+
+	snapshot, err := saveService.CreateSave(session.ID, "Test Save")
+	require.NoError(t, err)
+
+	loadedSession, err := saveService.LoadSave(snapshot.ID)
+	require.NoError(t, err)
+	assert.Equal(t, session.DiceSeed, loadedSession.DiceSeed)
+	assert.Equal(t, session.DiceCursor, loadedSession.DiceCursor)
+	require.NotNil(t, loadedSession.Rules)
+	assert.Equal(t, 4, loadedSession.Rules.DiceCount)
+
+	// 加载后的掷骰与原会话的下一次掷骰相同
+	expected := domain.NewDiceService().ForSession(session).Roll(0)
+	actual := domain.NewDiceService().ForSession(loadedSession).Roll(0)
+	assert.Equal(t, expected.Dice, actual.Dice)
+
+	// 加载的规则是独立副本
+	loadedSession.Rules.DiceCount = 6
+	assert.Equal(t, 4, session.Rules.DiceCount)
+}
+
 // TestSaveService_SerializeDeserialize 测试序列化和反序列化
 func TestSaveService_SerializeDeserialize(t *testing.T) {
 	// 创建服务
@@ -172,18 +172,7 @@ func (s *saveServiceWithRepo) LoadSave(saveID string) (*domain.GameSession, erro
 			WithDetails("current_version", s.version)
 	}
 
-	// 创建会话的深拷贝
-	sessionCopy := &domain.GameSession{
-		ID:         uuid.New().String(), // 生成新的会话ID
-		AgentID:    snapshot.Snapshot.AgentID,
-		ScenarioID: snapshot.Snapshot.ScenarioID,
-		Phase:      snapshot.Snapshot.Phase,
-		State:      copyGameState(snapshot.Snapshot.State),
-		CreatedAt:  time.Now(),
-		UpdatedAt:  time.Now(),
-	}
-
-	return sessionCopy, nil
+	return copySessionForLoad(snapshot.Snapshot), nil
 }
 
 // SerializeSession 序列化游戏会话
@@ -194,11 +183,11 @@ func (s *saveServiceWithRepo) SerializeSession(session *domain.GameSession) ([]b
 
 	// 创建包含版本信息的包装结构
 	wrapper := struct {
-		Version string              `json:"version"`
-		Session *domain.GameSession `json:"session"`
+		Version string               `json:"version"`
+		Session domain.StoredSession `json:"session"`
 	}{
 		Version: s.version,
-		Session: session,
+		Session: domain.StoredSession{GameSession: session},
 	}
 
 	data, err := json.Marshal(wrapper)
@@ -223,8 +212,8 @@ func (s *saveServiceWithRepo) DeserializeSession(data []byte) (*domain.GameSessi
 
 	// 解析包装结构
 	var wrapper struct {
-		Version string              `json:"version"`
-		Session *domain.GameSession `json:"session"`
+		Version string               `json:"version"`
+		Session domain.StoredSession `json:"session"`
 	}
 
 	if err := json.Unmarshal(data, &wrapper); err != nil {
@@ -232,11 +221,11 @@ func (s *saveServiceWithRepo) DeserializeSession(data []byte) (*domain.GameSessi
 			WithDetails("error", err.Error())
 	}
 
-	if wrapper.Session == nil {
+	if wrapper.Session.GameSession == nil {
 		return nil, domain.NewGameError(domain.ErrDataCorrupted, "存档数据损坏")
 	}
 
-	return wrapper.Session, nil
+	return wrapper.Session.GameSession, nil
 }
 
 // ValidateVersion 验证版本兼容性
@@ -192,6 +192,11 @@ func (s *scenarioService) ValidateScenario(scenario *domain.Scenario) error {
 		}
 	}
 
+	// 验证遭遇行动
+	if err := validateEncounter(scenario.Encounter); err != nil {
+		return err
+	}
+
 	// 验证线索引用
 	for sceneID, scene := range scenario.Scenes {
 		for _, clue := range scene.Clues {
@@ -210,6 +215,47 @@ func (s *scenarioService) ValidateScenario(scenario *domain.Scenario) error {
 	return nil
 }
 
+// validateEncounter 检查遭遇行动的资质和任务结果，最终阶段的每个行动都必须指定任务结果
+func validateEncounter(encounter *domain.Encounter) error {
+	if encounter == nil {
+		return nil
+	}
+
+	for i, phase := range encounter.Phases {
+		final := i == len(encounter.Phases)-1
+		for j, action := range phase.Actions {
+			if action == nil || action.Description == "" {
+				return domain.NewGameError(domain.ErrInvalidInput, "遭遇行动缺少描述").
+					WithDetails("phase_id", phase.ID).
+					WithDetails("action_index", j)
+			}
+			if action.Quality != "" && !contains(domain.AllQualities, action.Quality) {
+				return domain.NewGameError(domain.ErrInvalidInput, "遭遇行动的资质无效").
+					WithDetails("phase_id", phase.ID).
+					WithDetails("action", action.Description).
+					WithDetails("quality", action.Quality)
+			}
+
+			switch action.Outcome {
+			case OutcomeCaptured, OutcomeNeutralized, OutcomeDeal:
+			case "":
+				if final {
+					return domain.NewGameError(domain.ErrInvalidInput, "最终阶段的遭遇行动必须指定任务结果").
+						WithDetails("phase_id", phase.ID).
+						WithDetails("action", action.Description)
+				}
+			default:
+				return domain.NewGameError(domain.ErrInvalidInput, "遭遇行动的任务结果无效").
+					WithDetails("phase_id", phase.ID).
+					WithDetails("action", action.Description).
+					WithDetails("outcome", action.Outcome)
+			}
+		}
+	}
+
+	return nil
+}
+
 // GetScene 获取场景
 func (s *scenarioService) GetScene(scenarioID, sceneID string) (*domain.Scene, error) {
 	// 加载剧本
@@ -471,7 +517,11 @@ func CreateTestScenario() *domain.Scenario {
 				{
 					ID:          "phase-1",
 					Description: "接近异常体",
-					Actions:     []string{"观察", "攻击", "逃跑"},
+					Actions: []*domain.PhaseAction{
+						{Description: "捕获", Quality: domain.QualityInitiative, Outcome: OutcomeCaptured},
+						{Description: "攻击", Quality: domain.QualityVitality, Outcome: OutcomeNeutralized},
+						{Description: "谈判", Quality: domain.QualityEmpathy, Outcome: OutcomeDeal},
+					},
 				},
 			},
 		},
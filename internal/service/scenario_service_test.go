@@ -362,6 +362,45 @@ func TestScenarioService_ValidateScenario(t *testing.T) {
 	}
 }
 
+func TestScenarioService_ValidateEncounterActions(t *testing.T) {
+	service := NewScenarioService(t.TempDir())
+
+	tests := []struct {
+		name   string
+		modify func(actions []*domain.PhaseAction)
+	}{
+		{"最终阶段的行动缺少任务结果", func(actions []*domain.PhaseAction) { actions[0].Outcome = "" }},
+		{"任务结果无效", func(actions []*domain.PhaseAction) { actions[0].Outcome = OutcomeEscaped }},
+		{"资质无效", func(actions []*domain.PhaseAction) { actions[0].Quality = "魅力" }},
+		{"缺少描述", func(actions []*domain.PhaseAction) { actions[0].Description = "" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scenario := CreateTestScenario()
+			tt.modify(scenario.Encounter.Phases[0].Actions)
+
+			err := service.ValidateScenario(scenario)
+			if err == nil {
+				t.Fatal("期望验证失败，但没有错误")
+			}
+			if code := err.(*domain.GameError).Code; code != domain.ErrInvalidInput {
+				t.Errorf("期望错误码 %s, 得到 %s", domain.ErrInvalidInput, code)
+			}
+		})
+	}
+
+	// 非最终阶段的行动可以不指定任务结果
+	scenario := CreateTestScenario()
+	scenario.Encounter.Phases = append([]*domain.Phase{{
+		ID:      "approach",
+		Actions: []*domain.PhaseAction{{Description: "观察"}},
+	}}, scenario.Encounter.Phases...)
+	if err := service.ValidateScenario(scenario); err != nil {
+		t.Errorf("期望验证成功，但得到错误: %v", err)
+	}
+}
+
 func TestScenarioService_GetScene(t *testing.T) {
 	tmpDir := t.TempDir()
 	service := NewScenarioService(tmpDir).(*scenarioService)
@@ -186,7 +186,7 @@ func (s *sceneService) TransitionToScene(sessionID string, targetSceneID string)
 	}
 
 	// 更新当前场景
-	session.State.CurrentSceneID = targetSceneID
+	session.State.EnterScene(targetSceneID)
 
 	// 标记场景为已访问
 	if session.State.VisitedScenes == nil {
@@ -240,6 +240,11 @@ func (s *sceneService) InteractWithObject(sessionID string, objectID string, act
 	// 检查是否是线索交互
 	for _, clue := range scene.Clues {
 		if clue.ID == objectID {
+			if contains(session.State.CollectedClues, clue.ID) {
+				return nil, domain.NewGameError(domain.ErrInvalidAction, "线索已收集").
+					WithDetails("clue_id", clue.ID)
+			}
+
 			// 检查线索需求
 			if s.scenarioService.CheckClueRequirements(clue, session.State) {
 				result.Success = true
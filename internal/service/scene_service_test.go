@@ -176,6 +176,12 @@ func TestSceneService_InteractWithObject(t *testing.T) {
 		assert.Contains(t, updatedSession.State.UnlockedLocations, "scene-2")
 	})
 
+	t.Run("不能重复收集线索", func(t *testing.T) {
+		_, err := sceneService.InteractWithObject(session.ID, "clue-1", "调查")
+		require.Error(t, err)
+		assert.Equal(t, domain.ErrInvalidAction, err.(*domain.GameError).Code)
+	})
+
 	t.Run("与NPC交互", func(t *testing.T) {
 		result, err := sceneService.InteractWithObject(session.ID, "npc-1", "对话")
 		assert.NoError(t, err)
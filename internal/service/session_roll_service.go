@@ -0,0 +1,47 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/trpg-solo-engine/backend/internal/domain"
+)
+
+// SessionRollService 会话掷骰结算服务接口
+// 所有从会话随机流取数的掷骰都经过这里结算，账本和三重升华不会因入口不同而遗漏
+type SessionRollService interface {
+	// 结算会话内的掷骰：先处理三重升华效果，再写入掷骰账本
+	// session为nil时不结算
+	Settle(session *domain.GameSession, record *domain.RollRecord) (*domain.TripleAscension, error)
+}
+
+// sessionRollService 会话掷骰结算服务实现
+type sessionRollService struct {
+	rollLedger      RollLedgerService
+	tripleAscension TripleAscensionService
+}
+
+// NewSessionRollService 创建会话掷骰结算服务
+func NewSessionRollService(rollLedger RollLedgerService, tripleAscension TripleAscensionService) SessionRollService {
+	return &sessionRollService{
+		rollLedger:      rollLedger,
+		tripleAscension: tripleAscension,
+	}
+}
+
+// Settle 结算会话内的掷骰
+func (s *sessionRollService) Settle(session *domain.GameSession, record *domain.RollRecord) (*domain.TripleAscension, error) {
+	if session == nil {
+		return nil, nil
+	}
+
+	event, err := s.tripleAscension.Resolve(session, record)
+	if err != nil {
+		return nil, fmt.Errorf("结算三重升华失败: %w", err)
+	}
+
+	if err := s.rollLedger.RecordRoll(record); err != nil {
+		return nil, fmt.Errorf("记录掷骰失败: %w", err)
+	}
+
+	return event, nil
+}
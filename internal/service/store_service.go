@@ -0,0 +1,407 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/trpg-solo-engine/backend/internal/catalog"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+)
+
+// StoreService 机构商店服务接口
+// 角色花费嘉奖购买可申领物，物品放入物品栏，在会话中使用时由效果钩子结算；
+// 剧本奖励的物品在余波阶段发放
+type StoreService interface {
+	// 商店目录，指定角色时附带可购买和买得起标记
+	ListItems(agentID string) (*StoreListing, error)
+
+	// 购买物品，嘉奖扣除、账本记录和物品入库在同一事务中完成
+	Purchase(agentID string, req *PurchaseRequest) (*PurchaseResult, error)
+
+	// 查询角色的物品栏
+	GetInventory(agentID string) ([]*domain.InventoryItem, error)
+
+	// 在会话中使用物品
+	UseItem(sessionID string, req *UseItemRequest) (*ItemUseResult, error)
+
+	// 发放剧本奖励物品，每个会话只发放一次
+	GrantScenarioRewards(sessionID string) ([]*domain.InventoryItem, error)
+
+	// 注册或替换物品效果钩子
+	RegisterEffect(effectType string, fn ItemEffectFunc)
+}
+
+// ItemEffectFunc 物品效果钩子
+// 修改会话状态副本和角色副本，返回效果的叙事描述；返回错误时物品不会被消耗
+type ItemEffectFunc func(state *domain.GameState, agent *domain.Agent, effect *domain.ItemEffect) (string, error)
+
+// PurchaseRequest 购买物品请求
+type PurchaseRequest struct {
+	ItemID string `json:"item_id" binding:"required"` // 商店物品ID或名称
+}
+
+// UseItemRequest 使用物品请求
+type UseItemRequest struct {
+	ItemID string `json:"item_id" binding:"required"` // 物品栏中的物品实例ID
+}
+
+// StoreListing 商店目录
+type StoreListing struct {
+	AgentID       string              `json:"agent_id,omitempty"`
+	Commendations int                 `json:"commendations"`
+	Items         []*StoreListingItem `json:"items"`
+}
+
+// StoreListingItem 商店目录条目，未指定角色时Available和Affordable不计算
+type StoreListingItem struct {
+	*catalog.StoreItem
+	Available  bool `json:"available"`  // 角色的职能可以购买
+	Affordable bool `json:"affordable"` // 角色的嘉奖足够支付
+}
+
+// PurchaseResult 购买结果
+type PurchaseResult struct {
+	Item          *domain.InventoryItem `json:"item"`
+	Entry         *domain.LedgerEntry   `json:"ledger_entry"`
+	Commendations int                   `json:"commendations"` // 购买后剩余嘉奖
+}
+
+// ItemUseResult 使用物品结果
+type ItemUseResult struct {
+	Item      *domain.InventoryItem `json:"item"`
+	Narration string                `json:"narration"`
+	Remaining int                   `json:"remaining"` // 剩余使用次数，0表示不限次数或已用尽
+	Removed   bool                  `json:"removed"`   // 次数用尽，已从物品栏移除
+	State     *domain.GameState     `json:"state"`
+}
+
+// storeService 机构商店服务实现
+type storeService struct {
+	agentService    AgentService
+	gameService     GameService
+	scenarioService ScenarioService
+	catalog         *catalog.Catalog
+	effects         map[string]ItemEffectFunc
+	mu              sync.RWMutex
+}
+
+// NewStoreService 创建机构商店服务
+// arc为nil时使用内嵌配置
+func NewStoreService(agentService AgentService, gameService GameService, scenarioService ScenarioService, arc *catalog.Catalog) StoreService {
+	if arc == nil {
+		arc = catalog.Default()
+	}
+
+	s := &storeService{
+		agentService:    agentService,
+		gameService:     gameService,
+		scenarioService: scenarioService,
+		catalog:         arc,
+		effects:         make(map[string]ItemEffectFunc),
+	}
+
+	s.RegisterEffect(domain.ItemEffectReduceChaos, reduceChaosEffect)
+	s.RegisterEffect(domain.ItemEffectRestoreQA, restoreQAEffect)
+	s.RegisterEffect(domain.ItemEffectRerollToken, rerollTokenEffect)
+	s.RegisterEffect(domain.ItemEffectClearLooseEnds, clearLooseEndsEffect)
+	s.RegisterEffect(domain.ItemEffectNarrative, narrativeEffect)
+
+	return s
+}
+
+// RegisterEffect 注册或替换物品效果钩子
+func (s *storeService) RegisterEffect(effectType string, fn ItemEffectFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.effects[effectType] = fn
+}
+
+// ListItems 列出商店目录
+func (s *storeService) ListItems(agentID string) (*StoreListing, error) {
+	listing := &StoreListing{
+		Items: make([]*StoreListingItem, 0, len(s.catalog.Store)),
+	}
+
+	var agent *domain.Agent
+	if agentID != "" {
+		var err error
+		if agent, err = s.agentService.GetAgent(agentID); err != nil {
+			return nil, err
+		}
+		listing.AgentID = agent.ID
+		listing.Commendations = agent.Commendations
+	}
+
+	for _, item := range s.catalog.Store {
+		entry := &StoreListingItem{StoreItem: item}
+		if agent != nil {
+			entry.Available = agent.Career != nil && s.catalog.AvailableTo(item, agent.Career.Type)
+			entry.Affordable = agent.Commendations >= item.Price
+		}
+		listing.Items = append(listing.Items, entry)
+	}
+
+	return listing, nil
+}
+
+// Purchase 花费嘉奖购买物品
+func (s *storeService) Purchase(agentID string, req *PurchaseRequest) (*PurchaseResult, error) {
+	entry, ok := s.catalog.StoreItem(req.ItemID)
+	if !ok {
+		return nil, domain.NewGameError(domain.ErrNotFound, "商店中没有该物品").
+			WithDetails("item_id", req.ItemID)
+	}
+
+	result := &PurchaseResult{}
+	agent, err := s.agentService.ModifyAgent(agentID, func(agent *domain.Agent) error {
+		if agent.Career == nil || !s.catalog.AvailableTo(entry, agent.Career.Type) {
+			return domain.NewGameError(domain.ErrInvalidAction, "该物品不对角色的职能开放").
+				WithDetails("item_id", entry.ID).
+				WithDetails("careers", entry.Careers)
+		}
+
+		// 在事务内检查余额，避免并发购买透支
+		if agent.Commendations < entry.Price {
+			return domain.NewGameError(domain.ErrInsufficientCommendations, "嘉奖不足").
+				WithDetails("price", entry.Price).
+				WithDetails("commendations", agent.Commendations)
+		}
+
+		item, err := s.catalog.NewItem(entry.ID, domain.ItemSourcePurchase)
+		if err != nil {
+			return err
+		}
+		item.ID = uuid.New().String()
+		agent.AddItem(item)
+
+		result.Item = item
+		result.Entry = agent.RecordLedger(&domain.LedgerEntry{
+			Reason:        domain.LedgerReasonStorePurchase,
+			Behavior:      entry.Name,
+			Justification: fmt.Sprintf("在机构商店购买%s", entry.Name),
+			Commendations: -entry.Price,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.Commendations = agent.Commendations
+	return result, nil
+}
+
+// GetInventory 查询角色的物品栏
+func (s *storeService) GetInventory(agentID string) ([]*domain.InventoryItem, error) {
+	agent, err := s.agentService.GetAgent(agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if agent.Inventory == nil {
+		return []*domain.InventoryItem{}, nil
+	}
+	return agent.Inventory, nil
+}
+
+// UseItem 在会话中使用物品
+// 效果钩子作用于会话状态副本，角色保存成功后才写回会话
+func (s *storeService) UseItem(sessionID string, req *UseItemRequest) (*ItemUseResult, error) {
+	session, err := s.gameService.GetSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	state := copyGameState(session.State)
+	if state == nil {
+		state = &domain.GameState{}
+	}
+
+	result := &ItemUseResult{}
+	_, err = s.agentService.ModifyAgent(session.AgentID, func(agent *domain.Agent) error {
+		item := agent.FindItem(req.ItemID)
+		if item == nil {
+			return domain.NewGameError(domain.ErrNotFound, "物品不在物品栏中").
+				WithDetails("item_id", req.ItemID)
+		}
+
+		if !item.UsableIn(session.Phase) {
+			return domain.NewGameError(domain.ErrInvalidPhase, "当前阶段不能使用该物品").
+				WithDetails("item_id", item.ID).
+				WithDetails("phase", session.Phase).
+				WithDetails("usable_phases", item.Phases)
+		}
+
+		effect := item.Effect
+		if effect == nil {
+			effect = &domain.ItemEffect{Type: domain.ItemEffectNarrative}
+		}
+		hook, ok := s.effect(effect.Type)
+		if !ok {
+			return domain.NewGameError(domain.ErrInvalidState, "物品效果没有对应的处理").
+				WithDetails("effect", effect.Type)
+		}
+
+		narration, err := hook(state, agent, effect)
+		if err != nil {
+			return err
+		}
+
+		if err := agent.ConsumeItem(item.ID); err != nil {
+			return err
+		}
+
+		result.Item = item
+		result.Narration = narration
+		result.Remaining = item.Uses
+		result.Removed = agent.FindItem(item.ID) == nil
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	session.State = state
+	if err := s.gameService.SaveSession(session); err != nil {
+		return nil, err
+	}
+
+	result.State = state
+	return result, nil
+}
+
+// GrantScenarioRewards 将剧本奖励物品放入角色物品栏
+// 已发放过的会话返回空列表
+func (s *storeService) GrantScenarioRewards(sessionID string) ([]*domain.InventoryItem, error) {
+	session, err := s.gameService.GetSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.State == nil || session.State.RewardsGranted {
+		return []*domain.InventoryItem{}, nil
+	}
+
+	if s.scenarioService == nil {
+		return nil, domain.NewGameError(domain.ErrInvalidState, "没有可用的剧本服务")
+	}
+	scenario, err := s.scenarioService.LoadScenario(session.ScenarioID)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*domain.InventoryItem, 0)
+	if scenario.Rewards != nil {
+		for _, claimable := range scenario.Rewards.Claimables {
+			item, err := s.catalog.NewRewardItem(claimable)
+			if err != nil {
+				return nil, err
+			}
+			item.ID = uuid.New().String()
+			item.SessionID = sessionID
+			items = append(items, item)
+		}
+	}
+
+	if len(items) > 0 {
+		_, err = s.agentService.ModifyAgent(session.AgentID, func(agent *domain.Agent) error {
+			for _, item := range items {
+				agent.AddItem(item)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	session.State.RewardsGranted = true
+	if err := s.gameService.SaveSession(session); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// effect 查找物品效果钩子
+func (s *storeService) effect(effectType string) (ItemEffectFunc, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	fn, ok := s.effects[effectType]
+	return fn, ok
+}
+
+// reduceChaosEffect 从混沌池移除混沌，最低为0
+func reduceChaosEffect(state *domain.GameState, agent *domain.Agent, effect *domain.ItemEffect) (string, error) {
+	removed := effect.Amount
+	if removed > state.ChaosPool {
+		removed = state.ChaosPool
+	}
+	state.ChaosPool -= removed
+
+	// 混沌基准随之下降，避免移除的混沌被算作激增
+	if state.ChaosBaseline > state.ChaosPool {
+		state.ChaosBaseline = state.ChaosPool
+	}
+
+	return effectNarration(effect, fmt.Sprintf("混沌池减少%d点", removed)), nil
+}
+
+// restoreQAEffect 恢复资质保证，不超过职能初始值
+func restoreQAEffect(state *domain.GameState, agent *domain.Agent, effect *domain.ItemEffect) (string, error) {
+	limit := 0
+	if agent.Career != nil {
+		limit = agent.Career.QA[effect.Quality]
+	}
+	if agent.QA == nil {
+		agent.QA = make(map[string]int)
+	}
+
+	current := agent.QA[effect.Quality]
+	if current >= limit {
+		return "", domain.NewGameError(domain.ErrInvalidAction, "该资质的资质保证已满").
+			WithDetails("quality", effect.Quality).
+			WithDetails("current", current)
+	}
+
+	restored := effect.Amount
+	if current+restored > limit {
+		restored = limit - current
+	}
+	agent.QA[effect.Quality] = current + restored
+
+	return effectNarration(effect, fmt.Sprintf("%s恢复%d点资质保证", effect.Quality, restored)), nil
+}
+
+// rerollTokenEffect 获得免费重掷令牌
+func rerollTokenEffect(state *domain.GameState, agent *domain.Agent, effect *domain.ItemEffect) (string, error) {
+	state.RerollTokens += effect.Amount
+	return effectNarration(effect, fmt.Sprintf("获得%d个免费重掷令牌", effect.Amount)), nil
+}
+
+// clearLooseEndsEffect 消除散逸端，最低为0
+func clearLooseEndsEffect(state *domain.GameState, agent *domain.Agent, effect *domain.ItemEffect) (string, error) {
+	cleared := effect.Amount
+	if cleared > state.LooseEnds {
+		cleared = state.LooseEnds
+	}
+	state.LooseEnds -= cleared
+
+	return effectNarration(effect, fmt.Sprintf("消除%d个散逸端", cleared)), nil
+}
+
+// narrativeEffect 仅有叙事效果，由主持人裁定
+func narrativeEffect(state *domain.GameState, agent *domain.Agent, effect *domain.ItemEffect) (string, error) {
+	return effectNarration(effect, "效果由主持人裁定"), nil
+}
+
+// effectNarration 组合物品的叙事描述和机制结果
+func effectNarration(effect *domain.ItemEffect, mechanics string) string {
+	if effect.Note == "" {
+		return mechanics
+	}
+	return fmt.Sprintf("%s（%s）", effect.Note, mechanics)
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+)
+
+func setupStoreTest(t *testing.T) (AgentService, GameService, StoreService, *domain.Agent, *domain.GameSession) {
+	fx := newTestAgentSession(t, withScenario("eternal-spring"))
+	store := NewStoreService(fx.agentService, fx.gameService, fx.scenarioService, nil)
+
+	return fx.agentService, fx.gameService, store, fx.agent, fx.session
+}
+
+func TestAgentService_InitialItem(t *testing.T) {
+	agentService := NewAgentService()
+	agent, err := agentService.CreateAgent(&CreateAgentRequest{
+		Name:        "初始物品",
+		AnomalyType: domain.AnomalyWhisper,
+		RealityType: domain.RealityCaretaker,
+		CareerType:  domain.CareerPublicRelations,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, agent.Inventory, 1)
+	assert.Equal(t, "pr-spin-kit", agent.Inventory[0].ItemID)
+	assert.Equal(t, domain.ItemSourceCareer, agent.Inventory[0].Source)
+	assert.NotEmpty(t, agent.Inventory[0].ID)
+}
+
+func TestStoreService_ListItems(t *testing.T) {
+	_, _, store, agent, _ := setupStoreTest(t)
+
+	listing, err := store.ListItems(agent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, agent.ID, listing.AgentID)
+
+	availability := make(map[string]bool)
+	for _, item := range listing.Items {
+		availability[item.ID] = item.Available
+		assert.False(t, item.Affordable, "新角色没有嘉奖")
+	}
+	assert.True(t, availability["ripple-gun"])
+	assert.True(t, availability["pr-spin-kit"])
+	assert.False(t, availability["rd-scanner"], "其他职能的物品不能购买")
+
+	_, err = store.ListItems("missing")
+	require.Error(t, err)
+}
+
+func TestStoreService_Purchase(t *testing.T) {
+	agentService, _, store, agent, _ := setupStoreTest(t)
+
+	_, err := store.Purchase(agent.ID, &PurchaseRequest{ItemID: "ripple-gun"})
+	require.Error(t, err)
+	assert.Equal(t, domain.ErrInsufficientCommendations, err.(*domain.GameError).Code)
+	assert.Len(t, agent.Ledger, 0, "购买失败不记账")
+
+	require.NoError(t, agentService.AddCommendations(agent.ID, 5))
+
+	result, err := store.Purchase(agent.ID, &PurchaseRequest{ItemID: "波纹枪"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Commendations)
+	assert.Equal(t, domain.ItemSourcePurchase, result.Item.Source)
+	assert.Equal(t, domain.LedgerReasonStorePurchase, result.Entry.Reason)
+	assert.Equal(t, -4, result.Entry.Commendations)
+	assert.NotEmpty(t, result.Entry.ID)
+
+	stored, err := agentService.GetAgent(agent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stored.Commendations)
+	assert.NotNil(t, stored.FindItem(result.Item.ID))
+	assert.True(t, stored.ReconcileLedger().Balanced)
+
+	_, err = store.Purchase(agent.ID, &PurchaseRequest{ItemID: "rd-scanner"})
+	require.Error(t, err)
+	assert.Equal(t, domain.ErrInvalidAction, err.(*domain.GameError).Code)
+
+	_, err = store.Purchase(agent.ID, &PurchaseRequest{ItemID: "不存在的物品"})
+	require.Error(t, err)
+	assert.Equal(t, domain.ErrNotFound, err.(*domain.GameError).Code)
+}
+
+func TestStoreService_UseItem(t *testing.T) {
+	agentService, gameService, store, agent, session := setupStoreTest(t)
+	require.NoError(t, agentService.AddCommendations(agent.ID, 4))
+	purchased, err := store.Purchase(agent.ID, &PurchaseRequest{ItemID: "ripple-gun"})
+	require.NoError(t, err)
+	gunID := purchased.Item.ID
+
+	// 波纹枪只能在遭遇阶段使用
+	_, err = store.UseItem(session.ID, &UseItemRequest{ItemID: gunID})
+	require.Error(t, err)
+	assert.Equal(t, domain.ErrInvalidPhase, err.(*domain.GameError).Code)
+
+	session.Phase = domain.PhaseEncounter
+	session.State.ChaosPool = 5
+	require.NoError(t, gameService.SaveSession(session))
+
+	result, err := store.UseItem(session.ID, &UseItemRequest{ItemID: gunID})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.State.ChaosPool)
+	assert.Equal(t, 1, result.Remaining)
+	assert.False(t, result.Removed)
+	assert.Contains(t, result.Narration, "混沌池减少3点")
+
+	// 混沌池不会变成负数，次数用尽后移除
+	result, err = store.UseItem(session.ID, &UseItemRequest{ItemID: gunID})
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.State.ChaosPool)
+	assert.True(t, result.Removed)
+
+	state, err := gameService.GetState(session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, state.ChaosPool)
+
+	stored, err := agentService.GetAgent(agent.ID)
+	require.NoError(t, err)
+	assert.Nil(t, stored.FindItem(gunID))
+
+	_, err = store.UseItem(session.ID, &UseItemRequest{ItemID: gunID})
+	require.Error(t, err)
+	assert.Equal(t, domain.ErrNotFound, err.(*domain.GameError).Code)
+}
+
+func TestStoreService_EffectHookFailureKeepsItem(t *testing.T) {
+	agentService, _, store, agent, session := setupStoreTest(t)
+	kit := agent.Inventory[0]
+	looseEnds := session.State.LooseEnds
+
+	store.RegisterEffect(domain.ItemEffectClearLooseEnds, func(state *domain.GameState, agent *domain.Agent, effect *domain.ItemEffect) (string, error) {
+		state.LooseEnds = 99
+		return "", domain.NewGameError(domain.ErrInvalidAction, "现在不能使用")
+	})
+
+	_, err := store.UseItem(session.ID, &UseItemRequest{ItemID: kit.ID})
+	require.Error(t, err)
+
+	stored, err := agentService.GetAgent(agent.ID)
+	require.NoError(t, err)
+	require.NotNil(t, stored.FindItem(kit.ID))
+	assert.Equal(t, 1, stored.FindItem(kit.ID).Uses)
+	assert.Equal(t, looseEnds, session.State.LooseEnds, "失败时会话状态不变")
+}
+
+func TestStoreService_RestoreQAEffect(t *testing.T) {
+	agent := &domain.Agent{
+		Career: &domain.Career{QA: map[string]int{domain.QualityGrit: 2}},
+		QA:     map[string]int{domain.QualityGrit: 2},
+	}
+	effect := &domain.ItemEffect{Type: domain.ItemEffectRestoreQA, Quality: domain.QualityGrit, Amount: 3}
+
+	_, err := restoreQAEffect(&domain.GameState{}, agent, effect)
+	require.Error(t, err, "资质保证已满时不能使用")
+
+	agent.QA[domain.QualityGrit] = 0
+	_, err = restoreQAEffect(&domain.GameState{}, agent, effect)
+	require.NoError(t, err)
+	assert.Equal(t, 2, agent.QA[domain.QualityGrit], "不超过职能初始值")
+}
+
+func TestStoreService_GrantScenarioRewards(t *testing.T) {
+	agentService, _, store, agent, session := setupStoreTest(t)
+
+	items, err := store.GrantScenarioRewards(session.ID)
+	require.NoError(t, err)
+	require.NotEmpty(t, items)
+
+	var gun *domain.InventoryItem
+	for _, item := range items {
+		assert.Equal(t, domain.ItemSourceReward, item.Source)
+		assert.Equal(t, session.ID, item.SessionID)
+		if item.Name == "波纹枪" {
+			gun = item
+		}
+	}
+	require.NotNil(t, gun)
+	assert.Equal(t, "ripple-gun", gun.ItemID)
+	assert.Equal(t, domain.ItemEffectReduceChaos, gun.Effect.Type)
+
+	stored, err := agentService.GetAgent(agent.ID)
+	require.NoError(t, err)
+	assert.Len(t, stored.Inventory, len(items)+1)
+
+	// 同一会话只发放一次
+	again, err := store.GrantScenarioRewards(session.ID)
+	require.NoError(t, err)
+	assert.Empty(t, again)
+	assert.Len(t, stored.Inventory, len(items)+1)
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+)
+
+// TripleAscensionService 三重升华服务接口
+type TripleAscensionService interface {
+	// 结算三重升华：记录事件、授予效果并生成叙事
+	Resolve(session *domain.GameSession, record *domain.RollRecord) (*domain.TripleAscension, error)
+
+	// 消耗一个免费重掷令牌，检查和扣除在会话锁内完成，并发重掷只有一个能成功
+	SpendRerollToken(session *domain.GameSession) error
+}
+
+// tripleAscensionService 三重升华服务实现
+type tripleAscensionService struct {
+	agentService AgentService
+	gameService  GameService
+	aiService    AIService
+	rules        *domain.Ruleset
+}
+
+// NewTripleAscensionService 创建三重升华服务
+// 会话没有自身规则集时使用rules决定效果
+func NewTripleAscensionService(agentService AgentService, gameService GameService, aiService AIService, rules *domain.Ruleset) TripleAscensionService {
+	if rules == nil {
+		rules = domain.DefaultRuleset()
+	}
+
+	return &tripleAscensionService{
+		agentService: agentService,
+		gameService:  gameService,
+		aiService:    aiService,
+		rules:        rules,
+	}
+}
+
+// Resolve 结算三重升华
+// 非三重升华的掷骰返回nil；效果写入账本条目和会话状态
+// 按掷骰ID幂等：同一掷骰重试结算时返回已有事件，嘉奖也只授予一次
+func (s *tripleAscensionService) Resolve(session *domain.GameSession, record *domain.RollRecord) (*domain.TripleAscension, error) {
+	if record == nil || !record.TripleAsc {
+		return nil, nil
+	}
+
+	if session == nil || session.State == nil {
+		return nil, domain.NewGameError(domain.ErrInvalidInput, "三重升华必须发生在游戏会话中")
+	}
+
+	rules := s.rules
+	if session.Rules != nil {
+		rules = session.Rules
+	}
+
+	// 提前分配账本ID，便于事件关联到掷骰记录
+	if record.ID == "" {
+		record.ID = uuid.New().String()
+	}
+
+	// 已结算过的掷骰直接返回原事件
+	for _, event := range session.State.TripleAscensions {
+		if event.RollID == record.ID {
+			record.Effect = event.Effect
+			return event, nil
+		}
+	}
+
+	// 授予效果
+	effectText := ""
+	switch rules.TripleAscensionEffect {
+	case domain.TripleAscEffectCommendation:
+		// 账本条目ID由掷骰ID派生，之前的结算在授予嘉奖后失败时不会重复授予
+		entryID := tripleAscensionEntryID(record.ID)
+		if _, err := s.agentService.ModifyAgent(record.AgentID, func(agent *domain.Agent) error {
+			for _, entry := range agent.Ledger {
+				if entry.ID == entryID {
+					return nil
+				}
+			}
+			agent.RecordLedger(&domain.LedgerEntry{
+				ID:            entryID,
+				SessionID:     session.ID,
+				SceneID:       session.State.CurrentSceneID,
+				Reason:        domain.LedgerReasonTripleAscension,
+				Justification: "掷骰出现三重升华",
+				Commendations: rules.TripleAscensionReward,
+			})
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+		effectText = fmt.Sprintf("三重升华：获得 %d 次嘉奖", rules.TripleAscensionReward)
+	case domain.TripleAscEffectReroll:
+		session.State.RerollTokens += rules.TripleAscensionReward
+		effectText = fmt.Sprintf("三重升华：获得 %d 个免费重掷令牌", rules.TripleAscensionReward)
+	default:
+		return nil, domain.NewGameError(domain.ErrInvalidState, "无效的三重升华效果").
+			WithDetails("effect", rules.TripleAscensionEffect)
+	}
+
+	// 生成叙事钩子
+	description := record.Request
+	if description == "" {
+		description = "掷骰"
+	}
+	narration, err := s.aiService.NarrateResult(
+		&Action{
+			Type:        "triple_ascension",
+			Description: description,
+			Quality:     record.Quality,
+		},
+		&ActionResult{
+			Success:   record.Success,
+			Threes:    record.Threes,
+			Chaos:     record.Chaos,
+			TripleAsc: true,
+			Effects:   []string{effectText},
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	event := &domain.TripleAscension{
+		RollID:    record.ID,
+		AgentID:   record.AgentID,
+		Quality:   record.Quality,
+		Effect:    rules.TripleAscensionEffect,
+		Amount:    rules.TripleAscensionReward,
+		Narration: narration,
+		CreatedAt: time.Now(),
+	}
+
+	session.State.TripleAscensions = append(session.State.TripleAscensions, event)
+	record.Effect = rules.TripleAscensionEffect
+
+	if err := s.gameService.SaveSession(session); err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+// tripleAscensionEntryID 由掷骰ID派生三重升华嘉奖的账本条目ID
+func tripleAscensionEntryID(rollID string) string {
+	return uuid.NewSHA1(uuid.NameSpaceOID, []byte("triple-ascension:"+rollID)).String()
+}
+
+// SpendRerollToken 消耗一个免费重掷令牌
+// 在会话状态锁内检查并扣除，避免并发请求都通过检查
+func (s *tripleAscensionService) SpendRerollToken(session *domain.GameSession) error {
+	if session == nil || session.State == nil {
+		return domain.NewGameError(domain.ErrInvalidInput, "游戏会话不能为空")
+	}
+
+	return s.gameService.UpdateState(session.ID, func(state *domain.GameState) error {
+		if state.RerollTokens <= 0 {
+			return domain.NewGameError(domain.ErrInvalidState, "没有可用的免费重掷令牌").
+				WithDetails("session_id", session.ID)
+		}
+
+		state.RerollTokens--
+		return nil
+	})
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+)
+
+func setupTripleAscensionTest(t *testing.T, rules *domain.Ruleset) (TripleAscensionService, AgentService, GameService, *domain.GameSession) {
+	agentService := NewAgentService()
+	agent, err := agentService.CreateAgent(&CreateAgentRequest{
+		Name:        "测试特工",
+		AnomalyType: domain.AnomalyWhisper,
+		RealityType: domain.RealityCaretaker,
+		CareerType:  domain.CareerPublicRelations,
+	})
+	require.NoError(t, err)
+
+	gameService := NewGameService()
+	session, err := gameService.CreateSession(agent.ID, "test-scenario")
+	require.NoError(t, err)
+
+	svc := NewTripleAscensionService(agentService, gameService, NewAIService(), rules)
+	return svc, agentService, gameService, session
+}
+
+func newTripleAscRecord(session *domain.GameSession, tripleAsc bool) *domain.RollRecord {
+	record := domain.NewRollRecord(session.ID, session.AgentID, domain.RollKindRequest, &domain.RollResult{
+		RawDice:   []int{3, 3, 3, 1, 2, 4},
+		Dice:      []int{3, 3, 3, 1, 2, 4},
+		Threes:    3,
+		Success:   true,
+		TripleAsc: tripleAsc,
+	})
+	record.Quality = domain.QualityFocus
+	return record
+}
+
+func TestTripleAscensionService_Resolve(t *testing.T) {
+	t.Run("默认效果授予嘉奖", func(t *testing.T) {
+		svc, agentService, gameService, session := setupTripleAscensionTest(t, nil)
+		record := newTripleAscRecord(session, true)
+
+		event, err := svc.Resolve(session, record)
+		require.NoError(t, err)
+		require.NotNil(t, event)
+		assert.Equal(t, domain.TripleAscEffectCommendation, event.Effect)
+		assert.Equal(t, 1, event.Amount)
+		assert.Equal(t, record.ID, event.RollID)
+		assert.Contains(t, event.Narration, "三重升华")
+		assert.Equal(t, domain.TripleAscEffectCommendation, record.Effect)
+
+		agent, _ := agentService.GetAgent(session.AgentID)
+		assert.Equal(t, 1, agent.Commendations)
+
+		stored, _ := gameService.GetSession(session.ID)
+		require.Len(t, stored.State.TripleAscensions, 1)
+		assert.Equal(t, 0, stored.State.RerollTokens)
+	})
+
+	t.Run("同一掷骰重试结算只授予一次", func(t *testing.T) {
+		svc, agentService, gameService, session := setupTripleAscensionTest(t, nil)
+		record := newTripleAscRecord(session, true)
+
+		first, err := svc.Resolve(session, record)
+		require.NoError(t, err)
+
+		again, err := svc.Resolve(session, record)
+		require.NoError(t, err)
+		assert.Same(t, first, again)
+
+		// 授予嘉奖后结算失败，会话中没有事件时重试也不重复授予
+		session.State.TripleAscensions = nil
+		_, err = svc.Resolve(session, record)
+		require.NoError(t, err)
+
+		agent, _ := agentService.GetAgent(session.AgentID)
+		assert.Equal(t, 1, agent.Commendations)
+		assert.Len(t, agent.Ledger, 1)
+
+		stored, _ := gameService.GetSession(session.ID)
+		assert.Len(t, stored.State.TripleAscensions, 1)
+	})
+
+	t.Run("会话规则集可改为授予重掷令牌", func(t *testing.T) {
+		svc, agentService, gameService, session := setupTripleAscensionTest(t, nil)
+		session.Rules = domain.DefaultRuleset()
+		session.Rules.TripleAscensionEffect = domain.TripleAscEffectReroll
+		session.Rules.TripleAscensionReward = 2
+
+		event, err := svc.Resolve(session, newTripleAscRecord(session, true))
+		require.NoError(t, err)
+		require.NotNil(t, event)
+		assert.Equal(t, domain.TripleAscEffectReroll, event.Effect)
+
+		agent, _ := agentService.GetAgent(session.AgentID)
+		assert.Equal(t, 0, agent.Commendations)
+
+		stored, _ := gameService.GetSession(session.ID)
+		assert.Equal(t, 2, stored.State.RerollTokens)
+	})
+
+	t.Run("非三重升华不产生事件", func(t *testing.T) {
+		svc, _, _, session := setupTripleAscensionTest(t, nil)
+
+		event, err := svc.Resolve(session, newTripleAscRecord(session, false))
+		require.NoError(t, err)
+		assert.Nil(t, event)
+		assert.Empty(t, session.State.TripleAscensions)
+	})
+}
+
+func TestTripleAscensionService_SpendRerollToken(t *testing.T) {
+	svc, _, gameService, session := setupTripleAscensionTest(t, nil)
+
+	err := svc.SpendRerollToken(session)
+	require.Error(t, err)
+	assert.Equal(t, domain.ErrInvalidState, err.(*domain.GameError).Code)
+
+	session.State.RerollTokens = 1
+	require.NoError(t, svc.SpendRerollToken(session))
+
+	stored, _ := gameService.GetSession(session.ID)
+	assert.Equal(t, 0, stored.State.RerollTokens)
+}
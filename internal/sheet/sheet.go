@@ -0,0 +1,157 @@
+// Package sheet 将角色渲染为可打印的角色卡
+//
+// 模板内嵌在二进制中：HTML使用html/template自动转义，
+// Markdown不是HTML，使用接口相同的text/template，避免角色文本被转义为HTML实体
+package sheet
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/trpg-solo-engine/backend/internal/domain"
+)
+
+// 角色卡格式
+const (
+	FormatMarkdown = "md"
+	FormatHTML     = "html"
+	FormatJSON     = "json"
+)
+
+//go:embed templates/*
+var templateFiles embed.FS
+
+var funcs = map[string]interface{}{
+	"boxes":  boxes,
+	"join":   strings.Join,
+	"mdcell": mdcell,
+}
+
+var (
+	htmlTemplate = htmltemplate.Must(htmltemplate.New("sheet.html.tmpl").
+			Funcs(htmltemplate.FuncMap(funcs)).
+			ParseFS(templateFiles, "templates/sheet.html.tmpl"))
+	markdownTemplate = texttemplate.Must(texttemplate.New("sheet.md.tmpl").
+				Funcs(texttemplate.FuncMap(funcs)).
+				ParseFS(templateFiles, "templates/sheet.md.tmpl"))
+)
+
+// Sheet 角色卡视图
+type Sheet struct {
+	ID            string                   `json:"id"`
+	Name          string                   `json:"name"`
+	Pronouns      string                   `json:"pronouns"`
+	Alive         bool                     `json:"alive"`
+	Anomaly       *domain.Anomaly          `json:"anomaly"`
+	Reality       *domain.Reality          `json:"reality"`
+	Career        *domain.Career           `json:"career"`
+	QA            []*QARow                 `json:"qa"`
+	Relationships []*domain.Relationship   `json:"relationships"`
+	Degradation   *domain.DegradationTrack `json:"degradation"`
+	Commendations int                      `json:"commendations"`
+	Reprimands    int                      `json:"reprimands"`
+	Rating        string                   `json:"rating"`
+	InDebt        bool                     `json:"in_debt"`
+	Inventory     []*domain.InventoryItem  `json:"inventory"`
+}
+
+// QARow 资质保证表的一行
+type QARow struct {
+	Quality string `json:"quality"`
+	Current int    `json:"current"`
+	Max     int    `json:"max"` // 职能给予的上限
+}
+
+// IsValidFormat 检查角色卡格式是否支持
+func IsValidFormat(format string) bool {
+	switch format {
+	case FormatMarkdown, FormatHTML, FormatJSON:
+		return true
+	default:
+		return false
+	}
+}
+
+// Build 从角色构建角色卡视图
+func Build(agent *domain.Agent) *Sheet {
+	sheet := &Sheet{
+		ID:            agent.ID,
+		Name:          agent.Name,
+		Pronouns:      agent.Pronouns,
+		Alive:         agent.Alive,
+		Anomaly:       agent.Anomaly,
+		Reality:       agent.Reality,
+		Career:        agent.Career,
+		Relationships: agent.Relationships,
+		Commendations: agent.Commendations,
+		Reprimands:    agent.Reprimands,
+		Rating:        agent.Rating,
+		InDebt:        agent.InDebt,
+		Inventory:     agent.Inventory,
+	}
+
+	if agent.Reality != nil {
+		sheet.Degradation = agent.Reality.DegradationTrack
+	}
+
+	for _, quality := range domain.AllQualities {
+		row := &QARow{Quality: quality, Current: agent.QA[quality]}
+		if agent.Career != nil {
+			row.Max = agent.Career.QA[quality]
+		}
+		sheet.QA = append(sheet.QA, row)
+	}
+
+	return sheet
+}
+
+// Render 按格式渲染角色卡，json格式不经过模板
+func Render(w io.Writer, agent *domain.Agent, format string) error {
+	sheet := Build(agent)
+
+	switch format {
+	case FormatHTML:
+		return htmlTemplate.Execute(w, sheet)
+	case FormatMarkdown:
+		return markdownTemplate.Execute(w, sheet)
+	default:
+		return domain.NewGameError(domain.ErrInvalidInput, "不支持的角色卡格式").
+			WithDetails("format", format)
+	}
+}
+
+// RenderString 渲染为字符串
+func RenderString(agent *domain.Agent, format string) (string, error) {
+	var buf bytes.Buffer
+	if err := Render(&buf, agent, format); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// boxes 将退化轨道画成方格，例如 ■■□□
+func boxes(filled, total int) string {
+	if filled > total {
+		filled = total
+	}
+	if filled < 0 {
+		filled = 0
+	}
+	return strings.Repeat("■", filled) + strings.Repeat("□", total-filled)
+}
+
+// mdcell 将值转换为Markdown表格单元格：转义竖线，换行合并为空格，避免破坏表格结构
+func mdcell(v interface{}) string {
+	lines := strings.FieldsFunc(fmt.Sprint(v), func(r rune) bool {
+		return r == '\n' || r == '\r'
+	})
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	return strings.ReplaceAll(strings.Join(lines, " "), "|", `\|`)
+}
@@ -0,0 +1,103 @@
+package sheet
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trpg-solo-engine/backend/internal/domain"
+)
+
+func testAgent() *domain.Agent {
+	return &domain.Agent{
+		ID:   "agent-1",
+		Name: "林默",
+		Anomaly: &domain.Anomaly{
+			Type: domain.AnomalyWhisper,
+			Abilities: []*domain.AnomalyAbility{
+				{
+					Name:    "再说一遍？",
+					Trigger: &domain.AbilityTrigger{Type: domain.TriggerResponse, Description: "回应一句说出的话"},
+					Roll:    &domain.AbilityRoll{Quality: domain.QualityPresence, DiceCount: 6, DiceType: 4},
+					Effects: &domain.AbilityEffects{
+						Success: &domain.Effect{Description: "目标相信新的那句话"},
+					},
+				},
+				{Name: "没有触发器的能力"},
+			},
+		},
+		Reality: &domain.Reality{
+			Type:             domain.RealityCaretaker,
+			Trigger:          &domain.RealityTrigger{Name: "需要关爱", Effect: "受照料者需要你"},
+			DegradationTrack: &domain.DegradationTrack{Name: "独立", Filled: 1, Total: 4},
+		},
+		Career: &domain.Career{
+			Type: domain.CareerPublicRelations,
+			QA:   map[string]int{domain.QualityEmpathy: 2},
+		},
+		QA: map[string]int{domain.QualityEmpathy: 1},
+		Relationships: []*domain.Relationship{
+			{ID: "r1", Name: "李娜", Connection: 6, Notes: []string{"喜欢猫"}},
+		},
+		Commendations: 3,
+		Rating:        domain.RatingExcellent,
+		Alive:         true,
+		Inventory: []*domain.InventoryItem{
+			{ID: "i1", Name: "波纹枪", Uses: 2, Effect: &domain.ItemEffect{Type: domain.ItemEffectReduceChaos}},
+		},
+	}
+}
+
+func TestBuild(t *testing.T) {
+	s := Build(testAgent())
+
+	require.Len(t, s.QA, len(domain.AllQualities))
+	for _, row := range s.QA {
+		if row.Quality == domain.QualityEmpathy {
+			assert.Equal(t, 1, row.Current)
+			assert.Equal(t, 2, row.Max)
+		}
+	}
+	assert.Equal(t, 1, s.Degradation.Filled)
+}
+
+func TestRender(t *testing.T) {
+	for _, format := range []string{FormatMarkdown, FormatHTML} {
+		t.Run(format, func(t *testing.T) {
+			out, err := RenderString(testAgent(), format)
+			require.NoError(t, err)
+
+			for _, want := range []string{"林默", "再说一遍？", "回应一句说出的话", "6d4", "需要关爱", "■□□□", "李娜", "喜欢猫", "波纹枪", "评级良好"} {
+				assert.True(t, strings.Contains(out, want), "角色卡缺少 %s", want)
+			}
+		})
+	}
+
+	_, err := RenderString(testAgent(), FormatJSON)
+	require.Error(t, err, "json格式不经过模板")
+}
+
+func TestRender_MarkdownTableCells(t *testing.T) {
+	agent := testAgent()
+	agent.Relationships[0].Description = "邻居|同事\n周末一起\r\n钓鱼"
+	agent.Inventory[0].Name = "A|B"
+
+	out, err := RenderString(agent, FormatMarkdown)
+	require.NoError(t, err)
+
+	assert.Contains(t, out, `| 李娜 | 6 |  | 邻居\|同事 周末一起 钓鱼 |`)
+	assert.Contains(t, out, `| A\|B | 2 |`)
+}
+
+func TestMdcell(t *testing.T) {
+	assert.Equal(t, `a\|b`, mdcell("a|b"))
+	assert.Equal(t, "第一行 第二行", mdcell("第一行\n\n  第二行\r\n"))
+	assert.Equal(t, "3", mdcell(3))
+}
+
+func TestBoxes(t *testing.T) {
+	assert.Equal(t, "■■□□", boxes(2, 4))
+	assert.Equal(t, "■■", boxes(5, 2))
+	assert.Equal(t, "□□□", boxes(-1, 3))
+}